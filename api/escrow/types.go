@@ -6,21 +6,31 @@ import (
 	"strings"
 
 	"github.com/srpvpn/tensor-go-sdk/internal/utils"
+	"github.com/srpvpn/tensor-go-sdk/money"
 )
 
 // DepositWithdrawEscrowRequest represents the request for depositing/withdrawing from escrow
 type DepositWithdrawEscrowRequest struct {
-	Action                string  `json:"action"`                          // The action to perform. Either "deposit" or "withdraw"
-	Owner                 string  `json:"owner"`                           // The owner of the Margin account
-	Lamports              float64 `json:"lamports"`                        // The amount of SOL to deposit/withdraw
-	Blockhash             string  `json:"blockhash"`                       // The blockhash to be passed into the transaction
-	Compute               *int32  `json:"compute,omitempty"`               // Compute units for the transaction
-	PriorityMicroLamports *int32  `json:"priorityMicroLamports,omitempty"` // The priority in micro-lamports to be used for the transaction
+	Action                string         `json:"action"`                          // The action to perform. Either "deposit" or "withdraw"
+	Owner                 string         `json:"owner"`                           // The owner of the Margin account
+	Lamports              money.Lamports `json:"lamports"`                        // The amount of SOL to deposit/withdraw
+	Blockhash             string         `json:"blockhash"`                       // The blockhash to be passed into the transaction
+	Compute               *int32         `json:"compute,omitempty"`               // Compute units for the transaction
+	PriorityMicroLamports *int32         `json:"priorityMicroLamports,omitempty"` // The priority in micro-lamports to be used for the transaction
 }
 
 // DepositWithdrawEscrowResponse represents the response from the deposit/withdraw escrow endpoint
 type DepositWithdrawEscrowResponse struct {
-	Status string `json:"status"` // Success status, typically "Ok"
+	Status string        `json:"status"`        // Success status, typically "Ok"
+	Txs    []Transaction `json:"txs,omitempty"` // The unsigned transactions to sign and send
+}
+
+// Transaction represents an unsigned transaction returned by the escrow endpoint
+type Transaction struct {
+	Tx                   *string                `json:"tx"`
+	TxV0                 string                 `json:"txV0"`
+	LastValidBlockHeight *float64               `json:"lastValidBlockHeight"`
+	Metadata             map[string]interface{} `json:"metadata"`
 }
 
 // Validator interface for request validation
@@ -2,6 +2,7 @@ package escrow
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -23,7 +24,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "DEPOSIT",
 				Owner:     "11111111111111111111111111111112",
-				Lamports:  1000000000.0, // 1 SOL
+				Lamports:  1000000000, // 1 SOL
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: false,
@@ -33,7 +34,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "deposit",
 				Owner:     "11111111111111111111111111111112",
-				Lamports:  1000000000.0, // 1 SOL
+				Lamports:  1000000000, // 1 SOL
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: false,
@@ -43,7 +44,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "WITHDRAW",
 				Owner:     "11111111111111111111111111111112",
-				Lamports:  500000000.0, // 0.5 SOL
+				Lamports:  500000000, // 0.5 SOL
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: false,
@@ -53,7 +54,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "WithDraw",
 				Owner:     "11111111111111111111111111111112",
-				Lamports:  500000000.0, // 0.5 SOL
+				Lamports:  500000000, // 0.5 SOL
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: false,
@@ -63,7 +64,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "",
 				Owner:     "11111111111111111111111111111112",
-				Lamports:  1000000000.0,
+				Lamports:  1000000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -74,7 +75,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "invalid",
 				Owner:     "11111111111111111111111111111112",
-				Lamports:  1000000000.0,
+				Lamports:  1000000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -85,7 +86,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "DEPOSIT",
 				Owner:     "",
-				Lamports:  1000000000.0,
+				Lamports:  1000000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -96,7 +97,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "DEPOSIT",
 				Owner:     "invalid-address",
-				Lamports:  1000000000.0,
+				Lamports:  1000000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -107,7 +108,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "DEPOSIT",
 				Owner:     "11111111111111111111111111111112",
-				Lamports:  -1000.0,
+				Lamports:  -1000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -118,7 +119,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "DEPOSIT",
 				Owner:     "11111111111111111111111111111112",
-				Lamports:  1000000000.0,
+				Lamports:  1000000000,
 				Blockhash: "",
 			},
 			wantErr: true,
@@ -129,7 +130,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "DEPOSIT",
 				Owner:     "11111111111111111111111111111112",
-				Lamports:  1000000000.0,
+				Lamports:  1000000000,
 				Blockhash: "11111111111111111111111111111114",
 				Compute:   int32Ptr(-1),
 			},
@@ -141,7 +142,7 @@ func TestDepositWithdrawEscrowRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:                "WITHDRAW",
 				Owner:                 "11111111111111111111111111111112",
-				Lamports:              2000000000.0, // 2 SOL
+				Lamports:              2000000000, // 2 SOL
 				Blockhash:             "11111111111111111111111111111114",
 				Compute:               int32Ptr(200000),
 				PriorityMicroLamports: int32Ptr(1000),
@@ -191,7 +192,7 @@ func TestDepositWithdrawEscrowRequest_JSON(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:    "DEPOSIT",
 				Owner:     "11111111111111111111111111111112",
-				Lamports:  1000000000.0,
+				Lamports:  1000000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			expected: `{"action":"DEPOSIT","owner":"11111111111111111111111111111112","lamports":1000000000,"blockhash":"11111111111111111111111111111114"}`,
@@ -201,7 +202,7 @@ func TestDepositWithdrawEscrowRequest_JSON(t *testing.T) {
 			request: &DepositWithdrawEscrowRequest{
 				Action:                "WITHDRAW",
 				Owner:                 "11111111111111111111111111111112",
-				Lamports:              2000000000.0,
+				Lamports:              2000000000,
 				Blockhash:             "11111111111111111111111111111114",
 				Compute:               int32Ptr(200000),
 				PriorityMicroLamports: int32Ptr(1000),
@@ -316,6 +317,14 @@ func TestDepositWithdrawEscrowResponse_JSON(t *testing.T) {
 			},
 			expected: `{"status":""}`,
 		},
+		{
+			name: "response with transaction",
+			response: &DepositWithdrawEscrowResponse{
+				Status: "Ok",
+				Txs:    []Transaction{{TxV0: "AQ=="}},
+			},
+			expected: `{"status":"Ok","txs":[{"tx":null,"txV0":"AQ==","lastValidBlockHeight":null,"metadata":null}]}`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -354,7 +363,7 @@ func TestDepositWithdrawEscrowResponse_UnmarshalJSON_FromAPIExample(t *testing.T
 		Status: "Ok",
 	}
 
-	if response != expected {
+	if !reflect.DeepEqual(response, expected) {
 		t.Errorf("Unmarshaled response = %+v, want %+v", response, expected)
 	}
 }
@@ -6,19 +6,20 @@ import (
 	"strings"
 
 	"github.com/srpvpn/tensor-go-sdk/internal/utils"
+	"github.com/srpvpn/tensor-go-sdk/money"
 )
 
 // EditListingRequest represents the request parameters for editing an NFT listing
 type EditListingRequest struct {
-	Mint                  string  `json:"mint"`
-	Owner                 string  `json:"owner"`
-	Price                 float64 `json:"price"`
-	Blockhash             string  `json:"blockhash"`
-	MakerBroker           *string `json:"makerBroker,omitempty"`
-	ExpireIn              *int32  `json:"expireIn,omitempty"`
-	FeePayer              *string `json:"feePayer,omitempty"`
-	Compute               *int32  `json:"compute,omitempty"`
-	PriorityMicroLamports *int32  `json:"priorityMicroLamports,omitempty"`
+	Mint                  string         `json:"mint"`
+	Owner                 string         `json:"owner"`
+	Price                 money.Lamports `json:"price"`
+	Blockhash             string         `json:"blockhash"`
+	MakerBroker           *string        `json:"makerBroker,omitempty"`
+	ExpireIn              *int32         `json:"expireIn,omitempty"`
+	FeePayer              *string        `json:"feePayer,omitempty"`
+	Compute               *int32         `json:"compute,omitempty"`
+	PriorityMicroLamports *int32         `json:"priorityMicroLamports,omitempty"`
 }
 
 // EditListingResponse represents the response from the edit listing API
@@ -28,15 +29,15 @@ type EditListingResponse struct {
 
 // EditBidRequest represents the request parameters for editing a bid
 type EditBidRequest struct {
-	BidStateAddress       string   `json:"bidStateAddress"`
-	Blockhash             string   `json:"blockhash"`
-	Price                 *float64 `json:"price,omitempty"`
-	Quantity              *int32   `json:"quantity,omitempty"`
-	ExpireIn              *int32   `json:"expireIn,omitempty"`
-	PrivateTaker          *string  `json:"privateTaker,omitempty"`
-	UseSharedEscrow       *bool    `json:"useSharedEscrow,omitempty"`
-	Compute               *int32   `json:"compute,omitempty"`
-	PriorityMicroLamports *int32   `json:"priorityMicroLamports,omitempty"`
+	BidStateAddress       string          `json:"bidStateAddress"`
+	Blockhash             string          `json:"blockhash"`
+	Price                 *money.Lamports `json:"price,omitempty"`
+	Quantity              *int32          `json:"quantity,omitempty"`
+	ExpireIn              *int32          `json:"expireIn,omitempty"`
+	PrivateTaker          *string         `json:"privateTaker,omitempty"`
+	UseSharedEscrow       *bool           `json:"useSharedEscrow,omitempty"`
+	Compute               *int32          `json:"compute,omitempty"`
+	PriorityMicroLamports *int32          `json:"priorityMicroLamports,omitempty"`
 }
 
 // EditBidResponse represents the response from the edit bid API
@@ -61,16 +62,16 @@ type CancelBidResponse struct {
 
 // PlaceNFTBidRequest represents the request parameters for placing a bid on a single NFT
 type PlaceNFTBidRequest struct {
-	Owner                 string  `json:"owner"`
-	Price                 float64 `json:"price"`
-	Mint                  string  `json:"mint"`
-	Blockhash             string  `json:"blockhash"`
-	MakerBroker           *string `json:"makerBroker,omitempty"`
-	UseSharedEscrow       *bool   `json:"useSharedEscrow,omitempty"`
-	RentPayer             *string `json:"rentPayer,omitempty"`
-	ExpireIn              *int32  `json:"expireIn,omitempty"`
-	Compute               *int32  `json:"compute,omitempty"`
-	PriorityMicroLamports *int32  `json:"priorityMicroLamports,omitempty"`
+	Owner                 string         `json:"owner"`
+	Price                 money.Lamports `json:"price"`
+	Mint                  string         `json:"mint"`
+	Blockhash             string         `json:"blockhash"`
+	MakerBroker           *string        `json:"makerBroker,omitempty"`
+	UseSharedEscrow       *bool          `json:"useSharedEscrow,omitempty"`
+	RentPayer             *string        `json:"rentPayer,omitempty"`
+	ExpireIn              *int32         `json:"expireIn,omitempty"`
+	Compute               *int32         `json:"compute,omitempty"`
+	PriorityMicroLamports *int32         `json:"priorityMicroLamports,omitempty"`
 }
 
 // PlaceNFTBidResponse represents the response from the place NFT bid API
@@ -80,18 +81,18 @@ type PlaceNFTBidResponse struct {
 
 // PlaceTraitBidRequest represents the request parameters for placing a trait bid on a collection
 type PlaceTraitBidRequest struct {
-	Owner                 string   `json:"owner"`
-	Price                 float64  `json:"price"`
-	Quantity              int32    `json:"quantity"`
-	CollId                string   `json:"collId"`
-	Blockhash             string   `json:"blockhash"`
-	MakerBroker           *string  `json:"makerBroker,omitempty"`
-	Traits                []string `json:"traits,omitempty"`
-	UseSharedEscrow       *bool    `json:"useSharedEscrow,omitempty"`
-	RentPayer             *string  `json:"rentPayer,omitempty"`
-	ExpireIn              *int32   `json:"expireIn,omitempty"`
-	Compute               *int32   `json:"compute,omitempty"`
-	PriorityMicroLamports *int32   `json:"priorityMicroLamports,omitempty"`
+	Owner                 string         `json:"owner"`
+	Price                 money.Lamports `json:"price"`
+	Quantity              int32          `json:"quantity"`
+	CollId                string         `json:"collId"`
+	Blockhash             string         `json:"blockhash"`
+	MakerBroker           *string        `json:"makerBroker,omitempty"`
+	Traits                []string       `json:"traits,omitempty"`
+	UseSharedEscrow       *bool          `json:"useSharedEscrow,omitempty"`
+	RentPayer             *string        `json:"rentPayer,omitempty"`
+	ExpireIn              *int32         `json:"expireIn,omitempty"`
+	Compute               *int32         `json:"compute,omitempty"`
+	PriorityMicroLamports *int32         `json:"priorityMicroLamports,omitempty"`
 }
 
 // PlaceTraitBidResponse represents the response from the place trait bid API
@@ -101,18 +102,18 @@ type PlaceTraitBidResponse struct {
 
 // PlaceCollectionBidRequest represents the request parameters for placing a collection wide bid
 type PlaceCollectionBidRequest struct {
-	Owner                 string   `json:"owner"`
-	Price                 float64  `json:"price"`
-	Quantity              int32    `json:"quantity"`
-	CollId                string   `json:"collId"`
-	Blockhash             string   `json:"blockhash"`
-	MakerBroker           *string  `json:"makerBroker,omitempty"`
-	UseSharedEscrow       *bool    `json:"useSharedEscrow,omitempty"`
-	RentPayer             *string  `json:"rentPayer,omitempty"`
-	ExpireIn              *int32   `json:"expireIn,omitempty"`
-	TopUp                 *float64 `json:"topUp,omitempty"`
-	Compute               *int32   `json:"compute,omitempty"`
-	PriorityMicroLamports *int32   `json:"priorityMicroLamports,omitempty"`
+	Owner                 string          `json:"owner"`
+	Price                 money.Lamports  `json:"price"`
+	Quantity              int32           `json:"quantity"`
+	CollId                string          `json:"collId"`
+	Blockhash             string          `json:"blockhash"`
+	MakerBroker           *string         `json:"makerBroker,omitempty"`
+	UseSharedEscrow       *bool           `json:"useSharedEscrow,omitempty"`
+	RentPayer             *string         `json:"rentPayer,omitempty"`
+	ExpireIn              *int32          `json:"expireIn,omitempty"`
+	TopUp                 *money.Lamports `json:"topUp,omitempty"`
+	Compute               *int32          `json:"compute,omitempty"`
+	PriorityMicroLamports *int32          `json:"priorityMicroLamports,omitempty"`
 }
 
 // PlaceCollectionBidResponse represents the response from the place collection bid API
@@ -122,19 +123,19 @@ type PlaceCollectionBidResponse struct {
 
 // BuyNFTRequest represents the request parameters for buying an NFT
 type BuyNFTRequest struct {
-	Buyer                 string  `json:"buyer"`
-	Mint                  string  `json:"mint"`
-	Owner                 string  `json:"owner"`
-	MaxPrice              float64 `json:"maxPrice"`
-	Blockhash             string  `json:"blockhash"`
-	IncludeTotalCost      *bool   `json:"includeTotalCost,omitempty"`
-	Payer                 *string `json:"payer,omitempty"`
-	FeePayer              *string `json:"feePayer,omitempty"`
-	OptionalRoyaltyPct    *int32  `json:"optionalRoyaltyPct,omitempty"`
-	Currency              *string `json:"currency,omitempty"`
-	TakerBroker           *string `json:"takerBroker,omitempty"`
-	Compute               *int32  `json:"compute,omitempty"`
-	PriorityMicroLamports *int32  `json:"priorityMicroLamports,omitempty"`
+	Buyer                 string         `json:"buyer"`
+	Mint                  string         `json:"mint"`
+	Owner                 string         `json:"owner"`
+	MaxPrice              money.Lamports `json:"maxPrice"`
+	Blockhash             string         `json:"blockhash"`
+	IncludeTotalCost      *bool          `json:"includeTotalCost,omitempty"`
+	Payer                 *string        `json:"payer,omitempty"`
+	FeePayer              *string        `json:"feePayer,omitempty"`
+	OptionalRoyaltyPct    *int32         `json:"optionalRoyaltyPct,omitempty"`
+	Currency              *string        `json:"currency,omitempty"`
+	TakerBroker           *string        `json:"takerBroker,omitempty"`
+	Compute               *int32         `json:"compute,omitempty"`
+	PriorityMicroLamports *int32         `json:"priorityMicroLamports,omitempty"`
 }
 
 // BuyNFTResponse represents the response from the buy NFT API
@@ -144,19 +145,19 @@ type BuyNFTResponse struct {
 
 // SellNFTRequest represents the request parameters for selling an NFT (accepting a bid)
 type SellNFTRequest struct {
-	Seller                string  `json:"seller"`
-	Mint                  string  `json:"mint"`
-	BidAddress            string  `json:"bidAddress"`
-	MinPrice              float64 `json:"minPrice"`
-	Blockhash             string  `json:"blockhash"`
-	TakerBroker           *string `json:"takerBroker,omitempty"`
-	FeePayer              *string `json:"feePayer,omitempty"`
-	OptionalRoyaltyPct    *int32  `json:"optionalRoyaltyPct,omitempty"`
-	Currency              *string `json:"currency,omitempty"`
-	DelegateSigner        *bool   `json:"delegateSigner,omitempty"`
-	IncludeProof          *bool   `json:"includeProof,omitempty"`
-	Compute               *int32  `json:"compute,omitempty"`
-	PriorityMicroLamports *int32  `json:"priorityMicroLamports,omitempty"`
+	Seller                string         `json:"seller"`
+	Mint                  string         `json:"mint"`
+	BidAddress            string         `json:"bidAddress"`
+	MinPrice              money.Lamports `json:"minPrice"`
+	Blockhash             string         `json:"blockhash"`
+	TakerBroker           *string        `json:"takerBroker,omitempty"`
+	FeePayer              *string        `json:"feePayer,omitempty"`
+	OptionalRoyaltyPct    *int32         `json:"optionalRoyaltyPct,omitempty"`
+	Currency              *string        `json:"currency,omitempty"`
+	DelegateSigner        *bool          `json:"delegateSigner,omitempty"`
+	IncludeProof          *bool          `json:"includeProof,omitempty"`
+	Compute               *int32         `json:"compute,omitempty"`
+	PriorityMicroLamports *int32         `json:"priorityMicroLamports,omitempty"`
 }
 
 // SellNFTResponse represents the response from the sell NFT API
@@ -166,20 +167,20 @@ type SellNFTResponse struct {
 
 // ListNFTRequest represents the request parameters for listing an NFT
 type ListNFTRequest struct {
-	Mint                  string  `json:"mint"`
-	Owner                 string  `json:"owner"`
-	Price                 float64 `json:"price"`
-	Blockhash             string  `json:"blockhash"`
-	MakerBroker           *string `json:"makerBroker,omitempty"`
-	Payer                 *string `json:"payer,omitempty"`
-	FeePayer              *string `json:"feePayer,omitempty"`
-	RentPayer             *string `json:"rentPayer,omitempty"`
-	Currency              *string `json:"currency,omitempty"`
-	ExpireIn              *int32  `json:"expireIn,omitempty"`
-	PrivateTaker          *string `json:"privateTaker,omitempty"`
-	DelegateSigner        *bool   `json:"delegateSigner,omitempty"`
-	Compute               *int32  `json:"compute,omitempty"`
-	PriorityMicroLamports *int32  `json:"priorityMicroLamports,omitempty"`
+	Mint                  string         `json:"mint"`
+	Owner                 string         `json:"owner"`
+	Price                 money.Lamports `json:"price"`
+	Blockhash             string         `json:"blockhash"`
+	MakerBroker           *string        `json:"makerBroker,omitempty"`
+	Payer                 *string        `json:"payer,omitempty"`
+	FeePayer              *string        `json:"feePayer,omitempty"`
+	RentPayer             *string        `json:"rentPayer,omitempty"`
+	Currency              *string        `json:"currency,omitempty"`
+	ExpireIn              *int32         `json:"expireIn,omitempty"`
+	PrivateTaker          *string        `json:"privateTaker,omitempty"`
+	DelegateSigner        *bool          `json:"delegateSigner,omitempty"`
+	Compute               *int32         `json:"compute,omitempty"`
+	PriorityMicroLamports *int32         `json:"priorityMicroLamports,omitempty"`
 }
 
 // ListNFTResponse represents the response from the list NFT API
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/srpvpn/tensor-go-sdk/money"
 )
 
 func TestBuyNFTRequest_Validate(t *testing.T) {
@@ -19,7 +21,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:     "11111111111111111111111111111112",
 				Mint:      "11111111111111111111111111111113",
 				Owner:     "11111111111111111111111111111114",
-				MaxPrice:  1.5,
+				MaxPrice:  1500000000,
 				Blockhash: "11111111111111111111111111111115",
 			},
 			wantErr: false,
@@ -30,7 +32,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:     "",
 				Mint:      "11111111111111111111111111111113",
 				Owner:     "11111111111111111111111111111114",
-				MaxPrice:  1.5,
+				MaxPrice:  1500000000,
 				Blockhash: "11111111111111111111111111111115",
 			},
 			wantErr: true,
@@ -42,7 +44,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:     "invalid",
 				Mint:      "11111111111111111111111111111113",
 				Owner:     "11111111111111111111111111111114",
-				MaxPrice:  1.5,
+				MaxPrice:  1500000000,
 				Blockhash: "11111111111111111111111111111115",
 			},
 			wantErr: true,
@@ -54,7 +56,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:     "11111111111111111111111111111112",
 				Mint:      "",
 				Owner:     "11111111111111111111111111111114",
-				MaxPrice:  1.5,
+				MaxPrice:  1500000000,
 				Blockhash: "11111111111111111111111111111115",
 			},
 			wantErr: true,
@@ -66,7 +68,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:     "11111111111111111111111111111112",
 				Mint:      "11111111111111111111111111111113",
 				Owner:     "",
-				MaxPrice:  1.5,
+				MaxPrice:  1500000000,
 				Blockhash: "11111111111111111111111111111115",
 			},
 			wantErr: true,
@@ -78,7 +80,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:     "11111111111111111111111111111112",
 				Mint:      "11111111111111111111111111111113",
 				Owner:     "11111111111111111111111111111114",
-				MaxPrice:  -1.0,
+				MaxPrice:  -1000000000,
 				Blockhash: "11111111111111111111111111111115",
 			},
 			wantErr: true,
@@ -90,7 +92,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:     "11111111111111111111111111111112",
 				Mint:      "11111111111111111111111111111113",
 				Owner:     "11111111111111111111111111111114",
-				MaxPrice:  1.5,
+				MaxPrice:  1500000000,
 				Blockhash: "",
 			},
 			wantErr: true,
@@ -102,7 +104,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:              "11111111111111111111111111111112",
 				Mint:               "11111111111111111111111111111113",
 				Owner:              "11111111111111111111111111111114",
-				MaxPrice:           1.5,
+				MaxPrice:           1500000000,
 				Blockhash:          "11111111111111111111111111111115",
 				OptionalRoyaltyPct: int32Ptr(101),
 			},
@@ -115,7 +117,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:              "11111111111111111111111111111112",
 				Mint:               "11111111111111111111111111111113",
 				Owner:              "11111111111111111111111111111114",
-				MaxPrice:           1.5,
+				MaxPrice:           1500000000,
 				Blockhash:          "11111111111111111111111111111115",
 				OptionalRoyaltyPct: int32Ptr(-1),
 			},
@@ -128,7 +130,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:     "11111111111111111111111111111112",
 				Mint:      "11111111111111111111111111111113",
 				Owner:     "11111111111111111111111111111114",
-				MaxPrice:  1.5,
+				MaxPrice:  1500000000,
 				Blockhash: "11111111111111111111111111111115",
 				Compute:   int32Ptr(-1),
 			},
@@ -141,7 +143,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:                 "11111111111111111111111111111112",
 				Mint:                  "11111111111111111111111111111113",
 				Owner:                 "11111111111111111111111111111114",
-				MaxPrice:              1.5,
+				MaxPrice:              1500000000,
 				Blockhash:             "11111111111111111111111111111115",
 				PriorityMicroLamports: int32Ptr(-1),
 			},
@@ -154,7 +156,7 @@ func TestBuyNFTRequest_Validate(t *testing.T) {
 				Buyer:                 "11111111111111111111111111111112",
 				Mint:                  "11111111111111111111111111111113",
 				Owner:                 "11111111111111111111111111111114",
-				MaxPrice:              1.5,
+				MaxPrice:              1500000000,
 				Blockhash:             "11111111111111111111111111111115",
 				IncludeTotalCost:      boolPtr(true),
 				Payer:                 stringPtr("11111111111111111111111111111116"),
@@ -201,10 +203,10 @@ func TestBuyNFTRequest_JSON(t *testing.T) {
 				Buyer:     "11111111111111111111111111111112",
 				Mint:      "11111111111111111111111111111113",
 				Owner:     "11111111111111111111111111111114",
-				MaxPrice:  1.5,
+				MaxPrice:  1500000000,
 				Blockhash: "11111111111111111111111111111115",
 			},
-			expected: `{"buyer":"11111111111111111111111111111112","mint":"11111111111111111111111111111113","owner":"11111111111111111111111111111114","maxPrice":1.5,"blockhash":"11111111111111111111111111111115"}`,
+			expected: `{"buyer":"11111111111111111111111111111112","mint":"11111111111111111111111111111113","owner":"11111111111111111111111111111114","maxPrice":1500000000,"blockhash":"11111111111111111111111111111115"}`,
 		},
 		{
 			name: "full request",
@@ -212,7 +214,7 @@ func TestBuyNFTRequest_JSON(t *testing.T) {
 				Buyer:                 "11111111111111111111111111111112",
 				Mint:                  "11111111111111111111111111111113",
 				Owner:                 "11111111111111111111111111111114",
-				MaxPrice:              1.5,
+				MaxPrice:              1500000000,
 				Blockhash:             "11111111111111111111111111111115",
 				IncludeTotalCost:      boolPtr(true),
 				Payer:                 stringPtr("11111111111111111111111111111116"),
@@ -223,7 +225,7 @@ func TestBuyNFTRequest_JSON(t *testing.T) {
 				Compute:               int32Ptr(200000),
 				PriorityMicroLamports: int32Ptr(1000),
 			},
-			expected: `{"buyer":"11111111111111111111111111111112","mint":"11111111111111111111111111111113","owner":"11111111111111111111111111111114","maxPrice":1.5,"blockhash":"11111111111111111111111111111115","includeTotalCost":true,"payer":"11111111111111111111111111111116","feePayer":"11111111111111111111111111111117","optionalRoyaltyPct":5,"currency":"11111111111111111111111111111118","takerBroker":"11111111111111111111111111111119","compute":200000,"priorityMicroLamports":1000}`,
+			expected: `{"buyer":"11111111111111111111111111111112","mint":"11111111111111111111111111111113","owner":"11111111111111111111111111111114","maxPrice":1500000000,"blockhash":"11111111111111111111111111111115","includeTotalCost":true,"payer":"11111111111111111111111111111116","feePayer":"11111111111111111111111111111117","optionalRoyaltyPct":5,"currency":"11111111111111111111111111111118","takerBroker":"11111111111111111111111111111119","compute":200000,"priorityMicroLamports":1000}`,
 		},
 	}
 
@@ -337,7 +339,7 @@ func TestSellNFTRequest_Validate(t *testing.T) {
 				Seller:     "11111111111111111111111111111112",
 				Mint:       "11111111111111111111111111111113",
 				BidAddress: "11111111111111111111111111111114",
-				MinPrice:   1.5,
+				MinPrice:   1500000000,
 				Blockhash:  "11111111111111111111111111111115",
 			},
 			wantErr: false,
@@ -348,7 +350,7 @@ func TestSellNFTRequest_Validate(t *testing.T) {
 				Seller:     "",
 				Mint:       "11111111111111111111111111111113",
 				BidAddress: "11111111111111111111111111111114",
-				MinPrice:   1.5,
+				MinPrice:   1500000000,
 				Blockhash:  "11111111111111111111111111111115",
 			},
 			wantErr: true,
@@ -360,7 +362,7 @@ func TestSellNFTRequest_Validate(t *testing.T) {
 				Seller:     "11111111111111111111111111111112",
 				Mint:       "11111111111111111111111111111113",
 				BidAddress: "",
-				MinPrice:   1.5,
+				MinPrice:   1500000000,
 				Blockhash:  "11111111111111111111111111111115",
 			},
 			wantErr: true,
@@ -372,7 +374,7 @@ func TestSellNFTRequest_Validate(t *testing.T) {
 				Seller:     "11111111111111111111111111111112",
 				Mint:       "11111111111111111111111111111113",
 				BidAddress: "11111111111111111111111111111114",
-				MinPrice:   -1.0,
+				MinPrice:   -1000000000,
 				Blockhash:  "11111111111111111111111111111115",
 			},
 			wantErr: true,
@@ -412,7 +414,7 @@ func TestListNFTRequest_Validate(t *testing.T) {
 			request: &ListNFTRequest{
 				Mint:      "11111111111111111111111111111112",
 				Owner:     "11111111111111111111111111111113",
-				Price:     2.5,
+				Price:     2500000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: false,
@@ -422,7 +424,7 @@ func TestListNFTRequest_Validate(t *testing.T) {
 			request: &ListNFTRequest{
 				Mint:      "",
 				Owner:     "11111111111111111111111111111113",
-				Price:     2.5,
+				Price:     2500000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -433,7 +435,7 @@ func TestListNFTRequest_Validate(t *testing.T) {
 			request: &ListNFTRequest{
 				Mint:      "11111111111111111111111111111112",
 				Owner:     "",
-				Price:     2.5,
+				Price:     2500000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -444,7 +446,7 @@ func TestListNFTRequest_Validate(t *testing.T) {
 			request: &ListNFTRequest{
 				Mint:      "11111111111111111111111111111112",
 				Owner:     "11111111111111111111111111111113",
-				Price:     -1.0,
+				Price:     -1000000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -455,7 +457,7 @@ func TestListNFTRequest_Validate(t *testing.T) {
 			request: &ListNFTRequest{
 				Mint:      "11111111111111111111111111111112",
 				Owner:     "11111111111111111111111111111113",
-				Price:     2.5,
+				Price:     2500000000,
 				Blockhash: "11111111111111111111111111111114",
 				ExpireIn:  int32Ptr(-1),
 			},
@@ -574,7 +576,7 @@ func TestEditListingRequest_Validate(t *testing.T) {
 			request: &EditListingRequest{
 				Mint:      "11111111111111111111111111111112",
 				Owner:     "11111111111111111111111111111113",
-				Price:     2.5,
+				Price:     2500000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: false,
@@ -584,7 +586,7 @@ func TestEditListingRequest_Validate(t *testing.T) {
 			request: &EditListingRequest{
 				Mint:      "",
 				Owner:     "11111111111111111111111111111113",
-				Price:     2.5,
+				Price:     2500000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -595,7 +597,7 @@ func TestEditListingRequest_Validate(t *testing.T) {
 			request: &EditListingRequest{
 				Mint:      "11111111111111111111111111111112",
 				Owner:     "11111111111111111111111111111113",
-				Price:     -1.0,
+				Price:     -1000000000,
 				Blockhash: "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -634,7 +636,7 @@ func TestPlaceNFTBidRequest_Validate(t *testing.T) {
 			name: "valid request",
 			request: &PlaceNFTBidRequest{
 				Owner:     "11111111111111111111111111111112",
-				Price:     1.5,
+				Price:     1500000000,
 				Mint:      "11111111111111111111111111111113",
 				Blockhash: "11111111111111111111111111111114",
 			},
@@ -644,7 +646,7 @@ func TestPlaceNFTBidRequest_Validate(t *testing.T) {
 			name: "empty owner",
 			request: &PlaceNFTBidRequest{
 				Owner:     "",
-				Price:     1.5,
+				Price:     1500000000,
 				Mint:      "11111111111111111111111111111113",
 				Blockhash: "11111111111111111111111111111114",
 			},
@@ -655,7 +657,7 @@ func TestPlaceNFTBidRequest_Validate(t *testing.T) {
 			name: "negative price",
 			request: &PlaceNFTBidRequest{
 				Owner:     "11111111111111111111111111111112",
-				Price:     -1.0,
+				Price:     -1000000000,
 				Mint:      "11111111111111111111111111111113",
 				Blockhash: "11111111111111111111111111111114",
 			},
@@ -695,7 +697,7 @@ func TestPlaceTraitBidRequest_Validate(t *testing.T) {
 			name: "valid request",
 			request: &PlaceTraitBidRequest{
 				Owner:     "11111111111111111111111111111112",
-				Price:     1.5,
+				Price:     1500000000,
 				Quantity:  2,
 				CollId:    "collection123",
 				Blockhash: "11111111111111111111111111111114",
@@ -706,7 +708,7 @@ func TestPlaceTraitBidRequest_Validate(t *testing.T) {
 			name: "zero quantity",
 			request: &PlaceTraitBidRequest{
 				Owner:     "11111111111111111111111111111112",
-				Price:     1.5,
+				Price:     1500000000,
 				Quantity:  0,
 				CollId:    "collection123",
 				Blockhash: "11111111111111111111111111111114",
@@ -718,7 +720,7 @@ func TestPlaceTraitBidRequest_Validate(t *testing.T) {
 			name: "empty collId",
 			request: &PlaceTraitBidRequest{
 				Owner:     "11111111111111111111111111111112",
-				Price:     1.5,
+				Price:     1500000000,
 				Quantity:  2,
 				CollId:    "",
 				Blockhash: "11111111111111111111111111111114",
@@ -759,7 +761,7 @@ func TestPlaceCollectionBidRequest_Validate(t *testing.T) {
 			name: "valid request",
 			request: &PlaceCollectionBidRequest{
 				Owner:     "11111111111111111111111111111112",
-				Price:     1.5,
+				Price:     1500000000,
 				Quantity:  3,
 				CollId:    "collection123",
 				Blockhash: "11111111111111111111111111111114",
@@ -770,7 +772,7 @@ func TestPlaceCollectionBidRequest_Validate(t *testing.T) {
 			name: "zero quantity",
 			request: &PlaceCollectionBidRequest{
 				Owner:     "11111111111111111111111111111112",
-				Price:     1.5,
+				Price:     1500000000,
 				Quantity:  0,
 				CollId:    "collection123",
 				Blockhash: "11111111111111111111111111111114",
@@ -782,11 +784,11 @@ func TestPlaceCollectionBidRequest_Validate(t *testing.T) {
 			name: "negative topUp",
 			request: &PlaceCollectionBidRequest{
 				Owner:     "11111111111111111111111111111112",
-				Price:     1.5,
+				Price:     1500000000,
 				Quantity:  3,
 				CollId:    "collection123",
 				Blockhash: "11111111111111111111111111111114",
-				TopUp:     float64Ptr(-1.0),
+				TopUp:     lamportsPtr(-1000000000),
 			},
 			wantErr: true,
 			errMsg:  "topUp must be >= 0",
@@ -813,9 +815,9 @@ func TestPlaceCollectionBidRequest_Validate(t *testing.T) {
 	}
 }
 
-// Helper function for float64 pointer
-func float64Ptr(f float64) *float64 {
-	return &f
+// Helper function for lamports pointer
+func lamportsPtr(l money.Lamports) *money.Lamports {
+	return &l
 }
 func TestEditBidRequest_Validate(t *testing.T) {
 	tests := []struct {
@@ -864,7 +866,7 @@ func TestEditBidRequest_Validate(t *testing.T) {
 			request: &EditBidRequest{
 				BidStateAddress: "11111111111111111111111111111112",
 				Blockhash:       "11111111111111111111111111111113",
-				Price:           float64Ptr(-1.0),
+				Price:           lamportsPtr(-1000000000),
 			},
 			wantErr: true,
 			errMsg:  "price must be >= 0",
@@ -894,7 +896,7 @@ func TestEditBidRequest_Validate(t *testing.T) {
 			request: &EditBidRequest{
 				BidStateAddress:       "11111111111111111111111111111112",
 				Blockhash:             "11111111111111111111111111111113",
-				Price:                 float64Ptr(2.5),
+				Price:                 lamportsPtr(2500000000),
 				Quantity:              int32Ptr(3),
 				ExpireIn:              int32Ptr(3600),
 				PrivateTaker:          stringPtr("11111111111111111111111111111114"),
@@ -0,0 +1,175 @@
+package nfts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/request"
+)
+
+// BatchError reports the mints that could not be fetched when a chunked
+// GetNFTsInfo call partially fails. The mints that succeeded are still returned.
+type BatchError struct {
+	Failures map[string]error // Failed mint address -> error of the chunk it belonged to
+	Total    int              // Number of mints requested
+}
+
+// Error implements the error interface
+func (e *BatchError) Error() string {
+	// Report a stable first error so messages are reproducible
+	mints := make([]string, 0, len(e.Failures))
+	for mint := range e.Failures {
+		mints = append(mints, mint)
+	}
+	sort.Strings(mints)
+
+	if len(mints) == 0 {
+		return fmt.Sprintf("failed to fetch 0 of %d mints", e.Total)
+	}
+	return fmt.Sprintf("failed to fetch %d of %d mints: %v", len(mints), e.Total, e.Failures[mints[0]])
+}
+
+// Unwrap returns the distinct underlying chunk errors
+func (e *BatchError) Unwrap() []error {
+	seen := make(map[error]bool)
+	var errs []error
+	for _, err := range e.Failures {
+		if !seen[err] {
+			seen[err] = true
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// chunkResult is the outcome of fetching one chunk of mints
+type chunkResult struct {
+	body       []byte
+	statusCode int
+	err        error
+}
+
+// getNFTsInfoChunked splits the mints into chunks, fetches them with bounded
+// concurrency and merges the returned NFTs into a single JSON array in input order.
+// If some chunks fail, the merged successes are returned together with a *BatchError.
+func (s *nftsAPI) getNFTsInfoChunked(ctx context.Context, req *NFTsInfoRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("request validation failed: %w", err)
+	}
+
+	var chunks [][]string
+	for start := 0; start < len(req.Mints); start += s.mintsChunkSize {
+		end := start + s.mintsChunkSize
+		if end > len(req.Mints) {
+			end = len(req.Mints)
+		}
+		chunks = append(chunks, req.Mints[start:end])
+	}
+
+	results := make([]chunkResult, len(chunks))
+	sem := make(chan struct{}, s.mintsConcurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i].err = ctx.Err()
+				return
+			}
+			body, status, err := request.Raw(ctx, s.transport, "/api/v1/mint", &NFTsInfoRequest{Mints: chunk})
+			results[i] = chunkResult{body: body, statusCode: status, err: err}
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	position := make(map[string]int, len(req.Mints))
+	for i, mint := range req.Mints {
+		if _, ok := position[mint]; !ok {
+			position[mint] = i
+		}
+	}
+
+	merged := []json.RawMessage{}
+	failures := make(map[string]error)
+	statusCode := 0
+	for i, result := range results {
+		if result.err != nil {
+			for _, mint := range chunks[i] {
+				failures[mint] = result.err
+			}
+			if statusCode == 0 {
+				statusCode = result.statusCode
+			}
+			continue
+		}
+
+		var items []json.RawMessage
+		if err := json.Unmarshal(result.body, &items); err != nil {
+			err = fmt.Errorf("failed to decode response: %w", err)
+			for _, mint := range chunks[i] {
+				failures[mint] = err
+			}
+			continue
+		}
+		sortByMintPosition(items, position)
+		merged = append(merged, items...)
+		statusCode = result.statusCode
+	}
+
+	if len(failures) == len(req.Mints) {
+		// Nothing succeeded, surface the first chunk error directly
+		return nil, statusCode, failures[req.Mints[0]]
+	}
+
+	body, err := json.Marshal(merged)
+	if err != nil {
+		return nil, statusCode, fmt.Errorf("failed to encode merged response: %w", err)
+	}
+
+	if len(failures) > 0 {
+		return body, statusCode, &BatchError{Failures: failures, Total: len(req.Mints)}
+	}
+	return body, statusCode, nil
+}
+
+// sortByMintPosition orders NFTs by the position of their mint in the request.
+// Items without a recognizable mint keep their relative order at the end.
+func sortByMintPosition(items []json.RawMessage, position map[string]int) {
+	keys := make([]int, len(items))
+	for i, item := range items {
+		keys[i] = len(position) + i
+		var ident struct {
+			Mint      string `json:"mint"`
+			OnchainId string `json:"onchainId"`
+		}
+		if err := json.Unmarshal(item, &ident); err != nil {
+			continue
+		}
+		for _, mint := range []string{ident.Mint, ident.OnchainId} {
+			if pos, ok := position[strings.TrimSpace(mint)]; ok {
+				keys[i] = pos
+				break
+			}
+		}
+	}
+
+	idx := make([]int, len(items))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return keys[idx[a]] < keys[idx[b]] })
+
+	sorted := make([]json.RawMessage, len(items))
+	for i, j := range idx {
+		sorted[i] = items[j]
+	}
+	copy(items, sorted)
+}
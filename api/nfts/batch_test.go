@@ -0,0 +1,200 @@
+package nfts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apierrors "github.com/srpvpn/tensor-go-sdk/internal/errors"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// testMint returns a distinct, valid looking mint address for i
+func testMint(i int) string {
+	suffix := make([]byte, 4)
+	for j := 3; j >= 0; j-- {
+		suffix[j] = base58Alphabet[i%len(base58Alphabet)]
+		i /= len(base58Alphabet)
+	}
+	return strings.Repeat("1", 28) + string(suffix)
+}
+
+// chunkTransport answers /api/v1/mint with one object per requested mint, in reverse order
+type chunkTransport struct {
+	mu       sync.Mutex
+	calls    int
+	inFlight int32
+	maxSeen  int32
+	failMint string
+}
+
+func (m *chunkTransport) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+
+	n := atomic.AddInt32(&m.inFlight, 1)
+	defer atomic.AddInt32(&m.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&m.maxSeen)
+		if n <= max || atomic.CompareAndSwapInt32(&m.maxSeen, max, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	mints := strings.Split(params.Get("mints"), ",")
+	items := make([]map[string]string, 0, len(mints))
+	for i := len(mints) - 1; i >= 0; i-- {
+		if mints[i] == m.failMint {
+			return nil, &apierrors.APIError{Code: 500, Message: "internal server error"}
+		}
+		items = append(items, map[string]string{"mint": mints[i]})
+	}
+	body, _ := json.Marshal(items)
+
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func decodeMints(t *testing.T, body []byte) []string {
+	t.Helper()
+	var items []struct {
+		Mint string `json:"mint"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		t.Fatalf("failed to decode merged body: %v", err)
+	}
+	mints := make([]string, len(items))
+	for i, item := range items {
+		mints[i] = item.Mint
+	}
+	return mints
+}
+
+func TestGetNFTsInfo_ChunksAndMergesInOrder(t *testing.T) {
+	transport := &chunkTransport{}
+	api := New(transport, WithMintsChunkSize(3), WithMintsConcurrency(2))
+
+	var mints []string
+	for i := 0; i < 10; i++ {
+		mints = append(mints, testMint(i))
+	}
+
+	body, status, err := api.GetNFTsInfo(context.Background(), &NFTsInfoRequest{Mints: mints})
+	if err != nil {
+		t.Fatalf("GetNFTsInfo() error = %v", err)
+	}
+	if status != 200 {
+		t.Errorf("GetNFTsInfo() status = %d, want 200", status)
+	}
+	if transport.calls != 4 {
+		t.Errorf("expected 4 chunk requests, got %d", transport.calls)
+	}
+	if max := atomic.LoadInt32(&transport.maxSeen); max > 2 {
+		t.Errorf("expected at most 2 concurrent requests, saw %d", max)
+	}
+
+	got := decodeMints(t, body)
+	if strings.Join(got, ",") != strings.Join(mints, ",") {
+		t.Errorf("merged order = %v, want %v", got, mints)
+	}
+}
+
+func TestGetNFTsInfo_PartialFailure(t *testing.T) {
+	var mints []string
+	for i := 0; i < 6; i++ {
+		mints = append(mints, testMint(i))
+	}
+	transport := &chunkTransport{failMint: mints[4]}
+	api := New(transport, WithMintsChunkSize(3))
+
+	body, _, err := api.GetNFTsInfo(context.Background(), &NFTsInfoRequest{Mints: mints})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected *BatchError, got %v", err)
+	}
+	if len(batchErr.Failures) != 3 || batchErr.Total != 6 {
+		t.Errorf("BatchError = %d failures of %d, want 3 of 6", len(batchErr.Failures), batchErr.Total)
+	}
+	for _, mint := range mints[3:] {
+		if batchErr.Failures[mint] == nil {
+			t.Errorf("expected failure for mint %s", mint)
+		}
+	}
+
+	var apiErr *apierrors.APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected BatchError to unwrap to *errors.APIError")
+	}
+
+	got := decodeMints(t, body)
+	if strings.Join(got, ",") != strings.Join(mints[:3], ",") {
+		t.Errorf("merged successes = %v, want %v", got, mints[:3])
+	}
+}
+
+func TestGetNFTsInfo_AllChunksFail(t *testing.T) {
+	mints := []string{testMint(1), testMint(2)}
+	api := New(failingTransport{}, WithMintsChunkSize(1))
+
+	body, _, err := api.GetNFTsInfo(context.Background(), &NFTsInfoRequest{Mints: mints})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		t.Errorf("expected the chunk error, not a BatchError")
+	}
+	if body != nil {
+		t.Errorf("expected nil body, got %s", body)
+	}
+}
+
+func TestGetNFTsInfo_SmallListUsesSingleRequest(t *testing.T) {
+	transport := &chunkTransport{}
+	api := New(transport)
+
+	_, _, err := api.GetNFTsInfo(context.Background(), &NFTsInfoRequest{Mints: []string{testMint(1), testMint(2)}})
+	if err != nil {
+		t.Fatalf("GetNFTsInfo() error = %v", err)
+	}
+	if transport.calls != 1 {
+		t.Errorf("expected 1 request, got %d", transport.calls)
+	}
+}
+
+func TestGetNFTsInfo_ChunkedValidation(t *testing.T) {
+	transport := &chunkTransport{}
+	api := New(transport, WithMintsChunkSize(1))
+
+	_, _, err := api.GetNFTsInfo(context.Background(), &NFTsInfoRequest{Mints: []string{testMint(1), "bad"}})
+	if err == nil || !strings.Contains(err.Error(), "request validation failed") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if transport.calls != 0 {
+		t.Errorf("expected no requests, got %d", transport.calls)
+	}
+}
+
+// failingTransport fails every request
+type failingTransport struct{}
+
+func (failingTransport) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	return nil, &apierrors.NetworkError{Op: "http_request", Err: fmt.Errorf("connection refused")}
+}
@@ -2,71 +2,72 @@ package nfts
 
 import (
 	"context"
-	"fmt"
-	"io"
 
+	"github.com/srpvpn/tensor-go-sdk/internal/request"
 	"github.com/srpvpn/tensor-go-sdk/internal/transport"
-	"github.com/srpvpn/tensor-go-sdk/internal/utils"
+)
+
+const (
+	// DefaultMintsChunkSize is the maximum number of mints sent in a single GetNFTsInfo request
+	DefaultMintsChunkSize = 50
+	// DefaultMintsConcurrency is the maximum number of GetNFTsInfo chunks fetched in parallel
+	DefaultMintsConcurrency = 4
 )
 
 // nftsAPI implements the NFTsAPI interface
 type nftsAPI struct {
-	transport transport.Transport
+	transport        transport.Transport
+	mintsChunkSize   int
+	mintsConcurrency int
+}
+
+// Option configures the NFTs service
+type Option func(*nftsAPI)
+
+// WithMintsChunkSize sets how many mints GetNFTsInfo sends per request
+func WithMintsChunkSize(n int) Option {
+	return func(s *nftsAPI) {
+		if n > 0 {
+			s.mintsChunkSize = n
+		}
+	}
+}
+
+// WithMintsConcurrency sets how many GetNFTsInfo chunks are fetched in parallel
+func WithMintsConcurrency(n int) Option {
+	return func(s *nftsAPI) {
+		if n > 0 {
+			s.mintsConcurrency = n
+		}
+	}
 }
 
 // New creates a new NFTs service
-func New(t transport.Transport) NFTsAPI {
-	return &nftsAPI{
-		transport: t,
+func New(t transport.Transport, opts ...Option) NFTsAPI {
+	s := &nftsAPI{
+		transport:        t,
+		mintsChunkSize:   DefaultMintsChunkSize,
+		mintsConcurrency: DefaultMintsConcurrency,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// GetNFTsInfo retrieves NFT info based on the mint addresses provided
+// GetNFTsInfo retrieves NFT info based on the mint addresses provided.
+// Mint lists larger than the chunk size are split into several requests
+// (see getNFTsInfoChunked); smaller lists are sent as a single request.
 // Returns: raw response bytes, status code, error
 func (s *nftsAPI) GetNFTsInfo(ctx context.Context, req *NFTsInfoRequest) ([]byte, int, error) {
-	return s.executeRequest(ctx, "/api/v1/mint", req)
+	if len(req.Mints) > s.mintsChunkSize {
+		return s.getNFTsInfoChunked(ctx, req)
+	}
+	return request.Raw(ctx, s.transport, "/api/v1/mint", req)
 }
 
 // GetNFTsByCollection retrieves mints based on the collection ID provided
 // Returns: raw response bytes, status code, error
 func (s *nftsAPI) GetNFTsByCollection(ctx context.Context, req *NFTsByCollectionRequest) ([]byte, int, error) {
-	return s.executeRequest(ctx, "/api/v1/mint/collection", req)
-}
-
-// executeRequest is a helper method that handles the common pattern of:
-// 1. Request validation
-// 2. Query parameter building
-// 3. HTTP request execution
-// 4. Response handling
-func (s *nftsAPI) executeRequest(ctx context.Context, endpoint string, req Validator) ([]byte, int, error) {
-	// Validate the request
-	if err := req.Validate(); err != nil {
-		return nil, 0, fmt.Errorf("request validation failed: %w", err)
-	}
-
-	// Build query parameters from the request
-	params, err := utils.BuildQueryParams(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to build query parameters: %w", err)
-	}
-
-	// Make the HTTP request
-	resp, err := s.transport.Get(ctx, endpoint, params)
-	if err != nil {
-		return nil, 0, fmt.Errorf("HTTP request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Check for HTTP errors
-	if resp.StatusCode >= 400 {
-		return body, resp.StatusCode, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	return body, resp.StatusCode, nil
+	return request.Raw(ctx, s.transport, "/api/v1/mint/collection", req)
 }
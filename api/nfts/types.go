@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/srpvpn/tensor-go-sdk/internal/utils"
+	"github.com/srpvpn/tensor-go-sdk/money"
 )
 
 // NFTsInfoRequest represents the request for getting NFT info
@@ -15,27 +16,27 @@ type NFTsInfoRequest struct {
 
 // NFTsByCollectionRequest represents the request for getting NFTs by collection
 type NFTsByCollectionRequest struct {
-	CollId            string   `json:"collId"`                      // The collection ID of the mint to filter for
-	SortBy            string   `json:"sortBy"`                      // The order in with the NFTs should be returned
-	Limit             int32    `json:"limit"`                       // 1 to 250 Number of mint addresses to return
-	OnlyListings      *bool    `json:"onlyListings,omitempty"`      // Hide unlisted NFTs
-	Mints             []string `json:"mints,omitempty"`             // The list of mints for filter for
-	Cursor            *string  `json:"cursor,omitempty"`            // The cursor string received in the previous response
-	ListingSources    []string `json:"listingSources,omitempty"`    // Sources to agregate listings from
-	MinPrice          *float64 `json:"minPrice,omitempty"`          // The minimum price of to filter for
-	MaxPrice          *float64 `json:"maxPrice,omitempty"`          // The maximum price to filter for
-	TraitCountMin     *int32   `json:"traitCountMin,omitempty"`     // Minimum number of traits to filter for
-	TraitCountMax     *int32   `json:"traitCountMax,omitempty"`     // Maximum number of traits to filter for
-	Name              *string  `json:"name,omitempty"`              // Name of the NFT to filter for
-	ExcludeOwners     []string `json:"excludeOwners,omitempty"`     // Owners to exclude in results
-	IncludeOwners     []string `json:"includeOwners,omitempty"`     // Owners to include in results
-	IncludeCurrencies []string `json:"includeCurrencies,omitempty"` // Currencies to include in results
-	Traits            []string `json:"traits,omitempty"`            // Traits and values to filter for
-	RaritySystem      *string  `json:"raritySystem,omitempty"`      // Rarity System to use when filtering for rarity
-	RarityMin         *float64 `json:"rarityMin,omitempty"`         // Minimum rarity points to return in results
-	RarityMax         *float64 `json:"rarityMax,omitempty"`         // Maximum rarity points to return in results
-	OnlyInscriptions  *bool    `json:"onlyInscriptions,omitempty"`  // Filter to include only Solana Inscriptions
-	ImmutableStatus   *string  `json:"immutableStatus,omitempty"`   // Filter the immutability of the Inscriptions
+	CollId            string          `json:"collId"`                      // The collection ID of the mint to filter for
+	SortBy            string          `json:"sortBy"`                      // The order in with the NFTs should be returned
+	Limit             int32           `json:"limit"`                       // 1 to 250 Number of mint addresses to return
+	OnlyListings      *bool           `json:"onlyListings,omitempty"`      // Hide unlisted NFTs
+	Mints             []string        `json:"mints,omitempty"`             // The list of mints for filter for
+	Cursor            *string         `json:"cursor,omitempty"`            // The cursor string received in the previous response
+	ListingSources    []string        `json:"listingSources,omitempty"`    // Sources to agregate listings from
+	MinPrice          *money.Lamports `json:"minPrice,omitempty"`          // The minimum listing price to filter for
+	MaxPrice          *money.Lamports `json:"maxPrice,omitempty"`          // The maximum listing price to filter for
+	TraitCountMin     *int32          `json:"traitCountMin,omitempty"`     // Minimum number of traits to filter for
+	TraitCountMax     *int32          `json:"traitCountMax,omitempty"`     // Maximum number of traits to filter for
+	Name              *string         `json:"name,omitempty"`              // Name of the NFT to filter for
+	ExcludeOwners     []string        `json:"excludeOwners,omitempty"`     // Owners to exclude in results
+	IncludeOwners     []string        `json:"includeOwners,omitempty"`     // Owners to include in results
+	IncludeCurrencies []string        `json:"includeCurrencies,omitempty"` // Currencies to include in results
+	Traits            []string        `json:"traits,omitempty"`            // Traits and values to filter for
+	RaritySystem      *string         `json:"raritySystem,omitempty"`      // Rarity System to use when filtering for rarity
+	RarityMin         *float64        `json:"rarityMin,omitempty"`         // Minimum rarity points to return in results
+	RarityMax         *float64        `json:"rarityMax,omitempty"`         // Maximum rarity points to return in results
+	OnlyInscriptions  *bool           `json:"onlyInscriptions,omitempty"`  // Filter to include only Solana Inscriptions
+	ImmutableStatus   *string         `json:"immutableStatus,omitempty"`   // Filter the immutability of the Inscriptions
 }
 
 // Validator interface for request validation
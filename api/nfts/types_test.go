@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/srpvpn/tensor-go-sdk/money"
 )
 
 func TestNFTsInfoRequest_Validate(t *testing.T) {
@@ -96,6 +98,7 @@ func TestNFTsByCollectionRequest_Validate(t *testing.T) {
 	stringPtr := func(s string) *string { return &s }
 	int32Ptr := func(i int32) *int32 { return &i }
 	float64Ptr := func(f float64) *float64 { return &f }
+	lamportsPtr := func(l money.Lamports) *money.Lamports { return &l }
 
 	tests := []struct {
 		name    string
@@ -122,8 +125,8 @@ func TestNFTsByCollectionRequest_Validate(t *testing.T) {
 				Mints:             []string{"11111111111111111111111111111112"},
 				Cursor:            stringPtr("cursor-string"),
 				ListingSources:    []string{"tensor", "magiceden"},
-				MinPrice:          float64Ptr(0.5),
-				MaxPrice:          float64Ptr(10.0),
+				MinPrice:          lamportsPtr(500_000_000),
+				MaxPrice:          lamportsPtr(10_000_000_000),
 				TraitCountMin:     int32Ptr(1),
 				TraitCountMax:     int32Ptr(10),
 				Name:              stringPtr("Cool NFT"),
@@ -218,7 +221,7 @@ func TestNFTsByCollectionRequest_Validate(t *testing.T) {
 				CollId:   "collection-id",
 				SortBy:   "PriceAsc",
 				Limit:    50,
-				MinPrice: float64Ptr(-1.0),
+				MinPrice: lamportsPtr(-1),
 			},
 			wantErr: true,
 			errMsg:  "minPrice must be >= 0",
@@ -229,7 +232,7 @@ func TestNFTsByCollectionRequest_Validate(t *testing.T) {
 				CollId:   "collection-id",
 				SortBy:   "PriceAsc",
 				Limit:    50,
-				MaxPrice: float64Ptr(-1.0),
+				MaxPrice: lamportsPtr(-1),
 			},
 			wantErr: true,
 			errMsg:  "maxPrice must be >= 0",
@@ -356,7 +359,7 @@ func TestNFTsByCollectionRequest_JSON(t *testing.T) {
 	// Helper functions for pointers
 	boolPtr := func(b bool) *bool { return &b }
 	stringPtr := func(s string) *string { return &s }
-	float64Ptr := func(f float64) *float64 { return &f }
+	lamportsPtr := func(l money.Lamports) *money.Lamports { return &l }
 
 	tests := []struct {
 		name     string
@@ -379,11 +382,11 @@ func TestNFTsByCollectionRequest_JSON(t *testing.T) {
 				SortBy:       "PriceDesc",
 				Limit:        100,
 				OnlyListings: boolPtr(true),
-				MinPrice:     float64Ptr(1.5),
-				MaxPrice:     float64Ptr(10.0),
+				MinPrice:     lamportsPtr(1_500_000_000),
+				MaxPrice:     lamportsPtr(10_000_000_000),
 				Name:         stringPtr("Cool NFT"),
 			},
-			expected: `{"collId":"collection-id","sortBy":"PriceDesc","limit":100,"onlyListings":true,"minPrice":1.5,"maxPrice":10,"name":"Cool NFT"}`,
+			expected: `{"collId":"collection-id","sortBy":"PriceDesc","limit":100,"onlyListings":true,"minPrice":1500000000,"maxPrice":10000000000,"name":"Cool NFT"}`,
 		},
 	}
 
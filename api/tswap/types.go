@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/srpvpn/tensor-go-sdk/internal/utils"
+	"github.com/srpvpn/tensor-go-sdk/money"
 )
 
 // CloseTSwapPoolRequest represents the request parameters for closing a TSwap pool
@@ -21,20 +22,55 @@ type CloseTSwapPoolResponse struct {
 	Txs []Transaction `json:"txs"`
 }
 
+// Delta is a pool's price step as the API encodes it: lamports for linear
+// curves and basis points for exponential ones. Build it with LinearDelta or
+// ExponentialDelta and read it with the accessor matching the pool's curve.
+type Delta int64
+
+// LinearDelta returns the Delta of a linear curve that moves by step per trade
+func LinearDelta(step money.Lamports) Delta {
+	return Delta(step)
+}
+
+// ExponentialDelta returns the Delta of an exponential curve that moves by bps basis points per trade
+func ExponentialDelta(bps int64) Delta {
+	return Delta(bps)
+}
+
+// Lamports returns the step of a linear curve
+func (d Delta) Lamports() money.Lamports {
+	return money.Lamports(d)
+}
+
+// Bps returns the step of an exponential curve, in basis points
+func (d Delta) Bps() int64 {
+	return int64(d)
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string, as pools are read back from the API
+func (d *Delta) UnmarshalJSON(data []byte) error {
+	var n money.Lamports
+	if err := n.UnmarshalJSON(data); err != nil {
+		return fmt.Errorf("invalid delta %s", data)
+	}
+	*d = Delta(n)
+	return nil
+}
+
 // EditTSwapPoolRequest represents the request parameters for editing a TSwap pool
 type EditTSwapPoolRequest struct {
-	PoolAddress           string   `json:"poolAddress"`
-	PoolType              string   `json:"poolType"`
-	CurveType             string   `json:"curveType"`
-	StartingPrice         float64  `json:"startingPrice"`
-	Delta                 float64  `json:"delta"`
-	Blockhash             string   `json:"blockhash"`
-	MmKeepFeesSeparate    *bool    `json:"mmKeepFeesSeparate,omitempty"`
-	MmFeeBps              *float64 `json:"mmFeeBps,omitempty"`
-	MaxTakerSellCount     *int32   `json:"maxTakerSellCount,omitempty"`
-	UseSharedEscrow       *bool    `json:"useSharedEscrow,omitempty"`
-	Compute               *int32   `json:"compute,omitempty"`
-	PriorityMicroLamports *int32   `json:"priorityMicroLamports,omitempty"`
+	PoolAddress           string         `json:"poolAddress"`
+	PoolType              string         `json:"poolType"`
+	CurveType             string         `json:"curveType"`
+	StartingPrice         money.Lamports `json:"startingPrice"`
+	Delta                 Delta          `json:"delta"`
+	Blockhash             string         `json:"blockhash"`
+	MmKeepFeesSeparate    *bool          `json:"mmKeepFeesSeparate,omitempty"`
+	MmFeeBps              *float64       `json:"mmFeeBps,omitempty"`
+	MaxTakerSellCount     *int32         `json:"maxTakerSellCount,omitempty"`
+	UseSharedEscrow       *bool          `json:"useSharedEscrow,omitempty"`
+	Compute               *int32         `json:"compute,omitempty"`
+	PriorityMicroLamports *int32         `json:"priorityMicroLamports,omitempty"`
 }
 
 // EditTSwapPoolResponse represents the response from the edit TSwap pool API
@@ -60,12 +96,12 @@ type DepositWithdrawNFTResponse struct {
 
 // DepositWithdrawSOLRequest represents the request parameters for depositing/withdrawing SOL to/from a TSwap pool
 type DepositWithdrawSOLRequest struct {
-	Action                string  `json:"action"`
-	PoolAddress           string  `json:"poolAddress"`
-	Lamports              float64 `json:"lamports"`
-	Blockhash             string  `json:"blockhash"`
-	Compute               *int32  `json:"compute,omitempty"`
-	PriorityMicroLamports *int32  `json:"priorityMicroLamports,omitempty"`
+	Action                string         `json:"action"`
+	PoolAddress           string         `json:"poolAddress"`
+	Lamports              money.Lamports `json:"lamports"`
+	Blockhash             string         `json:"blockhash"`
+	Compute               *int32         `json:"compute,omitempty"`
+	PriorityMicroLamports *int32         `json:"priorityMicroLamports,omitempty"`
 }
 
 // DepositWithdrawSOLResponse represents the response from the deposit/withdraw SOL API
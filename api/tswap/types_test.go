@@ -113,8 +113,8 @@ func TestEditTSwapPoolRequest_Validate(t *testing.T) {
 				PoolAddress:   "11111111111111111111111111111112",
 				PoolType:      "TOKEN",
 				CurveType:     "linear",
-				StartingPrice: 1.5,
-				Delta:         0.1,
+				StartingPrice: 1500000000,
+				Delta:         100000000,
 				Blockhash:     "11111111111111111111111111111113",
 			},
 			wantErr: false,
@@ -125,8 +125,8 @@ func TestEditTSwapPoolRequest_Validate(t *testing.T) {
 				PoolAddress:   "",
 				PoolType:      "TOKEN",
 				CurveType:     "linear",
-				StartingPrice: 1.5,
-				Delta:         0.1,
+				StartingPrice: 1500000000,
+				Delta:         100000000,
 				Blockhash:     "11111111111111111111111111111113",
 			},
 			wantErr: true,
@@ -138,8 +138,8 @@ func TestEditTSwapPoolRequest_Validate(t *testing.T) {
 				PoolAddress:   "11111111111111111111111111111112",
 				PoolType:      "INVALID",
 				CurveType:     "linear",
-				StartingPrice: 1.5,
-				Delta:         0.1,
+				StartingPrice: 1500000000,
+				Delta:         100000000,
 				Blockhash:     "11111111111111111111111111111113",
 			},
 			wantErr: true,
@@ -151,8 +151,8 @@ func TestEditTSwapPoolRequest_Validate(t *testing.T) {
 				PoolAddress:   "11111111111111111111111111111112",
 				PoolType:      "TOKEN",
 				CurveType:     "invalid",
-				StartingPrice: 1.5,
-				Delta:         0.1,
+				StartingPrice: 1500000000,
+				Delta:         100000000,
 				Blockhash:     "11111111111111111111111111111113",
 			},
 			wantErr: true,
@@ -164,8 +164,8 @@ func TestEditTSwapPoolRequest_Validate(t *testing.T) {
 				PoolAddress:   "11111111111111111111111111111112",
 				PoolType:      "TOKEN",
 				CurveType:     "linear",
-				StartingPrice: -1.0,
-				Delta:         0.1,
+				StartingPrice: -1000000000,
+				Delta:         100000000,
 				Blockhash:     "11111111111111111111111111111113",
 			},
 			wantErr: true,
@@ -177,8 +177,8 @@ func TestEditTSwapPoolRequest_Validate(t *testing.T) {
 				PoolAddress:   "11111111111111111111111111111112",
 				PoolType:      "TOKEN",
 				CurveType:     "linear",
-				StartingPrice: 1.5,
-				Delta:         -0.1,
+				StartingPrice: 1500000000,
+				Delta:         -100000000,
 				Blockhash:     "11111111111111111111111111111113",
 			},
 			wantErr: true,
@@ -190,8 +190,8 @@ func TestEditTSwapPoolRequest_Validate(t *testing.T) {
 				PoolAddress:   "11111111111111111111111111111112",
 				PoolType:      "TOKEN",
 				CurveType:     "linear",
-				StartingPrice: 1.5,
-				Delta:         0.1,
+				StartingPrice: 1500000000,
+				Delta:         100000000,
 				Blockhash:     "11111111111111111111111111111113",
 				MmFeeBps:      float64Ptr(10001),
 			},
@@ -204,8 +204,8 @@ func TestEditTSwapPoolRequest_Validate(t *testing.T) {
 				PoolAddress:       "11111111111111111111111111111112",
 				PoolType:          "TOKEN",
 				CurveType:         "linear",
-				StartingPrice:     1.5,
-				Delta:             0.1,
+				StartingPrice:     1500000000,
+				Delta:             100000000,
 				Blockhash:         "11111111111111111111111111111113",
 				MaxTakerSellCount: int32Ptr(-1),
 			},
@@ -218,8 +218,8 @@ func TestEditTSwapPoolRequest_Validate(t *testing.T) {
 				PoolAddress:           "11111111111111111111111111111112",
 				PoolType:              "NFT",
 				CurveType:             "exponential",
-				StartingPrice:         2.5,
-				Delta:                 0.2,
+				StartingPrice:         2500000000,
+				Delta:                 200,
 				Blockhash:             "11111111111111111111111111111113",
 				MmKeepFeesSeparate:    boolPtr(true),
 				MmFeeBps:              float64Ptr(250.5),
@@ -322,11 +322,11 @@ func TestEditTSwapPoolRequest_JSON(t *testing.T) {
 				PoolAddress:   "11111111111111111111111111111112",
 				PoolType:      "TOKEN",
 				CurveType:     "linear",
-				StartingPrice: 1.5,
-				Delta:         0.1,
+				StartingPrice: 1500000000,
+				Delta:         100000000,
 				Blockhash:     "11111111111111111111111111111113",
 			},
-			expected: `{"poolAddress":"11111111111111111111111111111112","poolType":"TOKEN","curveType":"linear","startingPrice":1.5,"delta":0.1,"blockhash":"11111111111111111111111111111113"}`,
+			expected: `{"poolAddress":"11111111111111111111111111111112","poolType":"TOKEN","curveType":"linear","startingPrice":1500000000,"delta":100000000,"blockhash":"11111111111111111111111111111113"}`,
 		},
 		{
 			name: "full request",
@@ -334,8 +334,8 @@ func TestEditTSwapPoolRequest_JSON(t *testing.T) {
 				PoolAddress:           "11111111111111111111111111111112",
 				PoolType:              "NFT",
 				CurveType:             "exponential",
-				StartingPrice:         2.5,
-				Delta:                 0.2,
+				StartingPrice:         2500000000,
+				Delta:                 200,
 				Blockhash:             "11111111111111111111111111111113",
 				MmKeepFeesSeparate:    boolPtr(true),
 				MmFeeBps:              float64Ptr(250.5),
@@ -344,7 +344,7 @@ func TestEditTSwapPoolRequest_JSON(t *testing.T) {
 				Compute:               int32Ptr(200000),
 				PriorityMicroLamports: int32Ptr(1000),
 			},
-			expected: `{"poolAddress":"11111111111111111111111111111112","poolType":"NFT","curveType":"exponential","startingPrice":2.5,"delta":0.2,"blockhash":"11111111111111111111111111111113","mmKeepFeesSeparate":true,"mmFeeBps":250.5,"maxTakerSellCount":10,"useSharedEscrow":false,"compute":200000,"priorityMicroLamports":1000}`,
+			expected: `{"poolAddress":"11111111111111111111111111111112","poolType":"NFT","curveType":"exponential","startingPrice":2500000000,"delta":200,"blockhash":"11111111111111111111111111111113","mmKeepFeesSeparate":true,"mmFeeBps":250.5,"maxTakerSellCount":10,"useSharedEscrow":false,"compute":200000,"priorityMicroLamports":1000}`,
 		},
 	}
 
@@ -380,6 +380,39 @@ func TestEditTSwapPoolRequest_JSON(t *testing.T) {
 	}
 }
 
+func TestDelta_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Delta
+	}{
+		{name: "number", input: `100000000`, want: LinearDelta(100_000_000)},
+		{name: "numeric string", input: `"250"`, want: ExponentialDelta(250)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Delta
+			if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %d, want %d", got, tt.want)
+			}
+			data, err := json.Marshal(got)
+			if err != nil || string(data) != strings.Trim(tt.input, `"`) {
+				t.Errorf("Marshal() = %s, %v", data, err)
+			}
+		})
+	}
+	if d := ExponentialDelta(300); d.Bps() != 300 || LinearDelta(5).Lamports() != 5 {
+		t.Errorf("accessors do not return the constructed step")
+	}
+	var d Delta
+	if err := json.Unmarshal([]byte(`"ten"`), &d); err == nil {
+		t.Error("Unmarshal() accepted a non-numeric delta")
+	}
+}
+
 func TestCloseTSwapPoolRequest_UnmarshalJSON_TrimWhitespace(t *testing.T) {
 	jsonStr := `{"poolAddress":"  11111111111111111111111111111112  ","blockhash":"  11111111111111111111111111111113  "}`
 
@@ -402,7 +435,7 @@ func TestCloseTSwapPoolRequest_UnmarshalJSON_TrimWhitespace(t *testing.T) {
 }
 
 func TestEditTSwapPoolRequest_UnmarshalJSON_TrimWhitespace(t *testing.T) {
-	jsonStr := `{"poolAddress":"  11111111111111111111111111111112  ","poolType":"  TOKEN  ","curveType":"  linear  ","startingPrice":1.5,"delta":0.1,"blockhash":"  11111111111111111111111111111113  "}`
+	jsonStr := `{"poolAddress":"  11111111111111111111111111111112  ","poolType":"  TOKEN  ","curveType":"  linear  ","startingPrice":1500000000,"delta":100000000,"blockhash":"  11111111111111111111111111111113  "}`
 
 	var request EditTSwapPoolRequest
 	err := json.Unmarshal([]byte(jsonStr), &request)
@@ -641,7 +674,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "DEPOSIT",
 				PoolAddress: "11111111111111111111111111111112",
-				Lamports:    1000000000.0, // 1 SOL
+				Lamports:    1000000000, // 1 SOL
 				Blockhash:   "11111111111111111111111111111114",
 			},
 			wantErr: false,
@@ -651,7 +684,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "deposit",
 				PoolAddress: "11111111111111111111111111111112",
-				Lamports:    1000000000.0, // 1 SOL
+				Lamports:    1000000000, // 1 SOL
 				Blockhash:   "11111111111111111111111111111114",
 			},
 			wantErr: false,
@@ -661,7 +694,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "WITHDRAW",
 				PoolAddress: "11111111111111111111111111111112",
-				Lamports:    500000000.0, // 0.5 SOL
+				Lamports:    500000000, // 0.5 SOL
 				Blockhash:   "11111111111111111111111111111114",
 			},
 			wantErr: false,
@@ -671,7 +704,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "WithDraw",
 				PoolAddress: "11111111111111111111111111111112",
-				Lamports:    500000000.0, // 0.5 SOL
+				Lamports:    500000000, // 0.5 SOL
 				Blockhash:   "11111111111111111111111111111114",
 			},
 			wantErr: false,
@@ -681,7 +714,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "",
 				PoolAddress: "11111111111111111111111111111112",
-				Lamports:    1000000000.0,
+				Lamports:    1000000000,
 				Blockhash:   "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -692,7 +725,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "INVALID",
 				PoolAddress: "11111111111111111111111111111112",
-				Lamports:    1000000000.0,
+				Lamports:    1000000000,
 				Blockhash:   "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -704,7 +737,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "deposit",
 				PoolAddress: "",
-				Lamports:    1000000000.0,
+				Lamports:    1000000000,
 				Blockhash:   "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -715,7 +748,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "deposit",
 				PoolAddress: "invalid",
-				Lamports:    1000000000.0,
+				Lamports:    1000000000,
 				Blockhash:   "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -726,7 +759,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "deposit",
 				PoolAddress: "11111111111111111111111111111112",
-				Lamports:    -1000.0,
+				Lamports:    -1000,
 				Blockhash:   "11111111111111111111111111111114",
 			},
 			wantErr: true,
@@ -737,7 +770,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "deposit",
 				PoolAddress: "11111111111111111111111111111112",
-				Lamports:    1000000000.0,
+				Lamports:    1000000000,
 				Blockhash:   "",
 			},
 			wantErr: true,
@@ -748,7 +781,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "deposit",
 				PoolAddress: "11111111111111111111111111111112",
-				Lamports:    1000000000.0,
+				Lamports:    1000000000,
 				Blockhash:   "11111111111111111111111111111114",
 				Compute:     int32Ptr(-1),
 			},
@@ -760,7 +793,7 @@ func TestDepositWithdrawSOLRequest_Validate(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:                "WITHDRAW",
 				PoolAddress:           "11111111111111111111111111111112",
-				Lamports:              2000000000.0, // 2 SOL
+				Lamports:              2000000000, // 2 SOL
 				Blockhash:             "11111111111111111111111111111114",
 				Compute:               int32Ptr(200000),
 				PriorityMicroLamports: int32Ptr(1000),
@@ -866,7 +899,7 @@ func TestDepositWithdrawSOLRequest_JSON(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:      "DEPOSIT",
 				PoolAddress: "11111111111111111111111111111112",
-				Lamports:    1000000000.0,
+				Lamports:    1000000000,
 				Blockhash:   "11111111111111111111111111111114",
 			},
 			expected: `{"action":"DEPOSIT","poolAddress":"11111111111111111111111111111112","lamports":1000000000,"blockhash":"11111111111111111111111111111114"}`,
@@ -876,7 +909,7 @@ func TestDepositWithdrawSOLRequest_JSON(t *testing.T) {
 			request: &DepositWithdrawSOLRequest{
 				Action:                "WITHDRAW",
 				PoolAddress:           "11111111111111111111111111111112",
-				Lamports:              2000000000.0,
+				Lamports:              2000000000,
 				Blockhash:             "11111111111111111111111111111114",
 				Compute:               int32Ptr(200000),
 				PriorityMicroLamports: int32Ptr(1000),
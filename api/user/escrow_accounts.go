@@ -2,10 +2,12 @@ package user
 
 import (
 	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/request"
 )
 
 // GetEscrowAccounts retrieves details for all escrow accounts for a supplied wallet
 // Returns: response body, status code, error
 func (u *userAPI) GetEscrowAccounts(ctx context.Context, req *EscrowAccountsRequest) ([]byte, int, error) {
-	return u.executeRequest(ctx, "/api/v1/user/escrow_accounts", req)
+	return request.Raw(ctx, u.transport, "/api/v1/user/escrow_accounts", req)
 }
@@ -2,10 +2,12 @@ package user
 
 import (
 	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/request"
 )
 
 // GetInventoryForCollection retrieves all active listings for supplied wallets
 // Returns: response body, status code, error
 func (u *userAPI) GetInventoryForCollection(ctx context.Context, req *InventoryForCollectionRequest) ([]byte, int, error) {
-	return u.executeRequest(ctx, "/api/v1/user/inventory_by_collection", req)
+	return request.Raw(ctx, u.transport, "/api/v1/user/inventory_by_collection", req)
 }
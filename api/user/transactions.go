@@ -2,10 +2,12 @@ package user
 
 import (
 	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/request"
 )
 
 // GetTransactions retrieves all NFT transactions for a supplied wallet.
 // Returns: response body, status code, error
 func (u *userAPI) GetTransactions(ctx context.Context, req *TransactionsRequest) ([]byte, int, error) {
-	return u.executeRequest(ctx, "/api/v1/user/transactions", req)
+	return request.Raw(ctx, u.transport, "/api/v1/user/transactions", req)
 }
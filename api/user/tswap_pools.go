@@ -2,10 +2,12 @@ package user
 
 import (
 	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/request"
 )
 
 // GetTSwapPools retrieves TSwap pools owned by an address.
 // Returns: response body, status code, error
 func (u *userAPI) GetTSwapPools(ctx context.Context, req *TSwapsPoolsRequest) ([]byte, int, error) {
-	return u.executeRequest(ctx, "/api/v1/user/amm_pools", req)
+	return request.Raw(ctx, u.transport, "/api/v1/user/amm_pools", req)
 }
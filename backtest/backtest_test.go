@@ -0,0 +1,479 @@
+package backtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/rpc"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/paper"
+	"github.com/srpvpn/tensor-go-sdk/pricing"
+	"github.com/srpvpn/tensor-go-sdk/tensortest"
+)
+
+var (
+	trader = tensortest.Address("trader")
+	seller = tensortest.Address("seller")
+	rival  = tensortest.Address("rival")
+	start  = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func mint(name string) string { return tensortest.Address("mint-" + name) }
+
+func at(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+
+func collectionEvent(t time.Time, royaltyBps int32) Event {
+	return Event{Time: t, Kind: KindCollection, CollId: tensortest.CollID, Collection: &collections.CollectionDetailed{CollId: tensortest.CollID, Name: "Backtest", SellRoyaltyFeeBPS: royaltyBps}}
+}
+
+// listingsEvent records a snapshot of listings given as name, price pairs
+func listingsEvent(t time.Time, pairs ...string) Event {
+	e := Event{Time: t, Kind: KindListings, CollId: tensortest.CollID, Listings: []market.NFT{}}
+	for i := 0; i < len(pairs); i += 2 {
+		e.Listings = append(e.Listings, market.NFT{Mint: mint(pairs[i]), CollId: tensortest.CollID, Owner: seller, Listing: &market.Listing{Price: money.MustParseSOL(pairs[i+1]), Seller: seller}})
+	}
+	return e
+}
+
+func tradeEvent(t time.Time, txType, name, price string) Event {
+	return Event{Time: t, Kind: KindTrade, CollId: tensortest.CollID, Trade: &market.Transaction{
+		TxId: tensortest.Address("tx-" + name), TxType: txType, Mint: mint(name), CollId: tensortest.CollID,
+		Price: money.MustParseSOL(price), Buyer: rival, Seller: seller, BlockTime: t.Unix(),
+	}}
+}
+
+func TestEventsRoundTripAndSort(t *testing.T) {
+	events := []Event{
+		tradeEvent(at(1), "SALE_BUY_NOW", "a", "1"),
+		listingsEvent(at(1), "b", "1.2"),
+		collectionEvent(at(1), 500),
+		listingsEvent(at(0)),
+	}
+	Sort(events)
+	var kinds []string
+	for _, e := range events {
+		kinds = append(kinds, string(e.Kind))
+	}
+	if got := strings.Join(kinds, ","); got != "listings,collection,listings,trade" {
+		t.Errorf("sorted kinds = %s, want listings,collection,listings,trade", got)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteEvents(&buf, events); err != nil {
+		t.Fatalf("WriteEvents() error = %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(events) {
+		t.Errorf("WriteEvents() wrote %d lines, want %d", lines, len(events))
+	}
+	got, err := ReadEvents(&buf)
+	if err != nil {
+		t.Fatalf("ReadEvents() error = %v", err)
+	}
+	if len(got) != len(events) {
+		t.Fatalf("ReadEvents() = %d events, want %d", len(got), len(events))
+	}
+	if got[2].Listings[0].Listing.Price != money.MustParseSOL("1.2") || got[3].Trade.Price != money.MustParseSOL("1") || !got[3].Time.Equal(at(1)) {
+		t.Errorf("ReadEvents() = %+v, want the written events", got)
+	}
+	if got[1].Collection.SellRoyaltyFeeBPS != 500 {
+		t.Errorf("round tripped royalty = %d, want 500", got[1].Collection.SellRoyaltyFeeBPS)
+	}
+
+	for name, line := range map[string]string{
+		"bad json":        `{"time":`,
+		"unknown kind":    `{"time":"2026-06-01T12:00:00Z","kind":"bids","collId":"c"}`,
+		"missing trade":   `{"time":"2026-06-01T12:00:00Z","kind":"trade","collId":"c"}`,
+		"missing time":    `{"kind":"listings","collId":"c"}`,
+		"foreign listing": `{"time":"2026-06-01T12:00:00Z","kind":"listings","collId":"c","listings":[{"mint":"m","collId":"d","listing":{"price":1}}]}`,
+	} {
+		if _, err := ReadEvents(strings.NewReader(line + "\n")); err == nil || !strings.Contains(err.Error(), "line 1") {
+			t.Errorf("ReadEvents(%s) error = %v, want a line 1 error", name, err)
+		}
+	}
+}
+
+func TestRecorderCapture(t *testing.T) {
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.SetClock(func() time.Time { return at(0) })
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID, Name: "Backtest", SellRoyaltyFeeBPS: 500})
+	srv.AddCollection(tensortest.Collection{CollId: "empty-collection", Name: "Empty"})
+	for name, price := range map[string]string{"a": "1000000000", "b": "1200000000"} {
+		srv.AddNFT(tensortest.NFT{Mint: mint(name), CollId: tensortest.CollID, Owner: seller, Listing: &tensortest.Listing{Price: price}})
+	}
+	srv.AddNFT(tensortest.NFT{Mint: mint("c"), CollId: tensortest.CollID, Owner: seller})
+	c := client.New(&client.Config{BaseURL: srv.URL})
+	ctx := context.Background()
+	if _, _, err := c.Marketplace.BuyNFT(ctx, &marketplace.BuyNFTRequest{Buyer: rival, Mint: mint("a"), Owner: seller, MaxPrice: money.MustParseSOL("1"), Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("BuyNFT() error = %v", err)
+	}
+
+	if _, err := NewRecorder(RecorderConfig{}, APIs{Collections: c.Collections, NFTs: c.NFTs}); err == nil {
+		t.Error("NewRecorder() without collIds succeeded")
+	}
+	if _, err := NewRecorder(RecorderConfig{CollIds: []string{tensortest.CollID}, Wallets: []string{rival}}, APIs{Collections: c.Collections, NFTs: c.NFTs}); err == nil {
+		t.Error("NewRecorder() recording wallets without a user API succeeded")
+	}
+	rec, err := NewRecorder(RecorderConfig{CollIds: []string{tensortest.CollID, "empty-collection"}, Wallets: []string{rival}}, APIs{Collections: c.Collections, NFTs: c.NFTs, User: c.User})
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	rec.now = func() time.Time { return at(1) }
+
+	events, err := rec.Capture(ctx)
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+	byKind := make(map[Kind][]Event)
+	for _, e := range events {
+		byKind[e.Kind] = append(byKind[e.Kind], e)
+	}
+	if got := len(byKind[KindCollection]); got != 2 {
+		t.Errorf("Capture() recorded %d collections, want 2", got)
+	}
+	for _, e := range byKind[KindCollection] {
+		if e.CollId == tensortest.CollID && (e.Collection.SellRoyaltyFeeBPS != 500 || !e.Time.Equal(at(1))) {
+			t.Errorf("collection event = %+v, want royalty 500 at the capture time", e)
+		}
+	}
+	if got := len(byKind[KindListings]); got != 2 {
+		t.Fatalf("Capture() recorded %d listing snapshots, want 2", got)
+	}
+	if l := byKind[KindListings][0].Listings; len(l) != 1 || l[0].Mint != mint("b") || l[0].Listing.Price != money.MustParseSOL("1.2") {
+		t.Errorf("listings of %s = %+v, want only b at 1.2 SOL", tensortest.CollID, l)
+	}
+	if l := byKind[KindListings][1].Listings; len(l) != 0 {
+		t.Errorf("listings of the empty collection = %+v, want none", l)
+	}
+	trades := byKind[KindTrade]
+	if len(trades) != 1 || trades[0].Trade.TxType != "SALE_BUY_NOW" || trades[0].Trade.Mint != mint("a") || !trades[0].Time.Equal(at(0)) {
+		t.Fatalf("trades = %+v, want the buy of a at its block time", trades)
+	}
+
+	events, err = rec.Capture(ctx)
+	if err != nil {
+		t.Fatalf("second Capture() error = %v", err)
+	}
+	for _, e := range events {
+		if e.Kind == KindTrade {
+			t.Errorf("second Capture() recorded trade %s again", e.Trade.TxId)
+		}
+	}
+	if n := srv.RequestCount("/api/v1/user/transactions"); n != 4 {
+		t.Errorf("transaction requests = %d, want one per collection per capture", n)
+	}
+}
+
+func TestReplayAnswersFromRecording(t *testing.T) {
+	r := newReplay()
+	for _, e := range []Event{
+		collectionEvent(at(0), 500),
+		listingsEvent(at(0), "a", "1", "b", "1.2"),
+		tradeEvent(at(1), "SALE_BUY_NOW", "a", "1"),
+	} {
+		r.apply(e)
+	}
+	ctx := context.Background()
+	onlyListings := true
+
+	data, _, err := r.GetNFTsByCollection(ctx, &nfts.NFTsByCollectionRequest{CollId: tensortest.CollID, SortBy: "PriceAsc", Limit: 10, OnlyListings: &onlyListings})
+	if err != nil {
+		t.Fatalf("GetNFTsByCollection() error = %v", err)
+	}
+	items, _, _ := market.DecodeNFTs(data)
+	if len(items) != 1 || items[0].Mint != mint("b") {
+		t.Errorf("listings after the sale of a = %+v, want only b", items)
+	}
+	data, _, err = r.GetNFTsByCollection(ctx, &nfts.NFTsByCollectionRequest{CollId: tensortest.CollID, SortBy: "PriceAsc", Limit: 1})
+	if err != nil {
+		t.Fatalf("GetNFTsByCollection() error = %v", err)
+	}
+	items, page, _ := market.DecodeNFTs(data)
+	if len(items) != 1 || items[0].Mint != mint("b") || !page.HasMore {
+		t.Errorf("first page with unlisted NFTs = %+v %+v, want b with more to come", items, page)
+	}
+
+	data, _, err = r.GetNFTsInfo(ctx, &nfts.NFTsInfoRequest{Mints: []string{mint("a")}})
+	if err != nil {
+		t.Fatalf("GetNFTsInfo() error = %v", err)
+	}
+	items, _, _ = market.DecodeNFTs(data)
+	if len(items) != 1 || items[0].Owner != rival || items[0].Listing != nil {
+		t.Errorf("GetNFTsInfo(a) = %+v, want a owned by the buyer and unlisted", items)
+	}
+
+	coll, err := market.Collection(ctx, r, tensortest.CollID)
+	if err != nil || coll.SellRoyaltyFeeBPS != 500 {
+		t.Errorf("market.Collection() = %+v, %v, want the recorded collection", coll, err)
+	}
+
+	data, _, err = r.GetTransactions(ctx, &user.TransactionsRequest{Wallets: []string{rival}, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetTransactions() error = %v", err)
+	}
+	txs, _, _ := market.DecodeTransactions(data)
+	if len(txs) != 1 || txs[0].Mint != mint("a") {
+		t.Errorf("GetTransactions(rival) = %+v, want the sale of a", txs)
+	}
+	inventory, err := market.Inventory(ctx, r, rival, tensortest.CollID)
+	if err != nil || len(inventory) != 1 {
+		t.Errorf("market.Inventory(rival) = %+v, %v, want a", inventory, err)
+	}
+	bids, err := market.Bids(ctx, r, market.BidTargetCollection, rival, tensortest.CollID)
+	if err != nil || len(bids) != 0 {
+		t.Errorf("market.Bids() = %+v, %v, want no bids", bids, err)
+	}
+}
+
+// flipper buys the first floor it sees, keeps a collection bid open and
+// lists whatever it holds 0.2 SOL over cost
+type flipper struct {
+	c      *client.Client
+	wallet *paper.Wallet
+	bought bool
+	bid    bool
+}
+
+func (f *flipper) Step(ctx context.Context, now time.Time) error {
+	if !f.bought {
+		floor, ok, err := market.Floor(ctx, f.c.NFTs, tensortest.CollID, trader)
+		if err != nil || !ok {
+			return errors.Join(err, errors.New("no floor"))
+		}
+		if _, _, err := f.c.Marketplace.BuyNFT(ctx, &marketplace.BuyNFTRequest{Buyer: trader, Mint: floor.Mint, Owner: floor.Owner, MaxPrice: floor.Listing.Price, Blockhash: tensortest.Blockhash}); err != nil {
+			return err
+		}
+		f.bought = true
+	}
+	if !f.bid {
+		if _, _, err := f.c.Marketplace.PlaceCollectionBid(ctx, &marketplace.PlaceCollectionBidRequest{Owner: trader, Price: money.MustParseSOL("0.9"), Quantity: 2, CollId: tensortest.CollID, Blockhash: tensortest.Blockhash}); err != nil {
+			return err
+		}
+		f.bid = true
+	}
+	for _, h := range f.wallet.Holdings() {
+		if h.Listed == 0 {
+			if _, _, err := f.c.Marketplace.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: h.Mint, Owner: trader, Price: h.Cost + money.MustParseSOL("0.2"), Blockhash: tensortest.Blockhash}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestRunFlipper(t *testing.T) {
+	events := []Event{
+		listingsEvent(at(3), "d", "1"),
+		tradeEvent(at(2), "SALE_BUY_NOW", "b", "1.2"),
+		listingsEvent(at(1), "b", "1.2", "c", "0.85"),
+		collectionEvent(at(0), 500),
+		listingsEvent(at(0), "a", "1", "b", "1.2"),
+	}
+	r, err := New(Config{Wallet: trader, Balance: money.MustParseSOL("10"), Fees: &pricing.Fees{TakerFeeBps: 200}}, events)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var steps []time.Time
+	f := &flipper{c: r.Client(), wallet: r.Wallet()}
+	res, err := r.Run(context.Background(), StrategyFunc(func(ctx context.Context, now time.Time) error {
+		steps = append(steps, now)
+		return f.Step(ctx, now)
+	}))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if res.ErrorCount != 0 {
+		t.Fatalf("Run() errors = %+v", res.Errors)
+	}
+	if len(steps) != 4 || !steps[0].Equal(at(0)) {
+		t.Errorf("steps = %v, want one per recorded time", steps)
+	}
+
+	// a is bought at 1 SOL plus a 5% royalty and 2% taker fee, c fills the
+	// 0.9 SOL bid, and the recorded sale of b at 1.2 takes c's listing at 1.1
+	want := []struct {
+		venue paper.Venue
+		mint  string
+		price money.Lamports
+		fees  money.Lamports
+	}{
+		{paper.VenueBuyNow, mint("a"), money.MustParseSOL("1"), money.MustParseSOL("0.07")},
+		{paper.VenueBid, mint("c"), money.MustParseSOL("0.9"), 0},
+		{paper.VenueListing, mint("c"), money.MustParseSOL("1.1"), 0},
+	}
+	if len(res.Trades) != len(want) {
+		t.Fatalf("trades = %+v, want %d", res.Trades, len(want))
+	}
+	for i, w := range want {
+		got := res.Trades[i]
+		if got.Venue != w.venue || got.Mint != w.mint || got.Price != w.price || got.Fees != w.fees || !got.Time.Equal(at(i)) {
+			t.Errorf("trade %d = %+v, want %s of %s at %s with %s fees at %v", i, got, w.venue, w.mint, w.price, w.fees, at(i))
+		}
+	}
+	if res.Trades[2].Realized != money.MustParseSOL("0.2") {
+		t.Errorf("realized on c = %s, want 0.2 SOL", res.Trades[2].Realized)
+	}
+
+	// Cash 7.13 and 1.8 locked, a marked at b; then c joins at b's price; then
+	// c is sold and a is unmarked; then d sets the floor at 1
+	curve := []string{"10.13", "10.43", "10.2", "10.13"}
+	if len(res.Equity) != len(curve) {
+		t.Fatalf("equity curve = %+v, want %d points", res.Equity, len(curve))
+	}
+	for i, eq := range curve {
+		if p := res.Equity[i]; p.Equity != money.MustParseSOL(eq) || !p.Time.Equal(at(i)) {
+			t.Errorf("equity point %d = %+v, want %s SOL at %v", i, p, eq, at(i))
+		}
+	}
+
+	s := res.Stats
+	if s.Start != money.MustParseSOL("10") || s.End != money.MustParseSOL("10.13") || math.Abs(s.Return-0.013) > 1e-9 {
+		t.Errorf("start, end, return = %s, %s, %v, want 10, 10.13, 0.013", s.Start, s.End, s.Return)
+	}
+	if s.MaxDrawdownSOL != money.MustParseSOL("0.3") || math.Abs(s.MaxDrawdown-0.3/10.43) > 1e-9 {
+		t.Errorf("max drawdown = %v (%s), want 0.3 SOL from 10.43", s.MaxDrawdown, s.MaxDrawdownSOL)
+	}
+	if s.Orders != 4 || s.Filled != 2 || s.FillRate != 0.5 {
+		t.Errorf("orders, filled, fill rate = %d, %d, %v, want 4, 2, 0.5", s.Orders, s.Filled, s.FillRate)
+	}
+	if s.Trades != 3 || s.Fees != money.MustParseSOL("0.07") || s.Realized != money.MustParseSOL("0.2") || s.Sharpe == 0 {
+		t.Errorf("stats = %+v", s)
+	}
+	if res.Final.Holdings != 1 || res.Final.Locked != money.MustParseSOL("0.9") {
+		t.Errorf("final = %+v, want a held and one bid open", res.Final)
+	}
+	if got := s.String(); !strings.Contains(got, "3 trades, return 1.30%") || !strings.Contains(got, "fill rate 50.00% (2/4)") {
+		t.Errorf("Stats.String() = %q", got)
+	}
+
+	var log bytes.Buffer
+	if err := res.WriteTrades(&log); err != nil {
+		t.Fatalf("WriteTrades() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "2026-06-01T12:00:00Z,buy,buy_now,"+mint("a")) || !strings.Contains(lines[3], ",1.1,0,1.1,,0.2") {
+		t.Errorf("WriteTrades() = %q", log.String())
+	}
+	var equity bytes.Buffer
+	if err := res.WriteEquity(&equity); err != nil {
+		t.Fatalf("WriteEquity() error = %v", err)
+	}
+	if !strings.HasSuffix(equity.String(), "2026-06-01T15:00:00Z,10.13\n") {
+		t.Errorf("WriteEquity() = %q", equity.String())
+	}
+
+	if _, err := r.Run(context.Background(), f); err == nil {
+		t.Error("second Run() succeeded")
+	}
+}
+
+func TestRunStepsSamplesAndErrors(t *testing.T) {
+	var events []Event
+	for h := 0; h < 6; h++ {
+		events = append(events, listingsEvent(at(h), "a", "1"))
+	}
+	r, err := New(Config{Wallet: trader, Balance: money.MustParseSOL("1"), StepEvery: 2 * time.Hour, SampleEvery: 3 * time.Hour}, events)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var steps int
+	res, err := r.Run(context.Background(), StrategyFunc(func(ctx context.Context, now time.Time) error {
+		steps++
+		_, _, err := r.Client().RPC.GetPriorityFees(ctx, &rpc.PriorityFeesRequest{})
+		return err
+	}))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if steps != 3 {
+		t.Errorf("steps = %d, want 3 at hours 0, 2 and 4", steps)
+	}
+	if res.ErrorCount != 3 || len(res.Errors) != 3 || !strings.Contains(res.Errors[0].Err.Error(), "not replayed") {
+		t.Errorf("errors = %d %+v, want every live call to fail", res.ErrorCount, res.Errors)
+	}
+	var times []time.Time
+	for _, p := range res.Equity {
+		times = append(times, p.Time)
+	}
+	if len(times) != 3 || !times[0].Equal(at(0)) || !times[1].Equal(at(3)) || !times[2].Equal(at(5)) {
+		t.Errorf("equity times = %v, want hours 0, 3 and the end", times)
+	}
+	if res.Stats.Sharpe != 0 || res.Stats.Return != 0 || res.Stats.FillRate != 0 {
+		t.Errorf("stats of an idle run = %+v, want zeros", res.Stats)
+	}
+
+	if _, err := New(Config{Wallet: trader, SampleEvery: -time.Second}, events); err == nil {
+		t.Error("New() with a negative sampleEvery succeeded")
+	}
+	if _, err := New(Config{Wallet: "bad"}, events); err == nil {
+		t.Error("New() with an invalid wallet succeeded")
+	}
+	if _, err := New(Config{Wallet: trader}, []Event{{Time: at(0), Kind: KindTrade, CollId: tensortest.CollID}}); err == nil {
+		t.Error("New() with an invalid event succeeded")
+	}
+}
+
+func TestResample(t *testing.T) {
+	curve := []Point{
+		{Time: at(0), Equity: money.MustParseSOL("10")},
+		{Time: at(1), Equity: money.MustParseSOL("11")},
+		{Time: at(4), Equity: money.MustParseSOL("12")}, // Nothing was sampled at hours 2 and 3
+		{Time: at(4).Add(30 * time.Minute), Equity: money.MustParseSOL("9")},
+	}
+	tests := []struct {
+		name  string
+		every time.Duration
+		want  []string
+	}{
+		{"hourly", time.Hour, []string{"10", "11", "11", "11", "12"}},
+		{"two hours", 2 * time.Hour, []string{"10", "11", "12"}},
+		{"half hours", 30 * time.Minute, []string{"10", "10", "11", "11", "11", "11", "11", "11", "12", "9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, eq := range resample(curve, tt.every) {
+				got = append(got, eq.SOL())
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("resample() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := resample(nil, time.Hour); got != nil {
+		t.Errorf("resample(nil) = %v", got)
+	}
+}
+
+func TestSharpe(t *testing.T) {
+	tests := []struct {
+		name    string
+		returns []float64
+		every   time.Duration
+		want    float64
+	}{
+		{"daily", []float64{0.01, 0.03}, 24 * time.Hour, math.Sqrt2 * math.Sqrt(365)},
+		{"hourly", []float64{0.01, 0.03}, time.Hour, math.Sqrt2 * math.Sqrt(365*24)},
+		{"flat", []float64{0.01, 0.01, 0.01}, time.Hour, 0},
+		{"single", []float64{0.05}, time.Hour, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sharpe(tt.returns, tt.every); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("sharpe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package backtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/market"
+)
+
+// Kind identifies what an Event records
+type Kind string
+
+// Event kinds
+const (
+	KindCollection Kind = "collection" // A collection and its stats, from GetVerifiedCollections
+	KindListings   Kind = "listings"   // The cheapest listings of a collection, from GetNFTsByCollection
+	KindTrade      Kind = "trade"      // A completed transaction, from GetTransactions
+)
+
+// kindOrder breaks ties between events recorded at the same time
+var kindOrder = map[Kind]int{KindCollection: 0, KindListings: 1, KindTrade: 2}
+
+// Event is one recorded observation of the market. Exactly one of
+// Collection, Listings and Trade is set, matching Kind; Listings may be
+// empty when nothing was listed.
+type Event struct {
+	Time       time.Time                       `json:"time"`
+	Kind       Kind                            `json:"kind"`
+	CollId     string                          `json:"collId"`
+	Collection *collections.CollectionDetailed `json:"collection,omitempty"`
+	Listings   []market.NFT                    `json:"listings,omitempty"`
+	Trade      *market.Transaction             `json:"trade,omitempty"`
+}
+
+// validate checks that the event is complete
+func (e *Event) validate() error {
+	if e.Time.IsZero() || e.CollId == "" {
+		return fmt.Errorf("%s event needs a time and collId", e.Kind)
+	}
+	switch e.Kind {
+	case KindCollection:
+		if e.Collection == nil || e.Collection.CollId != e.CollId {
+			return fmt.Errorf("collection event for %s needs the collection", e.CollId)
+		}
+	case KindListings:
+		for _, n := range e.Listings {
+			if n.Listing == nil || n.CollId != e.CollId {
+				return fmt.Errorf("listings event for %s holds %s, which is not one of its listings", e.CollId, n.Mint)
+			}
+		}
+	case KindTrade:
+		if e.Trade == nil || e.Trade.CollId != e.CollId {
+			return fmt.Errorf("trade event for %s needs the transaction", e.CollId)
+		}
+	default:
+		return fmt.Errorf("unknown event kind %q", e.Kind)
+	}
+	return nil
+}
+
+// Sort orders events by time. Events at the same time keep their order
+// within a kind; collections come before listings and listings before trades.
+func Sort(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Time.Equal(events[j].Time) {
+			return events[i].Time.Before(events[j].Time)
+		}
+		return kindOrder[events[i].Kind] < kindOrder[events[j].Kind]
+	})
+}
+
+// WriteEvents writes events as JSON lines, one event per line
+func WriteEvents(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	for i := range events {
+		if err := enc.Encode(&events[i]); err != nil {
+			return fmt.Errorf("failed to write event %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ReadEvents reads events written by WriteEvents
+//
+// Returns:
+//   - The events, in file order
+//   - An error if a line is not a valid event
+func ReadEvents(r io.Reader) ([]Event, error) {
+	var out []Event
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := e.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		out = append(out, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
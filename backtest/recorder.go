@@ -0,0 +1,171 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/market"
+)
+
+// DefaultDepth is how many listings per collection a Recorder captures when RecorderConfig.Depth is zero
+const DefaultDepth = 100
+
+// SaleTxTypes are the transaction types a Recorder captures and a Runner
+// replays as sales. Other transaction types do not move NFTs between a
+// buyer and a seller at a price.
+var SaleTxTypes = []string{"SALE_BUY_NOW", "SALE_ACCEPT_BID", "SWAP_BUY_NFT", "SWAP_SELL_NFT", "SWAP_BUY_SINGLE_LISTING"}
+
+// RecorderConfig describes what a Recorder captures
+type RecorderConfig struct {
+	CollIds []string // Collections to record, at most 100
+	// Wallets whose sales in the recorded collections are captured. The
+	// transactions endpoint is per wallet, so trades are only recorded when
+	// this is set, typically to the collections' most active traders.
+	Wallets []string
+	Depth   int // Cheapest listings captured per collection; defaults to DefaultDepth, at most 250
+}
+
+// validate checks the config fields that have no default
+func (c *RecorderConfig) validate() error {
+	if len(c.CollIds) == 0 || len(c.CollIds) > 100 {
+		return fmt.Errorf("between 1 and 100 collIds are required")
+	}
+	if c.Depth < 0 || c.Depth > 250 {
+		return fmt.Errorf("depth must be between 0 and 250")
+	}
+	return nil
+}
+
+// APIs are the SDK services a Recorder reads. The fields match those of client.Client.
+type APIs struct {
+	Collections collections.CollectionsAPI // Collection stats
+	NFTs        nfts.NFTsAPI               // Listings
+	User        user.UserAPI               // Transactions
+}
+
+// Recorder captures snapshots of the market as events. Call Capture on a
+// schedule and append its events to a file with WriteEvents to build a
+// recording for a Runner.
+type Recorder struct {
+	cfg  RecorderConfig
+	apis APIs
+	now  func() time.Time
+
+	seen map[string]bool // Transaction IDs already captured
+}
+
+// NewRecorder creates a Recorder
+//
+// Returns:
+//   - The recorder
+//   - An error if the config is invalid or a required API is missing
+func NewRecorder(cfg RecorderConfig, apis APIs) (*Recorder, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if apis.Collections == nil || apis.NFTs == nil {
+		return nil, fmt.Errorf("collections and NFTs APIs are required")
+	}
+	if len(cfg.Wallets) > 0 && apis.User == nil {
+		return nil, fmt.Errorf("user API is required to record wallet trades")
+	}
+	if cfg.Depth == 0 {
+		cfg.Depth = DefaultDepth
+	}
+	return &Recorder{cfg: cfg, apis: apis, now: time.Now, seen: make(map[string]bool)}, nil
+}
+
+// Capture records one snapshot: every collection with its stats, each
+// collection's cheapest listings, and the configured wallets' sales not
+// captured by an earlier call. Collection and listing events are stamped
+// with the capture time and trades with their block time, so the events are
+// not in order; Sort them, or the whole recording, before replaying.
+//
+// Returns:
+//   - The captured events
+//   - An error if a read failed; nothing from this call is kept then
+func (r *Recorder) Capture(ctx context.Context) ([]Event, error) {
+	now := r.now().UTC()
+	data, _, err := r.apis.Collections.GetVerifiedCollections(ctx, &collections.GetVerifiedCollectionsRequest{
+		SortBy:  "statsV2.volume24h:desc",
+		Limit:   int32(len(r.cfg.CollIds)),
+		CollIds: r.cfg.CollIds,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collections: %w", err)
+	}
+	var resp collections.GetVerifiedCollectionsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode collections: %w", err)
+	}
+
+	var events []Event
+	for i := range resp.Collections {
+		c := resp.Collections[i]
+		events = append(events, Event{Time: now, Kind: KindCollection, CollId: c.CollId, Collection: &c})
+	}
+
+	onlyListings := true
+	for _, collID := range r.cfg.CollIds {
+		data, _, err := r.apis.NFTs.GetNFTsByCollection(ctx, &nfts.NFTsByCollectionRequest{
+			CollId:       collID,
+			SortBy:       "PriceAsc",
+			Limit:        int32(r.cfg.Depth),
+			OnlyListings: &onlyListings,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read listings of %s: %w", collID, err)
+		}
+		items, _, err := market.DecodeNFTs(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode listings of %s: %w", collID, err)
+		}
+		listings := make([]market.NFT, 0, len(items))
+		for _, n := range items {
+			if n.Listing != nil {
+				listings = append(listings, n)
+			}
+		}
+		events = append(events, Event{Time: now, Kind: KindListings, CollId: collID, Listings: listings})
+	}
+
+	if len(r.cfg.Wallets) == 0 {
+		return events, nil
+	}
+	captured := make(map[string]bool)
+	for _, collID := range r.cfg.CollIds {
+		data, _, err := r.apis.User.GetTransactions(ctx, &user.TransactionsRequest{
+			Wallets: r.cfg.Wallets,
+			Limit:   500,
+			TxTypes: SaleTxTypes,
+			Collid:  collID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transactions of %s: %w", collID, err)
+		}
+		txs, _, err := market.DecodeTransactions(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode transactions of %s: %w", collID, err)
+		}
+		for i := range txs {
+			tx := txs[i]
+			if tx.CollId == "" {
+				tx.CollId = collID
+			}
+			if r.seen[tx.TxId] || captured[tx.TxId] {
+				continue
+			}
+			captured[tx.TxId] = true
+			events = append(events, Event{Time: time.Unix(tx.BlockTime, 0).UTC(), Kind: KindTrade, CollId: tx.CollId, Trade: &tx})
+		}
+	}
+	for id := range captured {
+		r.seen[id] = true
+	}
+	return events, nil
+}
@@ -0,0 +1,372 @@
+package backtest
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/market"
+)
+
+var (
+	_ nfts.NFTsAPI               = (*replay)(nil)
+	_ collections.CollectionsAPI = (*replay)(nil)
+	_ user.UserAPI               = (*replay)(nil)
+)
+
+// replay answers market data queries from the events applied so far, so a
+// strategy only ever sees the market as it was at the replay clock. The
+// listings of a collection are those of its latest snapshot, minus the ones
+// bought since; only NFTs that appeared in a snapshot are known. Bids, pools,
+// escrow accounts and portfolios are not recorded and come back empty.
+type replay struct {
+	mu        sync.Mutex
+	now       time.Time
+	colls     map[string]collections.CollectionDetailed
+	collOrder []string
+	listings  map[string][]market.NFT // Latest snapshot by collection
+	nfts      map[string]market.NFT   // Every NFT seen, with its current listing and owner
+	trades    []market.Transaction    // Oldest first
+}
+
+func newReplay() *replay {
+	return &replay{
+		colls:    make(map[string]collections.CollectionDetailed),
+		listings: make(map[string][]market.NFT),
+		nfts:     make(map[string]market.NFT),
+	}
+}
+
+// clock returns the time of the last applied event
+func (r *replay) clock() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.now
+}
+
+// apply moves the replay to e
+func (r *replay) apply(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now = e.Time
+	switch e.Kind {
+	case KindCollection:
+		if _, ok := r.colls[e.CollId]; !ok {
+			r.collOrder = append(r.collOrder, e.CollId)
+		}
+		r.colls[e.CollId] = *e.Collection
+	case KindListings:
+		for _, n := range r.listings[e.CollId] {
+			r.delist(n.Mint)
+		}
+		r.listings[e.CollId] = slices.Clone(e.Listings)
+		for _, n := range e.Listings {
+			r.nfts[n.Mint] = n
+		}
+	case KindTrade:
+		tx := *e.Trade
+		r.trades = append(r.trades, tx)
+		r.listings[tx.CollId] = slices.DeleteFunc(r.listings[tx.CollId], func(n market.NFT) bool { return n.Mint == tx.Mint })
+		r.delist(tx.Mint)
+		if n, ok := r.nfts[tx.Mint]; ok && tx.Buyer != "" {
+			n.Owner = tx.Buyer
+			r.nfts[tx.Mint] = n
+		}
+	}
+}
+
+// delist clears the listing of a known NFT. Callers must hold r.mu.
+func (r *replay) delist(mint string) {
+	if n, ok := r.nfts[mint]; ok {
+		n.Listing = nil
+		r.nfts[mint] = n
+	}
+}
+
+// GetNFTsInfo returns the known NFTs among req.Mints as a bare array
+func (r *replay) GetNFTsInfo(ctx context.Context, req *nfts.NFTsInfoRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := []market.NFT{}
+	for _, mint := range req.Mints {
+		if n, ok := r.nfts[mint]; ok {
+			items = append(items, n)
+		}
+	}
+	return encode(items)
+}
+
+// GetNFTsByCollection returns a collection's current listings, and its known
+// unlisted NFTs unless OnlyListings is set, filtered like the live endpoint.
+// Trait, rarity and name filters are not applied.
+func (r *replay) GetNFTsByCollection(ctx context.Context, req *nfts.NFTsByCollectionRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var items []market.NFT
+	for _, n := range r.listings[req.CollId] {
+		if r.keep(req, n) {
+			items = append(items, n)
+		}
+	}
+	if (req.OnlyListings == nil || !*req.OnlyListings) && req.MinPrice == nil && req.MaxPrice == nil {
+		var unlisted []market.NFT
+		for _, n := range r.nfts {
+			if n.CollId == req.CollId && n.Listing == nil && r.keep(req, n) {
+				unlisted = append(unlisted, n)
+			}
+		}
+		slices.SortFunc(unlisted, func(a, b market.NFT) int { return cmp.Compare(a.Mint, b.Mint) })
+		items = append(items, unlisted...)
+	}
+	switch req.SortBy {
+	case "PriceAsc", "NormalizedPriceAsc":
+		sortByPrice(items, false)
+	case "PriceDesc", "NormalizedPriceDesc":
+		sortByPrice(items, true)
+	}
+	return paginate("mints", items, req.Cursor, int(req.Limit))
+}
+
+// keep applies the mint, owner and price filters of req to n
+func (r *replay) keep(req *nfts.NFTsByCollectionRequest, n market.NFT) bool {
+	owner := n.Owner
+	if n.Listing != nil && n.Listing.Seller != "" {
+		owner = n.Listing.Seller
+	}
+	if len(req.Mints) > 0 && !slices.Contains(req.Mints, n.Mint) {
+		return false
+	}
+	if len(req.IncludeOwners) > 0 && !slices.Contains(req.IncludeOwners, owner) {
+		return false
+	}
+	if slices.Contains(req.ExcludeOwners, owner) {
+		return false
+	}
+	if n.Listing != nil {
+		if req.MinPrice != nil && n.Listing.Price < *req.MinPrice {
+			return false
+		}
+		if req.MaxPrice != nil && n.Listing.Price > *req.MaxPrice {
+			return false
+		}
+	}
+	return true
+}
+
+// GetVerifiedCollections returns the latest recorded state of the requested collections
+func (r *replay) GetVerifiedCollections(ctx context.Context, req *collections.GetVerifiedCollectionsRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := []collections.CollectionDetailed{}
+	for _, id := range r.collOrder {
+		c := r.colls[id]
+		if len(req.CollIds) > 0 && !slices.Contains(req.CollIds, id) {
+			continue
+		}
+		if len(req.SlugDisplays) > 0 && !slices.Contains(req.SlugDisplays, c.SlugDisplay) {
+			continue
+		}
+		items = append(items, c)
+	}
+	page := int32(1)
+	if req.Page != nil {
+		page = *req.Page
+	}
+	start := min(int(page-1)*int(req.Limit), len(items))
+	end := min(start+int(req.Limit), len(items))
+	return encode(collections.GetVerifiedCollectionsResponse{Page: page, Total: int32(len(items)), Collections: items[start:end]})
+}
+
+// GetTransactions returns the replayed sales of the requested wallets, newest first
+func (r *replay) GetTransactions(ctx context.Context, req *user.TransactionsRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var items []market.Transaction
+	for i := len(r.trades) - 1; i >= 0; i-- {
+		tx := r.trades[i]
+		if !slices.Contains(req.Wallets, tx.Buyer) && !slices.Contains(req.Wallets, tx.Seller) {
+			continue
+		}
+		if len(req.TxTypes) > 0 && !slices.Contains(req.TxTypes, tx.TxType) {
+			continue
+		}
+		if req.Collid != "" && tx.CollId != req.Collid {
+			continue
+		}
+		items = append(items, tx)
+	}
+	return paginate("txs", items, req.Cursor, int(req.Limit))
+}
+
+// GetListings returns the current recorded listings sold by the requested wallets
+func (r *replay) GetListings(ctx context.Context, req *user.ListingsRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var items []market.NFT
+	for id, listings := range r.listings {
+		if req.CollId != nil && *req.CollId != "" && *req.CollId != id {
+			continue
+		}
+		for _, n := range listings {
+			if slices.Contains(req.Wallets, n.Listing.Seller) {
+				items = append(items, n)
+			}
+		}
+	}
+	slices.SortFunc(items, func(a, b market.NFT) int { return cmp.Compare(a.Mint, b.Mint) })
+	sortByPrice(items, req.SortBy == "PriceDesc")
+	return paginate("listings", items, req.Cursor, int(req.Limit))
+}
+
+// GetInventoryForCollection returns the known NFTs the requested wallets own
+func (r *replay) GetInventoryForCollection(ctx context.Context, req *user.InventoryForCollectionRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var items []market.NFT
+	for _, n := range r.nfts {
+		if slices.Contains(req.Wallets, n.Owner) && (req.CollId == nil || *req.CollId == "" || *req.CollId == n.CollId) {
+			items = append(items, n)
+		}
+	}
+	slices.SortFunc(items, func(a, b market.NFT) int { return cmp.Compare(a.Mint, b.Mint) })
+	limit := 0
+	if req.Limit != nil {
+		limit = int(*req.Limit)
+	}
+	return paginate("nfts", items, &req.Cursor, limit)
+}
+
+// GetPortfolio returns an empty portfolio; portfolios are not recorded
+func (r *replay) GetPortfolio(ctx context.Context, req *user.PortfolioRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return encode(user.PortfolioResponse{Collections: []user.Collection{}})
+}
+
+// GetNFTBids returns no bids; bids are not recorded
+func (r *replay) GetNFTBids(ctx context.Context, req *user.NFTBidsRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return paginate[market.Bid]("bids", nil, nil, 0)
+}
+
+// GetCollectionBids returns no bids; bids are not recorded
+func (r *replay) GetCollectionBids(ctx context.Context, req *user.CollectionBidsRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return paginate[market.Bid]("bids", nil, nil, 0)
+}
+
+// GetTraitBids returns no bids; bids are not recorded
+func (r *replay) GetTraitBids(ctx context.Context, req *user.TraitBidsRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return paginate[market.Bid]("bids", nil, nil, 0)
+}
+
+// GetTSwapPools returns no pools; pools are not recorded
+func (r *replay) GetTSwapPools(ctx context.Context, req *user.TSwapsPoolsRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return paginate[market.Pool]("pools", nil, nil, 0)
+}
+
+// GetTAmmPools returns no pools; pools are not recorded
+func (r *replay) GetTAmmPools(ctx context.Context, req *user.TAmmPoolsRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return paginate[market.Pool]("pools", nil, nil, 0)
+}
+
+// GetEscrowAccounts returns no accounts; escrow accounts are not recorded
+func (r *replay) GetEscrowAccounts(ctx context.Context, req *user.EscrowAccountsRequest) ([]byte, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return encode(map[string]any{"escrowAccounts": []market.EscrowAccount{}})
+}
+
+// sortByPrice orders listed NFTs by listing price, unlisted NFTs last
+func sortByPrice(items []market.NFT, desc bool) {
+	slices.SortStableFunc(items, func(a, b market.NFT) int {
+		switch {
+		case a.Listing == nil || b.Listing == nil:
+			return cmp.Compare(boolRank(a.Listing == nil), boolRank(b.Listing == nil))
+		case desc:
+			return cmp.Compare(b.Listing.Price, a.Listing.Price)
+		default:
+			return cmp.Compare(a.Listing.Price, b.Listing.Price)
+		}
+	})
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// paginate encodes one page of items under key. The cursor is the offset of
+// the next item; a limit of zero or less returns everything left.
+func paginate[T any](key string, items []T, cursor *string, limit int) ([]byte, int, error) {
+	offset := 0
+	if cursor != nil && *cursor != "" {
+		offset, _ = strconv.Atoi(*cursor)
+	}
+	offset = min(max(offset, 0), len(items))
+	end := len(items)
+	if limit > 0 {
+		end = min(offset+limit, len(items))
+	}
+	page := market.Page{HasMore: end < len(items)}
+	if page.HasMore {
+		page.EndCursor = strconv.Itoa(end)
+	}
+	out := items[offset:end]
+	if out == nil {
+		out = []T{}
+	}
+	return encode(map[string]any{key: out, "page": page})
+}
+
+// encode marshals v as a successful response body
+func encode(v any) ([]byte, int, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, http.StatusOK, nil
+}
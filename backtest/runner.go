@@ -0,0 +1,493 @@
+// Package backtest replays recorded market data through a strategy. A
+// Recorder captures collection stats, listings and sales with the SDK's own
+// GetVerifiedCollections, GetNFTsByCollection and GetTransactions calls; a
+// Runner replays them in time order behind a client.Client whose market data
+// APIs answer from the recording and whose trading APIs are a paper wallet.
+//
+// After each event the wallet's resting orders are matched: new listings
+// fill its bids and pool buy quotes, recorded sales take its listings and
+// pool sell quotes, and the strategy is stepped. Taker trades pay the taker
+// fee and each collection's recorded royalty. The Result holds the trade log,
+// an equity curve and summary statistics.
+package backtest
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/paper"
+	"github.com/srpvpn/tensor-go-sdk/pricing"
+)
+
+// DefaultSampleEvery is the equity curve spacing used when Config.SampleEvery is zero
+const DefaultSampleEvery = time.Hour
+
+// MaxErrors is how many step errors a Result keeps; the rest are only counted
+const MaxErrors = 100
+
+// Strategy is the code under test. Step is called with the replay time after
+// the market has moved; the strategy trades through Runner.Client.
+type Strategy interface {
+	Step(ctx context.Context, now time.Time) error
+}
+
+// StrategyFunc adapts a function to Strategy
+type StrategyFunc func(ctx context.Context, now time.Time) error
+
+// Step calls f
+func (f StrategyFunc) Step(ctx context.Context, now time.Time) error {
+	return f(ctx, now)
+}
+
+// Config configures a Runner
+type Config struct {
+	Wallet   string          // Address the strategy trades as
+	Balance  money.Lamports  // Starting SOL
+	Holdings []paper.Holding // Starting NFTs
+	Pools    []paper.Pool    // TSwap pools the strategy may use
+
+	// Fees are charged to the taker of every trade. The royalty of a
+	// collection comes from its recorded SellRoyaltyFeeBPS; Fees.RoyaltyBps
+	// only applies to collections without a collection event. Defaults to
+	// paper.DefaultFees.
+	Fees *pricing.Fees
+	// MatchDepth is how many listings per collection are matched against the wallet's orders
+	MatchDepth int
+
+	// StepEvery is the least replay time between two strategy steps. Zero
+	// steps the strategy after every event.
+	StepEvery time.Duration
+	// SampleEvery is the least replay time between two equity curve points.
+	// Defaults to DefaultSampleEvery.
+	SampleEvery time.Duration
+
+	// Logger receives fills and step errors. Nothing is logged when nil.
+	Logger *slog.Logger
+}
+
+// Point is one sample of the equity curve
+type Point struct {
+	Time   time.Time
+	Equity money.Lamports // Cash, locked SOL, pool SOL and holdings marked at the replayed floor
+}
+
+// StepError is a strategy or matching error, with the replay time it happened at
+type StepError struct {
+	Time time.Time
+	Err  error
+}
+
+// Stats summarizes a run
+type Stats struct {
+	Start  money.Lamports // Starting SOL plus the cost of the starting holdings
+	End    money.Lamports // Final equity
+	Return float64        // End over Start, minus one
+	// Sharpe is the annualized Sharpe ratio of the equity curve's returns
+	// over SampleEvery periods, with a zero risk free rate. Samples are taken
+	// at event times, so the curve is first resampled onto a grid SampleEvery
+	// apart, carrying each sample forward. It is zero when there are fewer
+	// than two returns or they do not vary.
+	Sharpe         float64
+	MaxDrawdown    float64        // Largest fall from a peak of the equity curve, as a fraction of the peak
+	MaxDrawdownSOL money.Lamports // The same fall in SOL
+
+	Trades   int            // Fills of every kind
+	Realized money.Lamports // Profit on NFTs sold
+	Fees     money.Lamports // Royalties and taker fees paid
+
+	Orders   int     // NFTs offered by listings and asked for by bids
+	Filled   int     // Listing and bid fills
+	FillRate float64 // Filled over Orders; zero without orders
+}
+
+// Result is the outcome of a run
+type Result struct {
+	Trades []paper.Fill // Trade log, oldest first
+	Equity []Point      // Equity curve, one point per SampleEvery and one at the end
+	Final  *paper.PnL   // Valuation at the end of the replay
+	Stats  Stats
+
+	Errors     []StepError // The first MaxErrors errors
+	ErrorCount int         // Every error, kept or not
+}
+
+// Runner replays a recording through a strategy
+type Runner struct {
+	cfg    Config
+	events []Event
+	replay *replay
+	client *client.Client
+	wallet *paper.Wallet
+	orders *orderCounter
+	logger *slog.Logger
+
+	mu  sync.Mutex
+	ran bool
+}
+
+// New creates a Runner over events, which are validated and sorted
+//
+// Returns:
+//   - The runner
+//   - An error if the config or an event is invalid
+func New(cfg Config, events []Event) (*Runner, error) {
+	if cfg.StepEvery < 0 || cfg.SampleEvery < 0 {
+		return nil, fmt.Errorf("stepEvery and sampleEvery must be >= 0")
+	}
+	if cfg.SampleEvery == 0 {
+		cfg.SampleEvery = DefaultSampleEvery
+	}
+	events = slices.Clone(events)
+	royalties := make(map[string]int64)
+	for i := range events {
+		if err := events[i].validate(); err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+	}
+	Sort(events)
+	for _, e := range events {
+		if _, ok := royalties[e.CollId]; !ok && e.Kind == KindCollection {
+			royalties[e.CollId] = int64(e.Collection.SellRoyaltyFeeBPS)
+		}
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	r := newReplay()
+	offline := client.New(&client.Config{
+		BaseURL:    client.DefaultBaseURL,
+		HTTPClient: &http.Client{Transport: offlineTransport{}},
+	})
+	offline.NFTs = r
+	offline.Collections = r
+	offline.User = r
+	c, w, err := paper.NewClient(offline, paper.Config{
+		Wallet:         cfg.Wallet,
+		Balance:        cfg.Balance,
+		Holdings:       cfg.Holdings,
+		Pools:          cfg.Pools,
+		Fees:           cfg.Fees,
+		Royalties:      royalties,
+		MatchDepth:     cfg.MatchDepth,
+		ObservedTrades: true,
+		Clock:          r.clock,
+		Logger:         cfg.Logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	orders := &orderCounter{MarketplaceAPI: c.Marketplace, wallet: w}
+	c.Marketplace = orders
+	return &Runner{cfg: cfg, events: events, replay: r, client: c, wallet: w, orders: orders, logger: logger}, nil
+}
+
+// Client returns the client the strategy trades through. Its NFTs,
+// Collections and User APIs answer from the replay, its Marketplace and
+// TSwap APIs from the paper wallet; every other API fails.
+func (r *Runner) Client() *client.Client {
+	return r.client
+}
+
+// Wallet returns the paper wallet the strategy trades with
+func (r *Runner) Wallet() *paper.Wallet {
+	return r.wallet
+}
+
+// Run replays every event through s. The wallet's orders are matched after
+// each event, and the strategy is stepped once all events recorded at the
+// same time have been applied. Errors returned by the strategy or by
+// matching are collected in the Result and the replay carries on. A Runner
+// can only run once.
+//
+// Returns:
+//   - The trade log, equity curve and statistics
+//   - An error if ctx was canceled, valuing the wallet failed or the runner already ran
+func (r *Runner) Run(ctx context.Context, s Strategy) (*Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ran {
+		return nil, fmt.Errorf("runner already ran")
+	}
+	r.ran = true
+
+	res := &Result{}
+	var lastStep, lastSample time.Time
+	first := true
+	for i, e := range r.events {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		r.replay.apply(e)
+		if e.Kind == KindTrade {
+			r.wallet.Observe(*e.Trade)
+		} else if _, err := r.wallet.Match(ctx); err != nil {
+			r.fail(res, e.Time, err)
+		}
+		// Events recorded at the same time form one snapshot; the strategy sees it whole
+		if i+1 < len(r.events) && r.events[i+1].Time.Equal(e.Time) {
+			continue
+		}
+		if first || !e.Time.Before(lastStep.Add(r.cfg.StepEvery)) {
+			if err := s.Step(ctx, e.Time); err != nil {
+				r.fail(res, e.Time, err)
+			}
+			lastStep = e.Time
+		}
+		if first || !e.Time.Before(lastSample.Add(r.cfg.SampleEvery)) {
+			if err := r.sample(ctx, res); err != nil {
+				return nil, err
+			}
+			lastSample = e.Time
+		}
+		first = false
+	}
+
+	final, err := r.wallet.PnL(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to value the wallet: %w", err)
+	}
+	if n := len(res.Equity); n == 0 || res.Equity[n-1].Time.Before(final.Time) {
+		res.Equity = append(res.Equity, Point{Time: final.Time, Equity: final.Equity})
+	} else {
+		res.Equity[n-1].Equity = final.Equity
+	}
+	res.Final = final
+	res.Trades = r.wallet.Fills()
+	res.Stats = summarize(final, res.Trades, res.Equity, r.orders.count(), r.cfg.SampleEvery)
+	return res, nil
+}
+
+// sample appends the current equity to the curve
+func (r *Runner) sample(ctx context.Context, res *Result) error {
+	pnl, err := r.wallet.PnL(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to value the wallet: %w", err)
+	}
+	res.Equity = append(res.Equity, Point{Time: pnl.Time, Equity: pnl.Equity})
+	return nil
+}
+
+// fail records a step error
+func (r *Runner) fail(res *Result, at time.Time, err error) {
+	r.logger.Warn("backtest step failed", "time", at, "error", err)
+	res.ErrorCount++
+	if len(res.Errors) < MaxErrors {
+		res.Errors = append(res.Errors, StepError{Time: at, Err: err})
+	}
+}
+
+// summarize computes the run statistics
+func summarize(final *paper.PnL, trades []paper.Fill, curve []Point, orders int, every time.Duration) Stats {
+	s := Stats{
+		Start:    final.Start,
+		End:      final.Equity,
+		Trades:   len(trades),
+		Realized: final.Realized,
+		Fees:     final.Fees,
+		Orders:   orders,
+	}
+	if s.Start > 0 {
+		s.Return = float64(s.End)/float64(s.Start) - 1
+	}
+	for _, f := range trades {
+		if f.Venue == paper.VenueListing || f.Venue == paper.VenueBid {
+			s.Filled++
+		}
+	}
+	if s.Orders > 0 {
+		s.FillRate = float64(s.Filled) / float64(s.Orders)
+	}
+
+	var peak money.Lamports
+	for _, p := range curve {
+		peak = max(peak, p.Equity)
+		if fall := peak - p.Equity; fall > s.MaxDrawdownSOL {
+			s.MaxDrawdownSOL = fall
+			s.MaxDrawdown = float64(fall) / float64(peak)
+		}
+	}
+	var returns []float64
+	grid := resample(curve, every)
+	for i := 1; i < len(grid); i++ {
+		if grid[i-1] > 0 {
+			returns = append(returns, float64(grid[i])/float64(grid[i-1])-1)
+		}
+	}
+	s.Sharpe = sharpe(returns, every)
+	return s
+}
+
+// resample returns the equity of curve at its first point and every period
+// after it up to its last point, each the equity of the latest sample at or
+// before that time
+func resample(curve []Point, every time.Duration) []money.Lamports {
+	if len(curve) == 0 {
+		return nil
+	}
+	var out []money.Lamports
+	end := curve[len(curve)-1].Time
+	j := 0
+	for t := curve[0].Time; !t.After(end); t = t.Add(every) {
+		for j+1 < len(curve) && !curve[j+1].Time.After(t) {
+			j++
+		}
+		out = append(out, curve[j].Equity)
+	}
+	return out
+}
+
+// sharpe annualizes the mean over the sample standard deviation of returns taken every period
+func sharpe(returns []float64, every time.Duration) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	std := math.Sqrt(variance / float64(len(returns)-1))
+	if std == 0 {
+		return 0
+	}
+	periods := float64(365*24*time.Hour) / float64(every)
+	return mean / std * math.Sqrt(periods)
+}
+
+// String summarizes the run, e.g. "12 trades, return 4.20%, sharpe 1.31, max drawdown 3.10% (0.4 SOL), fill rate 37.50% (3/8)"
+func (s Stats) String() string {
+	return fmt.Sprintf("%d trades, return %.2f%%, sharpe %.2f, max drawdown %.2f%% (%s), fill rate %.2f%% (%d/%d)",
+		s.Trades, s.Return*100, s.Sharpe, s.MaxDrawdown*100, s.MaxDrawdownSOL, s.FillRate*100, s.Filled, s.Orders)
+}
+
+// WriteTrades writes the trade log as CSV with a header row. Amounts are in SOL.
+func (r *Result) WriteTrades(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "side", "venue", "mint", "coll_id", "price", "fees", "amount", "order", "realized"}); err != nil {
+		return err
+	}
+	for _, f := range r.Trades {
+		if err := cw.Write([]string{
+			f.Time.UTC().Format(time.RFC3339), string(f.Side), string(f.Venue), f.Mint, f.CollId,
+			f.Price.SOL(), f.Fees.SOL(), f.Amount.SOL(), f.Order, f.Realized.SOL(),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteEquity writes the equity curve as CSV with a header row. Equity is in SOL.
+func (r *Result) WriteEquity(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "equity"}); err != nil {
+		return err
+	}
+	for _, p := range r.Equity {
+		if err := cw.Write([]string{p.Time.UTC().Format(time.RFC3339), p.Equity.SOL()}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// orderCounter counts the NFTs the strategy offers and asks for, the denominator of the fill rate
+type orderCounter struct {
+	marketplace.MarketplaceAPI
+	wallet *paper.Wallet
+
+	mu    sync.Mutex
+	units int
+}
+
+func (o *orderCounter) add(n int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.units += n
+}
+
+func (o *orderCounter) count() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.units
+}
+
+// ListNFT counts one order per listing
+func (o *orderCounter) ListNFT(ctx context.Context, req *marketplace.ListNFTRequest) (*marketplace.ListNFTResponse, int, error) {
+	resp, status, err := o.MarketplaceAPI.ListNFT(ctx, req)
+	if err == nil {
+		o.add(1)
+	}
+	return resp, status, err
+}
+
+// PlaceNFTBid counts one order per bid
+func (o *orderCounter) PlaceNFTBid(ctx context.Context, req *marketplace.PlaceNFTBidRequest) (*marketplace.PlaceNFTBidResponse, int, error) {
+	resp, status, err := o.MarketplaceAPI.PlaceNFTBid(ctx, req)
+	if err == nil {
+		o.add(1)
+	}
+	return resp, status, err
+}
+
+// PlaceTraitBid counts one order per NFT bid for
+func (o *orderCounter) PlaceTraitBid(ctx context.Context, req *marketplace.PlaceTraitBidRequest) (*marketplace.PlaceTraitBidResponse, int, error) {
+	resp, status, err := o.MarketplaceAPI.PlaceTraitBid(ctx, req)
+	if err == nil {
+		o.add(int(req.Quantity))
+	}
+	return resp, status, err
+}
+
+// PlaceCollectionBid counts one order per NFT bid for
+func (o *orderCounter) PlaceCollectionBid(ctx context.Context, req *marketplace.PlaceCollectionBidRequest) (*marketplace.PlaceCollectionBidResponse, int, error) {
+	resp, status, err := o.MarketplaceAPI.PlaceCollectionBid(ctx, req)
+	if err == nil {
+		o.add(int(req.Quantity))
+	}
+	return resp, status, err
+}
+
+// EditBid counts the NFTs a quantity increase adds
+func (o *orderCounter) EditBid(ctx context.Context, req *marketplace.EditBidRequest) (*marketplace.EditBidResponse, int, error) {
+	var before int32
+	if req != nil && req.Quantity != nil {
+		for _, b := range o.wallet.Bids() {
+			if b.Address == req.BidStateAddress {
+				before = b.Quantity
+			}
+		}
+	}
+	resp, status, err := o.MarketplaceAPI.EditBid(ctx, req)
+	if err == nil && req.Quantity != nil && *req.Quantity > before {
+		o.add(int(*req.Quantity - before))
+	}
+	return resp, status, err
+}
+
+// offlineTransport fails every request: a backtest must not reach the live API
+type offlineTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("backtest: %s %s is not replayed", req.Method, req.URL.Path)
+}
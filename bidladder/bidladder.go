@@ -0,0 +1,409 @@
+// Package bidladder keeps a wallet's collection and trait bids in line with a
+// target ladder of price levels. Plan reads the open bids and works out the
+// fewest place, edit and cancel operations that turn them into the ladder;
+// the resulting Diff can be previewed before Apply runs it. Plans are built
+// from the live bids every time, so running Plan and Apply again after a
+// partial failure picks up where the last run stopped, and a converged ladder
+// plans no operations.
+package bidladder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	apierrors "github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// Level is one rung of the ladder
+type Level struct {
+	Price    money.Lamports // Price per NFT
+	Quantity int32          // NFTs still to buy at this level
+	Traits   []string       // Traits of a trait bid; empty for a collection bid
+}
+
+// Ladder is the set of bids a wallet should have open on a collection
+type Ladder struct {
+	Owner        string
+	CollId       string
+	Levels       []Level
+	Expiry       time.Duration // Lifetime of placed and edited bids, 0 for bids that do not expire
+	Renew        time.Duration // Bids expiring within Renew are extended, 0 to never extend
+	SharedEscrow bool          // Fund the bids from the shared escrow account
+}
+
+// Validate checks the ladder fields
+func (l *Ladder) Validate() error {
+	if l.Owner == "" || l.CollId == "" {
+		return fmt.Errorf("owner and collId are required")
+	}
+	for i, level := range l.Levels {
+		if level.Price <= 0 || level.Quantity <= 0 {
+			return fmt.Errorf("level %d: price and quantity must be > 0", i)
+		}
+	}
+	if l.Expiry < 0 || l.Expiry.Seconds() > math.MaxInt32 {
+		return fmt.Errorf("expiry must be between 0 and %d seconds", math.MaxInt32)
+	}
+	if l.Renew < 0 || (l.Renew > 0 && l.Expiry == 0) {
+		return fmt.Errorf("renew must be >= 0 and requires an expiry")
+	}
+	return nil
+}
+
+// OpKind is the kind of an operation
+type OpKind string
+
+// Operation kinds, in the order Apply runs them
+const (
+	OpCancel OpKind = "cancel"
+	OpEdit   OpKind = "edit"
+	OpPlace  OpKind = "place"
+)
+
+// Op is one change to the open bids
+type Op struct {
+	Kind  OpKind
+	Bid   market.Bid // Existing bid, for edits and cancels
+	Level Level      // Target level, for places and edits
+}
+
+// Diff is the set of operations that converges the open bids on a ladder
+type Diff struct {
+	Ladder Ladder
+	Ops    []Op
+	Kept   []market.Bid // Bids that already match a level
+}
+
+// Empty reports whether the bids already match the ladder
+func (d *Diff) Empty() bool {
+	return len(d.Ops) == 0
+}
+
+// String renders the operations as an aligned text table for review
+func (d *Diff) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "op\tbid\ttraits\tprice\tquantity")
+	for _, op := range d.Ops {
+		switch op.Kind {
+		case OpPlace:
+			fmt.Fprintf(w, "%s\t-\t%s\t%s\t%d\n", op.Kind, traitsLabel(op.Level.Traits), op.Level.Price.SOL(), op.Level.Quantity)
+		case OpEdit:
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s -> %s\t%d -> %d\n", op.Kind, op.Bid.Address, traitsLabel(op.Bid.Traits),
+				op.Bid.Price.SOL(), op.Level.Price.SOL(), op.Bid.Remaining(), op.Level.Quantity)
+		case OpCancel:
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", op.Kind, op.Bid.Address, traitsLabel(op.Bid.Traits), op.Bid.Price.SOL(), op.Bid.Remaining())
+		}
+	}
+	w.Flush()
+	fmt.Fprintf(&b, "%d to place, %d to edit, %d to cancel, %d unchanged\n",
+		d.count(OpPlace), d.count(OpEdit), d.count(OpCancel), len(d.Kept))
+	return b.String()
+}
+
+// count returns the number of operations of kind
+func (d *Diff) count(kind OpKind) int {
+	n := 0
+	for _, op := range d.Ops {
+		if op.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Outcome is the result of running one operation
+type Outcome struct {
+	Op      Op
+	Txs     []marketplace.Transaction // Transactions of edits and cancels
+	Message string                    // Serialized transaction of places
+	Err     error
+}
+
+// APIs are the SDK services a Manager uses. The fields match those of client.Client.
+type APIs struct {
+	User        user.UserAPI
+	Marketplace marketplace.MarketplaceAPI
+}
+
+// Manager plans and applies bid ladders
+type Manager struct {
+	apis        APIs
+	blockhashes cluster.BlockhashSource
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+// Option configures a Manager
+type Option func(*Manager)
+
+// WithLogger sets the logger operations are reported to
+func WithLogger(logger *slog.Logger) Option {
+	return func(m *Manager) { m.logger = logger }
+}
+
+// WithClock sets the clock used to tell which bids have expired or need renewing
+func WithClock(now func() time.Time) Option {
+	return func(m *Manager) { m.now = now }
+}
+
+// New creates a Manager
+//
+// Returns:
+//   - The manager
+//   - An error if an API or the blockhash source is nil
+func New(apis APIs, blockhashes cluster.BlockhashSource, opts ...Option) (*Manager, error) {
+	if apis.User == nil || apis.Marketplace == nil {
+		return nil, fmt.Errorf("user and marketplace APIs are required")
+	}
+	if blockhashes == nil {
+		return nil, fmt.Errorf("blockhash source is required")
+	}
+	m := &Manager{
+		apis:        apis,
+		blockhashes: blockhashes,
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Plan reads the owner's open collection and trait bids on the ladder's
+// collection and works out the operations that turn them into the ladder
+//
+// Returns:
+//   - The diff
+//   - An error if the ladder is invalid or the bids could not be read
+func (m *Manager) Plan(ctx context.Context, ladder Ladder) (*Diff, error) {
+	if err := ladder.Validate(); err != nil {
+		return nil, err
+	}
+	var open []market.Bid
+	for _, target := range []string{market.BidTargetCollection, market.BidTargetTrait} {
+		bids, err := market.Bids(ctx, m.apis.User, target, ladder.Owner, ladder.CollId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s bids: %w", strings.ToLower(target), err)
+		}
+		open = append(open, bids...)
+	}
+	return diff(ladder, open, m.now()), nil
+}
+
+// diff matches open bids to levels. Within each trait set, bids that match a
+// level exactly are kept, the rest are paired with the remaining levels by
+// price rank and edited, and whatever is left over is placed or cancelled.
+// Expired bids that were not filled still hold their funds, so they are
+// cancelled rather than matched.
+func diff(ladder Ladder, open []market.Bid, now time.Time) *Diff {
+	d := &Diff{Ladder: ladder}
+	bidsByKey := make(map[string][]market.Bid)
+	levelsByKey := make(map[string][]Level)
+	var keys []string
+	addKey := func(key string) {
+		if _, ok := bidsByKey[key]; !ok {
+			if _, ok := levelsByKey[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	var cancels, edits, places []Op
+	for _, b := range open {
+		if b.CollId != ladder.CollId || b.Remaining() == 0 {
+			continue
+		}
+		if b.ExpiresAt != 0 && b.ExpiresAt <= now.Unix() {
+			cancels = append(cancels, Op{Kind: OpCancel, Bid: b})
+			continue
+		}
+		key := traitsKey(b.Traits)
+		addKey(key)
+		bidsByKey[key] = append(bidsByKey[key], b)
+	}
+	for _, level := range ladder.Levels {
+		key := traitsKey(level.Traits)
+		addKey(key)
+		levelsByKey[key] = append(levelsByKey[key], level)
+	}
+
+	for _, key := range keys {
+		bids, levels := bidsByKey[key], levelsByKey[key]
+		var unmatched []market.Bid
+		for _, b := range bids {
+			i := slices.IndexFunc(levels, func(l Level) bool { return matches(ladder, b, l) })
+			if i < 0 {
+				unmatched = append(unmatched, b)
+				continue
+			}
+			if ladder.Renew > 0 && b.ExpiresAt != 0 && time.Unix(b.ExpiresAt, 0).Sub(now) < ladder.Renew {
+				edits = append(edits, Op{Kind: OpEdit, Bid: b, Level: levels[i]})
+			} else {
+				d.Kept = append(d.Kept, b)
+			}
+			levels = slices.Delete(slices.Clone(levels), i, i+1)
+		}
+
+		sort.SliceStable(unmatched, func(i, j int) bool { return unmatched[i].Price > unmatched[j].Price })
+		levels = slices.Clone(levels)
+		sort.SliceStable(levels, func(i, j int) bool { return levels[i].Price > levels[j].Price })
+		for i := 0; i < len(unmatched) || i < len(levels); i++ {
+			switch {
+			case i >= len(levels):
+				cancels = append(cancels, Op{Kind: OpCancel, Bid: unmatched[i]})
+			case i >= len(unmatched):
+				places = append(places, Op{Kind: OpPlace, Level: levels[i]})
+			default:
+				edits = append(edits, Op{Kind: OpEdit, Bid: unmatched[i], Level: levels[i]})
+			}
+		}
+	}
+	d.Ops = append(append(cancels, edits...), places...)
+	return d
+}
+
+// matches reports whether bid b already implements level l
+func matches(ladder Ladder, b market.Bid, l Level) bool {
+	return b.Price == l.Price && b.Remaining() == l.Quantity && b.SharedEscrow == ladder.SharedEscrow
+}
+
+// traitsKey identifies a set of traits regardless of order
+func traitsKey(traits []string) string {
+	sorted := slices.Clone(traits)
+	slices.Sort(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// traitsLabel describes the traits of a level for display
+func traitsLabel(traits []string) string {
+	if len(traits) == 0 {
+		return "collection"
+	}
+	return traitsKey(traits)
+}
+
+// Apply runs the operations of d: cancels first to free escrow, then edits,
+// then places. Every operation is attempted; a cancel of a bid that no longer
+// exists counts as done.
+//
+// Returns:
+//   - One outcome per operation, in run order
+//   - An error joining the failed operations' errors, or the blockhash error
+func (m *Manager) Apply(ctx context.Context, d *Diff) ([]Outcome, error) {
+	if d.Empty() {
+		return nil, nil
+	}
+	blockhash, _, err := m.blockhashes.LatestBlockhash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blockhash: %w", err)
+	}
+	outcomes := make([]Outcome, 0, len(d.Ops))
+	var errs []error
+	for _, op := range d.Ops {
+		if err := ctx.Err(); err != nil {
+			return outcomes, err
+		}
+		out := m.run(ctx, d.Ladder, op, blockhash)
+		if out.Err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", op.Kind, describe(op), out.Err))
+			m.logger.Warn("bid ladder operation failed", "op", op.Kind, "target", describe(op), "error", out.Err)
+		} else {
+			m.logger.Info("bid ladder operation", "op", op.Kind, "target", describe(op), "price", op.Level.Price.SOL())
+		}
+		outcomes = append(outcomes, out)
+	}
+	return outcomes, errors.Join(errs...)
+}
+
+// describe names the bid or level an operation acts on
+func describe(op Op) string {
+	if op.Kind == OpPlace {
+		return traitsLabel(op.Level.Traits)
+	}
+	return op.Bid.Address
+}
+
+// run builds the transaction of one operation
+func (m *Manager) run(ctx context.Context, ladder Ladder, op Op, blockhash string) Outcome {
+	out := Outcome{Op: op}
+	var expireIn *int32
+	if ladder.Expiry > 0 {
+		secs := int32(ladder.Expiry / time.Second)
+		expireIn = &secs
+	}
+	shared := ladder.SharedEscrow
+
+	switch op.Kind {
+	case OpCancel:
+		resp, _, err := m.apis.Marketplace.CancelBid(ctx, &marketplace.CancelBidRequest{BidStateAddress: op.Bid.Address, Blockhash: blockhash})
+		switch {
+		case notFound(err):
+		case err != nil:
+			out.Err = err
+		default:
+			out.Txs = resp.Txs
+		}
+	case OpEdit:
+		req := &marketplace.EditBidRequest{BidStateAddress: op.Bid.Address, Blockhash: blockhash, ExpireIn: expireIn}
+		if op.Bid.Price != op.Level.Price {
+			req.Price = &op.Level.Price
+		}
+		if op.Bid.Remaining() != op.Level.Quantity {
+			quantity := op.Bid.FilledQuantity + op.Level.Quantity
+			req.Quantity = &quantity
+		}
+		if op.Bid.SharedEscrow != shared {
+			req.UseSharedEscrow = &shared
+		}
+		resp, _, err := m.apis.Marketplace.EditBid(ctx, req)
+		if err != nil {
+			out.Err = err
+		} else {
+			out.Txs = resp.Txs
+		}
+	case OpPlace:
+		var err error
+		if len(op.Level.Traits) == 0 {
+			var resp *marketplace.PlaceCollectionBidResponse
+			resp, _, err = m.apis.Marketplace.PlaceCollectionBid(ctx, &marketplace.PlaceCollectionBidRequest{
+				Owner: ladder.Owner, Price: op.Level.Price, Quantity: op.Level.Quantity, CollId: ladder.CollId,
+				Blockhash: blockhash, UseSharedEscrow: &shared, ExpireIn: expireIn,
+			})
+			if err == nil {
+				out.Message = resp.Message
+			}
+		} else {
+			var resp *marketplace.PlaceTraitBidResponse
+			resp, _, err = m.apis.Marketplace.PlaceTraitBid(ctx, &marketplace.PlaceTraitBidRequest{
+				Owner: ladder.Owner, Price: op.Level.Price, Quantity: op.Level.Quantity, CollId: ladder.CollId,
+				Blockhash: blockhash, Traits: op.Level.Traits, UseSharedEscrow: &shared, ExpireIn: expireIn,
+			})
+			if err == nil {
+				out.Message = resp.Message
+			}
+		}
+		out.Err = err
+	}
+	return out
+}
+
+// notFound reports whether err says the target of the request does not exist
+func notFound(err error) bool {
+	var apiErr *apierrors.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == 404
+}
@@ -0,0 +1,301 @@
+package bidladder
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/tensortest"
+)
+
+var (
+	alice = tensortest.Address("alice")
+	bob   = tensortest.Address("bob")
+	now   = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+)
+
+func newTestManager(t *testing.T) (*tensortest.Server, *client.Client, *Manager) {
+	t.Helper()
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.SetClock(func() time.Time { return now })
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID, Name: "Ladder"})
+	c := client.New(&client.Config{BaseURL: srv.URL})
+	m, err := New(APIs{User: c.User, Marketplace: c.Marketplace}, cluster.StaticBlockhash("hash"), WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return srv, c, m
+}
+
+func addBid(srv *tensortest.Server, price string, quantity, filled int32, traits ...string) string {
+	target := tensortest.BidTargetCollection
+	if len(traits) > 0 {
+		target = tensortest.BidTargetTrait
+	}
+	return srv.AddBid(tensortest.Bid{Owner: alice, Target: target, TargetId: tensortest.CollID, CollId: tensortest.CollID,
+		Price: tensortest.Lamports(price), Quantity: quantity, FilledQuantity: filled, Traits: traits})
+}
+
+func kinds(d *Diff) string {
+	var out []string
+	for _, op := range d.Ops {
+		out = append(out, string(op.Kind))
+	}
+	return strings.Join(out, ",")
+}
+
+// converge applies the plan for ladder and checks that planning again finds nothing to do
+func converge(t *testing.T, m *Manager, ladder Ladder) *Diff {
+	t.Helper()
+	ctx := context.Background()
+	d, err := m.Plan(ctx, ladder)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if _, err := m.Apply(ctx, d); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	again, err := m.Plan(ctx, ladder)
+	if err != nil {
+		t.Fatalf("Plan() after Apply error = %v", err)
+	}
+	if !again.Empty() {
+		t.Fatalf("Plan() after Apply =\n%s", again)
+	}
+	return d
+}
+
+func TestPlaceLadder(t *testing.T) {
+	srv, _, m := newTestManager(t)
+	ladder := Ladder{
+		Owner:  alice,
+		CollId: tensortest.CollID,
+		Levels: []Level{
+			{Price: money.MustParseSOL("1"), Quantity: 2},
+			{Price: money.MustParseSOL("0.9"), Quantity: 3},
+			{Price: money.MustParseSOL("1.2"), Quantity: 1, Traits: []string{"Hat:Cap"}},
+		},
+		Expiry:       24 * time.Hour,
+		SharedEscrow: true,
+	}
+	d := converge(t, m, ladder)
+	if got := kinds(d); got != "place,place,place" {
+		t.Errorf("ops = %s, want three places", got)
+	}
+
+	bids := srv.Bids(alice)
+	if len(bids) != 3 {
+		t.Fatalf("server has %d bids, want 3", len(bids))
+	}
+	for _, b := range bids {
+		if !b.SharedEscrow || b.ExpiresAt != now.Add(24*time.Hour).Unix() {
+			t.Errorf("bid %+v, want shared escrow expiring in a day", b)
+		}
+		if b.Target == tensortest.BidTargetTrait && (b.Price != tensortest.Lamports("1.2") || len(b.Traits) != 1) {
+			t.Errorf("trait bid = %+v, want 1.2 SOL on Hat:Cap", b)
+		}
+	}
+}
+
+func TestConvergeExistingBids(t *testing.T) {
+	srv, _, m := newTestManager(t)
+	keep := addBid(srv, "1", 2, 0)
+	reprice := addBid(srv, "0.8", 5, 2)
+	extra := addBid(srv, "0.5", 1, 0)
+	wrongTraits := addBid(srv, "1.1", 1, 0, "Eyes:Laser")
+	srv.AddBid(tensortest.Bid{Owner: bob, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: "1"})
+	srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetCollection, TargetId: "other", CollId: "other", Price: "1"})
+
+	ladder := Ladder{Owner: alice, CollId: tensortest.CollID, Levels: []Level{
+		{Price: money.MustParseSOL("1"), Quantity: 2},
+		{Price: money.MustParseSOL("0.9"), Quantity: 3},
+		{Price: money.MustParseSOL("1.2"), Quantity: 1, Traits: []string{"Hat:Cap", "Body:Red"}},
+	}}
+	d := converge(t, m, ladder)
+
+	if got := kinds(d); got != "cancel,cancel,edit,place" {
+		t.Errorf("ops = %s, want cancel,cancel,edit,place", got)
+	}
+	if len(d.Kept) != 1 || d.Kept[0].Address != keep {
+		t.Errorf("kept = %+v, want %s", d.Kept, keep)
+	}
+	for _, addr := range []string{extra, wrongTraits} {
+		if _, ok := srv.Bid(addr); ok {
+			t.Errorf("bid %s was not cancelled", addr)
+		}
+	}
+	b, _ := srv.Bid(reprice)
+	if b.Price != tensortest.Lamports("0.9") || b.Quantity != 5 {
+		t.Errorf("repriced bid = %+v, want 0.9 SOL with 3 of 5 open", b)
+	}
+	if n := len(srv.Bids(bob)); n != 1 {
+		t.Errorf("bob has %d bids, want 1", n)
+	}
+}
+
+func TestPlanPairsByPriceRank(t *testing.T) {
+	open := []market.Bid{
+		{Address: "low", CollId: tensortest.CollID, Price: money.MustParseSOL("0.5"), Quantity: 1},
+		{Address: "high", CollId: tensortest.CollID, Price: money.MustParseSOL("2"), Quantity: 1},
+		{Address: "filled", CollId: tensortest.CollID, Price: money.MustParseSOL("3"), Quantity: 1, FilledQuantity: 1},
+		{Address: "expired", CollId: tensortest.CollID, Price: money.MustParseSOL("3"), Quantity: 1, ExpiresAt: now.Unix()},
+	}
+	ladder := Ladder{Owner: alice, CollId: tensortest.CollID, Levels: []Level{
+		{Price: money.MustParseSOL("0.6"), Quantity: 1},
+		{Price: money.MustParseSOL("1.9"), Quantity: 1},
+	}}
+	d := diff(ladder, open, now)
+	if got := kinds(d); got != "cancel,edit,edit" {
+		t.Fatalf("ops = %s, want the expired cancel and two edits", got)
+	}
+	pairs := map[string]money.Lamports{}
+	for _, op := range d.Ops[1:] {
+		pairs[op.Bid.Address] = op.Level.Price
+	}
+	if pairs["high"] != money.MustParseSOL("1.9") || pairs["low"] != money.MustParseSOL("0.6") {
+		t.Errorf("pairs = %v, want high->1.9, low->0.6", pairs)
+	}
+}
+
+func TestPlanCancelsExpiredBids(t *testing.T) {
+	open := []market.Bid{
+		{Address: "expired", CollId: tensortest.CollID, Price: money.MustParseSOL("1"), Quantity: 2, FilledQuantity: 1, ExpiresAt: now.Add(-time.Minute).Unix()},
+		{Address: "expired-filled", CollId: tensortest.CollID, Price: money.MustParseSOL("1"), Quantity: 1, FilledQuantity: 1, ExpiresAt: now.Add(-time.Minute).Unix()},
+		{Address: "other-collection", CollId: "other", Price: money.MustParseSOL("1"), Quantity: 1, ExpiresAt: now.Add(-time.Minute).Unix()},
+	}
+	// The expired bid matches the level but can no longer buy, so the level is placed again.
+	ladder := Ladder{Owner: alice, CollId: tensortest.CollID, Levels: []Level{{Price: money.MustParseSOL("1"), Quantity: 1}}}
+	d := diff(ladder, open, now)
+	if got := kinds(d); got != "cancel,place" {
+		t.Fatalf("ops = %s, want cancel,place", got)
+	}
+	if d.Ops[0].Bid.Address != "expired" || len(d.Kept) != 0 {
+		t.Errorf("cancelled %s and kept %v, want the expired bid cancelled and nothing kept", d.Ops[0].Bid.Address, d.Kept)
+	}
+}
+
+func TestRenew(t *testing.T) {
+	srv, _, m := newTestManager(t)
+	soon := srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID,
+		Price: tensortest.Lamports("1"), Quantity: 1, ExpiresAt: now.Add(time.Hour).Unix()})
+	later := srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID,
+		Price: tensortest.Lamports("1"), Quantity: 1, ExpiresAt: now.Add(20 * time.Hour).Unix()})
+
+	ladder := Ladder{Owner: alice, CollId: tensortest.CollID, Expiry: 24 * time.Hour, Renew: 6 * time.Hour, Levels: []Level{
+		{Price: money.MustParseSOL("1"), Quantity: 1},
+		{Price: money.MustParseSOL("1"), Quantity: 1},
+	}}
+	d := converge(t, m, ladder)
+	if len(d.Ops) != 1 || d.Ops[0].Kind != OpEdit || d.Ops[0].Bid.Address != soon {
+		t.Fatalf("ops =\n%s\nwant one edit of %s", d, soon)
+	}
+	if b, _ := srv.Bid(soon); b.ExpiresAt != now.Add(24*time.Hour).Unix() {
+		t.Errorf("renewed bid expires at %d, want a day from now", b.ExpiresAt)
+	}
+	if b, _ := srv.Bid(later); b.ExpiresAt != now.Add(20*time.Hour).Unix() {
+		t.Errorf("bid %s was renewed", later)
+	}
+}
+
+func TestApplyIsIdempotent(t *testing.T) {
+	srv, c, m := newTestManager(t)
+	ctx := context.Background()
+	gone := addBid(srv, "0.5", 1, 0)
+	ladder := Ladder{Owner: alice, CollId: tensortest.CollID, Levels: []Level{{Price: money.MustParseSOL("1"), Quantity: 1}, {Price: money.MustParseSOL("2"), Quantity: 1}}}
+
+	d, err := m.Plan(ctx, ladder)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if got := kinds(d); got != "edit,place" {
+		t.Fatalf("ops = %s, want edit,place", got)
+	}
+	// The bid disappears between planning and applying, so the edit fails but the place goes through.
+	if _, _, err := c.Marketplace.CancelBid(ctx, &marketplace.CancelBidRequest{BidStateAddress: gone, Blockhash: "hash"}); err != nil {
+		t.Fatalf("CancelBid() error = %v", err)
+	}
+	outcomes, err := m.Apply(ctx, d)
+	if err == nil || !strings.Contains(err.Error(), "edit "+gone) {
+		t.Errorf("Apply() error = %v, want failed edit of %s", err, gone)
+	}
+	if len(outcomes) != 2 || outcomes[1].Err != nil || outcomes[1].Message == "" {
+		t.Errorf("outcomes = %+v, want the place to succeed", outcomes)
+	}
+
+	d = converge(t, m, ladder)
+	if got := kinds(d); got != "place" {
+		t.Errorf("ops on rerun = %s, want place", got)
+	}
+	if n := len(srv.Bids(alice)); n != 2 {
+		t.Errorf("alice has %d bids, want 2", n)
+	}
+}
+
+func TestCancelOfMissingBidSucceeds(t *testing.T) {
+	srv, c, m := newTestManager(t)
+	ctx := context.Background()
+	gone := addBid(srv, "0.5", 1, 0)
+	d, err := m.Plan(ctx, Ladder{Owner: alice, CollId: tensortest.CollID})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if _, _, err := c.Marketplace.CancelBid(ctx, &marketplace.CancelBidRequest{BidStateAddress: gone, Blockhash: "hash"}); err != nil {
+		t.Fatalf("CancelBid() error = %v", err)
+	}
+	if outcomes, err := m.Apply(ctx, d); err != nil || len(outcomes) != 1 {
+		t.Errorf("Apply() = %+v, %v; want the cancel to count as done", outcomes, err)
+	}
+}
+
+func TestDiffString(t *testing.T) {
+	d := &Diff{
+		Ops: []Op{
+			{Kind: OpCancel, Bid: market.Bid{Address: "b1", Price: money.MustParseSOL("0.5"), Quantity: 1}},
+			{Kind: OpEdit, Bid: market.Bid{Address: "b2", Price: money.MustParseSOL("0.8"), Quantity: 5, FilledQuantity: 2}, Level: Level{Price: money.MustParseSOL("0.9"), Quantity: 3}},
+			{Kind: OpPlace, Level: Level{Price: money.MustParseSOL("1.2"), Quantity: 1, Traits: []string{"Hat:Cap", "Body:Red"}}},
+		},
+		Kept: []market.Bid{{Address: "b3"}},
+	}
+	out := d.String()
+	for _, want := range []string{"0.8 -> 0.9", "Body:Red,Hat:Cap", "1 to place, 1 to edit, 1 to cancel, 1 unchanged"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("String() missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ladder  Ladder
+		wantErr string
+	}{
+		{name: "no owner", ladder: Ladder{CollId: tensortest.CollID}, wantErr: "owner and collId"},
+		{name: "zero price", ladder: Ladder{Owner: alice, CollId: tensortest.CollID, Levels: []Level{{Quantity: 1}}}, wantErr: "level 0"},
+		{name: "zero quantity", ladder: Ladder{Owner: alice, CollId: tensortest.CollID, Levels: []Level{{Price: 1}}}, wantErr: "level 0"},
+		{name: "negative expiry", ladder: Ladder{Owner: alice, CollId: tensortest.CollID, Expiry: -time.Second}, wantErr: "expiry"},
+		{name: "renew without expiry", ladder: Ladder{Owner: alice, CollId: tensortest.CollID, Renew: time.Hour}, wantErr: "renew"},
+		{name: "valid", ladder: Ladder{Owner: alice, CollId: tensortest.CollID, Levels: []Level{{Price: 1, Quantity: 1}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ladder.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,429 @@
+// Package bulk lists and delists many NFTs of one wallet at once. The NFTs
+// are either given as mints or taken from the wallet's inventory of a
+// collection. Listing prices come from a PriceRule: a fixed price, an offset
+// from the collection floor, or rarity tiers. Transactions are built with
+// bounded concurrency and a minimum interval between requests so large
+// batches stay under the API's rate limits, and every mint gets a result.
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// DefaultConcurrency is the number of transactions built at once when Pacing.Concurrency is zero
+const DefaultConcurrency = 4
+
+// Target selects the NFTs of a batch: the given Mints, or when Mints is
+// empty, everything Wallet holds in collection CollId
+type Target struct {
+	Wallet string
+	Mints  []string
+	CollId string
+}
+
+// Pacing bounds how fast a batch calls the API
+type Pacing struct {
+	Concurrency int           // Most requests in flight at once
+	Interval    time.Duration // Least time between the starts of two requests
+}
+
+// ListRequest describes a bulk listing
+type ListRequest struct {
+	Target
+	Price PriceRule
+	Pacing
+}
+
+// DelistRequest describes a bulk delisting
+type DelistRequest struct {
+	Target
+	Pacing
+}
+
+// Status is the outcome for one mint
+type Status string
+
+// Outcomes
+const (
+	StatusSuccess Status = "success" // The transaction was built
+	StatusSkipped Status = "skipped" // Nothing to do, e.g. the NFT is already listed
+	StatusError   Status = "error"   // The NFT could not be priced or the transaction could not be built
+)
+
+// Result is the outcome for one mint
+type Result struct {
+	Mint   string
+	Name   string
+	Price  money.Lamports // Listing price; zero for delistings
+	Status Status
+	Reason string // Why the mint was skipped or failed
+	Txs    []marketplace.Transaction
+	Err    error
+}
+
+// Report holds one result per mint, in target order
+type Report struct {
+	Results []Result
+}
+
+// Counts returns the number of results with each status
+func (r *Report) Counts() (success, skipped, failed int) {
+	for _, res := range r.Results {
+		switch res.Status {
+		case StatusSuccess:
+			success++
+		case StatusSkipped:
+			skipped++
+		case StatusError:
+			failed++
+		}
+	}
+	return success, skipped, failed
+}
+
+// String renders the results as an aligned text table
+func (r *Report) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "mint\tname\tstatus\tprice\tdetail")
+	for _, res := range r.Results {
+		price := "-"
+		if res.Price > 0 {
+			price = res.Price.SOL()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", res.Mint, res.Name, res.Status, price, res.Reason)
+	}
+	w.Flush()
+	success, skipped, failed := r.Counts()
+	fmt.Fprintf(&b, "%d succeeded, %d skipped, %d failed\n", success, skipped, failed)
+	return b.String()
+}
+
+// APIs are the SDK services a Lister uses. The fields match those of client.Client.
+type APIs struct {
+	User        user.UserAPI // Reads collection inventories
+	NFTs        nfts.NFTsAPI // Reads mints and floors
+	Marketplace marketplace.MarketplaceAPI
+}
+
+// Lister builds bulk listing and delisting transactions
+type Lister struct {
+	apis        APIs
+	blockhashes cluster.BlockhashSource
+}
+
+// New creates a Lister
+//
+// Returns:
+//   - The lister
+//   - An error if an API or the blockhash source is nil
+func New(apis APIs, blockhashes cluster.BlockhashSource) (*Lister, error) {
+	if apis.User == nil || apis.NFTs == nil || apis.Marketplace == nil {
+		return nil, fmt.Errorf("user, NFTs and marketplace APIs are required")
+	}
+	if blockhashes == nil {
+		return nil, fmt.Errorf("blockhash source is required")
+	}
+	return &Lister{apis: apis, blockhashes: blockhashes}, nil
+}
+
+// Validate checks the target fields
+func (t *Target) Validate() error {
+	if t.Wallet == "" {
+		return fmt.Errorf("wallet is required")
+	}
+	if len(t.Mints) == 0 && t.CollId == "" {
+		return fmt.Errorf("mints or collId is required")
+	}
+	return nil
+}
+
+// Validate checks the pacing fields
+func (p *Pacing) Validate() error {
+	if p.Concurrency < 0 || p.Interval < 0 {
+		return fmt.Errorf("concurrency and interval must be >= 0")
+	}
+	return nil
+}
+
+// List builds a ListNFT transaction for every unlisted NFT of the target.
+// NFTs that are already listed are skipped; NFTs the rule cannot price fail
+// without affecting the rest.
+//
+// Returns:
+//   - One result per mint
+//   - An error if the request is invalid or the NFTs could not be read
+func (l *Lister) List(ctx context.Context, req ListRequest) (*Report, error) {
+	if err := req.Target.Validate(); err != nil {
+		return nil, err
+	}
+	if err := req.Pacing.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Price == nil {
+		return nil, fmt.Errorf("price rule is required")
+	}
+	floors := &floorCache{nfts: l.apis.NFTs, wallet: req.Wallet, floors: make(map[string]*floorRead)}
+
+	return l.run(ctx, req.Target, req.Pacing, func(ctx context.Context, n market.NFT, blockhash string) Result {
+		res := Result{Mint: n.Mint, Name: n.Name}
+		if n.Listed() {
+			res.Status, res.Reason, res.Price = StatusSkipped, "already listed", n.Listing.Price
+			return res
+		}
+		var floorErr error
+		price, err := req.Price(n, func() (money.Lamports, error) {
+			floor, err := floors.get(ctx, n.CollId)
+			floorErr = err
+			return floor, err
+		})
+		if floorErr != nil {
+			return failed(res, "floor unavailable", floorErr)
+		}
+		if err != nil {
+			return failed(res, "no price", err)
+		}
+		if price <= 0 {
+			return failed(res, "no price", fmt.Errorf("price rule returned %s", price))
+		}
+		res.Price = price
+		resp, _, err := l.apis.Marketplace.ListNFT(ctx, &marketplace.ListNFTRequest{
+			Mint:      n.Mint,
+			Owner:     req.Wallet,
+			Price:     price,
+			Blockhash: blockhash,
+		})
+		if err != nil {
+			return failed(res, "list failed", err)
+		}
+		res.Status, res.Txs = StatusSuccess, resp.Txs
+		return res
+	})
+}
+
+// Delist builds a DelistNFT transaction for every listed NFT of the target.
+// NFTs that are not listed are skipped.
+//
+// Returns:
+//   - One result per mint
+//   - An error if the request is invalid or the NFTs could not be read
+func (l *Lister) Delist(ctx context.Context, req DelistRequest) (*Report, error) {
+	if err := req.Target.Validate(); err != nil {
+		return nil, err
+	}
+	if err := req.Pacing.Validate(); err != nil {
+		return nil, err
+	}
+	return l.run(ctx, req.Target, req.Pacing, func(ctx context.Context, n market.NFT, blockhash string) Result {
+		res := Result{Mint: n.Mint, Name: n.Name}
+		if !n.Listed() {
+			res.Status, res.Reason = StatusSkipped, "not listed"
+			return res
+		}
+		resp, _, err := l.apis.Marketplace.DelistNFT(ctx, &marketplace.DelistNFTRequest{
+			Mint:      n.Mint,
+			Owner:     req.Wallet,
+			Blockhash: blockhash,
+		})
+		if err != nil {
+			return failed(res, "delist failed", err)
+		}
+		res.Status, res.Txs = StatusSuccess, resp.Txs
+		return res
+	})
+}
+
+// failed marks a result as an error
+func failed(res Result, reason string, err error) Result {
+	res.Status, res.Reason, res.Err = StatusError, fmt.Sprintf("%s: %v", reason, err), err
+	return res
+}
+
+// run resolves the target and calls op for each NFT with bounded concurrency and pacing
+func (l *Lister) run(ctx context.Context, target Target, pacing Pacing, op func(ctx context.Context, n market.NFT, blockhash string) Result) (*Report, error) {
+	rows, items, err := l.resolve(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Results: rows}
+	if len(items) == 0 {
+		return report, nil
+	}
+
+	blockhash, _, err := l.blockhashes.LatestBlockhash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blockhash: %w", err)
+	}
+	concurrency := pacing.Concurrency
+	if concurrency == 0 {
+		concurrency = DefaultConcurrency
+	}
+	p := &pacer{interval: pacing.Interval}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := p.wait(ctx); err != nil {
+				report.Results[item.row] = failed(Result{Mint: item.nft.Mint, Name: item.nft.Name}, "cancelled", err)
+				return
+			}
+			report.Results[item.row] = op(ctx, item.nft, blockhash)
+		}()
+	}
+	wg.Wait()
+	return report, nil
+}
+
+// pending is a held NFT of the target and the index of its result row
+type pending struct {
+	nft market.NFT
+	row int
+}
+
+// resolve returns one result row per NFT of the target, in target order, and
+// the NFTs held by the wallet. The rows of requested mints that are not held or
+// could not be read are already filled in with the error.
+func (l *Lister) resolve(ctx context.Context, target Target) ([]Result, []pending, error) {
+	if len(target.Mints) == 0 {
+		held, err := market.Inventory(ctx, l.apis.User, target.Wallet, target.CollId)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read inventory: %w", err)
+		}
+		items := make([]pending, len(held))
+		for i, n := range held {
+			items[i] = pending{nft: n, row: i}
+		}
+		return make([]Result, len(held)), items, nil
+	}
+
+	// A partial failure still returns the mints that were read
+	data, _, err := l.apis.NFTs.GetNFTsInfo(ctx, &nfts.NFTsInfoRequest{Mints: target.Mints})
+	var batchErr *nfts.BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		return nil, nil, fmt.Errorf("failed to read mints: %w", err)
+	}
+	found, _, err := market.DecodeNFTs(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	byMint := make(map[string]market.NFT, len(found))
+	for _, n := range found {
+		byMint[n.Mint] = n
+	}
+	rows := make([]Result, len(target.Mints))
+	var items []pending
+	for i, mint := range target.Mints {
+		if batchErr != nil && batchErr.Failures[mint] != nil {
+			rows[i] = failed(Result{Mint: mint}, "failed to read mint", batchErr.Failures[mint])
+			continue
+		}
+		n, ok := byMint[mint]
+		held := ok && (n.Owner == target.Wallet || (n.Listing != nil && n.Listing.Seller == target.Wallet))
+		if !held || (target.CollId != "" && n.CollId != target.CollId) {
+			rows[i] = Result{Mint: mint, Status: StatusError, Reason: "not found in wallet"}
+			continue
+		}
+		items = append(items, pending{nft: n, row: i})
+	}
+	return rows, items, nil
+}
+
+// floorCache fetches each collection's floor once per batch, excluding the wallet's own listings
+type floorCache struct {
+	nfts   nfts.NFTsAPI
+	wallet string
+
+	mu     sync.Mutex
+	floors map[string]*floorRead
+}
+
+// floorRead is one collection's floor, read by the first caller while later callers wait on done
+type floorRead struct {
+	done  chan struct{}
+	floor money.Lamports
+	err   error
+}
+
+// get returns the floor of collID, zero if nothing else is listed. Reads of
+// different collections run in parallel; callers wanting a floor that is
+// being read wait for that read.
+func (c *floorCache) get(ctx context.Context, collID string) (money.Lamports, error) {
+	c.mu.Lock()
+	r, ok := c.floors[collID]
+	if !ok {
+		r = &floorRead{done: make(chan struct{})}
+		c.floors[collID] = r
+	}
+	c.mu.Unlock()
+	if ok {
+		select {
+		case <-r.done:
+			return r.floor, r.err
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	n, found, err := market.Floor(ctx, c.nfts, collID, c.wallet)
+	if found {
+		r.floor = n.Listing.Price
+	}
+	r.err = err
+	if err != nil {
+		// Forget the failure so later callers read again
+		c.mu.Lock()
+		delete(c.floors, collID)
+		c.mu.Unlock()
+	}
+	close(r.done)
+	return r.floor, r.err
+}
+
+// pacer spaces request starts at least interval apart
+type pacer struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the caller may start its request
+func (p *pacer) wait(ctx context.Context) error {
+	if p.interval <= 0 {
+		return ctx.Err()
+	}
+	p.mu.Lock()
+	now := time.Now()
+	start := p.next
+	if start.Before(now) {
+		start = now
+	}
+	p.next = start.Add(p.interval)
+	p.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(start))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,422 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/tensortest"
+)
+
+var (
+	alice = tensortest.Address("alice")
+	bob   = tensortest.Address("bob")
+)
+
+func newTestLister(t *testing.T) (*tensortest.Server, *client.Client, *Lister) {
+	t.Helper()
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID, Name: "Bulk"})
+	c := client.New(&client.Config{BaseURL: srv.URL})
+	l, err := New(APIs{User: c.User, NFTs: c.NFTs, Marketplace: c.Marketplace}, cluster.StaticBlockhash("hash"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return srv, c, l
+}
+
+func addNFT(srv *tensortest.Server, name, owner string, rank int32, price string) string {
+	mint := tensortest.Address(name)
+	n := tensortest.NFT{Mint: mint, CollId: tensortest.CollID, Name: name, Owner: owner, RarityRank: rank}
+	if price != "" {
+		n.Listing = &tensortest.Listing{Price: tensortest.Lamports(price), Seller: owner}
+	}
+	srv.AddNFT(n)
+	return mint
+}
+
+func resultsByMint(r *Report) map[string]Result {
+	out := make(map[string]Result, len(r.Results))
+	for _, res := range r.Results {
+		out[res.Mint] = res
+	}
+	return out
+}
+
+func TestRules(t *testing.T) {
+	rare := market.NFT{CollId: tensortest.CollID, RarityRank: 5}
+	common := market.NFT{CollId: tensortest.CollID, RarityRank: 800}
+	unranked := market.NFT{CollId: tensortest.CollID}
+	tiers := RarityTiers(FloorOffset(0),
+		Tier{MaxRank: 10, Rule: Fixed(money.MustParseSOL("10"))},
+		Tier{MaxRank: 100, Rule: FloorOffset(5000)},
+	)
+
+	tests := []struct {
+		name    string
+		rule    PriceRule
+		nft     market.NFT
+		floor   money.Lamports
+		want    money.Lamports
+		wantErr string
+	}{
+		{name: "fixed", rule: Fixed(money.MustParseSOL("1.5")), nft: common, want: money.MustParseSOL("1.5")},
+		{name: "under floor", rule: FloorOffset(-500), nft: common, floor: money.MustParseSOL("2"), want: money.MustParseSOL("1.9")},
+		{name: "over floor", rule: FloorOffset(1000), nft: common, floor: money.MustParseSOL("2"), want: money.MustParseSOL("2.2")},
+		{name: "no floor", rule: FloorOffset(0), nft: common, wantErr: "has no floor"},
+		{name: "rarest tier", rule: tiers, nft: rare, floor: money.MustParseSOL("1"), want: money.MustParseSOL("10")},
+		{name: "second tier", rule: tiers, nft: market.NFT{RarityRank: 50}, floor: money.MustParseSOL("1"), want: money.MustParseSOL("1.5")},
+		{name: "fallback", rule: tiers, nft: common, floor: money.MustParseSOL("1"), want: money.MustParseSOL("1")},
+		{name: "unranked fallback", rule: tiers, nft: unranked, floor: money.MustParseSOL("1"), want: money.MustParseSOL("1")},
+		{name: "no fallback", rule: RarityTiers(nil, Tier{MaxRank: 10, Rule: Fixed(1)}), nft: common, wantErr: "in no tier"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rule(tt.nft, func() (money.Lamports, error) { return tt.floor, nil })
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("rule error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("rule = %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestListCollection(t *testing.T) {
+	srv, _, l := newTestLister(t)
+	rare := addNFT(srv, "rare", alice, 3, "")
+	common := addNFT(srv, "common", alice, 500, "")
+	listed := addNFT(srv, "listed", alice, 40, "4")
+	addNFT(srv, "floor", bob, 900, "2")
+
+	report, err := l.List(context.Background(), ListRequest{
+		Target: Target{Wallet: alice, CollId: tensortest.CollID},
+		Price:  RarityTiers(FloorOffset(-1000), Tier{MaxRank: 10, Rule: FloorOffset(20_000)}),
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	results := resultsByMint(report)
+	want := map[string]struct {
+		status Status
+		price  money.Lamports
+	}{
+		rare:   {StatusSuccess, money.MustParseSOL("6")},
+		common: {StatusSuccess, money.MustParseSOL("1.8")},
+		listed: {StatusSkipped, money.MustParseSOL("4")},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("List() returned %d results, want %d:\n%s", len(results), len(want), report)
+	}
+	for mint, w := range want {
+		if res := results[mint]; res.Status != w.status || res.Price != w.price {
+			t.Errorf("%s = %s at %v, want %s at %v", res.Name, res.Status, res.Price, w.status, w.price)
+		}
+	}
+	if n, _ := srv.NFT(common); n.Listing == nil || n.Listing.Price != tensortest.Lamports("1.8") {
+		t.Errorf("common listing = %+v, want 1.8 SOL", n.Listing)
+	}
+	if n := srv.RequestCount("/api/v1/tx/list"); n != 2 {
+		t.Errorf("made %d list requests, want 2", n)
+	}
+	if n := srv.RequestCount("/api/v1/mint/collection"); n != 1 {
+		t.Errorf("read the floor %d times, want 1", n)
+	}
+	if s, k, f := report.Counts(); s != 2 || k != 1 || f != 0 {
+		t.Errorf("Counts() = %d, %d, %d; want 2, 1, 0", s, k, f)
+	}
+	if table := report.String(); !strings.Contains(table, "already listed") || !strings.Contains(table, "2 succeeded, 1 skipped, 0 failed") {
+		t.Errorf("String() =\n%s", table)
+	}
+}
+
+func TestListMints(t *testing.T) {
+	srv, _, l := newTestLister(t)
+	ok := addNFT(srv, "ok", alice, 0, "")
+	notMine := addNFT(srv, "not-mine", bob, 0, "")
+	unknown := tensortest.Address("unknown")
+
+	report, err := l.List(context.Background(), ListRequest{
+		Target: Target{Wallet: alice, Mints: []string{notMine, ok, unknown}},
+		Price:  FloorOffset(0),
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	for i, mint := range []string{notMine, ok, unknown} {
+		if got := report.Results[i].Mint; got != mint {
+			t.Errorf("result %d is for %s, want %s: results are in target order", i, got, mint)
+		}
+	}
+	results := resultsByMint(report)
+	// With only alice's NFT unlisted and nothing else for sale there is no floor.
+	if res := results[ok]; res.Status != StatusError || !strings.Contains(res.Reason, "no floor") {
+		t.Errorf("ok = %+v, want no-floor error", res)
+	}
+	for _, mint := range []string{notMine, unknown} {
+		if res := results[mint]; res.Status != StatusError || res.Reason != "not found in wallet" {
+			t.Errorf("%s = %+v, want not found in wallet", mint, res)
+		}
+	}
+	if n := srv.RequestCount("/api/v1/tx/list"); n != 0 {
+		t.Errorf("made %d list requests, want 0", n)
+	}
+}
+
+func TestListReportsBuildErrors(t *testing.T) {
+	srv, _, l := newTestLister(t)
+	mint := addNFT(srv, "a", alice, 0, "")
+	srv.InjectFault(tensortest.Fault{PathPrefix: "/api/v1/tx/list", StatusCode: 500})
+
+	report, err := l.List(context.Background(), ListRequest{Target: Target{Wallet: alice, Mints: []string{mint}}, Price: Fixed(money.MustParseSOL("1"))})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if res := report.Results[0]; res.Status != StatusError || res.Err == nil || !strings.HasPrefix(res.Reason, "list failed") {
+		t.Errorf("result = %+v, want list failed", res)
+	}
+}
+
+func TestListReadsFloorOnlyWhenPriced(t *testing.T) {
+	srv, _, l := newTestLister(t)
+	fixed := addNFT(srv, "fixed", alice, 0, "")
+	offset := addNFT(srv, "offset", alice, 0, "")
+	srv.InjectFault(tensortest.Fault{PathPrefix: "/api/v1/mint/collection", StatusCode: 500})
+
+	report, err := l.List(context.Background(), ListRequest{Target: Target{Wallet: alice, Mints: []string{fixed}}, Price: Fixed(money.MustParseSOL("1"))})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if res := report.Results[0]; res.Status != StatusSuccess {
+		t.Errorf("fixed price result = %+v, want success", res)
+	}
+	if n := srv.RequestCount("/api/v1/mint/collection"); n != 0 {
+		t.Errorf("read the floor %d times for a fixed price, want 0", n)
+	}
+
+	report, err = l.List(context.Background(), ListRequest{Target: Target{Wallet: alice, Mints: []string{offset}}, Price: FloorOffset(0)})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if res := report.Results[0]; res.Status != StatusError || !strings.HasPrefix(res.Reason, "floor unavailable") {
+		t.Errorf("floor offset result = %+v, want floor unavailable", res)
+	}
+}
+
+func TestDelist(t *testing.T) {
+	srv, _, l := newTestLister(t)
+	listed := addNFT(srv, "listed", alice, 0, "1")
+	unlisted := addNFT(srv, "unlisted", alice, 0, "")
+
+	report, err := l.Delist(context.Background(), DelistRequest{Target: Target{Wallet: alice, CollId: tensortest.CollID}})
+	if err != nil {
+		t.Fatalf("Delist() error = %v", err)
+	}
+	results := resultsByMint(report)
+	if results[listed].Status != StatusSuccess || len(results[listed].Txs) == 0 {
+		t.Errorf("listed = %+v, want success with txs", results[listed])
+	}
+	if results[unlisted].Status != StatusSkipped || results[unlisted].Reason != "not listed" {
+		t.Errorf("unlisted = %+v, want skipped", results[unlisted])
+	}
+	if n, _ := srv.NFT(listed); n.Listing != nil {
+		t.Error("listed NFT is still listed")
+	}
+}
+
+func TestDelistReportsUnreadMints(t *testing.T) {
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID, Name: "Bulk"})
+	// One mint per GetNFTsInfo chunk, and the first chunk request fails
+	c := client.New(&client.Config{BaseURL: srv.URL, MintsChunkSize: 1})
+	l, err := New(APIs{User: c.User, NFTs: c.NFTs, Marketplace: c.Marketplace}, cluster.StaticBlockhash("hash"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	a := addNFT(srv, "a", alice, 0, "1")
+	b := addNFT(srv, "b", alice, 0, "1")
+	srv.InjectFault(tensortest.Fault{PathPrefix: "/api/v1/mint", StatusCode: 500, Times: 1})
+
+	report, err := l.Delist(context.Background(), DelistRequest{Target: Target{Wallet: alice, Mints: []string{a, b}}})
+	if err != nil {
+		t.Fatalf("Delist() error = %v", err)
+	}
+	var succeeded, unread int
+	for _, res := range report.Results {
+		switch {
+		case res.Status == StatusSuccess:
+			succeeded++
+		case res.Status == StatusError && res.Err != nil && strings.HasPrefix(res.Reason, "failed to read mint"):
+			unread++
+		default:
+			t.Errorf("result = %+v, want success or a failed read", res)
+		}
+	}
+	if succeeded != 1 || unread != 1 {
+		t.Errorf("%d delisted and %d unread, want 1 and 1", succeeded, unread)
+	}
+}
+
+// blockingFloors serves an empty listing page for every collection once release is closed
+type blockingFloors struct {
+	nfts.NFTsAPI
+
+	started chan string
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (b *blockingFloors) GetNFTsByCollection(ctx context.Context, req *nfts.NFTsByCollectionRequest) ([]byte, int, error) {
+	b.calls.Add(1)
+	b.started <- req.CollId
+	<-b.release
+	return []byte(`{"mints":[],"page":{}}`), 200, nil
+}
+
+func TestFloorCacheReadsEachCollectionOnce(t *testing.T) {
+	b := &blockingFloors{started: make(chan string, 10), release: make(chan struct{})}
+	c := &floorCache{nfts: b, wallet: alice, floors: make(map[string]*floorRead)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.get(context.Background(), fmt.Sprint("coll-", i%2)); err != nil {
+				t.Errorf("get() error = %v", err)
+			}
+		}()
+	}
+	// Both collections are read at once: one read does not hold up the other.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-b.started:
+		case <-time.After(time.Second):
+			t.Fatal("floor reads are serialized")
+		}
+	}
+	close(b.release)
+	wg.Wait()
+	if n := b.calls.Load(); n != 2 {
+		t.Errorf("read floors %d times, want 2", n)
+	}
+}
+
+// gauge wraps a MarketplaceAPI and records the most ListNFT calls in flight and their start times
+type gauge struct {
+	marketplace.MarketplaceAPI
+
+	mu             sync.Mutex
+	inFlight, peak int
+	starts         []time.Time
+}
+
+func (g *gauge) ListNFT(ctx context.Context, req *marketplace.ListNFTRequest) (*marketplace.ListNFTResponse, int, error) {
+	g.mu.Lock()
+	g.inFlight++
+	g.peak = max(g.peak, g.inFlight)
+	g.starts = append(g.starts, time.Now())
+	g.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+	defer func() {
+		g.mu.Lock()
+		g.inFlight--
+		g.mu.Unlock()
+	}()
+	return g.MarketplaceAPI.ListNFT(ctx, req)
+}
+
+func TestPacing(t *testing.T) {
+	srv, c, _ := newTestLister(t)
+	for i := 0; i < 8; i++ {
+		addNFT(srv, fmt.Sprintf("nft-%d", i), alice, 0, "")
+	}
+	g := &gauge{MarketplaceAPI: c.Marketplace}
+	l, err := New(APIs{User: c.User, NFTs: c.NFTs, Marketplace: g}, cluster.StaticBlockhash("hash"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	report, err := l.List(context.Background(), ListRequest{
+		Target: Target{Wallet: alice, CollId: tensortest.CollID},
+		Price:  Fixed(money.MustParseSOL("1")),
+		Pacing: Pacing{Concurrency: 2, Interval: 5 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if s, _, _ := report.Counts(); s != 8 {
+		t.Fatalf("List() succeeded for %d NFTs, want 8:\n%s", s, report)
+	}
+	if g.peak > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", g.peak)
+	}
+	for i := 1; i < len(g.starts); i++ {
+		// Starts are recorded after the pacer releases them, so allow for scheduling jitter.
+		if gap := g.starts[i].Sub(g.starts[i-1]); gap < 4*time.Millisecond {
+			t.Errorf("request %d started %v after the previous one, want >= 5ms", i, gap)
+		}
+	}
+}
+
+func TestPacingCancelled(t *testing.T) {
+	srv, _, l := newTestLister(t)
+	for i := 0; i < 3; i++ {
+		addNFT(srv, fmt.Sprintf("nft-%d", i), alice, 0, "")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	report, err := l.List(ctx, ListRequest{
+		Target: Target{Wallet: alice, CollId: tensortest.CollID},
+		Price:  Fixed(money.MustParseSOL("1")),
+		Pacing: Pacing{Concurrency: 1, Interval: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if s, _, f := report.Counts(); s != 1 || f != 2 {
+		t.Errorf("Counts() = %d succeeded, %d failed; want 1, 2", s, f)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	_, c, l := newTestLister(t)
+	if _, err := New(APIs{User: c.User, NFTs: c.NFTs}, cluster.StaticBlockhash("hash")); err == nil {
+		t.Error("New() without marketplace succeeded")
+	}
+	tests := []struct {
+		name    string
+		req     ListRequest
+		wantErr string
+	}{
+		{name: "no wallet", req: ListRequest{Target: Target{CollId: tensortest.CollID}, Price: Fixed(1)}, wantErr: "wallet is required"},
+		{name: "no target", req: ListRequest{Target: Target{Wallet: alice}, Price: Fixed(1)}, wantErr: "mints or collId"},
+		{name: "no rule", req: ListRequest{Target: Target{Wallet: alice, CollId: tensortest.CollID}}, wantErr: "price rule"},
+		{name: "bad pacing", req: ListRequest{Target: Target{Wallet: alice, CollId: tensortest.CollID}, Price: Fixed(1), Pacing: Pacing{Concurrency: -1}}, wantErr: "concurrency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := l.List(context.Background(), tt.req); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("List() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package bulk
+
+import (
+	"fmt"
+
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// Floor returns the cheapest listing of an NFT's collection not sold by the
+// listing wallet, or zero if there is none. The floor is read on the first
+// call, so rules that do not price off it never read it.
+type Floor func() (money.Lamports, error)
+
+// PriceRule returns the listing price of n
+type PriceRule func(n market.NFT, floor Floor) (money.Lamports, error)
+
+// Fixed lists every NFT at price
+func Fixed(price money.Lamports) PriceRule {
+	return func(market.NFT, Floor) (money.Lamports, error) {
+		return price, nil
+	}
+}
+
+// FloorOffset lists at the collection floor adjusted by bps basis points,
+// e.g. -500 for 5% under the floor or 1000 for 10% over it. It fails when the
+// collection has no floor.
+func FloorOffset(bps int64) PriceRule {
+	return func(n market.NFT, floor Floor) (money.Lamports, error) {
+		f, err := floor()
+		if err != nil {
+			return 0, err
+		}
+		if f <= 0 {
+			return 0, fmt.Errorf("collection %s has no floor", n.CollId)
+		}
+		return f.MulDiv(10_000+bps, 10_000), nil
+	}
+}
+
+// Tier prices NFTs ranked MaxRank or better
+type Tier struct {
+	MaxRank int32 // Highest (least rare) rank in the tier
+	Rule    PriceRule
+}
+
+// RarityTiers prices each NFT by the first tier its rarity rank falls in.
+// Tiers are checked in order, so list them from rarest to most common. NFTs
+// without a rank or beyond the last tier use fallback; with a nil fallback
+// they fail.
+func RarityTiers(fallback PriceRule, tiers ...Tier) PriceRule {
+	return func(n market.NFT, floor Floor) (money.Lamports, error) {
+		if n.RarityRank > 0 {
+			for _, tier := range tiers {
+				if n.RarityRank <= tier.MaxRank {
+					return tier.Rule(n, floor)
+				}
+			}
+		}
+		if fallback == nil {
+			return 0, fmt.Errorf("rank %d is in no tier", n.RarityRank)
+		}
+		return fallback(n, floor)
+	}
+}
@@ -0,0 +1,46 @@
+// Package cache provides pluggable storage backends for caching Tensor API responses.
+package cache
+
+import (
+	"net/http"
+	"time"
+)
+
+// Entry is a cached API response
+type Entry struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"storedAt"`  // When the response was received
+	ExpiresAt  time.Time   `json:"expiresAt"` // When the response stops being fresh
+}
+
+// Fresh reports whether the entry is still within its TTL at the given time
+func (e *Entry) Fresh(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// Size returns the approximate number of bytes the entry occupies
+func (e *Entry) Size() int64 {
+	size := int64(len(e.Body))
+	for k, values := range e.Header {
+		size += int64(len(k))
+		for _, v := range values {
+			size += int64(len(v))
+		}
+	}
+	return size
+}
+
+// Store defines the interface for cache storage backends.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the entry stored under key, if any
+	Get(key string) (*Entry, bool)
+
+	// Set stores the entry under key, evicting older entries if needed
+	Set(key string, entry *Entry)
+
+	// Delete removes the entry stored under key
+	Delete(key string)
+}
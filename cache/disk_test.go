@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiskStore_GetSet(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDiskStore(dir, 0)
+	if err != nil {
+		t.Fatalf("NewDiskStore() error = %v", err)
+	}
+
+	entry := newEntry(`{"status":"ok"}`)
+	entry.Header = map[string][]string{"Content-Type": {"application/json"}}
+	store.Set("/api/v1/mint?mints=a", entry)
+
+	got, ok := store.Get("/api/v1/mint?mints=a")
+	if !ok {
+		t.Fatal("Get() did not return the stored entry")
+	}
+	if string(got.Body) != `{"status":"ok"}` {
+		t.Errorf("Get() body = %q", got.Body)
+	}
+	if got.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Get() header = %v", got.Header)
+	}
+	if !got.ExpiresAt.Equal(entry.ExpiresAt) {
+		t.Errorf("Get() expiresAt = %v, want %v", got.ExpiresAt, entry.ExpiresAt)
+	}
+
+	// A second store on the same directory sees the entry
+	reopened, _ := NewDiskStore(dir, 0)
+	if _, ok := reopened.Get("/api/v1/mint?mints=a"); !ok {
+		t.Error("reopened store did not find the entry")
+	}
+
+	store.Delete("/api/v1/mint?mints=a")
+	if _, ok := store.Get("/api/v1/mint?mints=a"); ok {
+		t.Error("Get() after Delete() returned an entry")
+	}
+}
+
+func TestDiskStore_EvictsOldestFiles(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewDiskStore(dir, 0)
+
+	store.Set("old", newEntry("old"))
+	info, err := os.Stat(store.path("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Room for one entry but not two
+	store.maxBytes = info.Size() + info.Size()/2
+
+	// Backdate the first file so eviction order is deterministic
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(store.path("old"), past, past)
+	store.Set("new", newEntry("new"))
+
+	if _, ok := store.Get("old"); ok {
+		t.Error("expected the oldest entry to be evicted")
+	}
+	if _, ok := store.Get("new"); !ok {
+		t.Error("expected the newest entry to be kept")
+	}
+}
+
+func TestDiskStore_CorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewDiskStore(dir, 0)
+
+	if err := os.WriteFile(store.path("bad"), []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Get("bad"); ok {
+		t.Error("Get() returned an entry for a corrupt file")
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.Base(store.path("bad")))); !os.IsNotExist(err) {
+		t.Error("expected corrupt file to be removed")
+	}
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// MemoryStore is an in-memory Store with LRU eviction
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	bytes      int64
+	order      *list.List // front is most recently used
+	items      map[string]*list.Element
+}
+
+type memoryItem struct {
+	key   string
+	entry *Entry
+}
+
+// NewMemoryStore creates a MemoryStore bounded by entry count and total size in bytes.
+// A limit of 0 disables that bound.
+func NewMemoryStore(maxEntries int, maxBytes int64) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry stored under key and marks it as recently used
+func (s *MemoryStore) Get(key string) (*Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	s.order.MoveToFront(elem)
+	return elem.Value.(*memoryItem).entry, true
+}
+
+// Set stores the entry under key and evicts least recently used entries beyond the bounds
+func (s *MemoryStore) Set(key string, entry *Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.items[key]; ok {
+		item := elem.Value.(*memoryItem)
+		s.bytes += entry.Size() - item.entry.Size()
+		item.entry = entry
+		s.order.MoveToFront(elem)
+	} else {
+		s.items[key] = s.order.PushFront(&memoryItem{key: key, entry: entry})
+		s.bytes += entry.Size()
+	}
+
+	for s.order.Len() > 0 && s.overLimit() {
+		s.removeElement(s.order.Back())
+	}
+}
+
+// Delete removes the entry stored under key
+func (s *MemoryStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.items[key]; ok {
+		s.removeElement(elem)
+	}
+}
+
+// Len returns the number of entries currently stored
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *MemoryStore) overLimit() bool {
+	if s.maxEntries > 0 && s.order.Len() > s.maxEntries {
+		return true
+	}
+	return s.maxBytes > 0 && s.bytes > s.maxBytes
+}
+
+func (s *MemoryStore) removeElement(elem *list.Element) {
+	item := s.order.Remove(elem).(*memoryItem)
+	delete(s.items, item.key)
+	s.bytes -= item.entry.Size()
+}
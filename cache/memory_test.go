@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newEntry(body string) *Entry {
+	now := time.Now()
+	return &Entry{
+		StatusCode: 200,
+		Body:       []byte(body),
+		StoredAt:   now,
+		ExpiresAt:  now.Add(time.Minute),
+	}
+}
+
+func TestMemoryStore_GetSet(t *testing.T) {
+	store := NewMemoryStore(10, 0)
+
+	if _, ok := store.Get("missing"); ok {
+		t.Fatal("Get() on empty store returned an entry")
+	}
+
+	store.Set("a", newEntry("one"))
+	entry, ok := store.Get("a")
+	if !ok {
+		t.Fatal("Get() did not return the stored entry")
+	}
+	if string(entry.Body) != "one" {
+		t.Errorf("Get() body = %q, want %q", entry.Body, "one")
+	}
+
+	store.Set("a", newEntry("two"))
+	entry, _ = store.Get("a")
+	if string(entry.Body) != "two" {
+		t.Errorf("Get() after overwrite body = %q, want %q", entry.Body, "two")
+	}
+
+	store.Delete("a")
+	if _, ok := store.Get("a"); ok {
+		t.Error("Get() after Delete() returned an entry")
+	}
+}
+
+func TestMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewMemoryStore(2, 0)
+
+	store.Set("a", newEntry("a"))
+	store.Set("b", newEntry("b"))
+	store.Get("a") // a is now more recent than b
+	store.Set("c", newEntry("c"))
+
+	if _, ok := store.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if _, ok := store.Get("a"); !ok {
+		t.Error("expected a to be kept")
+	}
+	if _, ok := store.Get("c"); !ok {
+		t.Error("expected c to be kept")
+	}
+	if store.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", store.Len())
+	}
+}
+
+func TestMemoryStore_ByteBound(t *testing.T) {
+	store := NewMemoryStore(0, 10)
+
+	for i := 0; i < 5; i++ {
+		store.Set(fmt.Sprintf("k%d", i), newEntry("1234"))
+	}
+
+	if store.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", store.Len())
+	}
+	if _, ok := store.Get("k4"); !ok {
+		t.Error("expected newest entry to be kept")
+	}
+}
+
+func TestEntry_Fresh(t *testing.T) {
+	now := time.Now()
+	entry := &Entry{ExpiresAt: now.Add(time.Second)}
+
+	if !entry.Fresh(now) {
+		t.Error("Fresh() = false before expiry")
+	}
+	if entry.Fresh(now.Add(2 * time.Second)) {
+		t.Error("Fresh() = true after expiry")
+	}
+}
@@ -0,0 +1,103 @@
+// Package cassette records Tensor API traffic into versioned fixture files and
+// replays it offline, so integration tests run deterministically without an API key.
+//
+// Both Recorder and Replayer are http.RoundTrippers and plug into the SDK through
+// client.Config.HTTPClient, which covers every API group.
+package cassette
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Version is the fixture file format version written by this package
+const Version = 1
+
+// Cassette is a set of recorded request/response pairs
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded part of an outgoing request
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"` // Canonical (sorted) query string
+}
+
+// Response is a recorded API response
+type Response struct {
+	StatusCode int             `json:"statusCode"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`     // Set when the body is valid JSON
+	BodyText   string          `json:"bodyText,omitempty"` // Set when the body is not JSON
+}
+
+// Load reads a cassette from a fixture file
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if c.Version != Version {
+		return nil, fmt.Errorf("unsupported cassette version %d in %s, want %d", c.Version, path, Version)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to a fixture file, creating parent directories
+func (c *Cassette) Save(path string) error {
+	c.Version = Version
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// body returns the recorded response body
+func (r *Response) body() []byte {
+	if len(r.Body) > 0 {
+		return r.Body
+	}
+	return []byte(r.BodyText)
+}
+
+// setBody stores body as JSON when possible so fixtures stay readable
+func (r *Response) setBody(body []byte) {
+	if json.Valid(body) {
+		r.Body = json.RawMessage(body)
+		return
+	}
+	r.BodyText = string(body)
+}
+
+// canonicalQuery returns the query string with keys sorted
+func canonicalQuery(q url.Values) string {
+	return q.Encode()
+}
@@ -0,0 +1,180 @@
+package cassette
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/rpc"
+	"github.com/srpvpn/tensor-go-sdk/client"
+)
+
+const testWallet = "DRpbCBMxVnDK7maPM5tGv6MvB3v1sRMC86PZ8okm21hy"
+
+func newAPIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/user/portfolio":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"wallet":"` + r.URL.Query().Get("wallet") + `","collections":[]}`))
+		case "/api/v1/rpc/priority_fees":
+			w.Write([]byte(`{"min":1,"low":2,"medium":3,"high":4,"veryHigh":5}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not found"))
+		}
+	}))
+}
+
+func get(t *testing.T, client *http.Client, rawURL string) (int, string, error) {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, rawURL, nil)
+	req.Header.Set(APIKeyHeader, "secret-key")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := newAPIServer(t)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures", "portfolio.json")
+	scrubber := NewScrubber(testWallet)
+
+	recorder := NewRecorder(path, nil, scrubber)
+	recording := &http.Client{Transport: recorder, Timeout: 5 * time.Second}
+
+	status, body, err := get(t, recording, server.URL+"/api/v1/user/portfolio?wallet="+testWallet+"&includeBidCount=true")
+	if err != nil {
+		t.Fatalf("recording request failed: %v", err)
+	}
+	if status != 200 || !strings.Contains(body, testWallet) {
+		t.Fatalf("recording must pass the live response through, got %d %s", status, body)
+	}
+	get(t, recording, server.URL+"/api/v1/unknown")
+
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// The fixture holds neither the API key nor the wallet
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "secret-key") {
+		t.Error("fixture contains the API key")
+	}
+	if strings.Contains(string(data), testWallet) {
+		t.Error("fixture contains the wallet address")
+	}
+
+	// Replay offline: the server is gone
+	server.Close()
+	replayer, err := LoadReplayer(path, Strict, scrubber)
+	if err != nil {
+		t.Fatalf("LoadReplayer() error = %v", err)
+	}
+	replaying := &http.Client{Transport: replayer}
+
+	// Query order does not matter thanks to the canonical form
+	status, body, err = get(t, replaying, "http://offline/api/v1/user/portfolio?includeBidCount=true&wallet="+testWallet)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if status != 200 || !strings.Contains(body, "WALLET_1") {
+		t.Errorf("replay = %d %s", status, body)
+	}
+
+	status, body, _ = get(t, replaying, "http://offline/api/v1/unknown")
+	if status != 404 || body != "not found" {
+		t.Errorf("replay of non-JSON response = %d %q", status, body)
+	}
+}
+
+func TestReplayer_StrictReportsDiff(t *testing.T) {
+	c := &Cassette{Version: Version, Interactions: []Interaction{{
+		Request:  Request{Method: "GET", Path: "/api/v1/collections", Query: "limit=10&sortBy=statsV2.volume1h%3Adesc"},
+		Response: Response{StatusCode: 200, Body: []byte(`{}`)},
+	}}}
+	client := &http.Client{Transport: NewReplayer(c, Strict, nil)}
+
+	_, _, err := get(t, client, "http://offline/api/v1/collections?limit=20&sortBy=statsV2.volume1h:desc")
+	if err == nil {
+		t.Fatal("expected no-match error")
+	}
+	for _, want := range []string{"no recorded interaction", "- limit=10", "+ limit=20"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+	diff := err.Error()[strings.Index(err.Error(), "candidate"):]
+	if strings.Contains(diff, "sortBy") {
+		t.Errorf("diff should only list differing parameters: %v", err)
+	}
+
+	_, _, err = get(t, client, "http://offline/api/v1/mint?mints=a")
+	if err == nil || !strings.Contains(err.Error(), "recorded paths: GET /api/v1/collections") {
+		t.Errorf("expected recorded paths in error, got %v", err)
+	}
+}
+
+func TestReplayer_LenientPicksClosest(t *testing.T) {
+	c := &Cassette{Version: Version, Interactions: []Interaction{
+		{
+			Request:  Request{Method: "GET", Path: "/api/v1/mint/collection", Query: "collId=a&limit=10&sortBy=PriceAsc"},
+			Response: Response{StatusCode: 200, Body: []byte(`{"match":"price"}`)},
+		},
+		{
+			Request:  Request{Method: "GET", Path: "/api/v1/mint/collection", Query: "collId=b&limit=50&sortBy=RarityAsc"},
+			Response: Response{StatusCode: 200, Body: []byte(`{"match":"rarity"}`)},
+		},
+	}}
+	client := &http.Client{Transport: NewReplayer(c, Lenient, nil)}
+
+	_, body, err := get(t, client, "http://offline/api/v1/mint/collection?collId=a&limit=25&sortBy=PriceAsc")
+	if err != nil {
+		t.Fatalf("lenient replay failed: %v", err)
+	}
+	if body != `{"match":"price"}` {
+		t.Errorf("lenient replay picked %s", body)
+	}
+}
+
+func TestLoad_RejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.json")
+	os.WriteFile(path, []byte(`{"version":99,"interactions":[]}`), 0o644)
+
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "unsupported cassette version") {
+		t.Errorf("Load() error = %v, want version error", err)
+	}
+}
+
+func TestReplayer_WithSDKClient(t *testing.T) {
+	c := &Cassette{Version: Version, Interactions: []Interaction{{
+		Request:  Request{Method: "GET", Path: "/api/v1/rpc/priority_fees"},
+		Response: Response{StatusCode: 200, Body: []byte(`{"min":1,"low":2,"medium":3,"high":4,"veryHigh":5}`)},
+	}}}
+
+	tc := client.New(&client.Config{
+		BaseURL:    "http://offline",
+		HTTPClient: &http.Client{Transport: NewReplayer(c, Strict, nil)},
+	})
+
+	fees, status, err := tc.RPC.GetPriorityFees(context.Background(), &rpc.PriorityFeesRequest{})
+	if err != nil {
+		t.Fatalf("GetPriorityFees() error = %v", err)
+	}
+	if status != 200 || fees.Medium != 3 {
+		t.Errorf("GetPriorityFees() = %d %+v", status, fees)
+	}
+}
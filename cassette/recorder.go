@@ -0,0 +1,83 @@
+package cassette
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Recorder is an http.RoundTripper that forwards requests to a real transport
+// and records every exchange. Call Save to write the fixture file.
+type Recorder struct {
+	next     http.RoundTripper
+	path     string
+	scrubber *Scrubber
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a Recorder that writes to path. If next is nil,
+// http.DefaultTransport is used. A nil scrubber records wallets verbatim;
+// the API key header is never recorded.
+func NewRecorder(path string, next http.RoundTripper, scrubber *Scrubber) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{
+		next:     next,
+		path:     path,
+		scrubber: scrubber,
+		cassette: Cassette{Version: Version},
+	}
+}
+
+// RoundTrip performs the request and records the exchange
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recorded := Response{
+		StatusCode: resp.StatusCode,
+		Header:     r.scrubber.Header(resp.Header),
+	}
+	recorded.setBody([]byte(r.scrubber.String(string(body))))
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  canonicalQuery(r.scrubber.Query(req.URL.Query())),
+		},
+		Response: recorded,
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Cassette returns a copy of what has been recorded so far
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := Cassette{Version: Version, Interactions: make([]Interaction, len(r.cassette.Interactions))}
+	copy(c.Interactions, r.cassette.Interactions)
+	return &c
+}
+
+// Save writes the recorded interactions to the fixture file
+func (r *Recorder) Save() error {
+	return r.Cassette().Save(r.path)
+}
@@ -0,0 +1,167 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Mode controls how live requests are matched against recorded ones
+type Mode int
+
+const (
+	// Strict requires the method, path and canonical query to match exactly
+	Strict Mode = iota
+	// Lenient falls back to the recording on the same path whose query
+	// differs the least when there is no exact match
+	Lenient
+)
+
+// Replayer is an http.RoundTripper that serves recorded responses without network access
+type Replayer struct {
+	cassette *Cassette
+	mode     Mode
+	scrubber *Scrubber
+}
+
+// NewReplayer creates a Replayer for a loaded cassette. The scrubber must match
+// the one used while recording so live wallets map onto the recorded placeholders.
+func NewReplayer(c *Cassette, mode Mode, scrubber *Scrubber) *Replayer {
+	return &Replayer{cassette: c, mode: mode, scrubber: scrubber}
+}
+
+// LoadReplayer loads a fixture file and creates a Replayer for it
+func LoadReplayer(path string, mode Mode, scrubber *Scrubber) (*Replayer, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(c, mode, scrubber), nil
+}
+
+// RoundTrip returns the recorded response matching req
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	query := r.scrubber.Query(req.URL.Query())
+	want := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  canonicalQuery(query),
+	}
+
+	interaction, err := r.match(want, query)
+	if err != nil {
+		return nil, err
+	}
+
+	header := interaction.Response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	status := interaction.Response.StatusCode
+
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(interaction.Response.body())),
+		Request:    req,
+	}, nil
+}
+
+// match finds the interaction for want according to the replay mode
+func (r *Replayer) match(want Request, query url.Values) (*Interaction, error) {
+	var samePath []*Interaction
+	for i := range r.cassette.Interactions {
+		in := &r.cassette.Interactions[i]
+		if in.Request.Method != want.Method || in.Request.Path != want.Path {
+			continue
+		}
+		if in.Request.Query == want.Query {
+			return in, nil
+		}
+		samePath = append(samePath, in)
+	}
+
+	if r.mode == Lenient && len(samePath) > 0 {
+		best, bestScore := samePath[0], -1
+		for _, in := range samePath {
+			recorded, _ := url.ParseQuery(in.Request.Query)
+			if score := len(queryDiff(recorded, query)); bestScore < 0 || score < bestScore {
+				best, bestScore = in, score
+			}
+		}
+		return best, nil
+	}
+
+	return nil, r.noMatchError(want, query, samePath)
+}
+
+// noMatchError explains why a request has no recording, diffing it against the closest candidates
+func (r *Replayer) noMatchError(want Request, query url.Values, samePath []*Interaction) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "cassette: no recorded interaction for %s %s", want.Method, want.Path)
+	if want.Query != "" {
+		fmt.Fprintf(&b, "?%s", want.Query)
+	}
+
+	if len(samePath) == 0 {
+		paths := make([]string, 0, len(r.cassette.Interactions))
+		seen := make(map[string]bool)
+		for _, in := range r.cassette.Interactions {
+			key := in.Request.Method + " " + in.Request.Path
+			if !seen[key] {
+				seen[key] = true
+				paths = append(paths, key)
+			}
+		}
+		sort.Strings(paths)
+		fmt.Fprintf(&b, "\nrecorded paths: %s", strings.Join(paths, ", "))
+		return fmt.Errorf("%s", b.String())
+	}
+
+	for i, in := range samePath {
+		recorded, _ := url.ParseQuery(in.Request.Query)
+		fmt.Fprintf(&b, "\ncandidate %d (- recorded, + request):", i+1)
+		for _, line := range queryDiff(recorded, query) {
+			fmt.Fprintf(&b, "\n  %s", line)
+		}
+	}
+	return fmt.Errorf("%s", b.String())
+}
+
+// queryDiff lists the differing parameters between two queries, sorted by key
+func queryDiff(recorded, live url.Values) []string {
+	keys := make(map[string]bool)
+	for k := range recorded {
+		keys[k] = true
+	}
+	for k := range live {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var lines []string
+	for _, k := range sorted {
+		a, b := strings.Join(recorded[k], ","), strings.Join(live[k], ",")
+		_, inA := recorded[k]
+		_, inB := live[k]
+		if inA && inB && a == b {
+			continue
+		}
+		if inA {
+			lines = append(lines, fmt.Sprintf("- %s=%s", k, a))
+		}
+		if inB {
+			lines = append(lines, fmt.Sprintf("+ %s=%s", k, b))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,75 @@
+package cassette
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// APIKeyHeader is the header carrying the Tensor API key
+const APIKeyHeader = "x-tensor-api-key"
+
+// Scrubber removes secrets from recorded traffic. The same Scrubber must be
+// given to the Replayer so live requests match the scrubbed fixtures.
+type Scrubber struct {
+	wallets []string          // In registration order, for deterministic replacement
+	aliases map[string]string // Wallet address -> placeholder
+}
+
+// NewScrubber creates a Scrubber that replaces each wallet with a stable placeholder
+func NewScrubber(wallets ...string) *Scrubber {
+	s := &Scrubber{aliases: make(map[string]string)}
+	for _, w := range wallets {
+		s.AddWallet(w)
+	}
+	return s
+}
+
+// AddWallet registers a wallet address to be replaced by a placeholder
+func (s *Scrubber) AddWallet(wallet string) {
+	if wallet == "" {
+		return
+	}
+	if _, ok := s.aliases[wallet]; ok {
+		return
+	}
+	s.wallets = append(s.wallets, wallet)
+	s.aliases[wallet] = fmt.Sprintf("WALLET_%d", len(s.wallets))
+}
+
+// String replaces every registered wallet in v with its placeholder
+func (s *Scrubber) String(v string) string {
+	if s == nil {
+		return v
+	}
+	for _, w := range s.wallets {
+		v = strings.ReplaceAll(v, w, s.aliases[w])
+	}
+	return v
+}
+
+// Query scrubs every value of q
+func (s *Scrubber) Query(q url.Values) url.Values {
+	out := make(url.Values, len(q))
+	for k, values := range q {
+		for _, v := range values {
+			out.Add(k, s.String(v))
+		}
+	}
+	return out
+}
+
+// Header drops the API key and scrubs wallets from the remaining values
+func (s *Scrubber) Header(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, values := range h {
+		if strings.EqualFold(k, APIKeyHeader) {
+			continue
+		}
+		for _, v := range values {
+			out.Add(k, s.String(v))
+		}
+	}
+	return out
+}
@@ -0,0 +1,332 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/internal/transport"
+)
+
+// ErrCircuitOpen is matched by errors.Is for calls rejected by an open circuit
+var ErrCircuitOpen = stderrors.New("circuit breaker is open")
+
+// CircuitOpenError is returned for calls rejected by an open or probing circuit
+type CircuitOpenError struct {
+	Prefix  string    // Endpoint prefix of the circuit
+	RetryAt time.Time // When the circuit will next admit a probe request
+}
+
+// Error implements the error interface
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open for %s until %s", e.Prefix, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+// Circuit states
+const (
+	CircuitClosed   CircuitState = iota // Requests flow normally
+	CircuitOpen                         // Requests fail immediately with ErrCircuitOpen
+	CircuitHalfOpen                     // A limited number of probe requests are admitted
+)
+
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitStatus is a snapshot of one circuit, suitable for health checks
+type CircuitStatus struct {
+	Prefix   string
+	State    CircuitState
+	Requests int       // Requests counted in the current window
+	Failures int       // Failures counted in the current window
+	OpenedAt time.Time // When the circuit last opened, zero if it never did
+}
+
+// DefaultBreakerPrefixes are the endpoint groups guarded when BreakerConfig.Prefixes is nil
+var DefaultBreakerPrefixes = []string{
+	"/api/v1/tx/",
+	"/api/v1/user/",
+	"/api/v1/mint",
+	"/api/v1/collections",
+	"/api/v1/rpc/",
+}
+
+// BreakerConfig configures per endpoint group circuit breaking in the transport.
+// Network errors, 429 and 5xx responses count as failures.
+type BreakerConfig struct {
+	// Prefixes are the endpoint prefixes that get their own circuit. The longest
+	// matching prefix wins; paths without a match are never blocked.
+	// Defaults to DefaultBreakerPrefixes.
+	Prefixes []string
+
+	// FailureRatio opens the circuit once failures/requests in the window
+	// reaches it. Defaults to 0.5.
+	FailureRatio float64
+
+	// MinRequests is the number of requests a window needs before the ratio
+	// is evaluated. Defaults to 10.
+	MinRequests int
+
+	// Window is how long request counts accumulate before they reset. Defaults to 30s.
+	Window time.Duration
+
+	// Cooldown is how long an open circuit rejects calls before admitting
+	// probes. Defaults to 30s.
+	Cooldown time.Duration
+
+	// HalfOpenProbes is how many probe requests must succeed to close the
+	// circuit again; any failing probe reopens it. Defaults to 1.
+	HalfOpenProbes int
+
+	// OnStateChange is called, without locks held, whenever a circuit changes state
+	OnStateChange func(prefix string, from, to CircuitState)
+}
+
+// breakerTransport wraps a transport.Transport with one circuit per endpoint prefix
+type breakerTransport struct {
+	next     transport.Transport
+	cfg      BreakerConfig
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+// circuit is the breaker state of one endpoint prefix
+type circuit struct {
+	mu             sync.Mutex
+	state          CircuitState
+	generation     int // Incremented on every state change so stale results are ignored
+	windowStart    time.Time
+	requests       int
+	failures       int
+	openedAt       time.Time
+	probesInFlight int
+	probeSuccesses int
+}
+
+// newBreakerTransport wraps next with circuit breaking as described by cfg
+func newBreakerTransport(next transport.Transport, cfg BreakerConfig) *breakerTransport {
+	if cfg.Prefixes == nil {
+		cfg.Prefixes = DefaultBreakerPrefixes
+	}
+	if cfg.FailureRatio <= 0 {
+		cfg.FailureRatio = 0.5
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = 10
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 30 * time.Second
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = 1
+	}
+
+	circuits := make(map[string]*circuit, len(cfg.Prefixes))
+	for _, prefix := range cfg.Prefixes {
+		circuits[prefix] = &circuit{}
+	}
+	return &breakerTransport{next: next, cfg: cfg, circuits: circuits, now: time.Now}
+}
+
+// Get performs the request unless the circuit for path is open
+func (b *breakerTransport) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	prefix := b.prefixFor(path)
+	if prefix == "" {
+		return b.next.Get(ctx, path, params)
+	}
+	c := b.circuits[prefix]
+
+	gen, err := b.allow(prefix, c)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.next.Get(ctx, path, params)
+	b.record(prefix, c, gen, classifyOutcome(ctx, resp, err))
+	return resp, err
+}
+
+// Circuits returns a snapshot of every circuit, sorted by prefix
+func (b *breakerTransport) Circuits() []CircuitStatus {
+	out := make([]CircuitStatus, 0, len(b.circuits))
+	for prefix, c := range b.circuits {
+		c.mu.Lock()
+		prev := b.advance(c)
+		out = append(out, CircuitStatus{
+			Prefix:   prefix,
+			State:    c.state,
+			Requests: c.requests,
+			Failures: c.failures,
+			OpenedAt: c.openedAt,
+		})
+		state := c.state
+		c.mu.Unlock()
+		b.notify(prefix, prev, state)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Prefix < out[j].Prefix })
+	return out
+}
+
+// prefixFor returns the longest configured prefix matching path
+func (b *breakerTransport) prefixFor(path string) string {
+	best := ""
+	for prefix := range b.circuits {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	return best
+}
+
+// advance applies time based transitions: window resets and the end of the
+// cooldown. Callers must hold c.mu. It returns the previous state.
+func (b *breakerTransport) advance(c *circuit) CircuitState {
+	now := b.now()
+	prev := c.state
+	switch c.state {
+	case CircuitClosed:
+		if now.Sub(c.windowStart) >= b.cfg.Window {
+			c.windowStart, c.requests, c.failures = now, 0, 0
+		}
+	case CircuitOpen:
+		if now.Sub(c.openedAt) >= b.cfg.Cooldown {
+			c.setState(CircuitHalfOpen)
+		}
+	}
+	return prev
+}
+
+// allow admits a request or rejects it with a CircuitOpenError
+func (b *breakerTransport) allow(prefix string, c *circuit) (int, error) {
+	c.mu.Lock()
+	prev := b.advance(c)
+	state, gen := c.state, c.generation
+	var err error
+	switch state {
+	case CircuitOpen:
+		err = &CircuitOpenError{Prefix: prefix, RetryAt: c.openedAt.Add(b.cfg.Cooldown)}
+	case CircuitHalfOpen:
+		if c.probesInFlight+c.probeSuccesses >= b.cfg.HalfOpenProbes {
+			err = &CircuitOpenError{Prefix: prefix, RetryAt: b.now()}
+		} else {
+			c.probesInFlight++
+		}
+	}
+	c.mu.Unlock()
+
+	b.notify(prefix, prev, state)
+	return gen, err
+}
+
+// outcome is how a completed request affects its circuit
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeIgnored // The caller gave up; says nothing about upstream health
+)
+
+// classifyOutcome decides whether a request counts against the circuit
+func classifyOutcome(ctx context.Context, resp *http.Response, err error) outcome {
+	if err == nil {
+		if resp != nil && resp.StatusCode >= 500 {
+			return outcomeFailure
+		}
+		return outcomeSuccess
+	}
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
+		if apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= 500 {
+			return outcomeFailure
+		}
+		return outcomeSuccess
+	}
+	if ctx.Err() != nil {
+		return outcomeIgnored
+	}
+	return outcomeFailure
+}
+
+// record applies the outcome of a request admitted in generation gen
+func (b *breakerTransport) record(prefix string, c *circuit, gen int, result outcome) {
+	c.mu.Lock()
+	prev := c.state
+	if gen == c.generation {
+		switch c.state {
+		case CircuitClosed:
+			if result != outcomeIgnored {
+				c.requests++
+				if result == outcomeFailure {
+					c.failures++
+				}
+				if c.requests >= b.cfg.MinRequests && float64(c.failures)/float64(c.requests) >= b.cfg.FailureRatio {
+					c.open(b.now())
+				}
+			}
+		case CircuitHalfOpen:
+			c.probesInFlight--
+			switch result {
+			case outcomeFailure:
+				c.open(b.now())
+			case outcomeSuccess:
+				c.probeSuccesses++
+				if c.probeSuccesses >= b.cfg.HalfOpenProbes {
+					c.setState(CircuitClosed)
+					c.windowStart, c.requests, c.failures = b.now(), 0, 0
+				}
+			}
+		}
+	}
+	state := c.state
+	c.mu.Unlock()
+
+	b.notify(prefix, prev, state)
+}
+
+// notify reports a state change to the configured callback
+func (b *breakerTransport) notify(prefix string, from, to CircuitState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(prefix, from, to)
+	}
+}
+
+// open trips the circuit. Callers must hold c.mu.
+func (c *circuit) open(now time.Time) {
+	c.setState(CircuitOpen)
+	c.openedAt = now
+}
+
+// setState moves to state and starts a new generation. Callers must hold c.mu.
+func (c *circuit) setState(state CircuitState) {
+	c.state = state
+	c.generation++
+	c.probesInFlight, c.probeSuccesses = 0, 0
+}
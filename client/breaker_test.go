@@ -0,0 +1,220 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	apierrors "github.com/srpvpn/tensor-go-sdk/internal/errors"
+)
+
+// scriptedTransport answers with a fixed error, or 200 when err is nil
+type scriptedTransport struct {
+	mu    sync.Mutex
+	err   error
+	calls int
+}
+
+func (s *scriptedTransport) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte("{}")))}, nil
+}
+
+func (s *scriptedTransport) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// transportFunc adapts a function to transport.Transport
+type transportFunc func(ctx context.Context, path string, params url.Values) (*http.Response, error)
+
+func (f transportFunc) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	return f(ctx, path, params)
+}
+
+// breakerClock is a manually advanced clock
+type breakerClock struct{ now time.Time }
+
+func (c *breakerClock) Now() time.Time { return c.now }
+
+func newTestBreaker(next *scriptedTransport, cfg BreakerConfig) (*breakerTransport, *breakerClock) {
+	clock := &breakerClock{now: time.Unix(1_700_000_000, 0)}
+	b := newBreakerTransport(next, cfg)
+	b.now = clock.Now
+	return b, clock
+}
+
+func circuitState(b *breakerTransport, prefix string) CircuitState {
+	for _, s := range b.Circuits() {
+		if s.Prefix == prefix {
+			return s.State
+		}
+	}
+	return -1
+}
+
+func TestBreaker_OpensHalfOpensAndCloses(t *testing.T) {
+	next := &scriptedTransport{err: &apierrors.APIError{Code: 503}}
+	var transitions []string
+	b, clock := newTestBreaker(next, BreakerConfig{
+		MinRequests: 4,
+		Cooldown:    10 * time.Second,
+		OnStateChange: func(prefix string, from, to CircuitState) {
+			transitions = append(transitions, prefix+" "+from.String()+"->"+to.String())
+		},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		if _, err := b.Get(ctx, "/api/v1/tx/buy", nil); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d rejected before the circuit opened", i)
+		}
+	}
+	if got := circuitState(b, "/api/v1/tx/"); got != CircuitOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+
+	// Open circuits fail fast without reaching the transport
+	_, err := b.Get(ctx, "/api/v1/tx/sell", nil)
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Get() error = %v, want CircuitOpenError", err)
+	}
+	if openErr.Prefix != "/api/v1/tx/" || !openErr.RetryAt.Equal(clock.now.Add(10*time.Second)) {
+		t.Errorf("CircuitOpenError = %+v", openErr)
+	}
+	if next.calls != 4 {
+		t.Errorf("transport calls = %d, want 4", next.calls)
+	}
+
+	// Other groups are unaffected
+	next.fail(nil)
+	if _, err := b.Get(ctx, "/api/v1/user/active_listings", nil); err != nil {
+		t.Errorf("user group call error = %v", err)
+	}
+
+	// After the cooldown a probe is admitted and closes the circuit
+	clock.now = clock.now.Add(10 * time.Second)
+	if got := circuitState(b, "/api/v1/tx/"); got != CircuitHalfOpen {
+		t.Fatalf("state after cooldown = %v, want half-open", got)
+	}
+	if _, err := b.Get(ctx, "/api/v1/tx/buy", nil); err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	if got := circuitState(b, "/api/v1/tx/"); got != CircuitClosed {
+		t.Errorf("state after probe = %v, want closed", got)
+	}
+
+	want := []string{
+		"/api/v1/tx/ closed->open",
+		"/api/v1/tx/ open->half-open",
+		"/api/v1/tx/ half-open->closed",
+	}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("transition %d = %q, want %q", i, transitions[i], want[i])
+		}
+	}
+}
+
+func TestBreaker_FailedProbeReopens(t *testing.T) {
+	next := &scriptedTransport{err: &apierrors.NetworkError{Op: "http_request", Err: io.ErrUnexpectedEOF}}
+	b, clock := newTestBreaker(next, BreakerConfig{MinRequests: 2, Cooldown: time.Second, HalfOpenProbes: 2})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, _ = b.Get(ctx, "/api/v1/mint", nil)
+	}
+	clock.now = clock.now.Add(time.Second)
+
+	if _, err := b.Get(ctx, "/api/v1/mint", nil); errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("probe was rejected")
+	}
+	if got := circuitState(b, "/api/v1/mint"); got != CircuitOpen {
+		t.Errorf("state after failed probe = %v, want open", got)
+	}
+}
+
+func TestBreaker_ProbeLimit(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	blocking := transportFunc(func(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+		started <- struct{}{}
+		<-release
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+	})
+	b := newBreakerTransport(blocking, BreakerConfig{})
+	clock := &breakerClock{now: time.Unix(1_700_000_000, 0)}
+	b.now = clock.Now
+	c := b.circuits["/api/v1/rpc/"]
+	c.open(clock.now.Add(-time.Hour))
+
+	done := make(chan error)
+	go func() {
+		_, err := b.Get(context.Background(), "/api/v1/rpc/priority_fees", nil)
+		done <- err
+	}()
+	<-started
+
+	// Only one probe may be in flight
+	if _, err := b.Get(context.Background(), "/api/v1/rpc/priority_fees", nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second probe error = %v, want ErrCircuitOpen", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("probe error = %v", err)
+	}
+}
+
+func TestBreaker_IgnoresClientErrorsAndCancellation(t *testing.T) {
+	next := &scriptedTransport{err: &apierrors.APIError{Code: 400}}
+	b, _ := newTestBreaker(next, BreakerConfig{MinRequests: 2})
+
+	for i := 0; i < 5; i++ {
+		_, _ = b.Get(context.Background(), "/api/v1/tx/buy", nil)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	next.fail(context.Canceled)
+	for i := 0; i < 5; i++ {
+		_, _ = b.Get(ctx, "/api/v1/tx/buy", nil)
+	}
+	if got := circuitState(b, "/api/v1/tx/"); got != CircuitClosed {
+		t.Errorf("state = %v, want closed", got)
+	}
+}
+
+func TestClient_Circuits(t *testing.T) {
+	if New(nil).Circuits() != nil {
+		t.Error("Circuits() without breaker should be nil")
+	}
+
+	client := New(&Config{BaseURL: "http://127.0.0.1:1", Breaker: &BreakerConfig{MinRequests: 1}})
+	req := &marketplace.DelistNFTRequest{Mint: testWallet, Owner: testWallet, Blockhash: "11111111111111111111111111111111"}
+	if _, _, err := client.Marketplace.DelistNFT(context.Background(), req); errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("first call rejected")
+	}
+	_, _, err := client.Marketplace.DelistNFT(context.Background(), req)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("DelistNFT() error = %v, want ErrCircuitOpen", err)
+	}
+	if got := len(client.Circuits()); got != len(DefaultBreakerPrefixes) {
+		t.Errorf("Circuits() = %d entries, want %d", got, len(DefaultBreakerPrefixes))
+	}
+}
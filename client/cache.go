@@ -0,0 +1,186 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/cache"
+	"github.com/srpvpn/tensor-go-sdk/internal/transport"
+)
+
+// txPathPrefix is the endpoint group for transaction building, which is never cached
+const txPathPrefix = "/api/v1/tx/"
+
+// DefaultCacheTTLs are the per endpoint group TTLs used when CacheConfig.TTLs is nil
+var DefaultCacheTTLs = map[string]time.Duration{
+	"/api/v1/collections":       time.Minute,
+	"/api/v1/mint":              30 * time.Second,
+	"/api/v1/rpc/priority_fees": 5 * time.Second,
+	"/api/v1/user/":             10 * time.Second,
+}
+
+// CacheConfig configures response caching in the transport
+type CacheConfig struct {
+	// Store is the storage backend. Defaults to an in-memory store with 1000 entries.
+	Store cache.Store
+
+	// TTLs maps endpoint path prefixes to how long their responses stay fresh.
+	// The longest matching prefix wins; paths without a match are not cached.
+	TTLs map[string]time.Duration
+
+	// StaleWhileRevalidate is how long an expired entry may still be served
+	// while it is refreshed in the background. 0 disables it.
+	StaleWhileRevalidate time.Duration
+}
+
+// cachingTransport wraps a transport.Transport and serves repeated GETs from a cache
+type cachingTransport struct {
+	next  transport.Transport
+	store cache.Store
+	ttls  map[string]time.Duration
+	stale time.Duration
+	now   func() time.Time
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+}
+
+// newCachingTransport wraps next with the caching behavior described by cfg
+func newCachingTransport(next transport.Transport, cfg CacheConfig) *cachingTransport {
+	store := cfg.Store
+	if store == nil {
+		store = cache.NewMemoryStore(1000, 0)
+	}
+	ttls := cfg.TTLs
+	if ttls == nil {
+		ttls = DefaultCacheTTLs
+	}
+
+	return &cachingTransport{
+		next:       next,
+		store:      store,
+		ttls:       ttls,
+		stale:      cfg.StaleWhileRevalidate,
+		now:        time.Now,
+		refreshing: make(map[string]bool),
+	}
+}
+
+// Get serves the request from the cache when possible and stores successful responses
+func (c *cachingTransport) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	ttl := c.ttlFor(path)
+	if ttl <= 0 {
+		return c.next.Get(ctx, path, params)
+	}
+
+	key := cacheKey(path, params)
+	now := c.now()
+
+	if entry, ok := c.store.Get(key); ok {
+		if entry.Fresh(now) {
+			return entryResponse(entry), nil
+		}
+		if now.Before(entry.ExpiresAt.Add(c.stale)) {
+			c.revalidate(ctx, key, path, params, ttl)
+			return entryResponse(entry), nil
+		}
+		c.store.Delete(key)
+	}
+
+	return c.fetch(ctx, key, path, params, ttl)
+}
+
+// fetch performs the upstream request and caches a successful response
+func (c *cachingTransport) fetch(ctx context.Context, key, path string, params url.Values, ttl time.Duration) (*http.Response, error) {
+	resp, err := c.next.Get(ctx, path, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	entry := &cache.Entry{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	c.store.Set(key, entry)
+
+	return entryResponse(entry), nil
+}
+
+// revalidate refreshes an entry in the background, at most once per key at a time
+func (c *cachingTransport) revalidate(ctx context.Context, key, path string, params url.Values, ttl time.Duration) {
+	c.mu.Lock()
+	if c.refreshing[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[key] = true
+	c.mu.Unlock()
+
+	// Detach from the caller so the refresh survives the request that triggered it
+	bgCtx := context.WithoutCancel(ctx)
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, key)
+			c.mu.Unlock()
+		}()
+		if resp, err := c.fetch(bgCtx, key, path, params, ttl); err == nil {
+			resp.Body.Close()
+		}
+	}()
+}
+
+// ttlFor returns the TTL of the longest matching prefix, or 0 if the path must not be cached
+func (c *cachingTransport) ttlFor(path string) time.Duration {
+	if strings.HasPrefix(path, txPathPrefix) {
+		return 0
+	}
+
+	var ttl time.Duration
+	longest := -1
+	for prefix, d := range c.ttls {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			longest = len(prefix)
+			ttl = d
+		}
+	}
+	return ttl
+}
+
+// cacheKey builds a key from the path and the canonical (sorted) query
+func cacheKey(path string, params url.Values) string {
+	if len(params) == 0 {
+		return path
+	}
+	return path + "?" + params.Encode()
+}
+
+// entryResponse builds a fresh *http.Response so each caller gets its own body
+func entryResponse(entry *cache.Entry) *http.Response {
+	return &http.Response{
+		StatusCode: entry.StatusCode,
+		Status:     fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		Header:     entry.Header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(entry.Body)),
+	}
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/cache"
+)
+
+func newCountingServer(t *testing.T, hits *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"hit":%d}`, n)
+	}))
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return string(body)
+}
+
+func TestCachingTransport_ServesFreshEntries(t *testing.T) {
+	var hits int32
+	server := newCountingServer(t, &hits)
+	defer server.Close()
+
+	ct := newCachingTransport(NewTransport(Config{BaseURL: server.URL, Timeout: 5 * time.Second}), CacheConfig{
+		TTLs: map[string]time.Duration{"/api/v1/collections": time.Minute},
+	})
+
+	params := url.Values{"sortBy": {"statsV2.volume1h:desc"}, "limit": {"10"}}
+	for i := 0; i < 3; i++ {
+		resp, err := ct.Get(context.Background(), "/api/v1/collections", params)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if body := readBody(t, resp); body != `{"hit":1}` {
+			t.Errorf("Get() body = %s, want first response", body)
+		}
+	}
+
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Errorf("expected 1 upstream call, got %d", hits)
+	}
+
+	// A different query is a different key
+	resp, _ := ct.Get(context.Background(), "/api/v1/collections", url.Values{"limit": {"5"}})
+	resp.Body.Close()
+	if atomic.LoadInt32(&hits) != 2 {
+		t.Errorf("expected 2 upstream calls, got %d", hits)
+	}
+}
+
+func TestCachingTransport_NeverCachesTxEndpoints(t *testing.T) {
+	var hits int32
+	server := newCountingServer(t, &hits)
+	defer server.Close()
+
+	ct := newCachingTransport(NewTransport(Config{BaseURL: server.URL, Timeout: 5 * time.Second}), CacheConfig{
+		TTLs: map[string]time.Duration{"/api/v1/": time.Minute},
+	})
+
+	for i := 0; i < 2; i++ {
+		resp, err := ct.Get(context.Background(), "/api/v1/tx/buy", nil)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if atomic.LoadInt32(&hits) != 2 {
+		t.Errorf("expected tx endpoint to bypass the cache, got %d upstream calls", hits)
+	}
+}
+
+func TestCachingTransport_TTLByLongestPrefix(t *testing.T) {
+	ct := newCachingTransport(nil, CacheConfig{
+		TTLs: map[string]time.Duration{
+			"/api/v1/mint":            time.Minute,
+			"/api/v1/mint/collection": time.Second,
+		},
+	})
+
+	if got := ct.ttlFor("/api/v1/mint"); got != time.Minute {
+		t.Errorf("ttlFor(/api/v1/mint) = %v, want 1m", got)
+	}
+	if got := ct.ttlFor("/api/v1/mint/collection"); got != time.Second {
+		t.Errorf("ttlFor(/api/v1/mint/collection) = %v, want 1s", got)
+	}
+	if got := ct.ttlFor("/api/v1/user/portfolio"); got != 0 {
+		t.Errorf("ttlFor(/api/v1/user/portfolio) = %v, want 0", got)
+	}
+}
+
+func TestCachingTransport_StaleWhileRevalidate(t *testing.T) {
+	var hits int32
+	server := newCountingServer(t, &hits)
+	defer server.Close()
+
+	store := cache.NewMemoryStore(10, 0)
+	ct := newCachingTransport(NewTransport(Config{BaseURL: server.URL, Timeout: 5 * time.Second}), CacheConfig{
+		Store:                store,
+		TTLs:                 map[string]time.Duration{"/api/v1/rpc/priority_fees": time.Second},
+		StaleWhileRevalidate: time.Minute,
+	})
+
+	now := time.Now()
+	ct.now = func() time.Time { return now }
+
+	resp, _ := ct.Get(context.Background(), "/api/v1/rpc/priority_fees", nil)
+	readBody(t, resp)
+
+	// Move past the TTL but inside the stale window
+	now = now.Add(2 * time.Second)
+	resp, err := ct.Get(context.Background(), "/api/v1/rpc/priority_fees", nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if body := readBody(t, resp); body != `{"hit":1}` {
+		t.Errorf("expected stale body to be served, got %s", body)
+	}
+
+	// The background refresh replaces the entry
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if entry, ok := store.Get("/api/v1/rpc/priority_fees"); ok && string(entry.Body) == `{"hit":2}` {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected entry to be revalidated in the background")
+}
+
+func TestCachingTransport_DoesNotCacheErrors(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ct := newCachingTransport(NewTransport(Config{BaseURL: server.URL, Timeout: 5 * time.Second}), CacheConfig{})
+
+	for i := 0; i < 2; i++ {
+		if _, err := ct.Get(context.Background(), "/api/v1/collections", nil); err == nil {
+			t.Fatal("Get() expected error")
+		}
+	}
+	if atomic.LoadInt32(&hits) != 2 {
+		t.Errorf("expected errors not to be cached, got %d upstream calls", hits)
+	}
+}
+
+func TestClient_WithCache(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		fmt.Fprint(w, `{"page":1,"total":0,"collections":[]}`)
+	}))
+	defer server.Close()
+
+	client := New(&Config{BaseURL: server.URL, Timeout: 5 * time.Second, Cache: &CacheConfig{}})
+
+	req := &collections.GetVerifiedCollectionsRequest{SortBy: "statsV2.volume1h:desc", Limit: 10}
+	for i := 0; i < 3; i++ {
+		if _, _, err := client.Collections.GetVerifiedCollections(context.Background(), req); err != nil {
+			t.Fatalf("GetVerifiedCollections() error = %v", err)
+		}
+	}
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Errorf("expected 1 upstream call, got %d", hits)
+	}
+}
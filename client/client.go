@@ -1,6 +1,9 @@
 package client
 
 import (
+	"fmt"
+	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/srpvpn/tensor-go-sdk/api/collections"
@@ -10,12 +13,16 @@ import (
 	"github.com/srpvpn/tensor-go-sdk/api/rpc"
 	"github.com/srpvpn/tensor-go-sdk/api/tswap"
 	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
 	"github.com/srpvpn/tensor-go-sdk/internal/transport"
 )
 
 // Client is the main SDK client that provides access to all API endpoints
 type Client struct {
 	transport   transport.Transport
+	breaker     *breakerTransport
+	failover    *failoverTransport
+	config      Config
 	User        user.UserAPI
 	Marketplace marketplace.MarketplaceAPI
 	TSwap       tswap.TSwapAPI
@@ -48,6 +55,9 @@ func New(config *Config) *Client {
 	}
 
 	// Set default values for missing configuration
+	if config.BaseURL == "" && config.Cluster != nil {
+		config.BaseURL = config.Cluster.TensorBaseURL
+	}
 	if config.BaseURL == "" {
 		config.BaseURL = DefaultBaseURL
 	}
@@ -55,8 +65,61 @@ func New(config *Config) *Client {
 		config.Timeout = DefaultTimeout
 	}
 
+	var logger *requestLogger
+	if config.Logger != nil {
+		logger = newRequestLogger(config.Logger, config.LogLevels, config.APIKey)
+	}
+
 	// Create transport layer
 	transport := NewTransport(*config)
+	// Spread requests over several endpoints if configured
+	var failover *failoverTransport
+	if len(config.BaseURLs) > 0 {
+		failover = newFailoverTransport(*config, config.BaseURLs)
+		transport = failover
+	}
+	// Throttle every upstream attempt if configured
+	if config.RateLimit != nil {
+		if config.RateLimit.RequestsPerSecond > 0 {
+			transport = newRateLimitTransport(transport, *config.RateLimit, logger)
+		} else {
+			warn := config.Logger
+			if warn == nil {
+				warn = slog.Default()
+			}
+			warn.Warn("tensor rate limit disabled: requests per second must be > 0",
+				slog.Float64("requests_per_second", config.RateLimit.RequestsPerSecond))
+		}
+	}
+	// Retry transient failures if configured
+	if config.Retry != nil {
+		transport = newRetryTransport(transport, *config.Retry, logger)
+	}
+	// Wrap transport with circuit breaking if configured
+	var breaker *breakerTransport
+	if config.Breaker != nil {
+		breaker = newBreakerTransport(transport, *config.Breaker)
+		transport = breaker
+	}
+	// Wrap transport with in-flight request deduplication if enabled
+	if config.CoalesceRequests {
+		transport = newCoalescingTransport(transport)
+	}
+	// Wrap transport with response caching if configured
+	if config.Cache != nil {
+		transport = newCachingTransport(transport, *config.Cache)
+	}
+	// Attach tracing, metrics and logging to the shared request path
+	var observers multiObserver
+	if config.Tracer != nil || config.Meter != nil {
+		observers = append(observers, newTelemetry(config.Tracer, config.Meter))
+	}
+	if logger != nil {
+		observers = append(observers, logger)
+	}
+	if len(observers) > 0 {
+		transport = &observedTransport{Transport: transport, observer: observers}
+	}
 	// Create User API with transport
 	userAPI := user.New(transport)
 	// Create Marketplace API with transport
@@ -68,13 +131,19 @@ func New(config *Config) *Client {
 	// Create Escrow API with transport
 	escrowAPI := escrow.New(transport)
 	// Create NFTs API with transport
-	nftsAPI := nfts.New(transport)
+	nftsAPI := nfts.New(transport,
+		nfts.WithMintsChunkSize(config.MintsChunkSize),
+		nfts.WithMintsConcurrency(config.MintsConcurrency),
+	)
 	// Create Collections API with transport
 	collectionsAPI := collections.New(transport)
 
 	// Return initialized client
 	return &Client{
 		transport:   transport,
+		breaker:     breaker,
+		failover:    failover,
+		config:      *config,
 		User:        userAPI,
 		Marketplace: marketplaceAPI,
 		TSwap:       tswapAPI,
@@ -85,6 +154,61 @@ func New(config *Config) *Client {
 	}
 }
 
+// Circuits returns the state of every circuit breaker, or nil when
+// circuit breaking is not configured
+func (c *Client) Circuits() []CircuitStatus {
+	if c.breaker == nil {
+		return nil
+	}
+	return c.breaker.Circuits()
+}
+
+// Endpoints returns the health of every configured base URL, or nil when
+// Config.BaseURLs is not set
+func (c *Client) Endpoints() []EndpointStatus {
+	if c.failover == nil {
+		return nil
+	}
+	return c.failover.Endpoints()
+}
+
+// Cluster returns the cluster configured with Config.Cluster, or nil when none is
+func (c *Client) Cluster() *cluster.Cluster {
+	return c.config.Cluster
+}
+
+// Submitter returns a cluster.Submitter for the configured cluster, using the
+// first of Config.RPCEndpoints when set and the cluster's RPC URL otherwise.
+//
+// Returns:
+//   - The Submitter
+//   - An error if no cluster is configured
+//   - A *cluster.MismatchError if a base URL belongs to a different known cluster
+func (c *Client) Submitter() (*cluster.Submitter, error) {
+	cl := c.config.Cluster
+	if cl == nil {
+		return nil, fmt.Errorf("no cluster configured: set Config.Cluster")
+	}
+	for _, baseURL := range append([]string{c.config.BaseURL}, c.config.BaseURLs...) {
+		if other, ok := cluster.ForTensorBaseURL(baseURL); ok && other.Name != cl.Name {
+			return nil, &cluster.MismatchError{
+				Cluster: cl.Name,
+				Detail:  fmt.Sprintf("base URL %s serves %s", baseURL, other.Name),
+			}
+		}
+	}
+
+	rpcURL := cl.RPCURL
+	if len(c.config.RPCEndpoints) > 0 {
+		rpcURL = c.config.RPCEndpoints[0]
+	}
+	httpClient := c.config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: c.config.Timeout}
+	}
+	return cluster.NewSubmitter(*cl, rpcURL, httpClient), nil
+}
+
 // Close closes the client and releases any resources.
 // Currently this is a no-op but provides future extensibility.
 func (c *Client) Close() error {
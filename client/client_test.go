@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
 )
 
 func TestNew(t *testing.T) {
@@ -85,6 +87,26 @@ func TestClient_Close(t *testing.T) {
 	}
 }
 
+func TestClient_Cluster(t *testing.T) {
+	c := New(&Config{Cluster: &cluster.Devnet})
+	if got := c.config.BaseURL; got != cluster.Devnet.TensorBaseURL {
+		t.Errorf("BaseURL = %q, want the devnet Tensor API", got)
+	}
+	sub, err := c.Submitter()
+	if err != nil || sub.Cluster().Name != "devnet" {
+		t.Fatalf("Submitter() = %v, %v", sub, err)
+	}
+
+	if _, err := New(nil).Submitter(); err == nil {
+		t.Error("Submitter() without a cluster succeeded")
+	}
+
+	mixed := New(&Config{Cluster: &cluster.Devnet, BaseURL: cluster.Mainnet.TensorBaseURL})
+	if _, err := mixed.Submitter(); !errors.Is(err, cluster.ErrClusterMismatch) {
+		t.Errorf("Submitter() with a mainnet base URL error = %v, want cluster mismatch", err)
+	}
+}
+
 func TestClient_IntegrationFlow(t *testing.T) {
 	// Create a test server that mimics the Tensor API
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
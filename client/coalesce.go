@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/srpvpn/tensor-go-sdk/cache"
+	"github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/internal/transport"
+)
+
+// coalescingTransport shares one upstream call among identical concurrent GETs
+type coalescingTransport struct {
+	next transport.Transport
+
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+// inflightCall is an upstream request that one or more callers are waiting on
+type inflightCall struct {
+	done    chan struct{}
+	entry   *cache.Entry
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+
+	attempts *atomic.Int32 // Upstream attempts of the shared request
+	servedBy *ServedBy     // Base URLs that served the shared request
+}
+
+// newCoalescingTransport wraps next with in-flight request deduplication
+func newCoalescingTransport(next transport.Transport) *coalescingTransport {
+	return &coalescingTransport{
+		next:  next,
+		calls: make(map[string]*inflightCall),
+	}
+}
+
+// Get joins an identical in-flight request or starts a new one.
+// Transaction-building endpoints are never shared between callers.
+func (c *coalescingTransport) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	if strings.HasPrefix(path, txPathPrefix) {
+		return c.next.Get(ctx, path, params)
+	}
+
+	key := cacheKey(path, params)
+
+	c.mu.Lock()
+	call, ok := c.calls[key]
+	if !ok {
+		// The upstream call outlives any single caller and is only
+		// cancelled once every waiter has given up. It carries none of the
+		// first caller's values, such as its span or attempt counter; every
+		// waiter is credited with the shared attempts and base URL instead.
+		upstreamCtx, attempts := transport.WithAttemptCounter(context.Background())
+		upstreamCtx, servedBy := WithServedBy(upstreamCtx)
+		upstreamCtx, cancel := context.WithCancel(upstreamCtx)
+		call = &inflightCall{
+			done:     make(chan struct{}),
+			cancel:   cancel,
+			attempts: attempts,
+			servedBy: servedBy,
+		}
+		c.calls[key] = call
+		go c.run(upstreamCtx, key, call, path, params)
+	}
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		transport.CountAttempts(ctx, call.attempts.Load())
+		for _, baseURL := range call.servedBy.All() {
+			recordServedBy(ctx, baseURL)
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		return entryResponse(call.entry), nil
+	case <-ctx.Done():
+		c.leave(key, call)
+		return nil, &errors.NetworkError{
+			Op:  "http_request",
+			Err: fmt.Errorf("HTTP request failed: %w", ctx.Err()),
+		}
+	}
+}
+
+// run performs the shared upstream request and publishes its result
+func (c *coalescingTransport) run(ctx context.Context, key string, call *inflightCall, path string, params url.Values) {
+	defer call.cancel()
+
+	resp, err := c.next.Get(ctx, path, params)
+	if err == nil {
+		var body []byte
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err == nil {
+			call.entry = &cache.Entry{
+				StatusCode: resp.StatusCode,
+				Header:     resp.Header.Clone(),
+				Body:       body,
+			}
+		}
+	}
+	call.err = err
+
+	c.mu.Lock()
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+	c.mu.Unlock()
+
+	close(call.done)
+}
+
+// leave drops a waiter and cancels the upstream request if nobody is left
+func (c *coalescingTransport) leave(key string, call *inflightCall) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call.waiters--
+	if call.waiters == 0 {
+		call.cancel()
+		if c.calls[key] == call {
+			delete(c.calls, key)
+		}
+	}
+}
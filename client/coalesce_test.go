@@ -0,0 +1,253 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apierrors "github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/internal/transport"
+)
+
+// gatedServer blocks every request until release is closed
+func gatedServer(hits *int32, release chan struct{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{"medium":100}`))
+	}))
+}
+
+// waitForHits polls until the server has seen n requests
+func waitForHits(t *testing.T, hits *int32, n int32) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(hits) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("server saw %d requests, want %d", atomic.LoadInt32(hits), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCoalescingTransport_SharesIdenticalRequests(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	server := gatedServer(&hits, release)
+	defer server.Close()
+
+	ct := newCoalescingTransport(NewTransport(Config{BaseURL: server.URL, Timeout: 5 * time.Second}))
+	params := url.Values{"mints": {"a,b"}}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	bodies := make([][]byte, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := ct.Get(context.Background(), "/api/v1/mint", params)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			defer resp.Body.Close()
+			bodies[i], errs[i] = io.ReadAll(resp.Body)
+		}(i)
+	}
+
+	waitForHits(t, &hits, 1)
+	// Give the remaining callers time to join the in-flight request
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("expected 1 upstream call, got %d", got)
+	}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("caller %d error = %v", i, errs[i])
+		}
+		if string(bodies[i]) != `{"medium":100}` {
+			t.Errorf("caller %d body = %s", i, bodies[i])
+		}
+	}
+
+	// Each caller owns its copy of the body
+	bodies[0][0] = 'X'
+	if bodies[1][0] != '{' {
+		t.Error("callers share the same body buffer")
+	}
+}
+
+func TestCoalescingTransport_CallerCancellation(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	server := gatedServer(&hits, release)
+	defer server.Close()
+
+	ct := newCoalescingTransport(NewTransport(Config{BaseURL: server.URL, Timeout: 5 * time.Second}))
+
+	patientDone := make(chan error, 1)
+	go func() {
+		resp, err := ct.Get(context.Background(), "/api/v1/rpc/priority_fees", nil)
+		if err == nil {
+			resp.Body.Close()
+		}
+		patientDone <- err
+	}()
+	waitForHits(t, &hits, 1)
+
+	// An impatient caller gives up without affecting the shared request
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := ct.Get(ctx, "/api/v1/rpc/priority_fees", nil)
+	var netErr *apierrors.NetworkError
+	if !errors.As(err, &netErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline NetworkError, got %v", err)
+	}
+
+	close(release)
+	if err := <-patientDone; err != nil {
+		t.Errorf("patient caller error = %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("expected 1 upstream call, got %d", got)
+	}
+}
+
+func TestCoalescingTransport_CancelsUpstreamWhenAllCallersLeave(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	defer close(release)
+	server := gatedServer(&hits, release)
+	defer server.Close()
+
+	ct := newCoalescingTransport(NewTransport(Config{BaseURL: server.URL, Timeout: 5 * time.Second}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for atomic.LoadInt32(&hits) < 1 {
+			time.Sleep(5 * time.Millisecond)
+		}
+		cancel()
+	}()
+	if _, err := ct.Get(ctx, "/api/v1/collections", nil); err == nil {
+		t.Fatal("expected cancellation error")
+	}
+
+	ct.mu.Lock()
+	pending := len(ct.calls)
+	ct.mu.Unlock()
+	if pending != 0 {
+		t.Errorf("expected no in-flight calls, got %d", pending)
+	}
+
+	// A later caller starts a fresh upstream request
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel2()
+	ct.Get(ctx2, "/api/v1/collections", nil)
+	waitForHits(t, &hits, 2)
+}
+
+func TestCoalescingTransport_DoesNotShareTxRequests(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	server := gatedServer(&hits, release)
+	defer server.Close()
+
+	ct := newCoalescingTransport(NewTransport(Config{BaseURL: server.URL, Timeout: 5 * time.Second}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp, err := ct.Get(context.Background(), "/api/v1/tx/buy", nil); err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+	waitForHits(t, &hits, 2)
+	close(release)
+	wg.Wait()
+}
+
+type callerKey struct{}
+
+// ctxSpy records the callerKey value of the contexts it forwards
+type ctxSpy struct {
+	transport.Transport
+	seen atomic.Value
+}
+
+func (s *ctxSpy) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	s.seen.Store(fmt.Sprint(ctx.Value(callerKey{})))
+	return s.Transport.Get(ctx, path, params)
+}
+
+func TestCoalescingTransport_IsolatesCallerContexts(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	server := gatedServer(&hits, release)
+	defer server.Close()
+
+	spy := &ctxSpy{Transport: newFailoverTransport(Config{Timeout: 5 * time.Second}, []string{server.URL})}
+	ct := newCoalescingTransport(spy)
+
+	type caller struct {
+		attempts *atomic.Int32
+		servedBy *ServedBy
+		err      error
+	}
+	callers := make([]caller, 2)
+	var wg sync.WaitGroup
+	for i := range callers {
+		ctx := context.WithValue(context.Background(), callerKey{}, i)
+		ctx, callers[i].attempts = transport.WithAttemptCounter(ctx)
+		ctx, callers[i].servedBy = WithServedBy(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := ct.Get(ctx, "/api/v1/mint", nil)
+			if err == nil {
+				resp.Body.Close()
+			}
+			callers[i].err = err
+		}()
+		// The first caller starts the shared request; the second joins it
+		waitForHits(t, &hits, 1)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := spy.seen.Load(); got != "<nil>" {
+		t.Errorf("upstream request carried caller value %v, want none", got)
+	}
+	for i, c := range callers {
+		if c.err != nil {
+			t.Fatalf("caller %d error = %v", i, c.err)
+		}
+		if got := c.attempts.Load(); got != 1 {
+			t.Errorf("caller %d counted %d attempts, want 1", i, got)
+		}
+		if got := c.servedBy.Last(); got != server.URL {
+			t.Errorf("caller %d served by %q, want %q", i, got, server.URL)
+		}
+	}
+}
@@ -1,10 +1,82 @@
 // client/config.go
 package client
 
-import "time"
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+)
 
 type Config struct {
 	APIKey  string
 	BaseURL string
 	Timeout time.Duration
+
+	// BaseURLs lists several API endpoints, e.g. regional proxies, to use
+	// instead of BaseURL. Requests go to the first healthy one and fail over
+	// on network errors and 5xx responses.
+	BaseURLs []string
+
+	// Failover tunes health tracking and request hedging across BaseURLs
+	Failover FailoverConfig
+
+	// HTTPClient is used for all requests when set, e.g. to plug in a
+	// cassette.Recorder or cassette.Replayer. Timeout applies if it has none.
+	HTTPClient *http.Client
+
+	// MintsChunkSize caps how many mints GetNFTsInfo sends per request.
+	// Defaults to nfts.DefaultMintsChunkSize.
+	MintsChunkSize int
+
+	// MintsConcurrency caps how many GetNFTsInfo chunks are fetched in parallel.
+	// Defaults to nfts.DefaultMintsConcurrency.
+	MintsConcurrency int
+
+	// CoalesceRequests shares one upstream call among identical concurrent reads
+	CoalesceRequests bool
+
+	// Cache enables response caching for read endpoints when set
+	Cache *CacheConfig
+
+	// Logger receives structured events for every request when set: start and
+	// end, validation, rate limit and decode failures. API keys and wallet
+	// addresses are redacted.
+	Logger *slog.Logger
+
+	// LogLevels sets the minimum level logged per endpoint group (see
+	// EndpointGroup), e.g. {GroupUser: slog.LevelWarn} to log only user failures
+	LogLevels map[string]slog.Level
+
+	// Tracer receives one span per API call when set
+	Tracer Tracer
+
+	// Meter records request counts, latency histograms and error counters
+	// when set. See prommetrics for a Prometheus text exposition adapter.
+	Meter Meter
+
+	// Breaker enables per endpoint group circuit breaking when set
+	Breaker *BreakerConfig
+
+	// Retry enables retries of network errors, 429 and 5xx responses when set
+	Retry *RetryConfig
+
+	// RateLimit throttles outgoing requests when set. Each retry attempt
+	// consumes its own token.
+	RateLimit *RateLimitConfig
+
+	// Cluster ties the client to a Solana cluster. BaseURL defaults to the
+	// cluster's Tensor API and Client.Submitter refuses transactions built
+	// for other clusters.
+	Cluster *cluster.Cluster
+
+	// RPCEndpoints lists Solana RPC URLs for submitting signed transactions,
+	// overriding the cluster's RPC URL in Client.Submitter. The API client
+	// itself does not use them.
+	RPCEndpoints []string
+
+	// KeypairPath is the path of the signing keypair file. Like RPCEndpoints
+	// it is carried for the caller and not read by the API client.
+	KeypairPath string
 }
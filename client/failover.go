@@ -0,0 +1,394 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/internal/transport"
+)
+
+// BaseURLHeader is set on every response served through a multi-endpoint
+// client to the base URL that answered it
+const BaseURLHeader = "X-Tensor-Base-Url"
+
+// FailoverConfig tunes health tracking and hedging when Config.BaseURLs lists
+// several endpoints. The zero value is usable.
+type FailoverConfig struct {
+	// UnhealthyAfter is the number of consecutive failures, network errors or
+	// 5xx responses, after which an endpoint is tried last. Defaults to 3.
+	UnhealthyAfter int
+
+	// RecheckAfter is how long an unhealthy endpoint stays demoted after its
+	// last failure. Defaults to 30s.
+	RecheckAfter time.Duration
+
+	// Hedge sends a second copy of a slow read request to the next endpoint
+	// once the first has been outstanding longer than the p95 latency of the
+	// path. The first successful response wins. Transaction-building
+	// endpoints are never hedged.
+	Hedge bool
+
+	// HedgeDelay replaces the observed p95 with a fixed delay when set
+	HedgeDelay time.Duration
+
+	// HedgeMinSamples is how many successful calls to a path are needed
+	// before its p95 is trusted for hedging. Defaults to 20.
+	HedgeMinSamples int
+}
+
+// EndpointStatus is a snapshot of the health of one base URL
+type EndpointStatus struct {
+	URL                 string
+	Healthy             bool
+	ConsecutiveFailures int
+	LastError           string
+	LastFailure         time.Time
+	Served              int64 // Calls this endpoint answered successfully
+}
+
+// ServedBy collects the base URLs that served the calls made with a context
+type ServedBy struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+type servedByKey struct{}
+
+// WithServedBy returns a context that records which base URL serves each call made with it
+func WithServedBy(ctx context.Context) (context.Context, *ServedBy) {
+	s := &ServedBy{}
+	return context.WithValue(ctx, servedByKey{}, s), s
+}
+
+// Last returns the base URL that served the most recent call, or "" if none completed
+func (s *ServedBy) Last() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.urls) == 0 {
+		return ""
+	}
+	return s.urls[len(s.urls)-1]
+}
+
+// All returns the base URLs that served calls, in completion order
+func (s *ServedBy) All() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.urls...)
+}
+
+func recordServedBy(ctx context.Context, url string) {
+	if s, ok := ctx.Value(servedByKey{}).(*ServedBy); ok {
+		s.mu.Lock()
+		s.urls = append(s.urls, url)
+		s.mu.Unlock()
+	}
+}
+
+// latencyWindow is how many recent successful latencies are kept per path
+const latencyWindow = 128
+
+// endpoint is one base URL with its health
+type endpoint struct {
+	url       string
+	transport transport.Transport
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	lastError           string
+	lastFailure         time.Time
+	served              int64
+}
+
+// failoverTransport spreads requests over several base URLs
+type failoverTransport struct {
+	endpoints []*endpoint
+	cfg       FailoverConfig
+	now       func() time.Time
+
+	mu        sync.Mutex
+	latencies map[string][]time.Duration // Ring buffers of successful latencies per path
+	next      map[string]int             // Next ring buffer slot per path
+}
+
+// newFailoverTransport creates a transport over baseURLs, each using the rest of cfg
+func newFailoverTransport(cfg Config, baseURLs []string) *failoverTransport {
+	fc := cfg.Failover
+	if fc.UnhealthyAfter <= 0 {
+		fc.UnhealthyAfter = 3
+	}
+	if fc.RecheckAfter <= 0 {
+		fc.RecheckAfter = 30 * time.Second
+	}
+	if fc.HedgeMinSamples <= 0 {
+		fc.HedgeMinSamples = 20
+	}
+
+	f := &failoverTransport{
+		cfg:       fc,
+		now:       time.Now,
+		latencies: make(map[string][]time.Duration),
+		next:      make(map[string]int),
+	}
+	for _, u := range baseURLs {
+		epCfg := cfg
+		epCfg.BaseURL = u
+		f.endpoints = append(f.endpoints, &endpoint{url: strings.TrimSuffix(u, "/"), transport: NewTransport(epCfg)})
+	}
+	return f
+}
+
+// Endpoints returns the health of every base URL in configuration order
+func (f *failoverTransport) Endpoints() []EndpointStatus {
+	now := f.now()
+	out := make([]EndpointStatus, len(f.endpoints))
+	for i, ep := range f.endpoints {
+		ep.mu.Lock()
+		out[i] = EndpointStatus{
+			URL:                 ep.url,
+			Healthy:             f.healthy(ep, now),
+			ConsecutiveFailures: ep.consecutiveFailures,
+			LastError:           ep.lastError,
+			LastFailure:         ep.lastFailure,
+			Served:              ep.served,
+		}
+		ep.mu.Unlock()
+	}
+	return out
+}
+
+// healthy reports whether ep should be preferred. Callers must hold ep.mu.
+func (f *failoverTransport) healthy(ep *endpoint, now time.Time) bool {
+	return ep.consecutiveFailures < f.cfg.UnhealthyAfter || now.Sub(ep.lastFailure) >= f.cfg.RecheckAfter
+}
+
+// order returns healthy endpoints in configuration order followed by
+// unhealthy ones, least recently failed first
+func (f *failoverTransport) order() []*endpoint {
+	now := f.now()
+	type failed struct {
+		ep *endpoint
+		at time.Time // ep.lastFailure, read under ep.mu
+	}
+	var healthy []*endpoint
+	var unhealthy []failed
+	for _, ep := range f.endpoints {
+		ep.mu.Lock()
+		ok := f.healthy(ep, now)
+		at := ep.lastFailure
+		ep.mu.Unlock()
+		if ok {
+			healthy = append(healthy, ep)
+		} else {
+			unhealthy = append(unhealthy, failed{ep: ep, at: at})
+		}
+	}
+	sort.SliceStable(unhealthy, func(i, j int) bool {
+		return unhealthy[i].at.Before(unhealthy[j].at)
+	})
+	for _, u := range unhealthy {
+		healthy = append(healthy, u.ep)
+	}
+	return healthy
+}
+
+// Get sends the request to the preferred endpoint, failing over on network
+// errors and 5xx responses and hedging slow reads when enabled
+func (f *failoverTransport) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	order := f.order()
+	if f.cfg.Hedge && len(order) > 1 && !strings.HasPrefix(path, txPathPrefix) {
+		if delay, ok := f.hedgeDelay(path); ok {
+			return f.hedged(ctx, path, params, order, delay)
+		}
+	}
+
+	var lastErr error
+	for _, ep := range order {
+		resp, err := f.try(ctx, ep, path, params)
+		if err == nil {
+			f.served(ctx, ep, resp)
+			return resp, nil
+		}
+		if !shouldFailover(ctx, err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// attempt is the outcome of one request in a hedged call
+type attempt struct {
+	id   int
+	ep   *endpoint
+	resp *http.Response
+	err  error
+}
+
+// hedged races the primary endpoint against a second one started after delay.
+// Failed attempts still fail over to the remaining endpoints.
+func (f *failoverTransport) hedged(ctx context.Context, path string, params url.Values, order []*endpoint, delay time.Duration) (*http.Response, error) {
+	results := make(chan attempt, len(order))
+	cancels := make([]context.CancelFunc, 0, len(order))
+	inflight := 0
+	launch := func() {
+		id, ep := len(cancels), order[len(cancels)]
+		attemptCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		inflight++
+		go func() {
+			resp, err := f.try(attemptCtx, ep, path, params)
+			results <- attempt{id: id, ep: ep, resp: resp, err: err}
+		}()
+	}
+	// abandon cancels every attempt except keep and closes late responses in the background
+	abandon := func(keep int) {
+		for id, cancel := range cancels {
+			if id != keep {
+				cancel()
+			}
+		}
+		go func(remaining int) {
+			for i := 0; i < remaining; i++ {
+				if r := <-results; r.resp != nil {
+					r.resp.Body.Close()
+				}
+			}
+		}(inflight)
+	}
+
+	launch()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var lastErr error
+	for inflight > 0 {
+		select {
+		case <-timer.C:
+			// Hedge once; further endpoints are only used for failover
+			if len(cancels) < len(order) {
+				launch()
+			}
+		case r := <-results:
+			inflight--
+			if r.err == nil {
+				abandon(r.id)
+				// The winner's context must outlive this call until the body is read
+				r.resp.Body = &cancelOnClose{ReadCloser: r.resp.Body, cancel: cancels[r.id]}
+				f.served(ctx, r.ep, r.resp)
+				return r.resp, nil
+			}
+			if !shouldFailover(ctx, r.err) {
+				abandon(-1)
+				return nil, r.err
+			}
+			lastErr = r.err
+			if inflight == 0 && len(cancels) < len(order) {
+				launch()
+			}
+		}
+	}
+	abandon(-1)
+	return nil, lastErr
+}
+
+// try sends the request to one endpoint and updates its health
+func (f *failoverTransport) try(ctx context.Context, ep *endpoint, path string, params url.Values) (*http.Response, error) {
+	start := f.now()
+	resp, err := ep.transport.Get(ctx, path, params)
+	elapsed := f.now().Sub(start)
+
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	switch {
+	case err == nil:
+		ep.consecutiveFailures = 0
+		f.observeLatency(path, elapsed)
+	case shouldFailover(ctx, err):
+		ep.consecutiveFailures++
+		ep.lastError = err.Error()
+		ep.lastFailure = f.now()
+	default:
+		// Client errors say nothing bad about the endpoint
+		var apiErr *errors.APIError
+		if stderrors.As(err, &apiErr) {
+			ep.consecutiveFailures = 0
+		}
+	}
+	return resp, err
+}
+
+// served marks ep as the endpoint that answered the call
+func (f *failoverTransport) served(ctx context.Context, ep *endpoint, resp *http.Response) {
+	ep.mu.Lock()
+	ep.served++
+	ep.mu.Unlock()
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+	resp.Header.Set(BaseURLHeader, ep.url)
+	recordServedBy(ctx, ep.url)
+}
+
+// shouldFailover reports whether err is an endpoint failure worth trying elsewhere
+func shouldFailover(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
+		return apiErr.Code >= 500
+	}
+	var netErr *errors.NetworkError
+	return stderrors.As(err, &netErr)
+}
+
+// observeLatency records a successful latency for path
+func (f *failoverTransport) observeLatency(path string, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ring := f.latencies[path]
+	if len(ring) < latencyWindow {
+		f.latencies[path] = append(ring, d)
+		return
+	}
+	ring[f.next[path]] = d
+	f.next[path] = (f.next[path] + 1) % latencyWindow
+}
+
+// hedgeDelay returns how long to wait before hedging a request to path
+func (f *failoverTransport) hedgeDelay(path string) (time.Duration, bool) {
+	if f.cfg.HedgeDelay > 0 {
+		return f.cfg.HedgeDelay, true
+	}
+	f.mu.Lock()
+	samples := append([]time.Duration(nil), f.latencies[path]...)
+	f.mu.Unlock()
+	if len(samples) < f.cfg.HedgeMinSamples {
+		return 0, false
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	idx := (len(samples)*95+99)/100 - 1
+	return samples[idx], true
+}
+
+// cancelOnClose releases a request context once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request context
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/rpc"
+)
+
+// countingServer answers every request with status after delay
+func countingServer(t *testing.T, hits *int32, status int, delay time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"medium":1}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFailover_ServerErrorAndNetworkError(t *testing.T) {
+	var badHits, goodHits int32
+	bad := countingServer(t, &badHits, http.StatusBadGateway, 0)
+	good := countingServer(t, &goodHits, http.StatusOK, 0)
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	client := New(&Config{BaseURLs: []string{down.URL, bad.URL, good.URL}})
+	ctx, served := WithServedBy(context.Background())
+
+	resp, status, err := client.RPC.GetPriorityFees(ctx, &rpc.PriorityFeesRequest{})
+	if err != nil || status != 200 || resp.Medium != 1 {
+		t.Fatalf("GetPriorityFees() = %v, %d, %v", resp, status, err)
+	}
+	if served.Last() != good.URL {
+		t.Errorf("served by %q, want %q", served.Last(), good.URL)
+	}
+	if badHits != 1 || goodHits != 1 {
+		t.Errorf("hits bad=%d good=%d, want 1 each", badHits, goodHits)
+	}
+
+	endpoints := client.Endpoints()
+	if len(endpoints) != 3 {
+		t.Fatalf("Endpoints() = %d entries, want 3", len(endpoints))
+	}
+	if endpoints[0].ConsecutiveFailures != 1 || endpoints[0].LastError == "" {
+		t.Errorf("down endpoint status = %+v", endpoints[0])
+	}
+	if endpoints[2].Served != 1 || !endpoints[2].Healthy {
+		t.Errorf("good endpoint status = %+v", endpoints[2])
+	}
+}
+
+func TestFailover_DemotesUnhealthyEndpoints(t *testing.T) {
+	var badHits, goodHits int32
+	bad := countingServer(t, &badHits, http.StatusServiceUnavailable, 0)
+	good := countingServer(t, &goodHits, http.StatusOK, 0)
+
+	client := New(&Config{BaseURLs: []string{bad.URL, good.URL}, Failover: FailoverConfig{UnhealthyAfter: 1, RecheckAfter: time.Hour}})
+	for i := 0; i < 3; i++ {
+		if _, _, err := client.RPC.GetPriorityFees(context.Background(), &rpc.PriorityFeesRequest{}); err != nil {
+			t.Fatalf("GetPriorityFees() error = %v", err)
+		}
+	}
+	if badHits != 1 || goodHits != 3 {
+		t.Errorf("hits bad=%d good=%d, want 1 and 3", badHits, goodHits)
+	}
+	if client.Endpoints()[0].Healthy {
+		t.Error("failing endpoint still reported healthy")
+	}
+}
+
+func TestFailover_ClientErrorsDoNotFailOver(t *testing.T) {
+	var aHits, bHits int32
+	a := countingServer(t, &aHits, http.StatusBadRequest, 0)
+	b := countingServer(t, &bHits, http.StatusOK, 0)
+
+	client := New(&Config{BaseURLs: []string{a.URL, b.URL}})
+	_, status, err := client.RPC.GetPriorityFees(context.Background(), &rpc.PriorityFeesRequest{})
+	if err == nil || status != 400 {
+		t.Errorf("GetPriorityFees() = %d, %v; want 400 error", status, err)
+	}
+	if bHits != 0 {
+		t.Errorf("second endpoint saw %d requests, want 0", bHits)
+	}
+}
+
+func TestFailover_HedgesSlowReads(t *testing.T) {
+	var slowHits, fastHits int32
+	slow := countingServer(t, &slowHits, http.StatusOK, 500*time.Millisecond)
+	fast := countingServer(t, &fastHits, http.StatusOK, 0)
+
+	tracer := &testTracer{}
+	client := New(&Config{
+		BaseURLs: []string{slow.URL, fast.URL},
+		Failover: FailoverConfig{Hedge: true, HedgeDelay: 20 * time.Millisecond},
+		Tracer:   tracer,
+	})
+	ctx, served := WithServedBy(context.Background())
+
+	start := time.Now()
+	if _, _, err := client.RPC.GetPriorityFees(ctx, &rpc.PriorityFeesRequest{}); err != nil {
+		t.Fatalf("GetPriorityFees() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 400*time.Millisecond {
+		t.Errorf("hedged call took %v", elapsed)
+	}
+	if served.Last() != fast.URL {
+		t.Errorf("served by %q, want %q", served.Last(), fast.URL)
+	}
+	span := tracer.spans[0]
+	if span.attrs["tensor.attempts"] != "2" || span.attrs["tensor.base_url"] != fast.URL {
+		t.Errorf("span attrs = %v", span.attrs)
+	}
+}
+
+func TestFailover_NeverHedgesTransactions(t *testing.T) {
+	var slowHits, fastHits int32
+	slow := countingServer(t, &slowHits, http.StatusOK, 100*time.Millisecond)
+	fast := countingServer(t, &fastHits, http.StatusOK, 0)
+
+	client := New(&Config{
+		BaseURLs: []string{slow.URL, fast.URL},
+		Failover: FailoverConfig{Hedge: true, HedgeDelay: time.Millisecond},
+	})
+	ctx, served := WithServedBy(context.Background())
+	req := &marketplace.DelistNFTRequest{Mint: testWallet, Owner: testWallet, Blockhash: "11111111111111111111111111111111"}
+	if _, _, err := client.Marketplace.DelistNFT(ctx, req); err != nil {
+		t.Fatalf("DelistNFT() error = %v", err)
+	}
+	if served.Last() != slow.URL || fastHits != 0 {
+		t.Errorf("served by %q with %d hedges, want %q and none", served.Last(), fastHits, slow.URL)
+	}
+}
+
+func TestFailover_HedgeDelayFromP95(t *testing.T) {
+	f := newFailoverTransport(Config{Timeout: time.Second}, []string{"http://a", "http://b"})
+	f.cfg.Hedge = true
+	if _, ok := f.hedgeDelay("/p"); ok {
+		t.Error("hedgeDelay() without samples should not hedge")
+	}
+	for i := 1; i <= 100; i++ {
+		f.observeLatency("/p", time.Duration(i)*time.Millisecond)
+	}
+	if d, ok := f.hedgeDelay("/p"); !ok || d != 95*time.Millisecond {
+		t.Errorf("hedgeDelay() = %v, %v; want 95ms", d, ok)
+	}
+}
+
+func TestCancelOnClose(t *testing.T) {
+	cancelled := false
+	body := &cancelOnClose{ReadCloser: io.NopCloser(nil), cancel: func() { cancelled = true }}
+	body.Close()
+	if !cancelled {
+		t.Error("Close() did not cancel the request context")
+	}
+}
@@ -0,0 +1,594 @@
+package client
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/subosito/gotenv"
+	"gopkg.in/yaml.v3"
+
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+)
+
+// Environment variables read by NewFromEnv and LoadConfig. List values such as
+// TENSOR_BASE_URL and TENSOR_RPC_ENDPOINTS are comma separated; durations
+// accept Go syntax ("1m30s") or a plain number of seconds.
+const (
+	EnvProfile             = "TENSOR_PROFILE"
+	EnvCluster             = "TENSOR_CLUSTER"
+	EnvAPIKey              = "TENSOR_API_KEY"
+	EnvBaseURL             = "TENSOR_BASE_URL"
+	EnvTimeout             = "TENSOR_TIMEOUT"
+	EnvRetryMaxAttempts    = "TENSOR_RETRY_MAX_ATTEMPTS"
+	EnvRetryInitialBackoff = "TENSOR_RETRY_INITIAL_BACKOFF"
+	EnvRetryMaxBackoff     = "TENSOR_RETRY_MAX_BACKOFF"
+	EnvRateLimitRPS        = "TENSOR_RATE_LIMIT_RPS"
+	EnvRateLimitBurst      = "TENSOR_RATE_LIMIT_BURST"
+	EnvRPCEndpoints        = "TENSOR_RPC_ENDPOINTS"
+	EnvKeypairPath         = "TENSOR_KEYPAIR_PATH"
+)
+
+// Built-in profile names. Config files may override their settings or
+// define profiles of their own.
+const (
+	ProfileMainnetProd = "mainnet-prod"
+	ProfileStaging     = "staging"
+	ProfileLocalMock   = "local-mock"
+)
+
+// DotEnvFile is the file NewFromEnv reads from the working directory when present
+const DotEnvFile = ".env"
+
+// setting is one configuration value, named as in config files
+type setting struct {
+	name string
+	env  string
+}
+
+var settings = []setting{
+	{name: "cluster", env: EnvCluster},
+	{name: "api_key", env: EnvAPIKey},
+	{name: "base_url", env: EnvBaseURL},
+	{name: "timeout", env: EnvTimeout},
+	{name: "retry.max_attempts", env: EnvRetryMaxAttempts},
+	{name: "retry.initial_backoff", env: EnvRetryInitialBackoff},
+	{name: "retry.max_backoff", env: EnvRetryMaxBackoff},
+	{name: "rate_limit.requests_per_second", env: EnvRateLimitRPS},
+	{name: "rate_limit.burst", env: EnvRateLimitBurst},
+	{name: "rpc_endpoints", env: EnvRPCEndpoints},
+	{name: "keypair_path", env: EnvKeypairPath},
+}
+
+// settingAliases maps alternative normalized names to setting names
+var settingAliases = map[string]string{
+	"baseurls": "base_url",
+	"keypair":  "keypair_path",
+}
+
+// builtinProfile is a named set of defaults shipped with the SDK
+type builtinProfile struct {
+	requireAPIKey bool
+	values        map[string]string
+}
+
+var builtinProfiles = map[string]builtinProfile{
+	ProfileMainnetProd: {
+		requireAPIKey: true,
+		values: map[string]string{
+			"cluster":            cluster.Mainnet.Name,
+			"timeout":            "30s",
+			"retry.max_attempts": "3",
+		},
+	},
+	ProfileStaging: {
+		requireAPIKey: true,
+		values: map[string]string{
+			"cluster":            cluster.Devnet.Name,
+			"timeout":            "30s",
+			"retry.max_attempts": "3",
+		},
+	},
+	ProfileLocalMock: {
+		values: map[string]string{
+			"cluster": cluster.Localnet.Name,
+			"timeout": "5s",
+		},
+	},
+}
+
+// ConfigError reports every problem found while loading a configuration,
+// so that all of them can be fixed in one go
+type ConfigError struct {
+	Problems []string
+}
+
+// Error implements the error interface
+func (e *ConfigError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid tensor config: " + e.Problems[0]
+	}
+	return fmt.Sprintf("invalid tensor config (%d problems):\n  - %s",
+		len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// NewFromEnv creates a Client configured from TENSOR_* environment variables.
+// A .env file in the working directory is read first when present; variables
+// already set in the environment take precedence over it. TENSOR_PROFILE
+// selects a built-in profile to use as defaults.
+//
+// Returns:
+//   - A Client built from the resulting Config
+//   - A *ConfigError listing every invalid or missing value
+func NewFromEnv() (*Client, error) {
+	config, err := LoadEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+	return New(config), nil
+}
+
+// LoadEnvConfig builds the Config NewFromEnv would use without creating a Client
+func LoadEnvConfig() (*Config, error) {
+	l := &loader{}
+	var dotenv layer
+	if _, err := os.Stat(DotEnvFile); err == nil {
+		dotenv, err = l.readFile(DotEnvFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	profile, _ := os.LookupEnv(EnvProfile)
+	if profile == "" && dotenv.profile != nil {
+		profile = dotenv.profile.value
+	}
+	if _, ok := builtinProfiles[profile]; profile != "" && !ok {
+		l.problem("%s: unknown profile %q", EnvProfile, profile)
+	}
+	return l.build(profile, nil, dotenv.values, envValues())
+}
+
+// LoadConfig reads a Config from a file. The format follows the extension:
+// .yaml/.yml, .toml, .json or .env (TENSOR_* variables in dotenv syntax).
+// TENSOR_* environment variables override values from the file.
+//
+// Structured files use the setting names below, either at the top level or
+// per profile; the profile used is TENSOR_PROFILE, else the file's "profile" key.
+//
+//	profile: staging
+//	cluster: devnet
+//	timeout: 10s
+//	retry:
+//	  max_attempts: 5
+//	profiles:
+//	  staging:
+//	    api_key: ...
+//	    rate_limit:
+//	      requests_per_second: 2
+//
+// Returns:
+//   - The loaded Config
+//   - A *ConfigError listing every invalid or missing value
+func LoadConfig(path string) (*Config, error) {
+	return LoadConfigProfile(path, "")
+}
+
+// LoadConfigProfile is like LoadConfig but uses the given profile, ignoring
+// TENSOR_PROFILE and the file's "profile" key. An empty profile behaves like LoadConfig.
+func LoadConfigProfile(path, profile string) (*Config, error) {
+	l := &loader{}
+	file, err := l.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if profile == "" {
+		profile, _ = os.LookupEnv(EnvProfile)
+	}
+	if profile == "" && file.profile != nil {
+		profile = file.profile.value
+	}
+
+	var fromProfile map[string]sourced
+	if profile != "" {
+		fromProfile = file.profiles[profile]
+		if fromProfile == nil {
+			if _, ok := builtinProfiles[profile]; !ok {
+				l.problem("%s: unknown profile %q", path, profile)
+			}
+		}
+	}
+	return l.build(profile, file.values, fromProfile, envValues())
+}
+
+// sourced is a raw setting value and where it came from, for error messages
+type sourced struct {
+	value  string
+	origin string
+}
+
+// layer is the content of one config file
+type layer struct {
+	profile  *sourced
+	values   map[string]sourced
+	profiles map[string]map[string]sourced
+}
+
+// loader accumulates problems while reading and validating configuration
+type loader struct {
+	problems []string
+}
+
+func (l *loader) problem(format string, args ...any) {
+	l.problems = append(l.problems, fmt.Sprintf(format, args...))
+}
+
+// envValues reads the TENSOR_* settings from the process environment
+func envValues() map[string]sourced {
+	values := make(map[string]sourced)
+	for _, s := range settings {
+		if v, ok := os.LookupEnv(s.env); ok && v != "" {
+			values[s.name] = sourced{value: v, origin: s.env}
+		}
+	}
+	return values
+}
+
+// readFile parses a config file. Problems with individual values are recorded
+// on the loader; an unreadable or malformed file is returned as an error.
+func (l *loader) readFile(path string) (layer, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if strings.HasPrefix(filepath.Base(path), ".env") {
+		ext = ".env"
+	}
+
+	if ext == ".env" {
+		return l.readDotEnv(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return layer{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root map[string]any
+	switch ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &root)
+	case ".toml":
+		err = toml.Unmarshal(data, &root)
+	case ".json":
+		err = json.Unmarshal(data, &root)
+	default:
+		return layer{}, fmt.Errorf("unsupported config file extension %q: use .yaml, .yml, .toml, .json or .env", ext)
+	}
+	if err != nil {
+		return layer{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	out := layer{values: make(map[string]sourced), profiles: make(map[string]map[string]sourced)}
+	for _, key := range sortedKeys(root) {
+		value := root[key]
+		switch normalizeKey(key) {
+		case "profile":
+			name, ok := value.(string)
+			if !ok {
+				l.problem("%s: profile: must be a string", path)
+				continue
+			}
+			out.profile = &sourced{value: name, origin: path + ": profile"}
+		case "profiles":
+			profiles, ok := value.(map[string]any)
+			if !ok {
+				l.problem("%s: profiles: must be a table of named profiles", path)
+				continue
+			}
+			for _, name := range sortedKeys(profiles) {
+				section, ok := profiles[name].(map[string]any)
+				if !ok {
+					l.problem("%s: profiles.%s: must be a table of settings", path, name)
+					continue
+				}
+				values := make(map[string]sourced)
+				l.flatten(path, key+"."+name, "", section, values)
+				out.profiles[name] = values
+			}
+		default:
+			l.flatten(path, "", key, value, out.values)
+		}
+	}
+	return out, nil
+}
+
+// readDotEnv parses a dotenv file of TENSOR_* variables
+func (l *loader) readDotEnv(path string) (layer, error) {
+	env, err := gotenv.Read(path)
+	if err != nil {
+		return layer{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	out := layer{values: make(map[string]sourced)}
+	known := make(map[string]string, len(settings))
+	for _, s := range settings {
+		known[s.env] = s.name
+	}
+	for _, key := range sortedKeys(env) {
+		origin := path + ": " + key
+		switch name, ok := known[key]; {
+		case key == EnvProfile:
+			out.profile = &sourced{value: env[key], origin: origin}
+		case ok:
+			if env[key] != "" {
+				out.values[name] = sourced{value: env[key], origin: origin}
+			}
+		case strings.HasPrefix(key, "TENSOR_"):
+			l.problem("%s: unknown setting", origin)
+		}
+	}
+	return out, nil
+}
+
+// flatten records value under its setting name, descending into tables.
+// prefix is the path of the enclosing profile, used only in messages.
+func (l *loader) flatten(path, prefix, key string, value any, out map[string]sourced) {
+	display := strings.TrimPrefix(prefix+"."+key, ".")
+	if table, ok := value.(map[string]any); ok {
+		for _, k := range sortedKeys(table) {
+			l.flatten(path, prefix, strings.TrimPrefix(key+"."+k, "."), table[k], out)
+		}
+		return
+	}
+
+	name, ok := lookupSetting(key)
+	if !ok {
+		l.problem("%s: %s: unknown setting", path, display)
+		return
+	}
+	raw, ok := scalarString(value)
+	if !ok {
+		l.problem("%s: %s: must be a string, number or list", path, display)
+		return
+	}
+	out[name] = sourced{value: raw, origin: path + ": " + display}
+}
+
+// lookupSetting resolves a dotted key to a setting name, ignoring case,
+// underscores and dashes so api_key, apiKey and api-key all match
+func lookupSetting(key string) (string, bool) {
+	norm := normalizeKey(key)
+	for _, s := range settings {
+		if normalizeKey(s.name) == norm {
+			return s.name, true
+		}
+	}
+	name, ok := settingAliases[norm]
+	return name, ok
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
+
+// scalarString renders a decoded value as the string form used by environment variables
+func scalarString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int, int64, uint64, bool:
+		return fmt.Sprint(v), true
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := scalarString(item)
+			if !ok {
+				return "", false
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), true
+	default:
+		return "", false
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// build merges the built-in profile defaults and the given layers, later
+// layers winning, then validates the result into a Config
+func (l *loader) build(profile string, layers ...map[string]sourced) (*Config, error) {
+	merged := make(map[string]sourced)
+	builtin, isBuiltin := builtinProfiles[profile]
+	if isBuiltin {
+		for name, v := range builtin.values {
+			merged[name] = sourced{value: v, origin: "profile " + profile + ": " + name}
+		}
+	}
+	for _, values := range layers {
+		for name, v := range values {
+			merged[name] = v
+		}
+	}
+
+	config := &Config{}
+	if v, ok := merged["api_key"]; ok {
+		if strings.ContainsAny(v.value, " \t\r\n") {
+			l.problem("%s: must not contain whitespace", v.origin)
+		}
+		config.APIKey = v.value
+	} else if builtin.requireAPIKey {
+		l.problem("api_key is required for profile %s (set %s)", profile, EnvAPIKey)
+	}
+
+	if v, ok := merged["cluster"]; ok {
+		c, known := cluster.ByName(v.value)
+		if !known {
+			l.problem("%s: unknown cluster %q", v.origin, v.value)
+		} else {
+			config.Cluster = &c
+		}
+	}
+
+	if v, ok := merged["base_url"]; ok {
+		urls := l.urls(v, "http", "https")
+		if len(urls) > 0 {
+			config.BaseURL = urls[0]
+		}
+		if len(urls) > 1 {
+			config.BaseURLs = urls
+		}
+	}
+	if v, ok := merged["timeout"]; ok {
+		config.Timeout = l.duration(v)
+	}
+
+	_, hasAttempts := merged["retry.max_attempts"]
+	_, hasInitial := merged["retry.initial_backoff"]
+	_, hasMax := merged["retry.max_backoff"]
+	if hasAttempts || hasInitial || hasMax {
+		retry := &RetryConfig{}
+		if v, ok := merged["retry.max_attempts"]; ok {
+			retry.MaxAttempts = l.positiveInt(v)
+		}
+		if v, ok := merged["retry.initial_backoff"]; ok {
+			retry.InitialBackoff = l.duration(v)
+		}
+		if v, ok := merged["retry.max_backoff"]; ok {
+			retry.MaxBackoff = l.duration(v)
+		}
+		if retry.InitialBackoff > 0 && retry.MaxBackoff > 0 && retry.InitialBackoff > retry.MaxBackoff {
+			l.problem("%s: must not exceed retry.max_backoff (%s)",
+				merged["retry.initial_backoff"].origin, retry.MaxBackoff)
+		}
+		config.Retry = retry
+	}
+
+	rps, hasRPS := merged["rate_limit.requests_per_second"]
+	burst, hasBurst := merged["rate_limit.burst"]
+	if hasRPS {
+		limit := &RateLimitConfig{}
+		f, err := strconv.ParseFloat(rps.value, 64)
+		if err != nil || f <= 0 {
+			l.problem("%s: must be a positive number, got %q", rps.origin, rps.value)
+		}
+		limit.RequestsPerSecond = f
+		if hasBurst {
+			limit.Burst = l.positiveInt(burst)
+		}
+		config.RateLimit = limit
+	} else if hasBurst {
+		l.problem("%s: rate_limit.requests_per_second is required when a burst is set", burst.origin)
+	}
+
+	if v, ok := merged["rpc_endpoints"]; ok {
+		config.RPCEndpoints = l.urls(v, "http", "https")
+	}
+	if config.Cluster != nil {
+		if config.BaseURL == "" {
+			config.BaseURL = config.Cluster.TensorBaseURL
+		}
+		if len(config.RPCEndpoints) == 0 {
+			config.RPCEndpoints = []string{config.Cluster.RPCURL}
+		}
+	}
+	if v, ok := merged["keypair_path"]; ok {
+		config.KeypairPath = l.keypairPath(v)
+	}
+
+	if len(l.problems) > 0 {
+		return nil, &ConfigError{Problems: l.problems}
+	}
+	return config, nil
+}
+
+// urls parses a comma separated list of absolute URLs with one of the given schemes
+func (l *loader) urls(v sourced, schemes ...string) []string {
+	var out []string
+	for _, raw := range strings.Split(v.value, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			l.problem("%s: invalid URL %q", v.origin, raw)
+			continue
+		}
+		valid := false
+		for _, s := range schemes {
+			valid = valid || u.Scheme == s
+		}
+		if !valid {
+			l.problem("%s: URL %q must use %s", v.origin, raw, strings.Join(schemes, " or "))
+			continue
+		}
+		out = append(out, strings.TrimSuffix(raw, "/"))
+	}
+	if len(out) == 0 && strings.TrimSpace(v.value) == "" {
+		l.problem("%s: must not be empty", v.origin)
+	}
+	return out
+}
+
+// duration parses a positive duration, accepting a bare number as seconds
+func (l *loader) duration(v sourced) time.Duration {
+	d, err := time.ParseDuration(v.value)
+	if err != nil {
+		secs, convErr := strconv.ParseFloat(v.value, 64)
+		if convErr != nil {
+			l.problem("%s: invalid duration %q", v.origin, v.value)
+			return 0
+		}
+		d = time.Duration(secs * float64(time.Second))
+	}
+	if d <= 0 {
+		l.problem("%s: must be positive, got %q", v.origin, v.value)
+		return 0
+	}
+	return d
+}
+
+// positiveInt parses an integer of at least 1
+func (l *loader) positiveInt(v sourced) int {
+	n, err := strconv.Atoi(v.value)
+	if err != nil || n < 1 {
+		l.problem("%s: must be a positive integer, got %q", v.origin, v.value)
+		return 0
+	}
+	return n
+}
+
+// keypairPath expands a leading ~ and checks the keypair file exists
+func (l *loader) keypairPath(v sourced) string {
+	path := v.value
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	info, err := os.Stat(path)
+	switch {
+	case stderrors.Is(err, fs.ErrNotExist):
+		l.problem("%s: keypair file %s does not exist", v.origin, path)
+	case err != nil:
+		l.problem("%s: %v", v.origin, err)
+	case info.IsDir():
+		l.problem("%s: %s is a directory, not a keypair file", v.origin, path)
+	}
+	return path
+}
@@ -0,0 +1,301 @@
+package client
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearTensorEnv blanks every TENSOR_* variable for the duration of the test
+func clearTensorEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv(EnvProfile, "")
+	for _, s := range settings {
+		t.Setenv(s.env, "")
+	}
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig_Formats(t *testing.T) {
+	keypair := writeConfigFile(t, "id.json", "[]")
+	want := &Config{
+		APIKey:       "file-key",
+		BaseURL:      "https://a.example.com",
+		BaseURLs:     []string{"https://a.example.com", "https://b.example.com"},
+		Timeout:      10 * time.Second,
+		Retry:        &RetryConfig{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second},
+		RateLimit:    &RateLimitConfig{RequestsPerSecond: 2.5, Burst: 4},
+		RPCEndpoints: []string{"https://rpc.example.com"},
+		KeypairPath:  keypair,
+	}
+
+	files := map[string]string{
+		"tensor.yaml": `
+api_key: file-key
+base_urls: [https://a.example.com, https://b.example.com]
+timeout: 10s
+retry:
+  max_attempts: 5
+  initial_backoff: 100ms
+  max_backoff: 2
+rate_limit:
+  requests_per_second: 2.5
+  burst: 4
+rpc_endpoints: [https://rpc.example.com]
+keypair_path: ` + keypair + "\n",
+		"tensor.toml": `
+apiKey = "file-key"
+baseUrl = "https://a.example.com,https://b.example.com"
+timeout = "10s"
+rpc_endpoints = ["https://rpc.example.com"]
+keypair_path = "` + keypair + `"
+
+[retry]
+max_attempts = 5
+initial_backoff = "100ms"
+max_backoff = "2s"
+
+[rate_limit]
+requests_per_second = 2.5
+burst = 4
+`,
+		"tensor.json": `{
+  "api_key": "file-key",
+  "base_url": ["https://a.example.com", "https://b.example.com/"],
+  "timeout": 10,
+  "retry": {"max_attempts": 5, "initial_backoff": "100ms", "max_backoff": "2s"},
+  "rate_limit": {"requests_per_second": 2.5, "burst": 4},
+  "rpc_endpoints": ["https://rpc.example.com"],
+  "keypair_path": "` + keypair + `"
+}`,
+		".env": `
+TENSOR_API_KEY=file-key
+TENSOR_BASE_URL=https://a.example.com,https://b.example.com
+TENSOR_TIMEOUT=10s
+TENSOR_RETRY_MAX_ATTEMPTS=5
+TENSOR_RETRY_INITIAL_BACKOFF=100ms
+TENSOR_RETRY_MAX_BACKOFF=2s
+TENSOR_RATE_LIMIT_RPS=2.5
+TENSOR_RATE_LIMIT_BURST=4
+TENSOR_RPC_ENDPOINTS=https://rpc.example.com
+TENSOR_KEYPAIR_PATH=` + keypair + "\n",
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			clearTensorEnv(t)
+			got, err := LoadConfig(writeConfigFile(t, name, content))
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("LoadConfig() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_ProfilesAndEnvOverrides(t *testing.T) {
+	path := writeConfigFile(t, "tensor.yaml", `
+profile: staging
+timeout: 10s
+profiles:
+  staging:
+    api_key: staging-key
+  local-mock:
+    base_url: http://127.0.0.1:9999
+  sandbox:
+    base_url: https://sandbox.example.com
+`)
+
+	tests := []struct {
+		name        string
+		profile     string
+		env         map[string]string
+		wantKey     string
+		wantBaseURL string
+		wantTimeout time.Duration
+		wantRPC     []string
+		wantCluster string
+	}{
+		{
+			name:        "profile from file",
+			wantKey:     "staging-key",
+			wantBaseURL: "https://api.devnet.tensordev.io",
+			wantTimeout: 10 * time.Second,
+			wantRPC:     []string{"https://api.devnet.solana.com"},
+			wantCluster: "devnet",
+		},
+		{
+			name:        "profile from environment",
+			env:         map[string]string{EnvProfile: ProfileLocalMock},
+			wantBaseURL: "http://127.0.0.1:9999",
+			wantTimeout: 10 * time.Second,
+			wantRPC:     []string{"http://localhost:8899"},
+			wantCluster: "localnet",
+		},
+		{
+			name:        "explicit profile defined only in the file",
+			profile:     "sandbox",
+			env:         map[string]string{EnvProfile: ProfileLocalMock},
+			wantBaseURL: "https://sandbox.example.com",
+			wantTimeout: 10 * time.Second,
+		},
+		{
+			name:        "environment overrides file",
+			env:         map[string]string{EnvAPIKey: "env-key", EnvTimeout: "45"},
+			wantKey:     "env-key",
+			wantBaseURL: "https://api.devnet.tensordev.io",
+			wantTimeout: 45 * time.Second,
+			wantRPC:     []string{"https://api.devnet.solana.com"},
+			wantCluster: "devnet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTensorEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			got, err := LoadConfigProfile(path, tt.profile)
+			if err != nil {
+				t.Fatalf("LoadConfigProfile() error = %v", err)
+			}
+			if got.APIKey != tt.wantKey || got.BaseURL != tt.wantBaseURL || got.Timeout != tt.wantTimeout {
+				t.Errorf("got key=%q base=%q timeout=%v, want key=%q base=%q timeout=%v",
+					got.APIKey, got.BaseURL, got.Timeout, tt.wantKey, tt.wantBaseURL, tt.wantTimeout)
+			}
+			gotCluster := ""
+			if got.Cluster != nil {
+				gotCluster = got.Cluster.Name
+			}
+			if gotCluster != tt.wantCluster {
+				t.Errorf("Cluster = %q, want %q", gotCluster, tt.wantCluster)
+			}
+			if !reflect.DeepEqual(got.RPCEndpoints, tt.wantRPC) {
+				t.Errorf("RPCEndpoints = %v, want %v", got.RPCEndpoints, tt.wantRPC)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_ReportsAllProblems(t *testing.T) {
+	clearTensorEnv(t)
+	t.Setenv(EnvRateLimitRPS, "-1")
+	path := writeConfigFile(t, "tensor.yaml", `
+profile: mainnet-prod
+base_url: ftp://example.com
+timeout: soon
+colour: blue
+retry:
+  max_attempts: 0
+  initial_backoff: 5s
+  max_backoff: 1s
+keypair_path: /nonexistent/id.json
+`)
+
+	_, err := LoadConfig(path)
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("LoadConfig() error = %v, want *ConfigError", err)
+	}
+	wantProblems := []string{
+		"colour: unknown setting",
+		"api_key is required for profile mainnet-prod",
+		`base_url: URL "ftp://example.com" must use http or https`,
+		`timeout: invalid duration "soon"`,
+		"retry.max_attempts: must be a positive integer",
+		"retry.initial_backoff: must not exceed retry.max_backoff",
+		"TENSOR_RATE_LIMIT_RPS: must be a positive number",
+		"keypair_path: keypair file /nonexistent/id.json does not exist",
+	}
+	if len(cfgErr.Problems) != len(wantProblems) {
+		t.Fatalf("got %d problems, want %d:\n%v", len(cfgErr.Problems), len(wantProblems), err)
+	}
+	for _, want := range wantProblems {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadConfig_FileErrors(t *testing.T) {
+	clearTensorEnv(t)
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"unsupported extension", "tensor.ini", "", "unsupported config file extension"},
+		{"malformed yaml", "tensor.yaml", "retry: [", "failed to parse config file"},
+		{"unknown profile", "tensor.json", `{"profile": "moon"}`, `unknown profile "moon"`},
+		{"unknown cluster", "tensor.json", `{"cluster": "pluto"}`, `unknown cluster "pluto"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfigFile(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadConfig() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadConfig() of a missing file succeeded")
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	clearTensorEnv(t)
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	dotenv := "TENSOR_PROFILE=local-mock\nTENSOR_API_KEY=dotenv-key\nTENSOR_TIMEOUT=2s\n"
+	if err := os.WriteFile(filepath.Join(dir, DotEnvFile), []byte(dotenv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvTimeout, "7s")
+
+	config, err := LoadEnvConfig()
+	if err != nil {
+		t.Fatalf("LoadEnvConfig() error = %v", err)
+	}
+	if config.APIKey != "dotenv-key" || config.Timeout != 7*time.Second || config.BaseURL != "http://localhost:8080" {
+		t.Errorf("LoadEnvConfig() = %+v, want dotenv key, env timeout and local-mock base URL", config)
+	}
+
+	c, err := NewFromEnv()
+	if err != nil || c == nil {
+		t.Fatalf("NewFromEnv() = %v, %v", c, err)
+	}
+
+	t.Setenv(EnvProfile, ProfileMainnetProd)
+	t.Setenv(EnvTimeout, "-3s")
+	os.Remove(filepath.Join(dir, DotEnvFile))
+	_, err = NewFromEnv()
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || len(cfgErr.Problems) != 2 {
+		t.Errorf("NewFromEnv() error = %v, want missing api key and negative timeout", err)
+	}
+}
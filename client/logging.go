@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/request"
+)
+
+// walletParams are query parameters that carry wallet addresses and are redacted in logs
+var walletParams = map[string]bool{
+	"wallet": true, "wallets": true, "owner": true, "buyer": true, "seller": true,
+	"payer": true, "feePayer": true, "rentPayer": true, "privateTaker": true,
+	"makerBroker": true, "takerBroker": true, "includeOwners": true, "excludeOwners": true,
+}
+
+// addressPattern matches base58 strings shaped like Solana addresses
+var addressPattern = regexp.MustCompile(`[1-9A-HJ-NP-Za-km-z]{32,44}`)
+
+// requestIDHeaders are checked in order for the ID the API assigned to a request
+var requestIDHeaders = []string{"X-Request-Id", "X-Amzn-Requestid", "Cf-Ray"}
+
+// requestLogger is a request.Observer that emits structured log events
+type requestLogger struct {
+	logger *slog.Logger
+	levels map[string]slog.Level
+	apiKey string
+}
+
+// newRequestLogger creates a requestLogger. levels maps endpoint groups to the
+// minimum level logged for them; groups without an entry log everything.
+func newRequestLogger(logger *slog.Logger, levels map[string]slog.Level, apiKey string) *requestLogger {
+	return &requestLogger{logger: logger, levels: levels, apiKey: apiKey}
+}
+
+// Start logs the request start at debug level
+func (l *requestLogger) Start(ctx context.Context, endpoint string, params url.Values) context.Context {
+	l.log(ctx, endpoint, slog.LevelDebug, "tensor request start",
+		slog.String("params", redactParams(params)),
+	)
+	return ctx
+}
+
+// End logs the request outcome. Failures are logged at warn level for client
+// side and 4xx problems and at error level for server, network and decode failures.
+func (l *requestLogger) End(ctx context.Context, meta *request.Metadata, err error) {
+	attrs := []slog.Attr{
+		slog.Int("status", meta.StatusCode),
+		slog.Duration("latency", meta.Duration),
+		slog.Int("attempts", meta.Attempts),
+	}
+	if id := requestID(meta); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if baseURL := meta.Header.Get(BaseURLHeader); baseURL != "" {
+		attrs = append(attrs, slog.String("base_url", baseURL))
+	}
+	if err == nil {
+		l.log(ctx, meta.Endpoint, slog.LevelInfo, "tensor request end", attrs...)
+		return
+	}
+
+	attrs = append(attrs, slog.String("error", l.redactError(err)))
+	switch meta.FailedAt {
+	case request.StageValidate, request.StageEncode:
+		l.log(ctx, meta.Endpoint, slog.LevelWarn, "tensor request validation failed", attrs...)
+	case request.StageDecode:
+		l.log(ctx, meta.Endpoint, slog.LevelError, "tensor response decode failed", attrs...)
+	case request.StageStatus:
+		if meta.StatusCode == 429 {
+			if retryAfter := meta.Header.Get("Retry-After"); retryAfter != "" {
+				attrs = append(attrs, slog.String("retry_after", retryAfter))
+			}
+			l.log(ctx, meta.Endpoint, slog.LevelWarn, "tensor request rate limited", attrs...)
+			return
+		}
+		level := slog.LevelError
+		if meta.StatusCode < 500 {
+			level = slog.LevelWarn
+		}
+		l.log(ctx, meta.Endpoint, level, "tensor request failed", attrs...)
+	default:
+		if stderrors.Is(err, ErrCircuitOpen) {
+			l.log(ctx, meta.Endpoint, slog.LevelWarn, "tensor request rejected by circuit breaker", attrs...)
+			return
+		}
+		if stderrors.Is(err, context.Canceled) {
+			l.log(ctx, meta.Endpoint, slog.LevelWarn, "tensor request canceled", attrs...)
+			return
+		}
+		l.log(ctx, meta.Endpoint, slog.LevelError, "tensor request failed", attrs...)
+	}
+}
+
+// log emits an event unless it is below the level configured for the endpoint group
+func (l *requestLogger) log(ctx context.Context, endpoint string, level slog.Level, msg string, attrs ...slog.Attr) {
+	group := EndpointGroup(endpoint)
+	if min, ok := l.levels[group]; ok && level < min {
+		return
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs = append([]slog.Attr{slog.String("endpoint", endpoint), slog.String("group", group)}, attrs...)
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// redactError renders err without the API key or anything shaped like an address
+func (l *requestLogger) redactError(err error) string {
+	msg := err.Error()
+	if l.apiKey != "" {
+		msg = strings.ReplaceAll(msg, l.apiKey, "[REDACTED]")
+	}
+	return addressPattern.ReplaceAllStringFunc(msg, redactAddress)
+}
+
+// redactParams encodes params with wallet addresses shortened
+func redactParams(params url.Values) string {
+	if len(params) == 0 {
+		return ""
+	}
+	redacted := make(url.Values, len(params))
+	for key, values := range params {
+		if !walletParams[key] {
+			redacted[key] = values
+			continue
+		}
+		out := make([]string, len(values))
+		for i, v := range values {
+			out[i] = addressPattern.ReplaceAllStringFunc(v, redactAddress)
+		}
+		redacted[key] = out
+	}
+	s, err := url.QueryUnescape(redacted.Encode())
+	if err != nil {
+		return redacted.Encode()
+	}
+	return s
+}
+
+// redactAddress keeps the first and last four characters of an address
+func redactAddress(addr string) string {
+	if len(addr) <= 8 {
+		return "****"
+	}
+	return addr[:4] + "…" + addr[len(addr)-4:]
+}
+
+// requestID returns the API request ID from the response headers, if any
+func requestID(meta *request.Metadata) string {
+	for _, h := range requestIDHeaders {
+		if id := meta.Header.Get(h); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+var _ request.Observer = (*requestLogger)(nil)
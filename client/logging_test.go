@@ -0,0 +1,195 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/rpc"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+)
+
+const testWallet = "DRpbCBMxVnDK7maPM5tGv6MvB3v1sRMC86PZ8okm21hy"
+
+// logRecords decodes the JSON lines written by a slog.JSONHandler
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func newLoggingClient(t *testing.T, handler http.HandlerFunc, levels map[string]slog.Level) (*Client, *bytes.Buffer) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return New(&Config{BaseURL: server.URL, APIKey: "secret-key", Logger: logger, LogLevels: levels}), &buf
+}
+
+func TestLogging_RequestStartAndEnd(t *testing.T) {
+	client, buf := newLoggingClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-42")
+		w.Write([]byte(`{"listings":[]}`))
+	}, nil)
+
+	_, _, err := client.User.GetListings(context.Background(), &user.ListingsRequest{Wallets: []string{testWallet}, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetListings() error = %v", err)
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d log records, want 2: %s", len(records), buf.String())
+	}
+	start, end := records[0], records[1]
+	if start["msg"] != "tensor request start" || start["level"] != "DEBUG" {
+		t.Errorf("start record = %v", start)
+	}
+	if params, _ := start["params"].(string); !strings.Contains(params, "wallets=DRpb…21hy") {
+		t.Errorf("start params = %q, want redacted wallet", params)
+	}
+	if end["msg"] != "tensor request end" || end["endpoint"] != "/api/v1/user/active_listings" || end["group"] != GroupUser {
+		t.Errorf("end record = %v", end)
+	}
+	if end["status"] != float64(200) || end["request_id"] != "req-42" {
+		t.Errorf("end record = %v, want status 200 and request id", end)
+	}
+	if _, ok := end["latency"]; !ok {
+		t.Errorf("end record has no latency: %v", end)
+	}
+	if strings.Contains(buf.String(), testWallet) || strings.Contains(buf.String(), "secret-key") {
+		t.Errorf("logs leak secrets: %s", buf.String())
+	}
+}
+
+func TestLogging_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantMsg string
+		wantLvl string
+	}{
+		{
+			name: "rate limited",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "3")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			wantMsg: "tensor request rate limited",
+			wantLvl: "WARN",
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			wantMsg: "tensor request failed",
+			wantLvl: "ERROR",
+		},
+		{
+			name: "decode failure",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("not json"))
+			},
+			wantMsg: "tensor response decode failed",
+			wantLvl: "ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, buf := newLoggingClient(t, tt.handler, nil)
+			_, _, err := client.Marketplace.BuyNFT(context.Background(), &marketplace.BuyNFTRequest{
+				Buyer: testWallet, Mint: testWallet, Owner: testWallet, MaxPrice: 1, Blockhash: "11111111111111111111111111111111",
+			})
+			if err == nil {
+				t.Fatal("BuyNFT() expected error")
+			}
+			records := logRecords(t, buf)
+			last := records[len(records)-1]
+			if last["msg"] != tt.wantMsg || last["level"] != tt.wantLvl {
+				t.Errorf("last record = %v, want %s at %s", last, tt.wantMsg, tt.wantLvl)
+			}
+			if tt.name == "rate limited" && last["retry_after"] != "3" {
+				t.Errorf("rate limit record = %v, want retry_after 3", last)
+			}
+		})
+	}
+}
+
+func TestLogging_ValidationFailureRedactsWallet(t *testing.T) {
+	client, buf := newLoggingClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server")
+	}, nil)
+
+	_, _, err := client.User.GetListings(context.Background(), &user.ListingsRequest{Wallets: []string{testWallet + "0"}, Limit: 10})
+	if err == nil {
+		t.Fatal("GetListings() expected validation error")
+	}
+	records := logRecords(t, buf)
+	last := records[len(records)-1]
+	if last["msg"] != "tensor request validation failed" || last["level"] != "WARN" {
+		t.Errorf("last record = %v", last)
+	}
+	if strings.Contains(buf.String(), testWallet) {
+		t.Errorf("logs leak wallet: %s", buf.String())
+	}
+}
+
+func TestLogging_PerGroupLevels(t *testing.T) {
+	client, buf := newLoggingClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}, map[string]slog.Level{GroupUser: slog.LevelWarn})
+
+	if _, _, err := client.User.GetListings(context.Background(), &user.ListingsRequest{Wallets: []string{testWallet}, Limit: 10}); err != nil {
+		t.Fatalf("GetListings() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("user group logged below warn: %s", buf.String())
+	}
+
+	// Groups without an explicit level log everything
+	if _, _, err := client.RPC.GetPriorityFees(context.Background(), &rpc.PriorityFeesRequest{}); err != nil {
+		t.Fatalf("GetPriorityFees() error = %v", err)
+	}
+	if records := logRecords(t, buf); len(records) != 2 || records[0]["group"] != GroupRPC {
+		t.Errorf("rpc records = %v, want start and end", records)
+	}
+}
+
+func TestEndpointGroup(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/user/portfolio":             GroupUser,
+		"/api/v1/tx/buy":                     GroupMarketplace,
+		"/api/v1/tx/tswap/edit_order":        GroupTSwap,
+		"/api/v1/tx/deposit_withdraw_escrow": GroupEscrow,
+		"/api/v1/rpc/priority_fees":          GroupRPC,
+		"/api/v1/mint":                       GroupNFTs,
+		"/api/v1/mint/collection":            GroupNFTs,
+		"/api/v1/collections":                GroupCollections,
+		"/health":                            GroupOther,
+	}
+	for path, want := range tests {
+		if got := EndpointGroup(path); got != want {
+			t.Errorf("EndpointGroup(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/request"
+	"github.com/srpvpn/tensor-go-sdk/internal/transport"
+)
+
+// Endpoint groups, matching the API packages of the SDK
+const (
+	GroupUser        = "user"
+	GroupMarketplace = "marketplace"
+	GroupTSwap       = "tswap"
+	GroupEscrow      = "escrow"
+	GroupRPC         = "rpc"
+	GroupNFTs        = "nfts"
+	GroupCollections = "collections"
+	GroupOther       = "other"
+)
+
+// EndpointGroup returns the endpoint group an API path belongs to
+func EndpointGroup(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/api/v1/user/"):
+		return GroupUser
+	case strings.HasPrefix(path, "/api/v1/tx/tswap/"):
+		return GroupTSwap
+	case path == "/api/v1/tx/deposit_withdraw_escrow":
+		return GroupEscrow
+	case strings.HasPrefix(path, txPathPrefix):
+		return GroupMarketplace
+	case strings.HasPrefix(path, "/api/v1/rpc/"):
+		return GroupRPC
+	case path == "/api/v1/mint" || strings.HasPrefix(path, "/api/v1/mint/"):
+		return GroupNFTs
+	case path == "/api/v1/collections":
+		return GroupCollections
+	default:
+		return GroupOther
+	}
+}
+
+// observedTransport attaches a request.Observer to a transport so that the
+// shared request path reports its lifecycle events
+type observedTransport struct {
+	transport.Transport
+	observer request.Observer
+}
+
+// Observer returns the observer notified of every request
+func (t *observedTransport) Observer() request.Observer {
+	return t.observer
+}
+
+// multiObserver fans request events out to several observers. Start runs in
+// order, each observer seeing the context returned by the previous one, and
+// End runs in reverse order.
+type multiObserver []request.Observer
+
+// Start notifies every observer of the request start
+func (m multiObserver) Start(ctx context.Context, endpoint string, params url.Values) context.Context {
+	for _, o := range m {
+		ctx = o.Start(ctx, endpoint, params)
+	}
+	return ctx
+}
+
+// End notifies every observer of the request outcome
+func (m multiObserver) End(ctx context.Context, meta *request.Metadata, err error) {
+	for i := len(m) - 1; i >= 0; i-- {
+		m[i].End(ctx, meta, err)
+	}
+}
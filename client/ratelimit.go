@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/transport"
+)
+
+// RateLimitConfig throttles outgoing requests with a token bucket
+type RateLimitConfig struct {
+	// RequestsPerSecond is the sustained request rate. It must be > 0; New
+	// logs a warning and sends requests unthrottled otherwise.
+	RequestsPerSecond float64
+
+	// Burst is how many requests may be sent at once after a quiet period. Defaults to 1.
+	Burst int
+}
+
+// rateLimitTransport delays requests so they stay within the configured rate
+type rateLimitTransport struct {
+	next   transport.Transport
+	rate   float64
+	burst  float64
+	logger *requestLogger // nil when logging is disabled
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimitTransport wraps next with the rate limit described by cfg
+func newRateLimitTransport(next transport.Transport, cfg RateLimitConfig, logger *requestLogger) *rateLimitTransport {
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	return &rateLimitTransport{
+		next:   next,
+		rate:   cfg.RequestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		logger: logger,
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+// Get waits for a token, then performs the request
+func (r *rateLimitTransport) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	if wait := r.reserve(); wait > 0 {
+		if r.logger != nil {
+			r.logger.log(ctx, path, slog.LevelInfo, "tensor rate limit wait", slog.Duration("wait", wait))
+		}
+		if err := r.sleep(ctx, wait); err != nil {
+			r.release()
+			return nil, err
+		}
+	}
+	return r.next.Get(ctx, path, params)
+}
+
+// reserve takes a token, possibly going into debt, and returns how long the
+// caller must wait for it to become available
+func (r *rateLimitTransport) reserve() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	if !r.last.IsZero() {
+		r.tokens += now.Sub(r.last).Seconds() * r.rate
+		if r.tokens > r.burst {
+			r.tokens = r.burst
+		}
+	}
+	r.last = now
+	r.tokens--
+	if r.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-r.tokens / r.rate * float64(time.Second))
+}
+
+// release returns a reserved token when the caller gave up waiting
+func (r *rateLimitTransport) release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens++
+}
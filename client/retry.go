@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/internal/transport"
+)
+
+// RetryConfig configures automatic retries of failed requests. Network
+// errors, 429 and 5xx responses are retried with exponential backoff;
+// a Retry-After header takes precedence over the computed backoff.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first. Defaults to 3.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry. Defaults to 200ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between attempts. Defaults to 5s.
+	MaxBackoff time.Duration
+}
+
+// retryTransport wraps a transport.Transport with retries
+type retryTransport struct {
+	next   transport.Transport
+	cfg    RetryConfig
+	logger *requestLogger // nil when logging is disabled
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func() float64
+}
+
+// newRetryTransport wraps next with the retry behavior described by cfg
+func newRetryTransport(next transport.Transport, cfg RetryConfig, logger *requestLogger) *retryTransport {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = 200 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Second
+	}
+	return &retryTransport{
+		next:   next,
+		cfg:    cfg,
+		logger: logger,
+		sleep:  sleepContext,
+		jitter: func() float64 { return 0.5 + rand.Float64()/2 },
+	}
+}
+
+// Get performs the request, retrying retryable failures
+func (r *retryTransport) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := r.next.Get(ctx, path, params)
+		if err == nil || attempt >= r.cfg.MaxAttempts || !retryable(ctx, err) {
+			return resp, err
+		}
+
+		wait := r.backoff(attempt, err)
+		if r.logger != nil {
+			r.logger.log(ctx, path, slog.LevelWarn, "tensor request retry",
+				slog.Int("attempt", attempt),
+				slog.Duration("wait", wait),
+				slog.String("error", r.logger.redactError(err)),
+			)
+		}
+		if sleepErr := r.sleep(ctx, wait); sleepErr != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff returns the wait before the retry following attempt
+func (r *retryTransport) backoff(attempt int, err error) time.Duration {
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
+		if secs, convErr := strconv.Atoi(apiErr.Header.Get("Retry-After")); convErr == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	wait := r.cfg.InitialBackoff << (attempt - 1)
+	if wait <= 0 || wait > r.cfg.MaxBackoff {
+		wait = r.cfg.MaxBackoff
+	}
+	return time.Duration(float64(wait) * r.jitter())
+}
+
+// retryable reports whether a failed request may succeed if sent again
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || stderrors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= 500
+	}
+	var netErr *errors.NetworkError
+	return stderrors.As(err, &netErr)
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
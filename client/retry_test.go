@@ -0,0 +1,221 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apierrors "github.com/srpvpn/tensor-go-sdk/internal/errors"
+)
+
+// recordSleeps replaces a transport's sleep with one that records waits and returns at once
+func recordSleeps(waits *[]time.Duration) func(ctx context.Context, d time.Duration) error {
+	return func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+}
+
+func TestRetryTransport_RetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "server error then success",
+			errs:      []error{&apierrors.APIError{Code: 503}},
+			wantCalls: 2,
+		},
+		{
+			name:      "network errors exhaust attempts",
+			errs:      []error{&apierrors.NetworkError{Op: "http_request"}, &apierrors.NetworkError{Op: "http_request"}, &apierrors.NetworkError{Op: "http_request"}},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			name:      "client errors are not retried",
+			errs:      []error{&apierrors.APIError{Code: 400}},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "open circuit is not retried",
+			errs:      []error{&CircuitOpenError{Prefix: "/api/v1/user"}},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			next := transportFunc(func(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+				calls++
+				if calls <= len(tt.errs) {
+					return nil, tt.errs[calls-1]
+				}
+				return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+			})
+			var waits []time.Duration
+			r := newRetryTransport(next, RetryConfig{MaxAttempts: 3}, nil)
+			r.sleep = recordSleeps(&waits)
+
+			_, err := r.Get(context.Background(), "/api/v1/user/portfolio", nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if len(waits) != tt.wantCalls-1 {
+				t.Errorf("waits = %v, want %d", waits, tt.wantCalls-1)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_Backoff(t *testing.T) {
+	r := newRetryTransport(nil, RetryConfig{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}, nil)
+	r.jitter = func() float64 { return 1 }
+
+	serverErr := &apierrors.APIError{Code: 500}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 5: time.Second, 70: time.Second} {
+		if got := r.backoff(attempt, serverErr); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	limited := &apierrors.APIError{Code: 429, Header: http.Header{"Retry-After": []string{"3"}}}
+	if got := r.backoff(1, limited); got != 3*time.Second {
+		t.Errorf("backoff with Retry-After = %v, want 3s", got)
+	}
+}
+
+func TestRetryTransport_StopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	next := transportFunc(func(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+		calls++
+		cancel()
+		return nil, &apierrors.APIError{Code: 502}
+	})
+	r := newRetryTransport(next, RetryConfig{MaxAttempts: 5}, nil)
+
+	_, err := r.Get(ctx, "/api/v1/rpc/priority_fees", nil)
+	var apiErr *apierrors.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 502 {
+		t.Fatalf("Get() error = %v, want the 502", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRateLimitTransport_Waits(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var waits []time.Duration
+	next := &scriptedTransport{}
+	r := newRateLimitTransport(next, RateLimitConfig{RequestsPerSecond: 2, Burst: 2}, nil)
+	r.now = func() time.Time { return now }
+	r.sleep = recordSleeps(&waits)
+
+	for i := 0; i < 4; i++ {
+		if _, err := r.Get(context.Background(), "/api/v1/user/portfolio", nil); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+	want := []time.Duration{500 * time.Millisecond, time.Second}
+	if len(waits) != len(want) || waits[0] != want[0] || waits[1] != want[1] {
+		t.Fatalf("waits = %v, want %v", waits, want)
+	}
+
+	// Tokens refill over time up to the burst
+	now = now.Add(10 * time.Second)
+	waits = nil
+	for i := 0; i < 2; i++ {
+		r.Get(context.Background(), "/api/v1/user/portfolio", nil)
+	}
+	if len(waits) != 0 {
+		t.Errorf("waits after refill = %v, want none", waits)
+	}
+	if next.calls != 6 {
+		t.Errorf("calls = %d, want 6", next.calls)
+	}
+}
+
+func TestNew_RateLimitWithoutRate(t *testing.T) {
+	var hits int32
+	srv := countingServer(t, &hits, http.StatusOK, 0)
+	var buf bytes.Buffer
+	c := New(&Config{
+		BaseURL:   srv.URL,
+		Logger:    slog.New(slog.NewJSONHandler(&buf, nil)),
+		RateLimit: &RateLimitConfig{Burst: 5},
+	})
+
+	if _, ok := c.transport.(*observedTransport).Transport.(*rateLimitTransport); ok {
+		t.Fatal("rate limiter installed without a rate")
+	}
+	for i := 0; i < 6; i++ {
+		resp, err := c.transport.Get(context.Background(), "/api/v1/rpc/priority_fees", nil)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+	}
+	if hits != 6 {
+		t.Errorf("hits = %d, want 6", hits)
+	}
+	records := logRecords(t, &buf)
+	if len(records) == 0 || records[0]["level"] != "WARN" || !strings.Contains(records[0]["msg"].(string), "rate limit disabled") {
+		t.Errorf("log records = %v, want a rate limit warning first", records)
+	}
+}
+
+func TestClient_RetryAndRateLimitAreLogged(t *testing.T) {
+	var hits int32
+	srv := countingServer(t, &hits, http.StatusOK, 0)
+	failing := transportFunc(func(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			return nil, &apierrors.APIError{Code: 503, Message: "unavailable"}
+		}
+		return http.Get(srv.URL + path)
+	})
+
+	var buf bytes.Buffer
+	logger := newRequestLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), nil, "")
+	var waits []time.Duration
+	limited := newRateLimitTransport(failing, RateLimitConfig{RequestsPerSecond: 1}, logger)
+	limited.sleep = recordSleeps(&waits)
+	r := newRetryTransport(limited, RetryConfig{}, logger)
+	r.sleep = recordSleeps(&waits)
+
+	resp, err := r.Get(context.Background(), "/api/v1/rpc/priority_fees", nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	records := logRecords(t, &buf)
+	msgs := make([]string, len(records))
+	for i, rec := range records {
+		msgs[i] = rec["msg"].(string)
+	}
+	got := strings.Join(msgs, ",")
+	if !strings.Contains(got, "tensor request retry") || !strings.Contains(got, "tensor rate limit wait") {
+		t.Errorf("log messages = %v, want a retry and a rate limit wait", msgs)
+	}
+	if records[0]["group"] != GroupRPC {
+		t.Errorf("group = %v, want %s", records[0]["group"], GroupRPC)
+	}
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/url"
+	"strconv"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/request"
+)
+
+// Attribute is a key/value pair attached to spans and metric samples
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Tracer starts spans. It mirrors the shape of OpenTelemetry's trace.Tracer so
+// an adapter is a few lines, without the SDK depending on OpenTelemetry.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a unit of traced work started by a Tracer
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Meter creates metric instruments. Instruments are created once per client.
+type Meter interface {
+	Float64Histogram(name, description, unit string) Histogram
+	Int64Counter(name, description string) Counter
+}
+
+// Histogram records a distribution of values
+type Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// Counter accumulates a monotonic count
+type Counter interface {
+	Add(ctx context.Context, delta int64, attrs ...Attribute)
+}
+
+// Metric names recorded for every request
+const (
+	MetricRequests        = "tensor_requests_total"
+	MetricRequestDuration = "tensor_request_duration_seconds"
+	MetricRequestErrors   = "tensor_request_errors_total"
+)
+
+// Error classes used as the "class" attribute of MetricRequestErrors
+const (
+	ErrorClassValidation  = "validation"
+	ErrorClassNetwork     = "network"
+	ErrorClassTimeout     = "timeout"
+	ErrorClassCanceled    = "canceled"
+	ErrorClassRateLimit   = "rate_limit"
+	ErrorClassClientError = "client_error"
+	ErrorClassServerError = "server_error"
+	ErrorClassDecode      = "decode"
+	ErrorClassCircuitOpen = "circuit_open"
+)
+
+// endpointMethods maps API paths to the SDK methods that call them, used as span names
+var endpointMethods = map[string]string{
+	"/api/v1/user/portfolio":                "User.GetPortfolio",
+	"/api/v1/user/active_listings":          "User.GetListings",
+	"/api/v1/user/nft_bids":                 "User.GetNFTBids",
+	"/api/v1/user/coll_bids":                "User.GetCollectionBids",
+	"/api/v1/user/trait_bids":               "User.GetTraitBids",
+	"/api/v1/user/amm_pools":                "User.GetTSwapPools",
+	"/api/v1/user/tamm_pools":               "User.GetTAmmPools",
+	"/api/v1/user/transactions":             "User.GetTransactions",
+	"/api/v1/user/escrow_accounts":          "User.GetEscrowAccounts",
+	"/api/v1/user/inventory_by_collection":  "User.GetInventoryForCollection",
+	"/api/v1/tx/buy":                        "Marketplace.BuyNFT",
+	"/api/v1/tx/sell":                       "Marketplace.SellNFT",
+	"/api/v1/tx/list":                       "Marketplace.ListNFT",
+	"/api/v1/tx/delist":                     "Marketplace.DelistNFT",
+	"/api/v1/tx/edit":                       "Marketplace.EditListing",
+	"/api/v1/tx/bid":                        "Marketplace.PlaceNFTBid",
+	"/api/v1/tx/trait_bid":                  "Marketplace.PlaceTraitBid",
+	"/api/v1/tx/collection_bid":             "Marketplace.PlaceCollectionBid",
+	"/api/v1/tx/edit_bid":                   "Marketplace.EditBid",
+	"/api/v1/tx/cancel_bid":                 "Marketplace.CancelBid",
+	"/api/v1/tx/tswap/close_order":          "TSwap.CloseTSwapPool",
+	"/api/v1/tx/tswap/edit_order":           "TSwap.EditTSwapPool",
+	"/api/v1/tx/tswap/deposit_withdraw":     "TSwap.DepositWithdrawNFT",
+	"/api/v1/tx/tswap/deposit_withdraw_sol": "TSwap.DepositWithdrawSOL",
+	"/api/v1/tx/deposit_withdraw_escrow":    "Escrow.DepositWithdrawEscrow",
+	"/api/v1/rpc/priority_fees":             "RPC.GetPriorityFees",
+	"/api/v1/mint":                          "NFTs.GetNFTsInfo",
+	"/api/v1/mint/collection":               "NFTs.GetNFTsByCollection",
+	"/api/v1/collections":                   "Collections.GetVerifiedCollections",
+}
+
+// SpanName returns the span name used for requests to an API path
+func SpanName(endpoint string) string {
+	if method, ok := endpointMethods[endpoint]; ok {
+		return "tensor." + method
+	}
+	return "tensor " + endpoint
+}
+
+// ErrorClass classifies a failed request for the errors counter
+func ErrorClass(meta *request.Metadata, err error) string {
+	switch meta.FailedAt {
+	case request.StageValidate, request.StageEncode:
+		return ErrorClassValidation
+	case request.StageDecode:
+		return ErrorClassDecode
+	case request.StageStatus:
+		switch {
+		case meta.StatusCode == 429:
+			return ErrorClassRateLimit
+		case meta.StatusCode >= 500:
+			return ErrorClassServerError
+		default:
+			return ErrorClassClientError
+		}
+	}
+	switch {
+	case stderrors.Is(err, ErrCircuitOpen):
+		return ErrorClassCircuitOpen
+	case stderrors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	}
+	var timeout interface{ Timeout() bool }
+	if stderrors.As(err, &timeout) && timeout.Timeout() {
+		return ErrorClassTimeout
+	}
+	return ErrorClassNetwork
+}
+
+// telemetry is a request.Observer that emits spans and metrics
+type telemetry struct {
+	tracer   Tracer
+	requests Counter
+	duration Histogram
+	errors   Counter
+}
+
+// newTelemetry creates the observer. Either tracer or meter may be nil.
+func newTelemetry(tracer Tracer, meter Meter) *telemetry {
+	t := &telemetry{tracer: tracer}
+	if meter != nil {
+		t.requests = meter.Int64Counter(MetricRequests, "Tensor API requests by endpoint and status")
+		t.duration = meter.Float64Histogram(MetricRequestDuration, "Tensor API request latency by endpoint", "s")
+		t.errors = meter.Int64Counter(MetricRequestErrors, "Failed Tensor API requests by endpoint and error class")
+	}
+	return t
+}
+
+type spanKey struct{}
+
+// Start opens the span for the request
+func (t *telemetry) Start(ctx context.Context, endpoint string, params url.Values) context.Context {
+	if t.tracer == nil {
+		return ctx
+	}
+	ctx, span := t.tracer.Start(ctx, SpanName(endpoint),
+		Attribute{Key: "tensor.endpoint", Value: endpoint},
+		Attribute{Key: "tensor.group", Value: EndpointGroup(endpoint)},
+	)
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// End closes the span and records the request metrics
+func (t *telemetry) End(ctx context.Context, meta *request.Metadata, err error) {
+	status := strconv.Itoa(meta.StatusCode)
+	class := ""
+	if err != nil {
+		class = ErrorClass(meta, err)
+	}
+
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		span.SetAttributes(
+			Attribute{Key: "http.status_code", Value: status},
+			Attribute{Key: "tensor.attempts", Value: strconv.Itoa(meta.Attempts)},
+		)
+		if baseURL := meta.Header.Get(BaseURLHeader); baseURL != "" {
+			span.SetAttributes(Attribute{Key: "tensor.base_url", Value: baseURL})
+		}
+		if err != nil {
+			span.SetAttributes(Attribute{Key: "tensor.error_class", Value: class})
+			span.RecordError(err)
+		}
+		span.End()
+	}
+
+	if t.requests == nil {
+		return
+	}
+	endpoint := Attribute{Key: "endpoint", Value: meta.Endpoint}
+	t.requests.Add(ctx, 1, endpoint, Attribute{Key: "status", Value: status})
+	if class != ErrorClassValidation {
+		t.duration.Record(ctx, meta.Duration.Seconds(), endpoint)
+	}
+	if err != nil {
+		t.errors.Add(ctx, 1, endpoint, Attribute{Key: "class", Value: class})
+	}
+}
+
+var _ request.Observer = (*telemetry)(nil)
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/srpvpn/tensor-go-sdk/api/rpc"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/internal/request"
+)
+
+type testSpan struct {
+	name  string
+	attrs map[string]string
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *testSpan) RecordError(err error) { s.err = err }
+
+func (s *testSpan) End() { s.ended = true }
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	span := &testSpan{name: name, attrs: map[string]string{}}
+	span.SetAttributes(attrs...)
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return ctx, span
+}
+
+type testInstrument struct {
+	mu      sync.Mutex
+	samples []map[string]string
+	values  []float64
+}
+
+func (i *testInstrument) record(value float64, attrs []Attribute) {
+	labels := map[string]string{}
+	for _, a := range attrs {
+		labels[a.Key] = a.Value
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.samples = append(i.samples, labels)
+	i.values = append(i.values, value)
+}
+
+func (i *testInstrument) Add(_ context.Context, delta int64, attrs ...Attribute) {
+	i.record(float64(delta), attrs)
+}
+
+func (i *testInstrument) Record(_ context.Context, value float64, attrs ...Attribute) {
+	i.record(value, attrs)
+}
+
+type testMeter struct {
+	instruments map[string]*testInstrument
+}
+
+func (m *testMeter) instrument(name string) *testInstrument {
+	if m.instruments == nil {
+		m.instruments = map[string]*testInstrument{}
+	}
+	if _, ok := m.instruments[name]; !ok {
+		m.instruments[name] = &testInstrument{}
+	}
+	return m.instruments[name]
+}
+
+func (m *testMeter) Float64Histogram(name, description, unit string) Histogram {
+	return m.instrument(name)
+}
+
+func (m *testMeter) Int64Counter(name, description string) Counter {
+	return m.instrument(name)
+}
+
+func TestTelemetry_SpansAndMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/rpc/priority_fees" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tracer := &testTracer{}
+	meter := &testMeter{}
+	client := New(&Config{BaseURL: server.URL, Tracer: tracer, Meter: meter})
+	ctx := context.Background()
+
+	if _, _, err := client.User.GetListings(ctx, &user.ListingsRequest{Wallets: []string{testWallet}, Limit: 10}); err != nil {
+		t.Fatalf("GetListings() error = %v", err)
+	}
+	if _, _, err := client.RPC.GetPriorityFees(ctx, &rpc.PriorityFeesRequest{}); err == nil {
+		t.Fatal("GetPriorityFees() expected error")
+	}
+	if _, _, err := client.User.GetListings(ctx, &user.ListingsRequest{}); err == nil {
+		t.Fatal("GetListings() expected validation error")
+	}
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(tracer.spans))
+	}
+	ok, failed, invalid := tracer.spans[0], tracer.spans[1], tracer.spans[2]
+	if ok.name != "tensor.User.GetListings" || !ok.ended || ok.err != nil {
+		t.Errorf("success span = %+v", ok)
+	}
+	if ok.attrs["tensor.endpoint"] != "/api/v1/user/active_listings" || ok.attrs["http.status_code"] != "200" || ok.attrs["tensor.attempts"] != "1" {
+		t.Errorf("success span attrs = %v", ok.attrs)
+	}
+	if failed.name != "tensor.RPC.GetPriorityFees" || failed.err == nil || failed.attrs["tensor.error_class"] != ErrorClassServerError {
+		t.Errorf("failed span = %+v", failed)
+	}
+	if invalid.attrs["tensor.error_class"] != ErrorClassValidation || invalid.attrs["tensor.attempts"] != "0" {
+		t.Errorf("validation span attrs = %v", invalid.attrs)
+	}
+
+	if n := len(meter.instruments[MetricRequests].samples); n != 3 {
+		t.Errorf("%s samples = %d, want 3", MetricRequests, n)
+	}
+	// Validation failures never reach the network and are not timed
+	if n := len(meter.instruments[MetricRequestDuration].samples); n != 2 {
+		t.Errorf("%s samples = %d, want 2", MetricRequestDuration, n)
+	}
+	errs := meter.instruments[MetricRequestErrors].samples
+	if len(errs) != 2 || errs[0]["class"] != ErrorClassServerError || errs[1]["class"] != ErrorClassValidation {
+		t.Errorf("%s samples = %v", MetricRequestErrors, errs)
+	}
+}
+
+func TestTelemetry_CacheHitsMakeNoAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tracer := &testTracer{}
+	client := New(&Config{BaseURL: server.URL, Tracer: tracer, Cache: &CacheConfig{}})
+	req := &user.ListingsRequest{Wallets: []string{testWallet}, Limit: 10}
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.User.GetListings(context.Background(), req); err != nil {
+			t.Fatalf("GetListings() error = %v", err)
+		}
+	}
+	if got := tracer.spans[0].attrs["tensor.attempts"]; got != "1" {
+		t.Errorf("first call attempts = %s, want 1", got)
+	}
+	if got := tracer.spans[1].attrs["tensor.attempts"]; got != "0" {
+		t.Errorf("cached call attempts = %s, want 0", got)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		name string
+		meta request.Metadata
+		err  error
+		want string
+	}{
+		{"rate limit", request.Metadata{FailedAt: request.StageStatus, StatusCode: 429}, nil, ErrorClassRateLimit},
+		{"client error", request.Metadata{FailedAt: request.StageStatus, StatusCode: 404}, nil, ErrorClassClientError},
+		{"decode", request.Metadata{FailedAt: request.StageDecode, StatusCode: 200}, nil, ErrorClassDecode},
+		{"canceled", request.Metadata{FailedAt: request.StageTransport}, context.Canceled, ErrorClassCanceled},
+		{"timeout", request.Metadata{FailedAt: request.StageTransport}, context.DeadlineExceeded, ErrorClassTimeout},
+		{"network", request.Metadata{FailedAt: request.StageTransport}, http.ErrServerClosed, ErrorClassNetwork},
+	}
+	for _, tt := range tests {
+		if got := ErrorClass(&tt.meta, tt.err); got != tt.want {
+			t.Errorf("%s: ErrorClass() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSpanName(t *testing.T) {
+	if got := SpanName("/api/v1/tx/buy"); got != "tensor.Marketplace.BuyNFT" {
+		t.Errorf("SpanName() = %q", got)
+	}
+	if got := SpanName("/unknown"); got != "tensor /unknown" {
+		t.Errorf("SpanName() = %q", got)
+	}
+}
@@ -24,6 +24,14 @@ func NewTransport(cfg Config) transport.Transport {
 	client := &http.Client{
 		Timeout: cfg.Timeout,
 	}
+	if cfg.HTTPClient != nil {
+		// Copy so the caller's client is never mutated
+		custom := *cfg.HTTPClient
+		if custom.Timeout == 0 {
+			custom.Timeout = cfg.Timeout
+		}
+		client = &custom
+	}
 
 	return &HTTPTransport{
 		client:  client,
@@ -58,6 +66,7 @@ func (t *HTTPTransport) Get(ctx context.Context, path string, params url.Values)
 	}
 
 	// Perform the request
+	transport.CountAttempt(ctx)
 	resp, err := t.client.Do(req)
 	if err != nil {
 		return nil, &errors.NetworkError{
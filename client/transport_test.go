@@ -425,3 +425,26 @@ func TestHTTPTransport_Get_InvalidURL(t *testing.T) {
 		t.Errorf("Expected operation 'create_request', got %q", netErr.Op)
 	}
 }
+
+func TestNewTransport_CustomHTTPClient(t *testing.T) {
+	custom := &http.Client{Transport: http.DefaultTransport}
+
+	got := NewTransport(Config{
+		BaseURL:    "https://api.example.com",
+		Timeout:    7 * time.Second,
+		HTTPClient: custom,
+	}).(*HTTPTransport)
+
+	if got.client == custom {
+		t.Error("NewTransport() must not share the caller's http.Client")
+	}
+	if got.client.Transport != http.DefaultTransport {
+		t.Error("NewTransport() did not keep the custom RoundTripper")
+	}
+	if got.client.Timeout != 7*time.Second {
+		t.Errorf("NewTransport().client.Timeout = %v, want 7s", got.client.Timeout)
+	}
+	if custom.Timeout != 0 {
+		t.Error("NewTransport() mutated the caller's http.Client")
+	}
+}
@@ -0,0 +1,192 @@
+// Package cluster describes the Solana clusters Tensor runs on: the Tensor API
+// base URL, the Solana RPC and websocket URLs, the Tensor program IDs and the
+// currency mints of each. A Submitter guards against sending a transaction
+// built for one cluster to another.
+//
+//	c := client.New(&client.Config{APIKey: key, Cluster: &cluster.Devnet})
+//	sub, err := c.Submitter()
+package cluster
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Tensor program IDs. They are deployed at the same addresses on every cluster.
+const (
+	ProgramTComp     = "TCMPhJdwDryooaGtiocG1u3xcYbRpiJzb283XfCZsDp"
+	ProgramTSwap     = "TSWAPaqyCSx2KABk68Shruf4rp7CxcNi8hAsbdwmHbN"
+	ProgramTAmm      = "TAMM6ub33ij1mbetoMyVBLeKY5iP41i4UPUJQGkhfsg"
+	ProgramTBid      = "TB1Dqt8JeKQh7RLDzfYDJsq8KS4fS2yt87avRjyRxMv"
+	ProgramTLock     = "TLoCKic2gGJm7VhZKumih4Lc35fUhYqVMgA4j389Buk"
+	ProgramWhitelist = "TL1ST2iRBzuGTqLn1KXnGdSnEow62BzPnGiqyRXhWtW"
+)
+
+// WrappedSOLMint is the native SOL mint, identical on every cluster
+const WrappedSOLMint = "So11111111111111111111111111111111111111112"
+
+// Programs holds the Tensor program IDs of a cluster
+type Programs struct {
+	TComp     string // Marketplace listings and bids
+	TSwap     string // Legacy AMM pools
+	TAmm      string // AMM pools
+	TBid      string // Single NFT bids
+	TLock     string // Price locks
+	Whitelist string // Collection whitelists used by pools and collection bids
+}
+
+// Currency is an SPL token accepted as a listing or bid currency
+type Currency struct {
+	Symbol   string
+	Mint     string
+	Decimals uint8
+}
+
+// Cluster bundles everything that must agree for a transaction to be valid:
+// the Tensor API that builds it and the Solana cluster that executes it
+type Cluster struct {
+	Name          string
+	TensorBaseURL string
+	RPCURL        string
+	WSURL         string
+
+	// GenesisHash identifies the Solana cluster. Empty for clusters without
+	// a stable genesis, such as a local validator, which skips that check.
+	GenesisHash string
+
+	Programs   Programs
+	Currencies []Currency
+}
+
+var tensorPrograms = Programs{
+	TComp:     ProgramTComp,
+	TSwap:     ProgramTSwap,
+	TAmm:      ProgramTAmm,
+	TBid:      ProgramTBid,
+	TLock:     ProgramTLock,
+	Whitelist: ProgramWhitelist,
+}
+
+// Known clusters
+var (
+	Mainnet = Cluster{
+		Name:          "mainnet",
+		TensorBaseURL: "https://api.mainnet.tensordev.io",
+		RPCURL:        "https://api.mainnet-beta.solana.com",
+		WSURL:         "wss://api.mainnet-beta.solana.com",
+		GenesisHash:   "5eykt4UsFv8P8NJdTREpY1vzqKqZKvdpKuc147dw2N9d",
+		Programs:      tensorPrograms,
+		Currencies: []Currency{
+			{Symbol: "SOL", Mint: WrappedSOLMint, Decimals: 9},
+			{Symbol: "USDC", Mint: "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v", Decimals: 6},
+			{Symbol: "USDT", Mint: "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB", Decimals: 6},
+			{Symbol: "BONK", Mint: "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263", Decimals: 5},
+		},
+	}
+
+	Devnet = Cluster{
+		Name:          "devnet",
+		TensorBaseURL: "https://api.devnet.tensordev.io",
+		RPCURL:        "https://api.devnet.solana.com",
+		WSURL:         "wss://api.devnet.solana.com",
+		GenesisHash:   "EtWTRABZaYq6iMfeYKouRu166VU2xqa1wcaWoxPkrZBG",
+		Programs:      tensorPrograms,
+		Currencies: []Currency{
+			{Symbol: "SOL", Mint: WrappedSOLMint, Decimals: 9},
+			{Symbol: "USDC", Mint: "4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDU", Decimals: 6},
+		},
+	}
+
+	// Localnet points at a local validator and a local Tensor API, e.g. a tensortest server
+	Localnet = Cluster{
+		Name:          "localnet",
+		TensorBaseURL: "http://localhost:8080",
+		RPCURL:        "http://localhost:8899",
+		WSURL:         "ws://localhost:8900",
+		Programs:      tensorPrograms,
+		Currencies: []Currency{
+			{Symbol: "SOL", Mint: WrappedSOLMint, Decimals: 9},
+		},
+	}
+)
+
+// Known returns the predefined clusters
+func Known() []Cluster {
+	return []Cluster{Mainnet, Devnet, Localnet}
+}
+
+// ByName looks up a predefined cluster. Solana's own names are accepted as
+// aliases: mainnet-beta for mainnet and localhost for localnet.
+func ByName(name string) (Cluster, bool) {
+	switch strings.ToLower(name) {
+	case "mainnet", "mainnet-beta":
+		return Mainnet, true
+	case "devnet":
+		return Devnet, true
+	case "localnet", "localhost":
+		return Localnet, true
+	}
+	return Cluster{}, false
+}
+
+// ForTensorBaseURL returns the predefined cluster served by a Tensor API base URL
+func ForTensorBaseURL(baseURL string) (Cluster, bool) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	for _, c := range Known() {
+		if c.TensorBaseURL == baseURL {
+			return c, true
+		}
+	}
+	return Cluster{}, false
+}
+
+// Currency returns the currency with the given symbol or mint address
+func (c Cluster) Currency(symbolOrMint string) (Currency, bool) {
+	for _, cur := range c.Currencies {
+		if strings.EqualFold(cur.Symbol, symbolOrMint) || cur.Mint == symbolOrMint {
+			return cur, true
+		}
+	}
+	return Currency{}, false
+}
+
+// Validate checks that the cluster has a name and well formed URLs
+func (c Cluster) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("cluster name is required")
+	}
+	if err := checkURL(c.TensorBaseURL, "tensorBaseURL", "http", "https"); err != nil {
+		return fmt.Errorf("cluster %s: %w", c.Name, err)
+	}
+	if err := checkURL(c.RPCURL, "rpcURL", "http", "https"); err != nil {
+		return fmt.Errorf("cluster %s: %w", c.Name, err)
+	}
+	if c.WSURL != "" {
+		if err := checkURL(c.WSURL, "wsURL", "ws", "wss"); err != nil {
+			return fmt.Errorf("cluster %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+func checkURL(raw, field string, schemes ...string) error {
+	if raw == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid %s: %s", field, raw)
+	}
+	for _, s := range schemes {
+		if u.Scheme == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must use %s: %s", field, strings.Join(schemes, " or "), raw)
+}
+
+// String returns the cluster name
+func (c Cluster) String() string {
+	return c.Name
+}
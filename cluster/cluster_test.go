@@ -0,0 +1,272 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestByName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"mainnet", "mainnet", true},
+		{"mainnet-beta", "mainnet", true},
+		{"Devnet", "devnet", true},
+		{"localhost", "localnet", true},
+		{"testnet", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ByName(tt.name)
+		if ok != tt.ok || got.Name != tt.want {
+			t.Errorf("ByName(%q) = %q, %v, want %q, %v", tt.name, got.Name, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestForTensorBaseURL(t *testing.T) {
+	if c, ok := ForTensorBaseURL("https://api.devnet.tensordev.io/"); !ok || c.Name != "devnet" {
+		t.Errorf("ForTensorBaseURL(devnet) = %q, %v", c.Name, ok)
+	}
+	if _, ok := ForTensorBaseURL("https://proxy.example.com"); ok {
+		t.Error("ForTensorBaseURL() matched an unknown URL")
+	}
+}
+
+func TestCluster_Currency(t *testing.T) {
+	usdc, ok := Mainnet.Currency("usdc")
+	if !ok || usdc.Decimals != 6 || usdc.Mint != "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v" {
+		t.Errorf("Mainnet.Currency(usdc) = %+v, %v", usdc, ok)
+	}
+	if _, ok := Devnet.Currency(usdc.Mint); ok {
+		t.Error("Devnet knows the mainnet USDC mint")
+	}
+	if sol, ok := Devnet.Currency(WrappedSOLMint); !ok || sol.Symbol != "SOL" {
+		t.Errorf("Devnet.Currency(wSOL) = %+v, %v", sol, ok)
+	}
+}
+
+func TestCluster_Validate(t *testing.T) {
+	for _, c := range Known() {
+		if err := c.Validate(); err != nil {
+			t.Errorf("%s.Validate() error = %v", c, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cluster Cluster
+		want    string
+	}{
+		{"missing name", Cluster{}, "cluster name is required"},
+		{"missing base URL", Cluster{Name: "x", RPCURL: "http://rpc"}, "tensorBaseURL is required"},
+		{"bad rpc scheme", Cluster{Name: "x", TensorBaseURL: "http://api", RPCURL: "ws://rpc"}, "rpcURL must use http or https"},
+		{"bad ws scheme", Cluster{Name: "x", TensorBaseURL: "http://api", RPCURL: "http://rpc", WSURL: "http://ws"}, "wsURL must use ws or wss"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cluster.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// buildTx serializes a minimal transaction with the given recent blockhash
+func buildTx(blockhash []byte, versioned bool) []byte {
+	var b bytes.Buffer
+	b.WriteByte(1)            // one signature
+	b.Write(make([]byte, 64)) // zeroed signature
+	if versioned {
+		b.WriteByte(0x80) // v0
+	}
+	b.Write([]byte{1, 0, 1}) // header
+	b.WriteByte(2)           // two account keys
+	b.Write(bytes.Repeat([]byte{7}, 64))
+	b.Write(blockhash)
+	b.WriteByte(0) // no instructions
+	if versioned {
+		b.WriteByte(0) // no address table lookups
+	}
+	return b.Bytes()
+}
+
+func TestRecentBlockhash(t *testing.T) {
+	hash := bytes.Repeat([]byte{9}, 32)
+	want := solana.HashFromBytes(hash).String()
+
+	for _, versioned := range []bool{false, true} {
+		got, err := RecentBlockhash(buildTx(hash, versioned))
+		if err != nil || got != want {
+			t.Errorf("RecentBlockhash(versioned=%v) = %q, %v, want %q", versioned, got, err, want)
+		}
+	}
+
+	tx := buildTx(hash, true)
+	if _, err := RecentBlockhash(tx[:100]); err == nil {
+		t.Error("RecentBlockhash() of a truncated transaction succeeded")
+	}
+	decoded, err := DecodeTransaction(base64.StdEncoding.EncodeToString(tx))
+	if err != nil || !bytes.Equal(decoded, tx) {
+		t.Errorf("DecodeTransaction() = %v, %v", decoded, err)
+	}
+}
+
+var (
+	latestBlockhash = solana.HashFromBytes(bytes.Repeat([]byte{5}, 32)).String()
+	sentSignature   = solana.SignatureFromBytes(bytes.Repeat([]byte{6}, 64)).String()
+)
+
+// fakeRPC is a JSON-RPC endpoint with a fixed genesis hash and set of valid blockhashes
+type fakeRPC struct {
+	genesis string
+	valid   map[string]bool
+	methods []string
+}
+
+func (f *fakeRPC) serve(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		f.methods = append(f.methods, req.Method)
+
+		var result any
+		switch req.Method {
+		case "getGenesisHash":
+			result = f.genesis
+		case "isBlockhashValid":
+			var hash string
+			json.Unmarshal(req.Params[0], &hash)
+			result = map[string]any{"value": f.valid[hash]}
+		case "getLatestBlockhash":
+			result = map[string]any{"value": map[string]any{"blockhash": latestBlockhash, "lastValidBlockHeight": 42}}
+		case "sendTransaction":
+			result = sentSignature
+		default:
+			json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": -32601, "message": "method not found"}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSubmitter_RefusesOtherClusters(t *testing.T) {
+	hash := bytes.Repeat([]byte{3}, 32)
+	tx := buildTx(hash, true)
+
+	tests := []struct {
+		name         string
+		cluster      Cluster
+		rpc          fakeRPC
+		wantMismatch string
+		wantErr      error
+		wantSig      string
+	}{
+		{
+			name:         "rpc serves another cluster",
+			cluster:      Mainnet,
+			rpc:          fakeRPC{genesis: Devnet.GenesisHash},
+			wantMismatch: "serves devnet",
+		},
+		{
+			name:    "expired blockhash",
+			cluster: Devnet,
+			rpc:     fakeRPC{genesis: Devnet.GenesisHash},
+			wantErr: ErrBlockhashExpired,
+		},
+		{
+			name:         "blockhash from another cluster without genesis hash",
+			cluster:      Localnet,
+			rpc:          fakeRPC{genesis: "anything"},
+			wantMismatch: "not valid on localnet",
+		},
+		{
+			name:    "matching cluster",
+			cluster: Devnet,
+			rpc:     fakeRPC{genesis: Devnet.GenesisHash, valid: map[string]bool{solana.HashFromBytes(hash).String(): true}},
+			wantSig: sentSignature,
+		},
+		{
+			name:    "cluster without genesis hash",
+			cluster: Localnet,
+			rpc:     fakeRPC{genesis: "anything", valid: map[string]bool{solana.HashFromBytes(hash).String(): true}},
+			wantSig: sentSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := tt.rpc.serve(t)
+			s := NewSubmitter(tt.cluster, srv.URL, nil)
+
+			sig, err := s.Send(context.Background(), tx)
+			if tt.wantErr != nil {
+				var mismatch *MismatchError
+				if !errors.Is(err, tt.wantErr) || errors.As(err, &mismatch) {
+					t.Fatalf("Send() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if tt.wantMismatch != "" {
+				var mismatch *MismatchError
+				if !errors.Is(err, ErrClusterMismatch) || !errors.As(err, &mismatch) || !strings.Contains(err.Error(), tt.wantMismatch) {
+					t.Fatalf("Send() error = %v, want mismatch mentioning %q", err, tt.wantMismatch)
+				}
+				for _, m := range tt.rpc.methods {
+					if m == "sendTransaction" {
+						t.Error("transaction was sent despite the mismatch")
+					}
+				}
+				return
+			}
+			if err != nil || sig != tt.wantSig {
+				t.Fatalf("Send() = %q, %v, want %q", sig, err, tt.wantSig)
+			}
+		})
+	}
+}
+
+func TestSubmitter_VerifiesOnceAndReportsRPCErrors(t *testing.T) {
+	rpc := &fakeRPC{genesis: Mainnet.GenesisHash}
+	srv := rpc.serve(t)
+	s := NewSubmitter(Mainnet, srv.URL, nil)
+
+	for i := 0; i < 2; i++ {
+		hash, height, err := s.LatestBlockhash(context.Background())
+		if err != nil || hash != latestBlockhash || height != 42 {
+			t.Fatalf("LatestBlockhash() = %q, %d, %v", hash, height, err)
+		}
+	}
+	if got := strings.Join(rpc.methods, ","); got != "getGenesisHash,getLatestBlockhash,getLatestBlockhash" {
+		t.Errorf("methods = %s, want one genesis check", got)
+	}
+
+	var rpcErr *RPCError
+	if _, err := s.rpc.GetHealth(context.Background()); !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Errorf("GetHealth() error = %v, want RPCError -32601", err)
+	}
+}
+
+func TestStaticBlockhash(t *testing.T) {
+	var source BlockhashSource = StaticBlockhash("abc")
+	hash, height, err := source.LatestBlockhash(context.Background())
+	if err != nil || hash != "abc" || height != 0 {
+		t.Errorf("LatestBlockhash() = %q, %d, %v", hash, height, err)
+	}
+}
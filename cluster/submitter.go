@@ -0,0 +1,196 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// ErrClusterMismatch is matched by every MismatchError, for use with errors.Is
+var ErrClusterMismatch = errors.New("cluster mismatch")
+
+// MismatchError reports a transaction or endpoint that belongs to a cluster
+// other than the one it was about to be used with
+type MismatchError struct {
+	Cluster string // The cluster the check was made for
+	Detail  string
+}
+
+// Error implements the error interface
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("cluster mismatch: expected %s: %s", e.Cluster, e.Detail)
+}
+
+// Is makes errors.Is(err, ErrClusterMismatch) match
+func (e *MismatchError) Is(target error) bool {
+	return target == ErrClusterMismatch
+}
+
+// RPCError is an error returned by a Solana JSON-RPC endpoint
+type RPCError = jsonrpc.RPCError
+
+// ErrBlockhashExpired reports a transaction for the right cluster whose recent
+// blockhash is no longer valid. Rebuild and re-sign it with a fresh blockhash.
+var ErrBlockhashExpired = errors.New("blockhash expired")
+
+// Submitter sends signed transactions to one cluster, refusing transactions
+// built for another. Before the first request it checks that the RPC endpoint
+// reports the cluster's genesis hash, and before each send it checks that the
+// transaction's recent blockhash is known to the cluster.
+type Submitter struct {
+	cluster Cluster
+	rpcURL  string
+	rpc     *rpc.Client
+
+	mu       sync.Mutex
+	verified bool
+}
+
+// NewSubmitter creates a Submitter for c. An empty rpcURL uses c.RPCURL and a
+// nil httpClient uses one with a 30 second timeout.
+func NewSubmitter(c Cluster, rpcURL string, httpClient *http.Client) *Submitter {
+	if rpcURL == "" {
+		rpcURL = c.RPCURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	client := rpc.NewWithCustomRPCClient(jsonrpc.NewClientWithOpts(rpcURL, &jsonrpc.RPCClientOpts{HTTPClient: httpClient}))
+	return &Submitter{cluster: c, rpcURL: rpcURL, rpc: client}
+}
+
+// Cluster returns the cluster transactions are submitted to
+func (s *Submitter) Cluster() Cluster {
+	return s.cluster
+}
+
+// VerifyRPC checks that the RPC endpoint serves the submitter's cluster.
+// Success is remembered; clusters without a GenesisHash always pass.
+//
+// Returns:
+//   - A *MismatchError if the endpoint belongs to another cluster
+//   - An error if the endpoint could not be queried
+func (s *Submitter) VerifyRPC(ctx context.Context) error {
+	if s.cluster.GenesisHash == "" {
+		return nil
+	}
+	s.mu.Lock()
+	verified := s.verified
+	s.mu.Unlock()
+	if verified {
+		return nil
+	}
+
+	hash, err := s.rpc.GetGenesisHash(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get genesis hash: %w", err)
+	}
+	if genesis := hash.String(); genesis != s.cluster.GenesisHash {
+		detail := fmt.Sprintf("RPC endpoint %s has genesis hash %s", s.rpcURL, genesis)
+		for _, known := range Known() {
+			if known.GenesisHash == genesis {
+				detail = fmt.Sprintf("RPC endpoint %s serves %s", s.rpcURL, known.Name)
+			}
+		}
+		return &MismatchError{Cluster: s.cluster.Name, Detail: detail}
+	}
+
+	s.mu.Lock()
+	s.verified = true
+	s.mu.Unlock()
+	return nil
+}
+
+// LatestBlockhash fetches a blockhash to build transactions with, from the
+// verified cluster so the resulting transactions pass CheckTransaction
+//
+// Returns:
+//   - The base58 blockhash and the last block height it is valid for
+//   - An error if the endpoint could not be verified or queried
+func (s *Submitter) LatestBlockhash(ctx context.Context) (string, uint64, error) {
+	if err := s.VerifyRPC(ctx); err != nil {
+		return "", 0, err
+	}
+	result, err := s.rpc.GetLatestBlockhash(ctx, rpc.CommitmentConfirmed)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get latest blockhash: %w", err)
+	}
+	if result == nil || result.Value == nil {
+		return "", 0, fmt.Errorf("failed to get latest blockhash: empty result")
+	}
+	return result.Value.Blockhash.String(), result.Value.LastValidBlockHeight, nil
+}
+
+// CheckTransaction verifies that a serialized transaction was built for the
+// submitter's cluster: its recent blockhash must be valid there. Once the
+// endpoint has passed the genesis check, a blockhash it does not know is
+// reported as expired; for clusters without a GenesisHash it may equally be
+// from another cluster and is reported as a mismatch.
+//
+// Returns:
+//   - A *MismatchError if the transaction does not belong to the cluster
+//   - ErrBlockhashExpired if the transaction's blockhash is no longer valid
+//   - An error if the transaction is malformed or the endpoint could not be queried
+func (s *Submitter) CheckTransaction(ctx context.Context, tx []byte) error {
+	blockhash, err := recentBlockhash(tx)
+	if err != nil {
+		return err
+	}
+	if err := s.VerifyRPC(ctx); err != nil {
+		return err
+	}
+	result, err := s.rpc.IsBlockhashValid(ctx, blockhash, rpc.CommitmentProcessed)
+	if err != nil {
+		return fmt.Errorf("failed to check blockhash: %w", err)
+	}
+	if result.Value {
+		return nil
+	}
+	if s.cluster.GenesisHash != "" {
+		return fmt.Errorf("%w: blockhash %s is no longer valid on %s", ErrBlockhashExpired, blockhash, s.cluster.Name)
+	}
+	return &MismatchError{
+		Cluster: s.cluster.Name,
+		Detail:  fmt.Sprintf("blockhash %s is not valid on %s; the transaction was built for another cluster or has expired", blockhash, s.cluster.Name),
+	}
+}
+
+// Send checks a signed transaction with CheckTransaction and submits it
+//
+// Returns:
+//   - The transaction signature
+//   - A *MismatchError if the transaction does not belong to the cluster
+//   - ErrBlockhashExpired if the transaction's blockhash is no longer valid
+//   - An error if the transaction was rejected or the endpoint could not be queried
+func (s *Submitter) Send(ctx context.Context, tx []byte) (string, error) {
+	if err := s.CheckTransaction(ctx, tx); err != nil {
+		return "", err
+	}
+	signature, err := s.rpc.SendRawTransaction(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return signature.String(), nil
+}
+
+// BlockhashSource supplies recent blockhashes to build transactions with.
+// *Submitter implements it; StaticBlockhash is a fixed source for tests and dry runs.
+type BlockhashSource interface {
+	LatestBlockhash(ctx context.Context) (string, uint64, error)
+}
+
+var _ BlockhashSource = (*Submitter)(nil)
+
+// StaticBlockhash is a BlockhashSource that always returns the same blockhash
+type StaticBlockhash string
+
+// LatestBlockhash returns the blockhash with a last valid block height of zero
+func (b StaticBlockhash) LatestBlockhash(ctx context.Context) (string, uint64, error) {
+	return string(b), 0, nil
+}
@@ -0,0 +1,37 @@
+package cluster
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// DecodeTransaction decodes a base64 serialized transaction, such as the
+// TxV0 field of a Tensor transaction response
+func DecodeTransaction(encoded string) ([]byte, error) {
+	tx, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// RecentBlockhash returns the base58 recent blockhash of a serialized legacy or
+// v0 transaction. The blockhash ties a transaction to the cluster it was built for.
+func RecentBlockhash(tx []byte) (string, error) {
+	hash, err := recentBlockhash(tx)
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+func recentBlockhash(tx []byte) (solana.Hash, error) {
+	decoded, err := solana.TransactionFromDecoder(bin.NewBinDecoder(tx))
+	if err != nil {
+		return solana.Hash{}, fmt.Errorf("malformed transaction: %w", err)
+	}
+	return decoded.Message.RecentBlockhash, nil
+}
@@ -0,0 +1,371 @@
+// Package escrowkeeper keeps a wallet's shared escrow funded for its bids.
+// Bids placed with UseSharedEscrow draw on one escrow account and stop
+// filling once it runs dry, without any error on the bid itself. Each Step
+// compares the escrow balance with the exposure of the wallet's open
+// shared-escrow bids, builds a deposit or withdrawal that brings the balance
+// back inside a band around that exposure, and raises an alert when the bids
+// are not fully collateralized. The caller charges the transfer to a rolling
+// daily cap with Confirm and only signs and sends its transactions when
+// Confirm succeeds.
+package escrowkeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/escrow"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// Defaults applied by New to zero config fields
+const (
+	DefaultMinCoverBps = 10_000     // Exposure must be fully covered
+	DefaultMaxCoverBps = 15_000     // Balances over 150% of exposure are withdrawn
+	DefaultMinTransfer = 10_000_000 // 0.01 SOL
+)
+
+// Config configures a Keeper
+type Config struct {
+	Owner string // Wallet that owns the escrow account and the bids
+
+	// The band is expressed in basis points of exposure, on top of Reserve.
+	// Below MinCoverBps the keeper deposits and above MaxCoverBps it
+	// withdraws, in both cases moving the balance to TargetCoverBps, which
+	// defaults to halfway between the two.
+	MinCoverBps    int64
+	TargetCoverBps int64
+	MaxCoverBps    int64
+	Reserve        money.Lamports // Balance kept in escrow on top of the band, e.g. for bids about to be placed
+
+	DailyCap    money.Lamports // Most SOL deposited plus withdrawn in any rolling 24 hours
+	MinTransfer money.Lamports // Smaller transfers are skipped
+
+	// OnAlert is called when the bids are under-collateralized after a step. Optional.
+	OnAlert func(Alert)
+	// Logger receives transfers and alerts. Nothing is logged when nil.
+	Logger *slog.Logger
+}
+
+// validate checks the config fields after defaults are applied
+func (c *Config) validate() error {
+	if c.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if c.MinCoverBps <= 0 || c.TargetCoverBps < c.MinCoverBps || c.MaxCoverBps < c.TargetCoverBps {
+		return fmt.Errorf("cover band must satisfy 0 < minCoverBps <= targetCoverBps <= maxCoverBps")
+	}
+	if c.Reserve < 0 || c.MinTransfer < 0 {
+		return fmt.Errorf("reserve and minTransfer must be >= 0")
+	}
+	if c.DailyCap <= 0 {
+		return fmt.Errorf("dailyCap must be > 0")
+	}
+	return nil
+}
+
+// ErrDailyCap is returned by Confirm for a transfer that would take the SOL
+// moved in the last 24 hours over Config.DailyCap
+var ErrDailyCap = errors.New("daily cap exceeded")
+
+// APIs are the SDK services a Keeper uses. The fields match those of client.Client.
+type APIs struct {
+	User   user.UserAPI // Reads escrow accounts and bids
+	Escrow escrow.EscrowAPI
+}
+
+// Direction of a transfer
+const (
+	Deposit  = "deposit"
+	Withdraw = "withdraw"
+)
+
+// Transfer is one escrow deposit or withdrawal. Building it moves no SOL:
+// the caller calls Keeper.Confirm, then signs and sends Txs.
+type Transfer struct {
+	Action string // Deposit or Withdraw
+	Amount money.Lamports
+	Reason string
+	Txs    []escrow.Transaction // The unsigned transactions
+
+	confirmed bool
+}
+
+// Alert reports bids the escrow cannot fully fund
+type Alert struct {
+	Time      time.Time
+	Owner     string
+	Balance   money.Lamports // Escrow balance read by the step
+	Exposure  money.Lamports
+	Shortfall money.Lamports // Exposure minus balance
+	Reason    string         // Why the keeper could not close the gap
+}
+
+// Report describes one Step
+type Report struct {
+	Time     time.Time
+	Balance  money.Lamports // Escrow balance before the transfer
+	Exposure money.Lamports // Price times remaining quantity of every open shared-escrow bid
+	Bids     int            // Number of open shared-escrow bids
+
+	Lower  money.Lamports // Balance under which the keeper deposits
+	Target money.Lamports // Balance transfers aim for
+	Upper  money.Lamports // Balance over which the keeper withdraws
+
+	Transfer *Transfer // The transfer built, nil if none; it only counts once confirmed
+	Skipped  string    // Why a needed transfer was reduced or not made
+	Alert    *Alert    // Set when the bids are under-collateralized
+}
+
+// Keeper manages one wallet's shared escrow. It is safe for concurrent use,
+// but Steps are serialized.
+type Keeper struct {
+	cfg         Config
+	apis        APIs
+	blockhashes cluster.BlockhashSource
+	logger      *slog.Logger
+	now         func() time.Time
+
+	mu        sync.Mutex
+	transfers []ledgerEntry // Confirmed transfers in the last 24 hours, oldest first
+}
+
+type ledgerEntry struct {
+	at     time.Time
+	amount money.Lamports
+}
+
+// New creates a Keeper
+//
+// Returns:
+//   - The keeper
+//   - An error if the config is incomplete or a required API is nil
+func New(cfg Config, apis APIs, blockhashes cluster.BlockhashSource) (*Keeper, error) {
+	if cfg.MinCoverBps == 0 {
+		cfg.MinCoverBps = DefaultMinCoverBps
+	}
+	if cfg.MaxCoverBps == 0 {
+		cfg.MaxCoverBps = max(DefaultMaxCoverBps, cfg.MinCoverBps)
+	}
+	if cfg.TargetCoverBps == 0 {
+		cfg.TargetCoverBps = (cfg.MinCoverBps + cfg.MaxCoverBps) / 2
+	}
+	if cfg.MinTransfer == 0 {
+		cfg.MinTransfer = DefaultMinTransfer
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if apis.User == nil || apis.Escrow == nil {
+		return nil, fmt.Errorf("user and escrow APIs are required")
+	}
+	if blockhashes == nil {
+		return nil, fmt.Errorf("blockhash source is required")
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return &Keeper{
+		cfg:         cfg,
+		apis:        apis,
+		blockhashes: blockhashes,
+		logger:      logger.With("owner", cfg.Owner),
+		now:         time.Now,
+	}, nil
+}
+
+// Step reads the escrow balance and bid exposure, builds at most one transfer
+// and raises an alert if the bids are under-collateralized at the balance
+// read. The transfer is sized against the transfers confirmed in the last
+// 24 hours; a transfer that is never confirmed does not use up the cap.
+//
+// Returns:
+//   - The report
+//   - An error if the balance or bids could not be read or building the
+//     transfer failed; the report is still returned, with any alert, when
+//     building the transfer failed
+func (k *Keeper) Step(ctx context.Context) (*Report, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := k.now()
+	report := &Report{Time: now}
+	balance, err := k.balance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exposure, bids, err := k.exposure(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	report.Balance, report.Exposure, report.Bids = balance, exposure, bids
+	report.Lower = exposure.MulDiv(k.cfg.MinCoverBps, 10_000) + k.cfg.Reserve
+	report.Target = exposure.MulDiv(k.cfg.TargetCoverBps, 10_000) + k.cfg.Reserve
+	report.Upper = exposure.MulDiv(k.cfg.MaxCoverBps, 10_000) + k.cfg.Reserve
+
+	transfer := k.plan(report, now)
+	var transferErr error
+	if transfer != nil {
+		if transferErr = k.build(ctx, transfer); transferErr == nil {
+			report.Transfer = transfer
+		} else {
+			report.Skipped = fmt.Sprintf("%s failed: %v", transfer.Action, transferErr)
+		}
+	}
+
+	if balance < exposure {
+		var reason string
+		switch {
+		case report.Skipped != "":
+			reason = report.Skipped
+		case report.Transfer != nil && report.Transfer.Action == Deposit:
+			reason = fmt.Sprintf("deposit of %s SOL not confirmed yet", report.Transfer.Amount.SOL())
+		default:
+			reason = "minCoverBps allows less than full cover"
+		}
+		report.Alert = &Alert{Time: now, Owner: k.cfg.Owner, Balance: balance, Exposure: exposure, Shortfall: exposure - balance, Reason: reason}
+		k.logger.Warn("bids are under-collateralized", "balance", balance.SOL(), "exposure", exposure.SOL(), "reason", reason)
+		if k.cfg.OnAlert != nil {
+			k.cfg.OnAlert(*report.Alert)
+		}
+	}
+	if transferErr != nil {
+		return report, fmt.Errorf("escrow %s failed: %w", transfer.Action, transferErr)
+	}
+	return report, nil
+}
+
+// Confirm charges t, built by Step, to the daily cap. Call it before sending
+// t's transactions and drop them if it fails: Steps made before a Confirm
+// all see the same remaining cap, so only the transfers confirmed first fit.
+//
+// Returns:
+//   - ErrDailyCap, wrapped, if t no longer fits the daily cap
+//   - An error if t is nil or was already confirmed
+func (k *Keeper) Confirm(t *Transfer) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if t == nil {
+		return fmt.Errorf("transfer is required")
+	}
+	if t.confirmed {
+		return fmt.Errorf("%s of %s SOL was already confirmed", t.Action, t.Amount.SOL())
+	}
+	if used := k.used(k.now()); t.Amount > k.cfg.DailyCap-used {
+		return fmt.Errorf("%w: %s of %s SOL does not fit after %s of %s SOL moved in the last 24h",
+			ErrDailyCap, t.Action, t.Amount.SOL(), used.SOL(), k.cfg.DailyCap.SOL())
+	}
+	t.confirmed = true
+	k.transfers = append(k.transfers, ledgerEntry{at: k.now(), amount: t.Amount})
+	k.logger.Info("escrow transfer confirmed", "action", t.Action, "amount", t.Amount.SOL())
+	return nil
+}
+
+// plan returns the transfer that brings the balance back into the band,
+// reduced to what the daily cap allows, or nil if none is needed or allowed
+func (k *Keeper) plan(report *Report, now time.Time) *Transfer {
+	var t *Transfer
+	switch {
+	case report.Balance < report.Lower:
+		t = &Transfer{Action: Deposit, Amount: report.Target - report.Balance, Reason: "balance under band"}
+	case report.Balance > report.Upper:
+		t = &Transfer{Action: Withdraw, Amount: report.Balance - report.Target, Reason: "balance over band"}
+	default:
+		return nil
+	}
+
+	used := k.used(now)
+	if remaining := k.cfg.DailyCap - used; t.Amount > remaining {
+		report.Skipped = fmt.Sprintf("daily cap: %s of %s SOL moved in the last 24h", used.SOL(), k.cfg.DailyCap.SOL())
+		t.Amount = max(remaining, 0)
+	}
+	if t.Amount < k.cfg.MinTransfer || t.Amount == 0 {
+		if report.Skipped == "" {
+			report.Skipped = fmt.Sprintf("%s of %s SOL is under the minimum transfer", t.Action, t.Amount.SOL())
+		}
+		return nil
+	}
+	return t
+}
+
+// used drops transfers older than 24 hours and returns the SOL moved by the rest
+func (k *Keeper) used(now time.Time) money.Lamports {
+	cutoff := now.Add(-24 * time.Hour)
+	for len(k.transfers) > 0 && !k.transfers[0].at.After(cutoff) {
+		k.transfers = k.transfers[1:]
+	}
+	var used money.Lamports
+	for _, e := range k.transfers {
+		used += e.amount
+	}
+	return used
+}
+
+// build fetches the transfer's unsigned transactions
+func (k *Keeper) build(ctx context.Context, t *Transfer) error {
+	blockhash, _, err := k.blockhashes.LatestBlockhash(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get blockhash: %w", err)
+	}
+	resp, _, err := k.apis.Escrow.DepositWithdrawEscrow(ctx, &escrow.DepositWithdrawEscrowRequest{
+		Action:    t.Action,
+		Owner:     k.cfg.Owner,
+		Lamports:  t.Amount,
+		Blockhash: blockhash,
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.Txs) == 0 {
+		return fmt.Errorf("no transaction returned")
+	}
+	t.Txs = resp.Txs
+	k.logger.Info("escrow transfer built", "action", t.Action, "amount", t.Amount.SOL(), "reason", t.Reason)
+	return nil
+}
+
+// balance returns the total balance of the owner's escrow accounts
+func (k *Keeper) balance(ctx context.Context) (money.Lamports, error) {
+	data, _, err := k.apis.User.GetEscrowAccounts(ctx, &user.EscrowAccountsRequest{Owner: k.cfg.Owner})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read escrow accounts: %w", err)
+	}
+	accounts, err := market.DecodeEscrowAccounts(data)
+	if err != nil {
+		return 0, err
+	}
+	var total money.Lamports
+	for _, a := range accounts {
+		total += a.Balance
+	}
+	return total, nil
+}
+
+// exposure returns the total exposure and count of the owner's open shared-escrow bids
+func (k *Keeper) exposure(ctx context.Context, now time.Time) (money.Lamports, int, error) {
+	var total money.Lamports
+	count := 0
+	for _, target := range []string{market.BidTargetNFT, market.BidTargetCollection, market.BidTargetTrait} {
+		bids, err := market.Bids(ctx, k.apis.User, target, k.cfg.Owner, "")
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to read %s bids: %w", strings.ToLower(target), err)
+		}
+		for _, b := range bids {
+			if !b.SharedEscrow || b.Remaining() == 0 || (b.ExpiresAt != 0 && b.ExpiresAt <= now.Unix()) {
+				continue
+			}
+			total += b.Exposure()
+			count++
+		}
+	}
+	return total, count, nil
+}
@@ -0,0 +1,263 @@
+package escrowkeeper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/escrow"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/tensortest"
+)
+
+var (
+	alice = tensortest.Address("alice")
+	start = time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+)
+
+// newTestKeeper returns a keeper for alice with 11 SOL of shared-escrow exposure
+func newTestKeeper(t *testing.T, cfg Config, clock *time.Time) (*tensortest.Server, *Keeper) {
+	t.Helper()
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.SetClock(func() time.Time { return *clock })
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID, Name: "Escrow"})
+	mint := tensortest.Address("nft")
+	srv.AddNFT(tensortest.NFT{Mint: mint, CollId: tensortest.CollID, Owner: tensortest.Address("bob")})
+
+	srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID,
+		Price: tensortest.Lamports("2"), Quantity: 7, FilledQuantity: 2, SharedEscrow: true})
+	srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetNFT, TargetId: mint, CollId: tensortest.CollID,
+		Price: tensortest.Lamports("1"), Quantity: 1, SharedEscrow: true})
+	// Neither of these draws on the shared escrow.
+	srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetTrait, TargetId: tensortest.CollID, CollId: tensortest.CollID,
+		Price: tensortest.Lamports("50"), Quantity: 1, Traits: []string{"Hat:Cap"}})
+	srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID,
+		Price: tensortest.Lamports("50"), Quantity: 1, SharedEscrow: true, ExpiresAt: start.Add(-time.Minute).Unix()})
+
+	c := client.New(&client.Config{BaseURL: srv.URL})
+	cfg.Owner = alice
+	k, err := New(cfg, APIs{User: c.User, Escrow: c.Escrow}, cluster.StaticBlockhash("hash"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	k.now = func() time.Time { return *clock }
+	return srv, k
+}
+
+func TestStepKeepsBalanceInBand(t *testing.T) {
+	tests := []struct {
+		name        string
+		balance     string
+		wantAction  string
+		wantAmount  money.Lamports
+		wantBalance money.Lamports
+		wantAlert   bool
+	}{
+		{name: "top up", balance: "3", wantAction: Deposit, wantAmount: money.MustParseSOL("11.75"), wantBalance: money.MustParseSOL("14.75"), wantAlert: true},
+		{name: "withdraw excess", balance: "20", wantAction: Withdraw, wantAmount: money.MustParseSOL("5.25"), wantBalance: money.MustParseSOL("14.75")},
+		{name: "in band", balance: "15", wantBalance: money.MustParseSOL("15")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := start
+			var alerts []Alert
+			srv, k := newTestKeeper(t, Config{DailyCap: money.MustParseSOL("100"), Reserve: money.MustParseSOL("1"), OnAlert: func(a Alert) { alerts = append(alerts, a) }}, &clock)
+			srv.SetEscrowBalance(alice, int64(money.MustParseSOL(tt.balance)))
+
+			report, err := k.Step(context.Background())
+			if err != nil {
+				t.Fatalf("Step() error = %v", err)
+			}
+			if report.Exposure != money.MustParseSOL("11") || report.Bids != 2 {
+				t.Errorf("exposure = %v over %d bids, want 11 SOL over 2", report.Exposure, report.Bids)
+			}
+			// Band: 100% to 150% of exposure plus the 1 SOL reserve, aiming for 125%.
+			if report.Lower != money.MustParseSOL("12") || report.Target != money.MustParseSOL("14.75") || report.Upper != money.MustParseSOL("17.5") {
+				t.Errorf("band = %v / %v / %v", report.Lower, report.Target, report.Upper)
+			}
+			switch {
+			case tt.wantAction == "" && report.Transfer != nil:
+				t.Errorf("transfer = %+v, want none", report.Transfer)
+			case tt.wantAction != "" && (report.Transfer == nil || report.Transfer.Action != tt.wantAction || report.Transfer.Amount != tt.wantAmount):
+				t.Errorf("transfer = %+v, want %s of %v", report.Transfer, tt.wantAction, tt.wantAmount)
+			case tt.wantAction != "" && (len(report.Transfer.Txs) != 1 || report.Transfer.Txs[0].TxV0 == ""):
+				t.Errorf("transfer txs = %+v, want the unsigned transaction", report.Transfer.Txs)
+			}
+			if got := money.Lamports(srv.EscrowBalance(alice)); got != tt.wantBalance {
+				t.Errorf("escrow balance = %v, want %v", got, tt.wantBalance)
+			}
+			// The balance read is what counts until the deposit is confirmed
+			if (report.Alert != nil) != tt.wantAlert || (len(alerts) == 1) != tt.wantAlert {
+				t.Errorf("alerts = %+v, want alert %v", alerts, tt.wantAlert)
+			}
+		})
+	}
+}
+
+func TestDailyCap(t *testing.T) {
+	clock := start
+	var alerts []Alert
+	srv, k := newTestKeeper(t, Config{DailyCap: money.MustParseSOL("5"), OnAlert: func(a Alert) { alerts = append(alerts, a) }}, &clock)
+	srv.SetEscrowBalance(alice, int64(money.MustParseSOL("3")))
+
+	report, err := k.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if report.Transfer == nil || report.Transfer.Amount != money.MustParseSOL("5") || !strings.HasPrefix(report.Skipped, "daily cap") {
+		t.Errorf("transfer = %+v, skipped %q; want a deposit capped at 5 SOL", report.Transfer, report.Skipped)
+	}
+	if report.Alert == nil || report.Alert.Shortfall != money.MustParseSOL("8") || report.Alert.Balance != money.MustParseSOL("3") {
+		t.Errorf("alert = %+v, want 8 SOL short at 3 SOL", report.Alert)
+	}
+	if err := k.Confirm(report.Transfer); err != nil {
+		t.Fatalf("Confirm() error = %v", err)
+	}
+	if err := k.Confirm(report.Transfer); err == nil {
+		t.Error("second Confirm() of the same transfer succeeded")
+	}
+
+	clock = clock.Add(23 * time.Hour)
+	report, err = k.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if report.Transfer != nil || report.Alert == nil || report.Alert.Shortfall != money.MustParseSOL("3") {
+		t.Errorf("within the day: transfer = %+v, alert = %+v; want no transfer and a 3 SOL alert", report.Transfer, report.Alert)
+	}
+
+	clock = clock.Add(time.Hour)
+	report, err = k.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if report.Transfer == nil || report.Transfer.Amount != money.MustParseSOL("5") {
+		t.Errorf("next day transfer = %+v, want 5 SOL", report.Transfer)
+	}
+	if report.Alert == nil || report.Alert.Balance != money.MustParseSOL("8") {
+		t.Errorf("next day alert = %+v, want 3 SOL short until the deposit is confirmed", report.Alert)
+	}
+	if len(alerts) != 3 {
+		t.Errorf("OnAlert called %d times, want 3", len(alerts))
+	}
+}
+
+func TestUnconfirmedTransfersDoNotUseCap(t *testing.T) {
+	clock := start
+	_, k := newTestKeeper(t, Config{DailyCap: money.MustParseSOL("100")}, &clock)
+	// tensortest applies deposits as soon as they are built, so a wallet
+	// that never sends them keeps the fake balance from moving
+	k.apis.Escrow = unsent{k.apis.Escrow}
+
+	for i := 0; i < 10; i++ {
+		report, err := k.Step(context.Background())
+		if err != nil {
+			t.Fatalf("Step() error = %v", err)
+		}
+		if report.Transfer == nil || report.Transfer.Amount != money.MustParseSOL("13.75") || report.Skipped != "" {
+			t.Fatalf("step %d: transfer = %+v, skipped %q; want the full deposit every time", i, report.Transfer, report.Skipped)
+		}
+		if report.Alert == nil || report.Alert.Balance != 0 {
+			t.Fatalf("step %d: alert = %+v, want the empty escrow reported", i, report.Alert)
+		}
+	}
+}
+
+func TestConfirmEnforcesDailyCap(t *testing.T) {
+	clock := start
+	_, k := newTestKeeper(t, Config{DailyCap: money.MustParseSOL("15")}, &clock)
+	k.apis.Escrow = unsent{k.apis.Escrow}
+
+	// Both Steps read the empty escrow and the whole cap before either is confirmed
+	var transfers []*Transfer
+	for i := 0; i < 2; i++ {
+		report, err := k.Step(context.Background())
+		if err != nil {
+			t.Fatalf("Step() error = %v", err)
+		}
+		if report.Transfer == nil || report.Transfer.Amount != money.MustParseSOL("13.75") {
+			t.Fatalf("step %d: transfer = %+v, want a 13.75 SOL deposit", i, report.Transfer)
+		}
+		transfers = append(transfers, report.Transfer)
+	}
+
+	if err := k.Confirm(transfers[0]); err != nil {
+		t.Fatalf("first Confirm() error = %v", err)
+	}
+	if err := k.Confirm(transfers[1]); !errors.Is(err, ErrDailyCap) {
+		t.Fatalf("second Confirm() error = %v, want ErrDailyCap", err)
+	}
+
+	// Only the first transfer is charged: 1.25 SOL of the cap is left
+	report, err := k.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if report.Transfer == nil || report.Transfer.Amount != money.MustParseSOL("1.25") {
+		t.Errorf("transfer = %+v, want the 1.25 SOL left under the cap", report.Transfer)
+	}
+}
+
+// unsent builds transfers without moving the fake server's balance
+type unsent struct{ escrow.EscrowAPI }
+
+func (unsent) DepositWithdrawEscrow(ctx context.Context, req *escrow.DepositWithdrawEscrowRequest) (*escrow.DepositWithdrawEscrowResponse, int, error) {
+	return &escrow.DepositWithdrawEscrowResponse{Status: "Ok", Txs: []escrow.Transaction{{TxV0: "AQ=="}}}, 200, nil
+}
+
+func TestMinTransfer(t *testing.T) {
+	clock := start
+	srv, k := newTestKeeper(t, Config{DailyCap: money.MustParseSOL("100"), MinCoverBps: 10_000, TargetCoverBps: 10_000, MaxCoverBps: 10_000, MinTransfer: money.MustParseSOL("0.5")}, &clock)
+	srv.SetEscrowBalance(alice, int64(money.MustParseSOL("11.2")))
+
+	report, err := k.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if report.Transfer != nil || !strings.Contains(report.Skipped, "under the minimum transfer") {
+		t.Errorf("transfer = %+v, skipped %q; want the 0.2 SOL withdrawal skipped", report.Transfer, report.Skipped)
+	}
+}
+
+func TestTransferFailureAlerts(t *testing.T) {
+	clock := start
+	var alerts []Alert
+	srv, k := newTestKeeper(t, Config{DailyCap: money.MustParseSOL("100"), OnAlert: func(a Alert) { alerts = append(alerts, a) }}, &clock)
+	srv.InjectFault(tensortest.Fault{PathPrefix: "/api/v1/tx/deposit_withdraw_escrow", StatusCode: 500})
+
+	report, err := k.Step(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "escrow deposit failed") {
+		t.Errorf("Step() error = %v, want deposit failure", err)
+	}
+	if report == nil || report.Transfer != nil || len(alerts) != 1 || alerts[0].Shortfall != money.MustParseSOL("11") {
+		t.Errorf("report = %+v, alerts = %+v; want an 11 SOL shortfall alert", report, alerts)
+	}
+	if _, err := k.Step(context.Background()); err == nil {
+		t.Error("second Step() succeeded with the fault still injected")
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{name: "no owner", cfg: Config{DailyCap: 1}, wantErr: "owner"},
+		{name: "no cap", cfg: Config{Owner: alice}, wantErr: "dailyCap"},
+		{name: "inverted band", cfg: Config{Owner: alice, DailyCap: 1, MinCoverBps: 12_000, TargetCoverBps: 11_000}, wantErr: "cover band"},
+		{name: "negative reserve", cfg: Config{Owner: alice, DailyCap: 1, Reserve: -1}, wantErr: "reserve"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg, APIs{}, cluster.StaticBlockhash("hash")); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+)
+
+var _ collections.CollectionsAPI = (*Collections)(nil)
+
+// Collections is a programmable fake of collections.CollectionsAPI
+type Collections struct {
+	recorder
+
+	GetVerifiedCollectionsStub Stub[*collections.GetVerifiedCollectionsRequest, []byte]
+}
+
+// GetVerifiedCollections records the call and returns the next scripted result of GetVerifiedCollectionsStub
+func (f *Collections) GetVerifiedCollections(ctx context.Context, req *collections.GetVerifiedCollectionsRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetVerifiedCollections", &f.GetVerifiedCollectionsStub, req)
+}
@@ -0,0 +1,21 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/api/escrow"
+)
+
+var _ escrow.EscrowAPI = (*Escrow)(nil)
+
+// Escrow is a programmable fake of escrow.EscrowAPI
+type Escrow struct {
+	recorder
+
+	DepositWithdrawEscrowStub Stub[*escrow.DepositWithdrawEscrowRequest, *escrow.DepositWithdrawEscrowResponse]
+}
+
+// DepositWithdrawEscrow records the call and returns the next scripted result of DepositWithdrawEscrowStub
+func (f *Escrow) DepositWithdrawEscrow(ctx context.Context, req *escrow.DepositWithdrawEscrowRequest) (*escrow.DepositWithdrawEscrowResponse, int, error) {
+	return call(ctx, &f.recorder, "DepositWithdrawEscrow", &f.DepositWithdrawEscrowStub, req)
+}
@@ -0,0 +1,75 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+)
+
+var _ marketplace.MarketplaceAPI = (*Marketplace)(nil)
+
+// Marketplace is a programmable fake of marketplace.MarketplaceAPI
+type Marketplace struct {
+	recorder
+
+	BuyNFTStub             Stub[*marketplace.BuyNFTRequest, *marketplace.BuyNFTResponse]
+	SellNFTStub            Stub[*marketplace.SellNFTRequest, *marketplace.SellNFTResponse]
+	ListNFTStub            Stub[*marketplace.ListNFTRequest, *marketplace.ListNFTResponse]
+	DelistNFTStub          Stub[*marketplace.DelistNFTRequest, *marketplace.DelistNFTResponse]
+	EditListingStub        Stub[*marketplace.EditListingRequest, *marketplace.EditListingResponse]
+	PlaceNFTBidStub        Stub[*marketplace.PlaceNFTBidRequest, *marketplace.PlaceNFTBidResponse]
+	PlaceTraitBidStub      Stub[*marketplace.PlaceTraitBidRequest, *marketplace.PlaceTraitBidResponse]
+	PlaceCollectionBidStub Stub[*marketplace.PlaceCollectionBidRequest, *marketplace.PlaceCollectionBidResponse]
+	EditBidStub            Stub[*marketplace.EditBidRequest, *marketplace.EditBidResponse]
+	CancelBidStub          Stub[*marketplace.CancelBidRequest, *marketplace.CancelBidResponse]
+}
+
+// BuyNFT records the call and returns the next scripted result of BuyNFTStub
+func (f *Marketplace) BuyNFT(ctx context.Context, req *marketplace.BuyNFTRequest) (*marketplace.BuyNFTResponse, int, error) {
+	return call(ctx, &f.recorder, "BuyNFT", &f.BuyNFTStub, req)
+}
+
+// SellNFT records the call and returns the next scripted result of SellNFTStub
+func (f *Marketplace) SellNFT(ctx context.Context, req *marketplace.SellNFTRequest) (*marketplace.SellNFTResponse, int, error) {
+	return call(ctx, &f.recorder, "SellNFT", &f.SellNFTStub, req)
+}
+
+// ListNFT records the call and returns the next scripted result of ListNFTStub
+func (f *Marketplace) ListNFT(ctx context.Context, req *marketplace.ListNFTRequest) (*marketplace.ListNFTResponse, int, error) {
+	return call(ctx, &f.recorder, "ListNFT", &f.ListNFTStub, req)
+}
+
+// DelistNFT records the call and returns the next scripted result of DelistNFTStub
+func (f *Marketplace) DelistNFT(ctx context.Context, req *marketplace.DelistNFTRequest) (*marketplace.DelistNFTResponse, int, error) {
+	return call(ctx, &f.recorder, "DelistNFT", &f.DelistNFTStub, req)
+}
+
+// EditListing records the call and returns the next scripted result of EditListingStub
+func (f *Marketplace) EditListing(ctx context.Context, req *marketplace.EditListingRequest) (*marketplace.EditListingResponse, int, error) {
+	return call(ctx, &f.recorder, "EditListing", &f.EditListingStub, req)
+}
+
+// PlaceNFTBid records the call and returns the next scripted result of PlaceNFTBidStub
+func (f *Marketplace) PlaceNFTBid(ctx context.Context, req *marketplace.PlaceNFTBidRequest) (*marketplace.PlaceNFTBidResponse, int, error) {
+	return call(ctx, &f.recorder, "PlaceNFTBid", &f.PlaceNFTBidStub, req)
+}
+
+// PlaceTraitBid records the call and returns the next scripted result of PlaceTraitBidStub
+func (f *Marketplace) PlaceTraitBid(ctx context.Context, req *marketplace.PlaceTraitBidRequest) (*marketplace.PlaceTraitBidResponse, int, error) {
+	return call(ctx, &f.recorder, "PlaceTraitBid", &f.PlaceTraitBidStub, req)
+}
+
+// PlaceCollectionBid records the call and returns the next scripted result of PlaceCollectionBidStub
+func (f *Marketplace) PlaceCollectionBid(ctx context.Context, req *marketplace.PlaceCollectionBidRequest) (*marketplace.PlaceCollectionBidResponse, int, error) {
+	return call(ctx, &f.recorder, "PlaceCollectionBid", &f.PlaceCollectionBidStub, req)
+}
+
+// EditBid records the call and returns the next scripted result of EditBidStub
+func (f *Marketplace) EditBid(ctx context.Context, req *marketplace.EditBidRequest) (*marketplace.EditBidResponse, int, error) {
+	return call(ctx, &f.recorder, "EditBid", &f.EditBidStub, req)
+}
+
+// CancelBid records the call and returns the next scripted result of CancelBidStub
+func (f *Marketplace) CancelBid(ctx context.Context, req *marketplace.CancelBidRequest) (*marketplace.CancelBidResponse, int, error) {
+	return call(ctx, &f.recorder, "CancelBid", &f.CancelBidStub, req)
+}
@@ -0,0 +1,27 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+)
+
+var _ nfts.NFTsAPI = (*NFTs)(nil)
+
+// NFTs is a programmable fake of nfts.NFTsAPI
+type NFTs struct {
+	recorder
+
+	GetNFTsInfoStub         Stub[*nfts.NFTsInfoRequest, []byte]
+	GetNFTsByCollectionStub Stub[*nfts.NFTsByCollectionRequest, []byte]
+}
+
+// GetNFTsInfo records the call and returns the next scripted result of GetNFTsInfoStub
+func (f *NFTs) GetNFTsInfo(ctx context.Context, req *nfts.NFTsInfoRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetNFTsInfo", &f.GetNFTsInfoStub, req)
+}
+
+// GetNFTsByCollection records the call and returns the next scripted result of GetNFTsByCollectionStub
+func (f *NFTs) GetNFTsByCollection(ctx context.Context, req *nfts.NFTsByCollectionRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetNFTsByCollection", &f.GetNFTsByCollectionStub, req)
+}
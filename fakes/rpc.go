@@ -0,0 +1,21 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/api/rpc"
+)
+
+var _ rpc.RPCAPI = (*RPC)(nil)
+
+// RPC is a programmable fake of rpc.RPCAPI
+type RPC struct {
+	recorder
+
+	GetPriorityFeesStub Stub[*rpc.PriorityFeesRequest, *rpc.PriorityFeesResponse]
+}
+
+// GetPriorityFees records the call and returns the next scripted result of GetPriorityFeesStub
+func (f *RPC) GetPriorityFees(ctx context.Context, req *rpc.PriorityFeesRequest) (*rpc.PriorityFeesResponse, int, error) {
+	return call(ctx, &f.recorder, "GetPriorityFees", &f.GetPriorityFeesStub, req)
+}
@@ -0,0 +1,222 @@
+// Package fakes provides programmable in-memory implementations of every SDK API
+// interface for unit tests.
+//
+// Each fake exposes one Stub per interface method. A stub records the requests it
+// receives and replays scripted results in order:
+//
+//	m := &fakes.Marketplace{}
+//	m.ListNFTStub.Returns(&marketplace.ListNFTResponse{}, 200)
+//	m.ListNFTStub.Fails(errors.New("boom"), 503)
+//	// ... exercise code that takes a marketplace.MarketplaceAPI ...
+//	m.ListNFTStub.AssertCalled(t, 2)
+//
+// The zero value of every fake is ready to use. Calls that have nothing scripted
+// fail with ErrUnscripted so that unexpected calls are loud.
+package fakes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// ErrUnscripted is returned by a stub that is called without a scripted result
+var ErrUnscripted = errors.New("fakes: no result scripted")
+
+// Result is a scripted return value of a stub
+type Result[Resp any] struct {
+	Resp   Resp
+	Status int
+	Err    error
+}
+
+// Call is a single recorded invocation on a fake
+type Call struct {
+	Method string
+	Req    any
+}
+
+// Stub is a programmable implementation of a single API method
+type Stub[Req, Resp any] struct {
+	mu       sync.Mutex
+	calls    []Req
+	queue    []Result[Resp]
+	handler  func(ctx context.Context, req Req) (Resp, int, error)
+	fallback *Result[Resp]
+}
+
+// Returns queues a successful result
+func (s *Stub[Req, Resp]) Returns(resp Resp, status int) *Stub[Req, Resp] {
+	return s.Enqueue(Result[Resp]{Resp: resp, Status: status})
+}
+
+// Fails queues a failed result
+func (s *Stub[Req, Resp]) Fails(err error, status int) *Stub[Req, Resp] {
+	return s.Enqueue(Result[Resp]{Status: status, Err: err})
+}
+
+// Enqueue queues results that are returned one per call, in order
+func (s *Stub[Req, Resp]) Enqueue(results ...Result[Resp]) *Stub[Req, Resp] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, results...)
+	return s
+}
+
+// Always sets the result returned once the queue is exhausted
+func (s *Stub[Req, Resp]) Always(resp Resp, status int, err error) *Stub[Req, Resp] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallback = &Result[Resp]{Resp: resp, Status: status, Err: err}
+	return s
+}
+
+// Handle computes results with fn once the queue is exhausted. It takes precedence over Always.
+func (s *Stub[Req, Resp]) Handle(fn func(ctx context.Context, req Req) (Resp, int, error)) *Stub[Req, Resp] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = fn
+	return s
+}
+
+// Reset clears recorded calls and scripted results
+func (s *Stub[Req, Resp]) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+	s.queue = nil
+	s.handler = nil
+	s.fallback = nil
+}
+
+// Calls returns the recorded requests in call order
+func (s *Stub[Req, Resp]) Calls() []Req {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Req(nil), s.calls...)
+}
+
+// CallCount returns the number of recorded calls
+func (s *Stub[Req, Resp]) CallCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.calls)
+}
+
+// LastCall returns the most recent request, or the zero value if the stub was never called
+func (s *Stub[Req, Resp]) LastCall() Req {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var last Req
+	if len(s.calls) > 0 {
+		last = s.calls[len(s.calls)-1]
+	}
+	return last
+}
+
+// AssertCalled fails the test unless the stub was called exactly n times
+func (s *Stub[Req, Resp]) AssertCalled(t testing.TB, n int) {
+	t.Helper()
+	if got := s.CallCount(); got != n {
+		t.Errorf("expected %d calls, got %d", n, got)
+	}
+}
+
+// AssertNotCalled fails the test if the stub was called
+func (s *Stub[Req, Resp]) AssertNotCalled(t testing.TB) {
+	t.Helper()
+	if got := s.CallCount(); got != 0 {
+		t.Errorf("expected no calls, got %d", got)
+	}
+}
+
+// AssertCalledWith fails the test unless at least one recorded request satisfies match
+func (s *Stub[Req, Resp]) AssertCalledWith(t testing.TB, match func(req Req) bool) {
+	t.Helper()
+	calls := s.Calls()
+	for _, req := range calls {
+		if match(req) {
+			return
+		}
+	}
+	t.Errorf("no matching call among %d calls", len(calls))
+}
+
+// AssertExhausted fails the test if queued results were never consumed
+func (s *Stub[Req, Resp]) AssertExhausted(t testing.TB) {
+	t.Helper()
+	s.mu.Lock()
+	remaining := len(s.queue)
+	s.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("%d scripted results were not consumed", remaining)
+	}
+}
+
+// invoke records the call and returns the next scripted result
+func (s *Stub[Req, Resp]) invoke(ctx context.Context, req Req) (Resp, int, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, req)
+	if len(s.queue) > 0 {
+		r := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+		return r.Resp, r.Status, r.Err
+	}
+	handler, fallback := s.handler, s.fallback
+	s.mu.Unlock()
+
+	if handler != nil {
+		return handler(ctx, req)
+	}
+	if fallback != nil {
+		return fallback.Resp, fallback.Status, fallback.Err
+	}
+	var zero Resp
+	return zero, 0, ErrUnscripted
+}
+
+// recorder keeps the ordered call log shared by all stubs of a fake
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns every call made on the fake, across all methods, in call order
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// AssertCallOrder fails the test unless the fake's calls, filtered to the given
+// methods, happened in exactly that order
+func (r *recorder) AssertCallOrder(t testing.TB, methods ...string) {
+	t.Helper()
+	var got []string
+	for _, c := range r.Calls() {
+		for _, m := range methods {
+			if c.Method == m {
+				got = append(got, c.Method)
+				break
+			}
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(methods) {
+		t.Errorf("expected call order %v, got %v", methods, got)
+	}
+}
+
+func (r *recorder) record(method string, req any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Req: req})
+}
+
+// call records the invocation on the fake and dispatches it to the stub
+func call[Req, Resp any](ctx context.Context, r *recorder, method string, s *Stub[Req, Resp], req Req) (Resp, int, error) {
+	r.record(method, req)
+	return s.invoke(ctx, req)
+}
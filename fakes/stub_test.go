@@ -0,0 +1,145 @@
+package fakes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+)
+
+// recordingTB captures assertion failures instead of failing the test
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestStub_ScriptedSequence(t *testing.T) {
+	f := &Marketplace{}
+	errBoom := errors.New("boom")
+	f.ListNFTStub.
+		Returns(&marketplace.ListNFTResponse{}, 200).
+		Fails(errBoom, 503)
+
+	ctx := context.Background()
+	resp, status, err := f.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: "a"})
+	if err != nil || status != 200 || resp == nil {
+		t.Errorf("first call = %v, %d, %v; want response, 200, nil", resp, status, err)
+	}
+	_, status, err = f.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: "b"})
+	if !errors.Is(err, errBoom) || status != 503 {
+		t.Errorf("second call = %d, %v; want 503, boom", status, err)
+	}
+	_, status, err = f.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: "c"})
+	if !errors.Is(err, ErrUnscripted) || status != 0 {
+		t.Errorf("third call = %d, %v; want ErrUnscripted", status, err)
+	}
+
+	f.ListNFTStub.AssertCalled(t, 3)
+	f.ListNFTStub.AssertExhausted(t)
+	f.ListNFTStub.AssertCalledWith(t, func(req *marketplace.ListNFTRequest) bool { return req.Mint == "b" })
+	if got := f.ListNFTStub.LastCall().Mint; got != "c" {
+		t.Errorf("LastCall().Mint = %q, want %q", got, "c")
+	}
+}
+
+func TestStub_FallbackAndHandler(t *testing.T) {
+	f := &User{}
+	f.GetListingsStub.Always([]byte(`{"listings":[]}`), 200, nil)
+
+	for i := 0; i < 3; i++ {
+		body, status, err := f.GetListings(context.Background(), &user.ListingsRequest{})
+		if err != nil || status != 200 || string(body) != `{"listings":[]}` {
+			t.Fatalf("call %d = %s, %d, %v", i, body, status, err)
+		}
+	}
+
+	f.GetListingsStub.Handle(func(ctx context.Context, req *user.ListingsRequest) ([]byte, int, error) {
+		return []byte(req.SortBy), 201, nil
+	})
+	body, status, _ := f.GetListings(context.Background(), &user.ListingsRequest{SortBy: "PriceAsc"})
+	if string(body) != "PriceAsc" || status != 201 {
+		t.Errorf("handler result = %s, %d; want PriceAsc, 201", body, status)
+	}
+
+	f.GetListingsStub.Reset()
+	f.GetListingsStub.AssertNotCalled(t)
+	if _, _, err := f.GetListings(context.Background(), &user.ListingsRequest{}); !errors.Is(err, ErrUnscripted) {
+		t.Errorf("call after Reset() error = %v, want ErrUnscripted", err)
+	}
+}
+
+func TestStub_AssertionFailures(t *testing.T) {
+	f := &Marketplace{}
+	f.BuyNFTStub.Returns(&marketplace.BuyNFTResponse{}, 200)
+	tb := &recordingTB{}
+
+	f.BuyNFTStub.AssertCalled(tb, 1)
+	f.BuyNFTStub.AssertExhausted(tb)
+	f.BuyNFTStub.AssertCalledWith(tb, func(*marketplace.BuyNFTRequest) bool { return true })
+	if len(tb.failures) != 3 {
+		t.Errorf("got %d assertion failures, want 3: %v", len(tb.failures), tb.failures)
+	}
+
+	_, _, _ = f.BuyNFT(context.Background(), &marketplace.BuyNFTRequest{})
+	tb.failures = nil
+	f.BuyNFTStub.AssertNotCalled(tb)
+	if len(tb.failures) != 1 {
+		t.Errorf("AssertNotCalled() failures = %v, want 1", tb.failures)
+	}
+}
+
+func TestRecorder_CallOrder(t *testing.T) {
+	f := &Marketplace{}
+	f.ListNFTStub.Always(&marketplace.ListNFTResponse{}, 200, nil)
+	f.EditListingStub.Always(&marketplace.EditListingResponse{}, 200, nil)
+	ctx := context.Background()
+
+	_, _, _ = f.ListNFT(ctx, &marketplace.ListNFTRequest{})
+	_, _, _ = f.EditListing(ctx, &marketplace.EditListingRequest{})
+	_, _, _ = f.BuyNFT(ctx, &marketplace.BuyNFTRequest{})
+
+	calls := f.Calls()
+	if len(calls) != 3 || calls[2].Method != "BuyNFT" {
+		t.Fatalf("Calls() = %+v, want 3 calls ending with BuyNFT", calls)
+	}
+	if _, ok := calls[0].Req.(*marketplace.ListNFTRequest); !ok {
+		t.Errorf("Calls()[0].Req = %T, want *marketplace.ListNFTRequest", calls[0].Req)
+	}
+	f.AssertCallOrder(t, "ListNFT", "EditListing")
+
+	tb := &recordingTB{}
+	f.AssertCallOrder(tb, "EditListing", "ListNFT")
+	if len(tb.failures) != 1 {
+		t.Errorf("AssertCallOrder() failures = %v, want 1", tb.failures)
+	}
+}
+
+func TestStub_ConcurrentCalls(t *testing.T) {
+	f := &NFTs{}
+	f.GetNFTsInfoStub.Always([]byte("[]"), 200, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _ = f.GetNFTsInfo(context.Background(), nil)
+		}()
+	}
+	wg.Wait()
+
+	f.GetNFTsInfoStub.AssertCalled(t, 50)
+	if n := len(f.Calls()); n != 50 {
+		t.Errorf("Calls() = %d, want 50", n)
+	}
+}
@@ -0,0 +1,39 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/api/tswap"
+)
+
+var _ tswap.TSwapAPI = (*TSwap)(nil)
+
+// TSwap is a programmable fake of tswap.TSwapAPI
+type TSwap struct {
+	recorder
+
+	CloseTSwapPoolStub     Stub[*tswap.CloseTSwapPoolRequest, *tswap.CloseTSwapPoolResponse]
+	EditTSwapPoolStub      Stub[*tswap.EditTSwapPoolRequest, *tswap.EditTSwapPoolResponse]
+	DepositWithdrawNFTStub Stub[*tswap.DepositWithdrawNFTRequest, *tswap.DepositWithdrawNFTResponse]
+	DepositWithdrawSOLStub Stub[*tswap.DepositWithdrawSOLRequest, *tswap.DepositWithdrawSOLResponse]
+}
+
+// CloseTSwapPool records the call and returns the next scripted result of CloseTSwapPoolStub
+func (f *TSwap) CloseTSwapPool(ctx context.Context, req *tswap.CloseTSwapPoolRequest) (*tswap.CloseTSwapPoolResponse, int, error) {
+	return call(ctx, &f.recorder, "CloseTSwapPool", &f.CloseTSwapPoolStub, req)
+}
+
+// EditTSwapPool records the call and returns the next scripted result of EditTSwapPoolStub
+func (f *TSwap) EditTSwapPool(ctx context.Context, req *tswap.EditTSwapPoolRequest) (*tswap.EditTSwapPoolResponse, int, error) {
+	return call(ctx, &f.recorder, "EditTSwapPool", &f.EditTSwapPoolStub, req)
+}
+
+// DepositWithdrawNFT records the call and returns the next scripted result of DepositWithdrawNFTStub
+func (f *TSwap) DepositWithdrawNFT(ctx context.Context, req *tswap.DepositWithdrawNFTRequest) (*tswap.DepositWithdrawNFTResponse, int, error) {
+	return call(ctx, &f.recorder, "DepositWithdrawNFT", &f.DepositWithdrawNFTStub, req)
+}
+
+// DepositWithdrawSOL records the call and returns the next scripted result of DepositWithdrawSOLStub
+func (f *TSwap) DepositWithdrawSOL(ctx context.Context, req *tswap.DepositWithdrawSOLRequest) (*tswap.DepositWithdrawSOLResponse, int, error) {
+	return call(ctx, &f.recorder, "DepositWithdrawSOL", &f.DepositWithdrawSOLStub, req)
+}
@@ -0,0 +1,75 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+)
+
+var _ user.UserAPI = (*User)(nil)
+
+// User is a programmable fake of user.UserAPI
+type User struct {
+	recorder
+
+	GetPortfolioStub              Stub[*user.PortfolioRequest, []byte]
+	GetListingsStub               Stub[*user.ListingsRequest, []byte]
+	GetNFTBidsStub                Stub[*user.NFTBidsRequest, []byte]
+	GetCollectionBidsStub         Stub[*user.CollectionBidsRequest, []byte]
+	GetTraitBidsStub              Stub[*user.TraitBidsRequest, []byte]
+	GetTSwapPoolsStub             Stub[*user.TSwapsPoolsRequest, []byte]
+	GetTAmmPoolsStub              Stub[*user.TAmmPoolsRequest, []byte]
+	GetTransactionsStub           Stub[*user.TransactionsRequest, []byte]
+	GetEscrowAccountsStub         Stub[*user.EscrowAccountsRequest, []byte]
+	GetInventoryForCollectionStub Stub[*user.InventoryForCollectionRequest, []byte]
+}
+
+// GetPortfolio records the call and returns the next scripted result of GetPortfolioStub
+func (f *User) GetPortfolio(ctx context.Context, req *user.PortfolioRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetPortfolio", &f.GetPortfolioStub, req)
+}
+
+// GetListings records the call and returns the next scripted result of GetListingsStub
+func (f *User) GetListings(ctx context.Context, req *user.ListingsRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetListings", &f.GetListingsStub, req)
+}
+
+// GetNFTBids records the call and returns the next scripted result of GetNFTBidsStub
+func (f *User) GetNFTBids(ctx context.Context, req *user.NFTBidsRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetNFTBids", &f.GetNFTBidsStub, req)
+}
+
+// GetCollectionBids records the call and returns the next scripted result of GetCollectionBidsStub
+func (f *User) GetCollectionBids(ctx context.Context, req *user.CollectionBidsRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetCollectionBids", &f.GetCollectionBidsStub, req)
+}
+
+// GetTraitBids records the call and returns the next scripted result of GetTraitBidsStub
+func (f *User) GetTraitBids(ctx context.Context, req *user.TraitBidsRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetTraitBids", &f.GetTraitBidsStub, req)
+}
+
+// GetTSwapPools records the call and returns the next scripted result of GetTSwapPoolsStub
+func (f *User) GetTSwapPools(ctx context.Context, req *user.TSwapsPoolsRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetTSwapPools", &f.GetTSwapPoolsStub, req)
+}
+
+// GetTAmmPools records the call and returns the next scripted result of GetTAmmPoolsStub
+func (f *User) GetTAmmPools(ctx context.Context, req *user.TAmmPoolsRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetTAmmPools", &f.GetTAmmPoolsStub, req)
+}
+
+// GetTransactions records the call and returns the next scripted result of GetTransactionsStub
+func (f *User) GetTransactions(ctx context.Context, req *user.TransactionsRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetTransactions", &f.GetTransactionsStub, req)
+}
+
+// GetEscrowAccounts records the call and returns the next scripted result of GetEscrowAccountsStub
+func (f *User) GetEscrowAccounts(ctx context.Context, req *user.EscrowAccountsRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetEscrowAccounts", &f.GetEscrowAccountsStub, req)
+}
+
+// GetInventoryForCollection records the call and returns the next scripted result of GetInventoryForCollectionStub
+func (f *User) GetInventoryForCollection(ctx context.Context, req *user.InventoryForCollectionRequest) ([]byte, int, error) {
+	return call(ctx, &f.recorder, "GetInventoryForCollection", &f.GetInventoryForCollectionStub, req)
+}
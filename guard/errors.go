@@ -0,0 +1,60 @@
+package guard
+
+import (
+	"fmt"
+
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// Rule names a policy limit
+type Rule string
+
+// Rules
+const (
+	RuleMaxPrice             Rule = "max_price"
+	RuleDailyOutflow         Rule = "daily_outflow"
+	RuleBlockedCollection    Rule = "blocked_collection"
+	RuleCollectionNotAllowed Rule = "collection_not_allowed"
+	RuleMaxFloorRatio        Rule = "max_floor_ratio"
+	RuleFloorUnknown         Rule = "floor_unknown" // The floor rule applies but the collection has no listings
+	RuleMaxBidQuantity       Rule = "max_bid_quantity"
+	RuleUnknownBid           Rule = "unknown_bid" // A bid edit needs checking but the bid could not be looked up
+)
+
+// Violation is the error returned for a request that breaks the policy
+type Violation struct {
+	Rule   Rule
+	Wallet string
+	CollId string
+	Mint   string
+	Bid    string // Bid account, for edits
+	Limit  int64  // The limit broken: lamports, or NFTs for RuleMaxBidQuantity; zero for collection rules
+	Value  int64  // The requested amount in the same unit
+}
+
+// violation builds a Violation for s
+func violation(rule Rule, s spend, limit, value int64) *Violation {
+	return &Violation{Rule: rule, Wallet: s.wallet, CollId: s.collID, Mint: s.mint, Bid: s.bid, Limit: limit, Value: value}
+}
+
+// Error describes the violation
+func (v *Violation) Error() string {
+	switch v.Rule {
+	case RuleBlockedCollection:
+		return fmt.Sprintf("policy violation: collection %s is blocked", v.CollId)
+	case RuleCollectionNotAllowed:
+		return fmt.Sprintf("policy violation: collection %s is not allowed", v.CollId)
+	case RuleFloorUnknown:
+		return fmt.Sprintf("policy violation: collection %s has no floor to check the price against", v.CollId)
+	case RuleUnknownBid:
+		return fmt.Sprintf("policy violation: bid %s could not be found to check the edit", v.Bid)
+	case RuleMaxBidQuantity:
+		return fmt.Sprintf("policy violation: bid quantity %d exceeds %d", v.Value, v.Limit)
+	case RuleDailyOutflow:
+		return fmt.Sprintf("policy violation: wallet %s would commit %s SOL today, limit is %s SOL",
+			v.Wallet, money.Lamports(v.Value).SOL(), money.Lamports(v.Limit).SOL())
+	default:
+		return fmt.Sprintf("policy violation: %s: price %s SOL exceeds %s SOL",
+			v.Rule, money.Lamports(v.Value).SOL(), money.Lamports(v.Limit).SOL())
+	}
+}
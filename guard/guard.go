@@ -0,0 +1,476 @@
+// Package guard enforces a spending policy on a marketplace.MarketplaceAPI.
+// Guard wraps another implementation and checks every purchase and bid
+// against the policy before the transaction is built: the price per NFT, the
+// wallet's SOL outflow for the day, the collection, the price relative to
+// the collection floor and the bid quantity. Requests that break a rule fail
+// with a *Violation and never reach the wrapped API. Listing, delisting,
+// selling and cancelling reduce exposure and pass through unchecked.
+//
+// Bid edits name only the bid account, so checking them against the budget
+// and the collection rules needs the bid's collection and current terms.
+// Pass WithUserAPI to let the Guard look bids up; without it, such edits are
+// refused.
+//
+// Daily outflow is tracked in a BudgetStore so that several processes can
+// share one budget. A Guard is safe for concurrent use; the budget is
+// reserved atomically before each request and released if it fails.
+package guard
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+var _ marketplace.MarketplaceAPI = (*Guard)(nil)
+
+// DefaultFloorMaxAge is how long a collection floor is reused when Policy.FloorMaxAge is zero
+const DefaultFloorMaxAge = 30 * time.Second
+
+// Policy lists the limits a Guard enforces. Zero values disable a limit.
+type Policy struct {
+	MaxPrice        money.Lamports // Most paid or bid for one NFT
+	MaxDailyOutflow money.Lamports // Most SOL a wallet commits per UTC day through purchases and bids
+
+	AllowedCollections []string // When set, only these collections may be bought or bid on
+	BlockedCollections []string // Collections that may never be bought or bid on
+
+	MaxFloorBps int64         // Most paid or bid per NFT, in basis points of the collection floor
+	FloorMaxAge time.Duration // How long a floor read is reused
+
+	MaxBidQuantity int32 // Most NFTs one bid may ask for
+}
+
+// validate checks the policy fields
+func (p *Policy) validate() error {
+	if p.MaxPrice < 0 || p.MaxDailyOutflow < 0 || p.MaxFloorBps < 0 || p.MaxBidQuantity < 0 || p.FloorMaxAge < 0 {
+		return fmt.Errorf("policy limits must be >= 0")
+	}
+	for _, id := range p.AllowedCollections {
+		if slices.Contains(p.BlockedCollections, id) {
+			return fmt.Errorf("collection %s is both allowed and blocked", id)
+		}
+	}
+	return nil
+}
+
+// needsCollections reports whether checking the policy requires the collection of a mint
+func (p *Policy) needsCollections() bool {
+	return len(p.AllowedCollections) > 0 || len(p.BlockedCollections) > 0 || p.MaxFloorBps > 0
+}
+
+// Guard is a marketplace.MarketplaceAPI that enforces a Policy
+type Guard struct {
+	next   marketplace.MarketplaceAPI
+	nfts   nfts.NFTsAPI
+	policy Policy
+	store  BudgetStore
+	user   user.UserAPI
+	now    func() time.Time
+
+	mu          sync.Mutex
+	collections map[string]string // Collection ID by mint
+	floors      map[string]floorEntry
+	owners      []string          // Wallets whose bids EditBid looks up
+	bids        map[string]bidRef // Where each bid EditBid resolved was found, by address
+}
+
+// bidRef is the owner and target kind to query a bid by
+type bidRef struct {
+	owner  string
+	target string
+}
+
+// Option configures a Guard
+type Option func(*Guard)
+
+// WithUserAPI lets EditBid look up the bid it edits among the bids of
+// owners. Owners of bids placed through the Guard are added as they are seen.
+func WithUserAPI(api user.UserAPI, owners ...string) Option {
+	return func(g *Guard) {
+		g.user = api
+		for _, owner := range owners {
+			g.addOwner(owner)
+		}
+	}
+}
+
+type floorEntry struct {
+	price money.Lamports
+	ok    bool
+	at    time.Time
+}
+
+// New creates a Guard around next
+//
+// Parameters:
+//   - next: The API requests are forwarded to once they pass the policy
+//   - nftsAPI: Reads mint collections and floors; required when the policy has collection or floor rules
+//   - policy: The limits to enforce
+//   - store: Records daily outflow; nil for an in-memory store
+//   - opts: Optional settings such as WithUserAPI
+//
+// Returns:
+//   - The guard
+//   - An error if the policy is invalid or a required API is nil
+func New(next marketplace.MarketplaceAPI, nftsAPI nfts.NFTsAPI, policy Policy, store BudgetStore, opts ...Option) (*Guard, error) {
+	if next == nil {
+		return nil, fmt.Errorf("marketplace API is required")
+	}
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+	if policy.needsCollections() && nftsAPI == nil {
+		return nil, fmt.Errorf("NFTs API is required for collection and floor rules")
+	}
+	if policy.FloorMaxAge == 0 {
+		policy.FloorMaxAge = DefaultFloorMaxAge
+	}
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	g := &Guard{
+		next:        next,
+		nfts:        nftsAPI,
+		policy:      policy,
+		store:       store,
+		now:         time.Now,
+		collections: make(map[string]string),
+		floors:      make(map[string]floorEntry),
+		bids:        make(map[string]bidRef),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, nil
+}
+
+// spend describes an outflow to check against the policy
+type spend struct {
+	wallet   string
+	collID   string // Empty when unknown, e.g. for bid edits the policy does not need to resolve
+	mint     string
+	bid      string         // Bid account, for edits
+	price    money.Lamports // Per NFT
+	quantity int32
+	isBid    bool
+	isEdit   bool
+	added    money.Lamports // Exposure an edit adds to the bid
+}
+
+// total is the outflow charged to the daily budget. An edit is charged only
+// the exposure it adds; lowering a bid gives nothing back.
+func (s spend) total() money.Lamports {
+	if s.isEdit {
+		return s.added
+	}
+	return s.price * money.Lamports(s.quantity)
+}
+
+// check enforces every rule on s and reserves its outflow
+//
+// Returns:
+//   - A function that releases the reservation, to call if the request fails
+//   - A *Violation if a rule is broken, or an error if the policy could not be checked
+func (g *Guard) check(ctx context.Context, s spend) (func(), error) {
+	p := &g.policy
+	if s.collID != "" {
+		if slices.Contains(p.BlockedCollections, s.collID) {
+			return nil, violation(RuleBlockedCollection, s, 0, 0)
+		}
+		if len(p.AllowedCollections) > 0 && !slices.Contains(p.AllowedCollections, s.collID) {
+			return nil, violation(RuleCollectionNotAllowed, s, 0, 0)
+		}
+	}
+	if p.MaxPrice > 0 && s.price > p.MaxPrice {
+		return nil, violation(RuleMaxPrice, s, int64(p.MaxPrice), int64(s.price))
+	}
+	if s.isBid && p.MaxBidQuantity > 0 && s.quantity > p.MaxBidQuantity {
+		return nil, violation(RuleMaxBidQuantity, s, int64(p.MaxBidQuantity), int64(s.quantity))
+	}
+	if p.MaxFloorBps > 0 && s.collID != "" {
+		floor, ok, err := g.floor(ctx, s.collID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read floor of %s: %w", s.collID, err)
+		}
+		if !ok {
+			return nil, violation(RuleFloorUnknown, s, 0, int64(s.price))
+		}
+		if limit := floor.MulDiv(p.MaxFloorBps, 10_000); s.price > limit {
+			return nil, violation(RuleMaxFloorRatio, s, int64(limit), int64(s.price))
+		}
+	}
+
+	if p.MaxDailyOutflow == 0 || s.total() == 0 {
+		return func() {}, nil
+	}
+	day := Day(g.now())
+	used, ok, err := g.store.Reserve(ctx, s.wallet, day, s.total(), p.MaxDailyOutflow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve budget: %w", err)
+	}
+	if !ok {
+		return nil, violation(RuleDailyOutflow, s, int64(p.MaxDailyOutflow), int64(used+s.total()))
+	}
+	return func() {
+		// A failed release leaves the budget over-counted, which errs on the safe side.
+		_ = g.store.Release(context.WithoutCancel(ctx), s.wallet, day, s.total())
+	}, nil
+}
+
+// collectionOf returns the collection of mint, reading it once per mint
+func (g *Guard) collectionOf(ctx context.Context, mint string) (string, error) {
+	if !g.policy.needsCollections() {
+		return "", nil
+	}
+	g.mu.Lock()
+	collID, ok := g.collections[mint]
+	g.mu.Unlock()
+	if ok {
+		return collID, nil
+	}
+
+	data, _, err := g.nfts.GetNFTsInfo(ctx, &nfts.NFTsInfoRequest{Mints: []string{mint}})
+	if err != nil {
+		return "", fmt.Errorf("failed to read mint %s: %w", mint, err)
+	}
+	items, _, err := market.DecodeNFTs(data)
+	if err != nil {
+		return "", err
+	}
+	for _, n := range items {
+		if n.Mint == mint && n.CollId != "" {
+			g.mu.Lock()
+			g.collections[mint] = n.CollId
+			g.mu.Unlock()
+			return n.CollId, nil
+		}
+	}
+	return "", fmt.Errorf("collection of mint %s is unknown", mint)
+}
+
+// floor returns the cheapest listing of collID, reusing reads younger than FloorMaxAge
+func (g *Guard) floor(ctx context.Context, collID string) (money.Lamports, bool, error) {
+	now := g.now()
+	g.mu.Lock()
+	e, ok := g.floors[collID]
+	g.mu.Unlock()
+	if ok && now.Sub(e.at) < g.policy.FloorMaxAge {
+		return e.price, e.ok, nil
+	}
+
+	n, found, err := market.Floor(ctx, g.nfts, collID)
+	if err != nil {
+		return 0, false, err
+	}
+	e = floorEntry{ok: found, at: now}
+	if found {
+		e.price = n.Listing.Price
+	}
+	g.mu.Lock()
+	g.floors[collID] = e
+	g.mu.Unlock()
+	return e.price, e.ok, nil
+}
+
+// addOwner adds owner to the wallets whose bids EditBid looks up
+func (g *Guard) addOwner(owner string) {
+	if g.user == nil || owner == "" {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !slices.Contains(g.owners, owner) {
+		g.owners = append(g.owners, owner)
+	}
+}
+
+// bid looks up the bid at address among the known owners' bids
+//
+// Returns:
+//   - The bid
+//   - Whether it was found
+//   - An error if a read failed
+func (g *Guard) bid(ctx context.Context, address string) (market.Bid, bool, error) {
+	if g.user == nil {
+		return market.Bid{}, false, nil
+	}
+	g.mu.Lock()
+	ref, known := g.bids[address]
+	owners := slices.Clone(g.owners)
+	g.mu.Unlock()
+
+	refs := []bidRef{ref}
+	if !known {
+		refs = refs[:0]
+		for _, owner := range owners {
+			for _, target := range []string{market.BidTargetCollection, market.BidTargetTrait, market.BidTargetNFT} {
+				refs = append(refs, bidRef{owner: owner, target: target})
+			}
+		}
+	}
+	for _, ref := range refs {
+		bids, err := g.readBids(ctx, ref, address)
+		if err != nil {
+			return market.Bid{}, false, fmt.Errorf("failed to read bid %s: %w", address, err)
+		}
+		for _, b := range bids {
+			if b.Address == address {
+				g.mu.Lock()
+				g.bids[address] = ref
+				g.mu.Unlock()
+				return b, true, nil
+			}
+		}
+	}
+	return market.Bid{}, false, nil
+}
+
+// readBids reads the bids of ref.owner and ref.target filtered to address
+func (g *Guard) readBids(ctx context.Context, ref bidRef, address string) ([]market.Bid, error) {
+	var data []byte
+	var err error
+	addresses := []string{address}
+	switch ref.target {
+	case market.BidTargetNFT:
+		data, _, err = g.user.GetNFTBids(ctx, &user.NFTBidsRequest{Owner: ref.owner, Limit: 1, BidAddresses: addresses})
+	case market.BidTargetCollection:
+		data, _, err = g.user.GetCollectionBids(ctx, &user.CollectionBidsRequest{Owner: ref.owner, Limit: 1, BidAddresses: addresses})
+	default:
+		data, _, err = g.user.GetTraitBids(ctx, &user.TraitBidsRequest{Owner: ref.owner, Limit: 1, BidAddresses: addresses})
+	}
+	if err != nil {
+		return nil, err
+	}
+	bids, _, err := market.DecodeBids(data)
+	return bids, err
+}
+
+// editSpend describes the bid b after req is applied
+func editSpend(b market.Bid, req *marketplace.EditBidRequest) spend {
+	s := spend{wallet: b.Owner, collID: b.CollId, bid: b.Address, price: b.Price, quantity: b.Quantity, isBid: true, isEdit: true}
+	if b.Target == market.BidTargetNFT {
+		s.mint = b.TargetId
+	}
+	if req.Price != nil {
+		s.price = *req.Price
+	}
+	if req.Quantity != nil {
+		s.quantity = *req.Quantity
+	}
+	after := s.price * money.Lamports(max(s.quantity-b.FilledQuantity, 0))
+	s.added = max(after-b.Exposure(), 0)
+	return s
+}
+
+// guarded checks s, calls fn if it passes and releases the budget if fn fails
+func guarded[Resp any](ctx context.Context, g *Guard, s spend, fn func() (Resp, int, error)) (Resp, int, error) {
+	var zero Resp
+	release, err := g.check(ctx, s)
+	if err != nil {
+		return zero, 0, err
+	}
+	resp, status, err := fn()
+	if err != nil {
+		release()
+	}
+	return resp, status, err
+}
+
+// BuyNFT checks the purchase at req.MaxPrice against the policy and forwards it
+func (g *Guard) BuyNFT(ctx context.Context, req *marketplace.BuyNFTRequest) (*marketplace.BuyNFTResponse, int, error) {
+	collID, err := g.collectionOf(ctx, req.Mint)
+	if err != nil {
+		return nil, 0, err
+	}
+	s := spend{wallet: req.Buyer, collID: collID, mint: req.Mint, price: req.MaxPrice, quantity: 1}
+	return guarded(ctx, g, s, func() (*marketplace.BuyNFTResponse, int, error) { return g.next.BuyNFT(ctx, req) })
+}
+
+// PlaceNFTBid checks the bid against the policy and forwards it
+func (g *Guard) PlaceNFTBid(ctx context.Context, req *marketplace.PlaceNFTBidRequest) (*marketplace.PlaceNFTBidResponse, int, error) {
+	g.addOwner(req.Owner)
+	collID, err := g.collectionOf(ctx, req.Mint)
+	if err != nil {
+		return nil, 0, err
+	}
+	s := spend{wallet: req.Owner, collID: collID, mint: req.Mint, price: req.Price, quantity: 1, isBid: true}
+	return guarded(ctx, g, s, func() (*marketplace.PlaceNFTBidResponse, int, error) { return g.next.PlaceNFTBid(ctx, req) })
+}
+
+// PlaceCollectionBid checks the bid against the policy, charging price times quantity to the budget, and forwards it
+func (g *Guard) PlaceCollectionBid(ctx context.Context, req *marketplace.PlaceCollectionBidRequest) (*marketplace.PlaceCollectionBidResponse, int, error) {
+	g.addOwner(req.Owner)
+	s := spend{wallet: req.Owner, collID: req.CollId, price: req.Price, quantity: max(req.Quantity, 1), isBid: true}
+	return guarded(ctx, g, s, func() (*marketplace.PlaceCollectionBidResponse, int, error) {
+		return g.next.PlaceCollectionBid(ctx, req)
+	})
+}
+
+// PlaceTraitBid checks the bid against the policy, charging price times quantity to the budget, and forwards it
+func (g *Guard) PlaceTraitBid(ctx context.Context, req *marketplace.PlaceTraitBidRequest) (*marketplace.PlaceTraitBidResponse, int, error) {
+	g.addOwner(req.Owner)
+	s := spend{wallet: req.Owner, collID: req.CollId, price: req.Price, quantity: max(req.Quantity, 1), isBid: true}
+	return guarded(ctx, g, s, func() (*marketplace.PlaceTraitBidResponse, int, error) {
+		return g.next.PlaceTraitBid(ctx, req)
+	})
+}
+
+// EditBid checks the bid as edited against the policy and forwards the
+// edit, charging any exposure it adds to the budget. When the policy has a
+// daily outflow or collection rules, the bid is looked up through the user
+// API to learn its collection and current terms; an edit of a bid that
+// cannot be found fails with a RuleUnknownBid violation.
+func (g *Guard) EditBid(ctx context.Context, req *marketplace.EditBidRequest) (*marketplace.EditBidResponse, int, error) {
+	s := spend{bid: req.BidStateAddress, isBid: true, isEdit: true}
+	if req.Price != nil {
+		s.price = *req.Price
+	}
+	if req.Quantity != nil {
+		s.quantity = *req.Quantity
+	}
+	if g.policy.MaxDailyOutflow > 0 || g.policy.needsCollections() {
+		b, ok, err := g.bid(ctx, req.BidStateAddress)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !ok {
+			return nil, 0, violation(RuleUnknownBid, s, 0, 0)
+		}
+		s = editSpend(b, req)
+	}
+	return guarded(ctx, g, s, func() (*marketplace.EditBidResponse, int, error) { return g.next.EditBid(ctx, req) })
+}
+
+// SellNFT forwards the sale unchecked
+func (g *Guard) SellNFT(ctx context.Context, req *marketplace.SellNFTRequest) (*marketplace.SellNFTResponse, int, error) {
+	return g.next.SellNFT(ctx, req)
+}
+
+// ListNFT forwards the listing unchecked
+func (g *Guard) ListNFT(ctx context.Context, req *marketplace.ListNFTRequest) (*marketplace.ListNFTResponse, int, error) {
+	return g.next.ListNFT(ctx, req)
+}
+
+// DelistNFT forwards the delisting unchecked
+func (g *Guard) DelistNFT(ctx context.Context, req *marketplace.DelistNFTRequest) (*marketplace.DelistNFTResponse, int, error) {
+	return g.next.DelistNFT(ctx, req)
+}
+
+// EditListing forwards the edit unchecked
+func (g *Guard) EditListing(ctx context.Context, req *marketplace.EditListingRequest) (*marketplace.EditListingResponse, int, error) {
+	return g.next.EditListing(ctx, req)
+}
+
+// CancelBid forwards the cancellation unchecked
+func (g *Guard) CancelBid(ctx context.Context, req *marketplace.CancelBidRequest) (*marketplace.CancelBidResponse, int, error) {
+	return g.next.CancelBid(ctx, req)
+}
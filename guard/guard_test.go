@@ -0,0 +1,413 @@
+package guard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/fakes"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+const (
+	wallet  = "wallet"
+	good    = "good-collection"
+	blocked = "blocked-collection"
+	other   = "other-collection"
+)
+
+var today = time.Date(2026, 7, 4, 23, 0, 0, 0, time.UTC)
+
+// walletBids are the bids of wallet served by the user API of newTestGuard
+var walletBids = []market.Bid{
+	{Address: "bid", Owner: wallet, Target: market.BidTargetCollection, TargetId: good, CollId: good, Price: money.MustParseSOL("1"), Quantity: 2},
+	{Address: "blocked-bid", Owner: wallet, Target: market.BidTargetTrait, TargetId: blocked, CollId: blocked, Price: money.MustParseSOL("1"), Quantity: 1},
+}
+
+// fakeNFTs serves mint collections from the mint name prefix ("good-collection/1") and a floor per collection
+func fakeNFTs(t *testing.T, floors map[string]money.Lamports) *fakes.NFTs {
+	t.Helper()
+	n := &fakes.NFTs{}
+	n.GetNFTsInfoStub.Handle(func(_ context.Context, req *nfts.NFTsInfoRequest) ([]byte, int, error) {
+		var out []market.NFT
+		for _, mint := range req.Mints {
+			collID, _, _ := strings.Cut(mint, "/")
+			out = append(out, market.NFT{Mint: mint, CollId: collID})
+		}
+		data, err := json.Marshal(out)
+		return data, 200, err
+	})
+	n.GetNFTsByCollectionStub.Handle(func(_ context.Context, req *nfts.NFTsByCollectionRequest) ([]byte, int, error) {
+		out := []market.NFT{}
+		if floor, ok := floors[req.CollId]; ok {
+			out = append(out, market.NFT{Mint: req.CollId + "/floor", Owner: "seller", Listing: &market.Listing{Price: floor, Seller: "seller"}})
+		}
+		data, err := json.Marshal(map[string]any{"mints": out})
+		return data, 200, err
+	})
+	return n
+}
+
+// fakeUser serves bids from the user bids endpoints, filtered by owner, target and address like the API
+func fakeUser(t *testing.T, bids []market.Bid) *fakes.User {
+	t.Helper()
+	serve := func(target, owner string, addresses []string) ([]byte, int, error) {
+		out := []market.Bid{}
+		for _, b := range bids {
+			if b.Target == target && b.Owner == owner && slices.Contains(addresses, b.Address) {
+				out = append(out, b)
+			}
+		}
+		data, err := json.Marshal(map[string]any{"bids": out})
+		return data, 200, err
+	}
+	u := &fakes.User{}
+	u.GetNFTBidsStub.Handle(func(_ context.Context, req *user.NFTBidsRequest) ([]byte, int, error) {
+		return serve(market.BidTargetNFT, req.Owner, req.BidAddresses)
+	})
+	u.GetCollectionBidsStub.Handle(func(_ context.Context, req *user.CollectionBidsRequest) ([]byte, int, error) {
+		return serve(market.BidTargetCollection, req.Owner, req.BidAddresses)
+	})
+	u.GetTraitBidsStub.Handle(func(_ context.Context, req *user.TraitBidsRequest) ([]byte, int, error) {
+		return serve(market.BidTargetTrait, req.Owner, req.BidAddresses)
+	})
+	return u
+}
+
+func okMarketplace() *fakes.Marketplace {
+	m := &fakes.Marketplace{}
+	m.BuyNFTStub.Always(&marketplace.BuyNFTResponse{}, 200, nil)
+	m.PlaceNFTBidStub.Always(&marketplace.PlaceNFTBidResponse{}, 200, nil)
+	m.PlaceCollectionBidStub.Always(&marketplace.PlaceCollectionBidResponse{}, 200, nil)
+	m.PlaceTraitBidStub.Always(&marketplace.PlaceTraitBidResponse{}, 200, nil)
+	m.EditBidStub.Always(&marketplace.EditBidResponse{}, 200, nil)
+	return m
+}
+
+func newTestGuard(t *testing.T, policy Policy, next marketplace.MarketplaceAPI, floors map[string]money.Lamports) (*Guard, *fakes.NFTs) {
+	t.Helper()
+	n := fakeNFTs(t, floors)
+	g, err := New(next, n, policy, nil, WithUserAPI(fakeUser(t, walletBids), wallet))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	g.now = func() time.Time { return today }
+	return g, n
+}
+
+func editBid(g *Guard, address, price string, quantity int32) error {
+	req := &marketplace.EditBidRequest{BidStateAddress: address}
+	if price != "" {
+		p := money.MustParseSOL(price)
+		req.Price = &p
+	}
+	if quantity > 0 {
+		req.Quantity = &quantity
+	}
+	_, _, err := g.EditBid(context.Background(), req)
+	return err
+}
+
+func buy(g *Guard, mint, price string) error {
+	_, _, err := g.BuyNFT(context.Background(), &marketplace.BuyNFTRequest{Buyer: wallet, Mint: mint, MaxPrice: money.MustParseSOL(price)})
+	return err
+}
+
+func collectionBid(g *Guard, collID, price string, quantity int32) error {
+	_, _, err := g.PlaceCollectionBid(context.Background(), &marketplace.PlaceCollectionBidRequest{Owner: wallet, CollId: collID, Price: money.MustParseSOL(price), Quantity: quantity})
+	return err
+}
+
+func TestPolicyRules(t *testing.T) {
+	policy := Policy{
+		MaxPrice:           money.MustParseSOL("5"),
+		MaxDailyOutflow:    money.MustParseSOL("15"),
+		AllowedCollections: []string{good, "thin-collection"},
+		BlockedCollections: []string{blocked},
+		MaxFloorBps:        12_000,
+		MaxBidQuantity:     5,
+	}
+	floors := map[string]money.Lamports{good: money.MustParseSOL("3"), blocked: money.MustParseSOL("1"), other: money.MustParseSOL("1")}
+
+	tests := []struct {
+		name      string
+		call      func(g *Guard) error
+		wantRule  Rule
+		wantLimit int64
+	}{
+		{name: "buy within policy", call: func(g *Guard) error { return buy(g, good+"/1", "3.5") }},
+		{name: "bid within policy", call: func(g *Guard) error { return collectionBid(g, good, "2", 5) }},
+		{name: "over max price", call: func(g *Guard) error { return buy(g, good+"/1", "5.5") }, wantRule: RuleMaxPrice, wantLimit: int64(money.MustParseSOL("5"))},
+		{name: "over floor ratio", call: func(g *Guard) error { return buy(g, good+"/1", "3.7") }, wantRule: RuleMaxFloorRatio, wantLimit: int64(money.MustParseSOL("3.6"))},
+		{name: "blocked collection", call: func(g *Guard) error { return buy(g, blocked+"/1", "1") }, wantRule: RuleBlockedCollection},
+		{name: "not allowed", call: func(g *Guard) error { return collectionBid(g, other, "1", 1) }, wantRule: RuleCollectionNotAllowed},
+		{name: "no floor", call: func(g *Guard) error { return collectionBid(g, "thin-collection", "1", 1) }, wantRule: RuleFloorUnknown},
+		{name: "bid quantity", call: func(g *Guard) error { return collectionBid(g, good, "1", 6) }, wantRule: RuleMaxBidQuantity, wantLimit: 5},
+		{name: "daily outflow", call: func(g *Guard) error { return collectionBid(g, good, "3.5", 5) }, wantRule: RuleDailyOutflow, wantLimit: int64(money.MustParseSOL("15"))},
+		{
+			name: "trait bid quantity",
+			call: func(g *Guard) error {
+				_, _, err := g.PlaceTraitBid(context.Background(), &marketplace.PlaceTraitBidRequest{Owner: wallet, CollId: good, Price: money.MustParseSOL("1"), Quantity: 9})
+				return err
+			},
+			wantRule: RuleMaxBidQuantity, wantLimit: 5,
+		},
+		{
+			name: "nft bid floor ratio",
+			call: func(g *Guard) error {
+				_, _, err := g.PlaceNFTBid(context.Background(), &marketplace.PlaceNFTBidRequest{Owner: wallet, Mint: good + "/2", Price: money.MustParseSOL("4")})
+				return err
+			},
+			wantRule: RuleMaxFloorRatio, wantLimit: int64(money.MustParseSOL("3.6")),
+		},
+		{name: "edit bid within policy", call: func(g *Guard) error { return editBid(g, "bid", "2", 3) }},
+		{name: "edit bid price", call: func(g *Guard) error { return editBid(g, "bid", "6", 0) }, wantRule: RuleMaxPrice, wantLimit: int64(money.MustParseSOL("5"))},
+		{name: "edit bid quantity", call: func(g *Guard) error { return editBid(g, "bid", "", 6) }, wantRule: RuleMaxBidQuantity, wantLimit: 5},
+		{name: "edit bid over floor ratio", call: func(g *Guard) error { return editBid(g, "bid", "4", 0) }, wantRule: RuleMaxFloorRatio, wantLimit: int64(money.MustParseSOL("3.6"))},
+		{name: "edit bid over daily outflow", call: func(g *Guard) error { return editBid(g, "bid", "3.5", 5) }, wantRule: RuleDailyOutflow, wantLimit: int64(money.MustParseSOL("15"))},
+		{name: "edit blocked bid", call: func(g *Guard) error { return editBid(g, "blocked-bid", "", 1) }, wantRule: RuleBlockedCollection},
+		{name: "edit unknown bid", call: func(g *Guard) error { return editBid(g, "gone", "1", 0) }, wantRule: RuleUnknownBid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := okMarketplace()
+			g, _ := newTestGuard(t, policy, next, floors)
+			err := tt.call(g)
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Fatalf("call error = %v", err)
+				}
+				if len(next.Calls()) != 1 {
+					t.Errorf("wrapped API called %d times, want 1", len(next.Calls()))
+				}
+				return
+			}
+			var v *Violation
+			if !errors.As(err, &v) || v.Rule != tt.wantRule || v.Limit != tt.wantLimit {
+				t.Fatalf("call error = %v, want %s violation with limit %d", err, tt.wantRule, tt.wantLimit)
+			}
+			if !strings.HasPrefix(err.Error(), "policy violation") {
+				t.Errorf("Error() = %q", err.Error())
+			}
+			if len(next.Calls()) != 0 {
+				t.Errorf("wrapped API called after a violation: %+v", next.Calls())
+			}
+		})
+	}
+}
+
+func TestDailyBudget(t *testing.T) {
+	next := okMarketplace()
+	store := NewMemoryStore()
+	g, err := New(next, nil, Policy{MaxDailyOutflow: money.MustParseSOL("10")}, store)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	clock := today
+	g.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	if err := collectionBid(g, good, "2", 4); err != nil {
+		t.Fatalf("8 SOL bid error = %v", err)
+	}
+	if used, _ := store.Used(ctx, wallet, "2026-07-04"); used != money.MustParseSOL("8") {
+		t.Errorf("used = %v, want 8 SOL", used)
+	}
+
+	// A request the wrapped API rejects gives its reservation back.
+	next.BuyNFTStub.Reset()
+	next.BuyNFTStub.Fails(errors.New("sold"), 400)
+	if err := buy(g, good+"/1", "2"); err == nil || strings.Contains(err.Error(), "policy") {
+		t.Fatalf("failed buy error = %v, want the API error", err)
+	}
+	if used, _ := store.Used(ctx, wallet, "2026-07-04"); used != money.MustParseSOL("8") {
+		t.Errorf("used after failure = %v, want 8 SOL", used)
+	}
+
+	next.BuyNFTStub.Always(&marketplace.BuyNFTResponse{}, 200, nil)
+	var v *Violation
+	if err := buy(g, good+"/1", "2.5"); !errors.As(err, &v) || v.Value != int64(money.MustParseSOL("10.5")) {
+		t.Errorf("buy over budget error = %v, want 10.5 SOL daily outflow violation", err)
+	}
+	if err := buy(g, good+"/1", "2"); err != nil {
+		t.Errorf("buy up to the budget error = %v", err)
+	}
+
+	clock = clock.Add(2 * time.Hour) // The next UTC day
+	if err := buy(g, good+"/1", "2.5"); err != nil {
+		t.Errorf("buy on the next day error = %v", err)
+	}
+	if used, _ := store.Used(ctx, "other-wallet", "2026-07-05"); used != 0 {
+		t.Errorf("other wallet used = %v, want 0", used)
+	}
+}
+
+func TestEditBidBudget(t *testing.T) {
+	next := okMarketplace()
+	store := NewMemoryStore()
+	bids := []market.Bid{{Address: "bid", Owner: wallet, Target: market.BidTargetCollection, CollId: good, Price: money.MustParseSOL("1"), Quantity: 2, FilledQuantity: 1}}
+	u := fakeUser(t, bids)
+	// No owners are passed: the wallet is learned from the bid it places.
+	g, err := New(next, nil, Policy{MaxDailyOutflow: money.MustParseSOL("10")}, store, WithUserAPI(u))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	g.now = func() time.Time { return today }
+	ctx := context.Background()
+
+	if err := collectionBid(g, good, "1", 2); err != nil {
+		t.Fatalf("bid error = %v", err)
+	}
+	// 3 SOL for each of the 3 unfilled NFTs replaces 1 SOL of exposure.
+	if err := editBid(g, "bid", "3", 4); err != nil {
+		t.Fatalf("raising edit error = %v", err)
+	}
+	if used, _ := store.Used(ctx, wallet, "2026-07-04"); used != money.MustParseSOL("10") {
+		t.Errorf("used = %v, want 10 SOL", used)
+	}
+	lookups := len(u.Calls())
+	if err := editBid(g, "bid", "0.5", 0); err != nil {
+		t.Fatalf("lowering edit error = %v", err)
+	}
+	if used, _ := store.Used(ctx, wallet, "2026-07-04"); used != money.MustParseSOL("10") {
+		t.Errorf("used after lowering = %v, want 10 SOL", used)
+	}
+	if got := len(u.Calls()) - lookups; got != 1 {
+		t.Errorf("known bid looked up with %d calls, want 1", got)
+	}
+	var v *Violation
+	if err := editBid(g, "bid", "1.5", 0); !errors.As(err, &v) || v.Rule != RuleDailyOutflow || v.Bid != "bid" {
+		t.Errorf("edit over budget error = %v, want daily outflow violation", err)
+	}
+	if got := next.EditBidStub.CallCount(); got != 2 {
+		t.Errorf("wrapped EditBid called %d times, want 2", got)
+	}
+
+	// Without a user API the bid cannot be resolved, so a budgeted edit is refused.
+	g, err = New(next, nil, Policy{MaxDailyOutflow: money.MustParseSOL("10")}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := editBid(g, "bid", "1", 0); !errors.As(err, &v) || v.Rule != RuleUnknownBid {
+		t.Errorf("edit without user API error = %v, want unknown bid violation", err)
+	}
+}
+
+func TestConcurrentBudget(t *testing.T) {
+	next := okMarketplace()
+	g, err := New(next, nil, Policy{MaxDailyOutflow: money.MustParseSOL("10")}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var ok, violations atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var v *Violation
+			switch err := buy(g, good+"/1", "1"); {
+			case err == nil:
+				ok.Add(1)
+			case errors.As(err, &v):
+				violations.Add(1)
+			default:
+				t.Errorf("buy error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if ok.Load() != 10 || violations.Load() != 40 {
+		t.Errorf("%d buys passed and %d were refused, want 10 and 40", ok.Load(), violations.Load())
+	}
+}
+
+func TestLookupsAreCached(t *testing.T) {
+	g, n := newTestGuard(t, Policy{MaxFloorBps: 20_000, FloorMaxAge: time.Minute}, okMarketplace(), map[string]money.Lamports{good: money.MustParseSOL("1")})
+	clock := today
+	g.now = func() time.Time { return clock }
+
+	for i := 0; i < 3; i++ {
+		if err := buy(g, good+"/1", "1.5"); err != nil {
+			t.Fatalf("buy error = %v", err)
+		}
+	}
+	if got := n.GetNFTsInfoStub.CallCount(); got != 1 {
+		t.Errorf("mint looked up %d times, want 1", got)
+	}
+	if got := n.GetNFTsByCollectionStub.CallCount(); got != 1 {
+		t.Errorf("floor read %d times, want 1", got)
+	}
+	clock = clock.Add(time.Minute)
+	if err := buy(g, good+"/1", "1.5"); err != nil {
+		t.Fatalf("buy error = %v", err)
+	}
+	if got := n.GetNFTsByCollectionStub.CallCount(); got != 2 {
+		t.Errorf("floor read %d times after it expired, want 2", got)
+	}
+}
+
+func TestPassThrough(t *testing.T) {
+	next := &fakes.Marketplace{}
+	next.ListNFTStub.Returns(&marketplace.ListNFTResponse{}, 200)
+	next.DelistNFTStub.Returns(&marketplace.DelistNFTResponse{}, 200)
+	next.SellNFTStub.Returns(&marketplace.SellNFTResponse{}, 200)
+	next.EditListingStub.Returns(&marketplace.EditListingResponse{}, 200)
+	next.CancelBidStub.Returns(&marketplace.CancelBidResponse{}, 200)
+	// Blocking everything shows the unchecked methods ignore the policy.
+	g, n := newTestGuard(t, Policy{MaxPrice: 1, AllowedCollections: []string{good}, BlockedCollections: []string{blocked}}, next, nil)
+	ctx := context.Background()
+
+	mint := blocked + "/1"
+	if _, _, err := g.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: mint, Price: money.MustParseSOL("100")}); err != nil {
+		t.Errorf("ListNFT() error = %v", err)
+	}
+	if _, _, err := g.DelistNFT(ctx, &marketplace.DelistNFTRequest{Mint: mint}); err != nil {
+		t.Errorf("DelistNFT() error = %v", err)
+	}
+	if _, _, err := g.SellNFT(ctx, &marketplace.SellNFTRequest{Mint: mint}); err != nil {
+		t.Errorf("SellNFT() error = %v", err)
+	}
+	if _, _, err := g.EditListing(ctx, &marketplace.EditListingRequest{Mint: mint, Price: money.MustParseSOL("100")}); err != nil {
+		t.Errorf("EditListing() error = %v", err)
+	}
+	if _, _, err := g.CancelBid(ctx, &marketplace.CancelBidRequest{BidStateAddress: "bid"}); err != nil {
+		t.Errorf("CancelBid() error = %v", err)
+	}
+	if len(n.Calls()) != 0 {
+		t.Errorf("unchecked methods read NFTs: %+v", n.Calls())
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	next := &fakes.Marketplace{}
+	tests := []struct {
+		name    string
+		next    marketplace.MarketplaceAPI
+		nfts    nfts.NFTsAPI
+		policy  Policy
+		wantErr string
+	}{
+		{name: "no marketplace", policy: Policy{}, wantErr: "marketplace API"},
+		{name: "negative limit", next: next, policy: Policy{MaxPrice: -1}, wantErr: ">= 0"},
+		{name: "allowed and blocked", next: next, nfts: &fakes.NFTs{}, policy: Policy{AllowedCollections: []string{good}, BlockedCollections: []string{good}}, wantErr: "both allowed and blocked"},
+		{name: "floor rule without NFTs", next: next, policy: Policy{MaxFloorBps: 10_000}, wantErr: "NFTs API"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.next, tt.nfts, tt.policy, nil); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package guard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// BudgetStore records each wallet's outflow per day. Implementations must be
+// safe for concurrent use; a shared store lets several guards and processes
+// draw on one budget.
+type BudgetStore interface {
+	// Reserve adds amount to the wallet's outflow for day if the total stays within limit.
+	// It returns the outflow before the call and whether the amount was reserved.
+	Reserve(ctx context.Context, wallet, day string, amount, limit money.Lamports) (used money.Lamports, ok bool, err error)
+
+	// Release takes back amount reserved for a request that failed
+	Release(ctx context.Context, wallet, day string, amount money.Lamports) error
+
+	// Used returns the wallet's outflow for day
+	Used(ctx context.Context, wallet, day string) (money.Lamports, error)
+}
+
+// Day returns the budget day of t: its UTC date as YYYY-MM-DD
+func Day(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// MemoryStore is a BudgetStore held in memory
+type MemoryStore struct {
+	mu   sync.Mutex
+	used map[budgetKey]money.Lamports
+}
+
+type budgetKey struct {
+	wallet, day string
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{used: make(map[budgetKey]money.Lamports)}
+}
+
+// Reserve adds amount to the wallet's outflow for day if the total stays within limit
+func (s *MemoryStore) Reserve(_ context.Context, wallet, day string, amount, limit money.Lamports) (money.Lamports, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := budgetKey{wallet, day}
+	used := s.used[key]
+	if used+amount > limit {
+		return used, false, nil
+	}
+	s.used[key] = used + amount
+	return used, true, nil
+}
+
+// Release takes back amount, never going below zero
+func (s *MemoryStore) Release(_ context.Context, wallet, day string, amount money.Lamports) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := budgetKey{wallet, day}
+	s.used[key] = max(s.used[key]-amount, 0)
+	return nil
+}
+
+// Used returns the wallet's outflow for day
+func (s *MemoryStore) Used(_ context.Context, wallet, day string) (money.Lamports, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.used[budgetKey{wallet, day}], nil
+}
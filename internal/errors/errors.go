@@ -3,6 +3,7 @@ package errors
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 )
 
@@ -11,6 +12,8 @@ type APIError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
+	// Header holds the response headers when the error was parsed from an HTTP response
+	Header http.Header `json:"-"`
 }
 
 // Error implements the error interface
@@ -63,8 +66,16 @@ func ParseAPIError(resp *http.Response) error {
 		return nil
 	}
 
+	body, _ := io.ReadAll(resp.Body)
+	apiErr := NewAPIError(resp.StatusCode, body)
+	apiErr.Header = resp.Header
+	return apiErr
+}
+
+// NewAPIError builds an APIError from a status code and a raw response body
+func NewAPIError(statusCode int, body []byte) *APIError {
 	apiErr := &APIError{
-		Code: resp.StatusCode,
+		Code: statusCode,
 	}
 
 	// Try to parse JSON error response
@@ -74,7 +85,7 @@ func ParseAPIError(resp *http.Response) error {
 		Error   string `json:"error,omitempty"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err == nil {
+	if err := json.Unmarshal(body, &errorResponse); err == nil {
 		if errorResponse.Message != "" {
 			apiErr.Message = errorResponse.Message
 		} else if errorResponse.Error != "" {
@@ -85,7 +96,7 @@ func ParseAPIError(resp *http.Response) error {
 
 	// Set default messages if not provided
 	if apiErr.Message == "" {
-		switch resp.StatusCode {
+		switch statusCode {
 		case 400:
 			apiErr.Message = "bad request"
 		case 401:
@@ -97,9 +108,9 @@ func ParseAPIError(resp *http.Response) error {
 		case 500:
 			apiErr.Message = "internal server error"
 		default:
-			apiErr.Message = http.StatusText(resp.StatusCode)
+			apiErr.Message = http.StatusText(statusCode)
 		}
 	}
 
 	return apiErr
-}
\ No newline at end of file
+}
@@ -0,0 +1,47 @@
+package request
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/transport"
+)
+
+// Stage identifies the step of the request path where a request failed
+type Stage string
+
+// Request path stages
+const (
+	StageValidate  Stage = "validate"  // The request failed validation
+	StageEncode    Stage = "encode"    // Query parameters could not be built
+	StageTransport Stage = "transport" // The transport returned an error
+	StageRead      Stage = "read"      // The response body could not be read
+	StageStatus    Stage = "status"    // The API answered with an error status
+	StageDecode    Stage = "decode"    // The response body could not be decoded
+)
+
+// Observer receives lifecycle events from the shared request path.
+// Implementations must be safe for concurrent use.
+type Observer interface {
+	// Start is called once per request, before the transport is invoked. params
+	// is nil when the request failed validation or encoding. The returned context
+	// is passed to the transport and to End.
+	Start(ctx context.Context, endpoint string, params url.Values) context.Context
+	// End is called once per request with the final metadata and error
+	End(ctx context.Context, meta *Metadata, err error)
+}
+
+// Observable is implemented by transports that carry an Observer.
+// Do notifies the observer of the outermost transport it is given.
+type Observable interface {
+	transport.Transport
+	Observer() Observer
+}
+
+// observerFor returns the observer attached to t, if any
+func observerFor(t transport.Transport) Observer {
+	if o, ok := t.(Observable); ok {
+		return o.Observer()
+	}
+	return nil
+}
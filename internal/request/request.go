@@ -0,0 +1,128 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/internal/transport"
+	"github.com/srpvpn/tensor-go-sdk/internal/utils"
+)
+
+// Validator defines the interface for request validation
+type Validator interface {
+	Validate() error
+}
+
+// Metadata describes the HTTP exchange behind a response
+type Metadata struct {
+	Endpoint   string        // The API path that was called
+	StatusCode int           // The HTTP status code, 0 if no response was received
+	Header     http.Header   // The response headers, nil if no response was received
+	Duration   time.Duration // Time spent from the transport call until the body was read
+	FailedAt   Stage         // The stage that failed, empty on success
+	Attempts   int           // Upstream attempts made, 0 if served without reaching the network
+}
+
+// Do is the single request path shared by every API package. It handles:
+// 1. Request validation
+// 2. Query parameter building
+// 3. HTTP request execution
+// 4. Error mapping
+// 5. Typed response decoding
+//
+// When Resp is []byte the raw body is returned without decoding.
+// The returned Metadata is never nil, so callers can always read the status code.
+func Do[Req Validator, Resp any](ctx context.Context, t transport.Transport, endpoint string, req Req) (out *Resp, meta *Metadata, err error) {
+	meta = &Metadata{Endpoint: endpoint}
+
+	// Validate the request and build query parameters from it
+	var params url.Values
+	if err = req.Validate(); err != nil {
+		meta.FailedAt = StageValidate
+		err = fmt.Errorf("request validation failed: %w", err)
+	} else if params, err = utils.BuildQueryParams(req); err != nil {
+		meta.FailedAt = StageEncode
+		err = fmt.Errorf("failed to build query parameters: %w", err)
+	}
+
+	// Notify the observer, if any, of the request lifecycle
+	if obs := observerFor(t); obs != nil {
+		ctx = obs.Start(ctx, endpoint, params)
+		defer func() { obs.End(ctx, meta, err) }()
+	}
+	if err != nil {
+		return nil, meta, err
+	}
+
+	// Make the HTTP request, counting the attempts transports make for it
+	ctx, attempts := transport.WithAttemptCounter(ctx)
+	start := time.Now()
+	resp, err := t.Get(ctx, endpoint, params)
+	meta.Attempts = int(attempts.Load())
+	if err != nil {
+		meta.Duration = time.Since(start)
+		meta.FailedAt = StageTransport
+		var apiErr *errors.APIError
+		if stderrors.As(err, &apiErr) {
+			meta.StatusCode = apiErr.Code
+			meta.Header = apiErr.Header
+			meta.FailedAt = StageStatus
+		}
+		return nil, meta, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	meta.StatusCode = resp.StatusCode
+	meta.Header = resp.Header
+
+	// Read the response body
+	body, err := io.ReadAll(resp.Body)
+	meta.Duration = time.Since(start)
+	if err != nil {
+		meta.FailedAt = StageRead
+		return nil, meta, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Map HTTP errors that the transport passed through
+	if resp.StatusCode >= 400 {
+		meta.FailedAt = StageStatus
+		return nil, meta, fmt.Errorf("HTTP request failed: %w", errors.NewAPIError(resp.StatusCode, body))
+	}
+
+	// Decode the response into the requested type
+	out = new(Resp)
+	if raw, ok := any(out).(*[]byte); ok {
+		*raw = body
+		return out, meta, nil
+	}
+	if err = json.Unmarshal(body, out); err != nil {
+		meta.FailedAt = StageDecode
+		return nil, meta, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return out, meta, nil
+}
+
+// Raw executes the request and returns the undecoded body along with the status code.
+// It is a convenience wrapper around Do for endpoints that expose raw JSON.
+func Raw[Req Validator](ctx context.Context, t transport.Transport, endpoint string, req Req) ([]byte, int, error) {
+	body, meta, err := Do[Req, []byte](ctx, t, endpoint, req)
+	if err != nil {
+		return nil, meta.StatusCode, err
+	}
+	return *body, meta.StatusCode, nil
+}
+
+// Typed executes the request and decodes the body into Resp, returning the status code.
+// It is a convenience wrapper around Do for endpoints with structured responses.
+func Typed[Req Validator, Resp any](ctx context.Context, t transport.Transport, endpoint string, req Req) (*Resp, int, error) {
+	out, meta, err := Do[Req, Resp](ctx, t, endpoint, req)
+	return out, meta.StatusCode, err
+}
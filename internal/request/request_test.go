@@ -0,0 +1,225 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/errors"
+)
+
+// mockTransport implements the transport.Transport interface for testing
+type mockTransport struct {
+	response   *http.Response
+	err        error
+	calls      int
+	lastPath   string
+	lastParams url.Values
+}
+
+func (m *mockTransport) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	m.calls++
+	m.lastPath = path
+	m.lastParams = params
+	return m.response, m.err
+}
+
+func newResponse(statusCode int, body string) *http.Response {
+	header := make(http.Header)
+	header.Set("X-Request-Id", "req-1")
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+		Header:     header,
+	}
+}
+
+type testRequest struct {
+	Wallet string `json:"wallet"`
+	Limit  int32  `json:"limit,omitempty"`
+}
+
+func (r *testRequest) Validate() error {
+	if r.Wallet == "" {
+		return fmt.Errorf("wallet is required")
+	}
+	return nil
+}
+
+type testResponse struct {
+	Status string `json:"status"`
+}
+
+func TestDo_DecodesTypedResponse(t *testing.T) {
+	transport := &mockTransport{response: newResponse(200, `{"status":"Ok"}`)}
+
+	out, meta, err := Do[*testRequest, testResponse](context.Background(), transport, "/api/v1/test", &testRequest{Wallet: "w", Limit: 5})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if out.Status != "Ok" {
+		t.Errorf("Do() status = %q, want %q", out.Status, "Ok")
+	}
+	if meta.StatusCode != 200 {
+		t.Errorf("Do() meta.StatusCode = %d, want 200", meta.StatusCode)
+	}
+	if meta.Endpoint != "/api/v1/test" {
+		t.Errorf("Do() meta.Endpoint = %q, want %q", meta.Endpoint, "/api/v1/test")
+	}
+	if meta.Header.Get("X-Request-Id") != "req-1" {
+		t.Errorf("Do() meta.Header missing request id")
+	}
+	if transport.lastParams.Get("wallet") != "w" || transport.lastParams.Get("limit") != "5" {
+		t.Errorf("Do() sent params %v", transport.lastParams)
+	}
+}
+
+func TestDo_RawBody(t *testing.T) {
+	transport := &mockTransport{response: newResponse(200, "invalid json")}
+
+	body, status, err := Raw(context.Background(), transport, "/api/v1/test", &testRequest{Wallet: "w"})
+	if err != nil {
+		t.Fatalf("Raw() error = %v", err)
+	}
+	if status != 200 {
+		t.Errorf("Raw() status = %d, want 200", status)
+	}
+	if string(body) != "invalid json" {
+		t.Errorf("Raw() body = %q, want %q", string(body), "invalid json")
+	}
+}
+
+func TestDo_ValidationError(t *testing.T) {
+	transport := &mockTransport{}
+
+	_, meta, err := Do[*testRequest, testResponse](context.Background(), transport, "/api/v1/test", &testRequest{})
+	if err == nil {
+		t.Fatal("Do() expected validation error")
+	}
+	if !strings.Contains(err.Error(), "request validation failed") {
+		t.Errorf("Do() error = %v, want validation failure", err)
+	}
+	if transport.calls != 0 {
+		t.Errorf("Do() made %d transport calls, want 0", transport.calls)
+	}
+	if meta == nil || meta.StatusCode != 0 {
+		t.Errorf("Do() meta = %+v, want zero status", meta)
+	}
+}
+
+func TestDo_TransportAPIError(t *testing.T) {
+	transport := &mockTransport{err: &errors.APIError{Code: 429, Message: "rate limit exceeded"}}
+
+	_, status, err := Typed[*testRequest, testResponse](context.Background(), transport, "/api/v1/test", &testRequest{Wallet: "w"})
+	if err == nil {
+		t.Fatal("Typed() expected error")
+	}
+	var apiErr *errors.APIError
+	if !stderrors.As(err, &apiErr) {
+		t.Fatalf("Typed() error = %T, want *errors.APIError", err)
+	}
+	if status != 429 {
+		t.Errorf("Typed() status = %d, want 429", status)
+	}
+}
+
+func TestDo_PassThroughHTTPError(t *testing.T) {
+	transport := &mockTransport{response: newResponse(503, `{"message":"maintenance"}`)}
+
+	_, status, err := Raw(context.Background(), transport, "/api/v1/test", &testRequest{Wallet: "w"})
+	if err == nil {
+		t.Fatal("Raw() expected error")
+	}
+	var apiErr *errors.APIError
+	if !stderrors.As(err, &apiErr) {
+		t.Fatalf("Raw() error = %T, want *errors.APIError", err)
+	}
+	if apiErr.Message != "maintenance" {
+		t.Errorf("Raw() message = %q, want %q", apiErr.Message, "maintenance")
+	}
+	if status != 503 {
+		t.Errorf("Raw() status = %d, want 503", status)
+	}
+}
+
+func TestDo_DecodeError(t *testing.T) {
+	transport := &mockTransport{response: newResponse(200, "not json")}
+
+	_, status, err := Typed[*testRequest, testResponse](context.Background(), transport, "/api/v1/test", &testRequest{Wallet: "w"})
+	if err == nil {
+		t.Fatal("Typed() expected decode error")
+	}
+	if !strings.Contains(err.Error(), "failed to decode response") {
+		t.Errorf("Typed() error = %v, want decode failure", err)
+	}
+	if status != 200 {
+		t.Errorf("Typed() status = %d, want 200", status)
+	}
+}
+
+// observedTransport is a mockTransport that carries a recordingObserver
+type observedTransport struct {
+	*mockTransport
+	observer *recordingObserver
+}
+
+func (o *observedTransport) Observer() Observer { return o.observer }
+
+type recordingObserver struct {
+	starts []url.Values
+	ends   []Metadata
+	errs   []error
+}
+
+func (r *recordingObserver) Start(ctx context.Context, endpoint string, params url.Values) context.Context {
+	r.starts = append(r.starts, params)
+	return ctx
+}
+
+func (r *recordingObserver) End(ctx context.Context, meta *Metadata, err error) {
+	r.ends = append(r.ends, *meta)
+	r.errs = append(r.errs, err)
+}
+
+func TestDo_NotifiesObserver(t *testing.T) {
+	tests := []struct {
+		name       string
+		transport  *mockTransport
+		req        *testRequest
+		wantStage  Stage
+		wantParams bool
+	}{
+		{"success", &mockTransport{response: newResponse(200, `{"status":"Ok"}`)}, &testRequest{Wallet: "w"}, "", true},
+		{"validation", &mockTransport{}, &testRequest{}, StageValidate, false},
+		{"api error", &mockTransport{err: &errors.APIError{Code: 503}}, &testRequest{Wallet: "w"}, StageStatus, true},
+		{"network error", &mockTransport{err: &errors.NetworkError{Op: "http_request", Err: io.EOF}}, &testRequest{Wallet: "w"}, StageTransport, true},
+		{"decode", &mockTransport{response: newResponse(200, "nope")}, &testRequest{Wallet: "w"}, StageDecode, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs := &recordingObserver{}
+			transport := &observedTransport{mockTransport: tt.transport, observer: obs}
+
+			_, _, err := Typed[*testRequest, testResponse](context.Background(), transport, "/api/v1/test", tt.req)
+			if len(obs.starts) != 1 || len(obs.ends) != 1 {
+				t.Fatalf("observer saw %d starts and %d ends, want 1 each", len(obs.starts), len(obs.ends))
+			}
+			if (obs.starts[0] != nil) != tt.wantParams {
+				t.Errorf("Start() params = %v, wantParams %v", obs.starts[0], tt.wantParams)
+			}
+			if obs.ends[0].FailedAt != tt.wantStage {
+				t.Errorf("End() FailedAt = %q, want %q", obs.ends[0].FailedAt, tt.wantStage)
+			}
+			if obs.errs[0] != err {
+				t.Errorf("End() err = %v, want %v", obs.errs[0], err)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package transport
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type attemptsKey struct{}
+
+// WithAttemptCounter returns a context that counts the upstream attempts made for
+// a request. Transports that reach the network call CountAttempt once per try.
+func WithAttemptCounter(ctx context.Context) (context.Context, *atomic.Int32) {
+	counter := new(atomic.Int32)
+	return context.WithValue(ctx, attemptsKey{}, counter), counter
+}
+
+// CountAttempt records one upstream attempt on the counter carried by ctx, if any
+func CountAttempt(ctx context.Context) {
+	if counter, ok := ctx.Value(attemptsKey{}).(*atomic.Int32); ok {
+		counter.Add(1)
+	}
+}
+
+// CountAttempts records n upstream attempts on the counter carried by ctx, if
+// any, such as the attempts of a request shared with other callers
+func CountAttempts(ctx context.Context, n int32) {
+	if counter, ok := ctx.Value(attemptsKey{}).(*atomic.Int32); ok {
+		counter.Add(n)
+	}
+}
@@ -1,17 +1,43 @@
 package utils
 
 import (
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/srpvpn/tensor-go-sdk/internal/errors"
 )
 
+// QueryMarshaler is implemented by types that encode themselves as query values.
+// Returning more than one value adds the parameter once per value.
+type QueryMarshaler interface {
+	MarshalQuery() ([]string, error)
+}
+
+// Array formats selectable with the `query` struct tag
+const (
+	ArrayFormatComma  = "comma"  // a=x,y (default)
+	ArrayFormatRepeat = "repeat" // a=x&a=y
+	ArrayFormatJSON   = "json"   // a=["x","y"]
+)
+
+var (
+	queryMarshalerType = reflect.TypeOf((*QueryMarshaler)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType           = reflect.TypeOf(time.Time{})
+)
+
 // BuildQueryParams converts a struct to URL query parameters
-// It uses struct tags to determine parameter names and handles omitempty
+// It uses struct tags to determine parameter names and handles omitempty.
+// Slices are comma-joined unless the field carries a `query:"repeat"` or
+// `query:"json"` tag. Types implementing QueryMarshaler or encoding.TextMarshaler
+// encode themselves, time.Time is sent as RFC 3339 in UTC, and maps and nested
+// structs are JSON encoded. Output is deterministic for a given input.
 func BuildQueryParams(req interface{}) (url.Values, error) {
 	if req == nil {
 		return url.Values{}, nil
@@ -59,14 +85,23 @@ func BuildQueryParams(req interface{}) (url.Values, error) {
 			continue
 		}
 
-		// Convert field value to string
-		paramValue, err := fieldToString(field)
+		format := fieldType.Tag.Get("query")
+		switch format {
+		case "", ArrayFormatComma, ArrayFormatRepeat, ArrayFormatJSON:
+		default:
+			return nil, fmt.Errorf("error converting field %s: unknown query format %q", fieldType.Name, format)
+		}
+
+		// Convert field value to strings
+		values, err := fieldToValues(field, format)
 		if err != nil {
 			return nil, fmt.Errorf("error converting field %s: %w", fieldType.Name, err)
 		}
 
-		if paramValue != "" {
-			params.Add(paramName, paramValue)
+		for _, value := range values {
+			if value != "" {
+				params.Add(paramName, value)
+			}
 		}
 	}
 
@@ -134,12 +169,88 @@ func isZeroValue(v reflect.Value) bool {
 		return v.Len() == 0
 	case reflect.Ptr, reflect.Interface:
 		return v.IsNil()
+	case reflect.Struct:
+		return v.IsZero()
 	default:
 		return false
 	}
 }
 
+// fieldToString converts a value to a single query string, comma-joining lists
 func fieldToString(v reflect.Value) (string, error) {
+	values, err := fieldToValues(v, ArrayFormatComma)
+	if err != nil || len(values) == 0 {
+		return "", err
+	}
+	return values[0], nil
+}
+
+// fieldToValues converts a value to its query representation using the given array format
+func fieldToValues(v reflect.Value, format string) ([]string, error) {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		return fieldToValues(v.Elem(), format)
+	}
+
+	if values, ok, err := marshalHooks(v); ok {
+		return values, err
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if format == ArrayFormatJSON {
+			data, err := json.Marshal(v.Interface())
+			if err != nil {
+				return nil, err
+			}
+			return []string{string(data)}, nil
+		}
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := scalarToString(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("unsupported slice type: %s", v.Type())
+			}
+			items = append(items, item)
+		}
+		if format == ArrayFormatRepeat {
+			return items, nil
+		}
+		return []string{strings.Join(items, ",")}, nil
+	case reflect.Map, reflect.Struct:
+		// encoding/json sorts map keys, so the result is deterministic
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return []string{string(data)}, nil
+	default:
+		value, err := scalarToString(v)
+		if err != nil {
+			return nil, err
+		}
+		return []string{value}, nil
+	}
+}
+
+// scalarToString converts a single, non-list value to a query string
+func scalarToString(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		return scalarToString(v.Elem())
+	}
+
+	if values, ok, err := marshalHooks(v); ok {
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(values, ","), nil
+	}
+
 	switch v.Kind() {
 	case reflect.String:
 		return v.String(), nil
@@ -151,22 +262,44 @@ func fieldToString(v reflect.Value) (string, error) {
 		return strconv.FormatUint(v.Uint(), 10), nil
 	case reflect.Float32, reflect.Float64:
 		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
-	case reflect.Slice:
-		if v.Type().Elem().Kind() == reflect.String {
-			// Handle []string by joining with commas
-			strs := make([]string, v.Len())
-			for i := 0; i < v.Len(); i++ {
-				strs[i] = v.Index(i).String()
-			}
-			return strings.Join(strs, ","), nil
-		}
-		return "", fmt.Errorf("unsupported slice type: %s", v.Type())
-	case reflect.Ptr:
-		if v.IsNil() {
-			return "", nil
-		}
-		return fieldToString(v.Elem())
 	default:
 		return "", fmt.Errorf("unsupported field type: %s", v.Type())
 	}
 }
+
+// marshalHooks applies QueryMarshaler, time.Time and encoding.TextMarshaler in that order.
+// Callers dereference pointers first so time values are always normalized to UTC.
+// The boolean reports whether one of them handled the value.
+func marshalHooks(v reflect.Value) ([]string, bool, error) {
+	if !v.CanInterface() {
+		return nil, false, nil
+	}
+
+	if v.Type().Implements(queryMarshalerType) {
+		values, err := v.Interface().(QueryMarshaler).MarshalQuery()
+		return values, true, err
+	}
+
+	if v.Type() == timeType {
+		return []string{v.Interface().(time.Time).UTC().Format(time.RFC3339Nano)}, true, nil
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, true, err
+		}
+		return []string{string(text)}, true, nil
+	}
+
+	// Pointer receivers are checked through an addressable copy
+	if v.Kind() != reflect.Ptr {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		if ptr.Type().Implements(queryMarshalerType) || ptr.Type().Implements(textMarshalerType) {
+			return marshalHooks(ptr)
+		}
+	}
+
+	return nil, false, nil
+}
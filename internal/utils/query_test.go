@@ -1,10 +1,13 @@
 package utils
 
 import (
+	"fmt"
 	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/srpvpn/tensor-go-sdk/internal/errors"
 )
@@ -134,6 +137,188 @@ func TestBuildQueryParams(t *testing.T) {
 	}
 }
 
+// testKey mimics solana.PublicKey, which encodes itself through MarshalText
+type testKey [4]byte
+
+func (k testKey) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("key%d%d%d%d", k[0], k[1], k[2], k[3])), nil
+}
+
+// testRange implements QueryMarshaler and expands to two values
+type testRange struct {
+	Min, Max int
+}
+
+func (r *testRange) MarshalQuery() ([]string, error) {
+	return []string{strconv.Itoa(r.Min), strconv.Itoa(r.Max)}, nil
+}
+
+func TestBuildQueryParams_RichTypes(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected url.Values
+		wantErr  bool
+	}{
+		{
+			name: "numeric slices are comma joined",
+			input: struct {
+				Ids    []int32   `json:"ids"`
+				Prices []float64 `json:"prices"`
+			}{
+				Ids:    []int32{1, 2, 3},
+				Prices: []float64{1.5, 2},
+			},
+			expected: url.Values{
+				"ids":    []string{"1,2,3"},
+				"prices": []string{"1.5,2"},
+			},
+		},
+		{
+			name: "repeat format",
+			input: struct {
+				Mints []string `json:"mints" query:"repeat"`
+			}{
+				Mints: []string{"a", "b"},
+			},
+			expected: url.Values{
+				"mints": []string{"a", "b"},
+			},
+		},
+		{
+			name: "json format",
+			input: struct {
+				Traits []string `json:"traits" query:"json"`
+			}{
+				Traits: []string{"Hat:Red", "Eyes:Blue"},
+			},
+			expected: url.Values{
+				"traits": []string{`["Hat:Red","Eyes:Blue"]`},
+			},
+		},
+		{
+			name: "time is normalized to UTC",
+			input: struct {
+				Since    time.Time  `json:"since"`
+				Until    *time.Time `json:"until,omitempty"`
+				NotSet   time.Time  `json:"notSet,omitempty"`
+				NotSetPt *time.Time `json:"notSetPt,omitempty"`
+			}{
+				Since: ts,
+				Until: &ts,
+			},
+			expected: url.Values{
+				"since": []string{"2024-05-01T10:30:00Z"},
+				"until": []string{"2024-05-01T10:30:00Z"},
+			},
+		},
+		{
+			name: "text marshaler and slices of them",
+			input: struct {
+				Owner  testKey   `json:"owner"`
+				Owners []testKey `json:"owners" query:"repeat"`
+			}{
+				Owner:  testKey{1, 2, 3, 4},
+				Owners: []testKey{{1, 1, 1, 1}, {2, 2, 2, 2}},
+			},
+			expected: url.Values{
+				"owner":  []string{"key1234"},
+				"owners": []string{"key1111", "key2222"},
+			},
+		},
+		{
+			name: "query marshaler with pointer receiver",
+			input: struct {
+				Range testRange `json:"range"`
+			}{
+				Range: testRange{Min: 1, Max: 9},
+			},
+			expected: url.Values{
+				"range": []string{"1", "9"},
+			},
+		},
+		{
+			name: "maps and nested structs are JSON encoded",
+			input: struct {
+				Filter map[string]int `json:"filter"`
+				Nested struct {
+					B string `json:"b"`
+					A int    `json:"a"`
+				} `json:"nested"`
+			}{
+				Filter: map[string]int{"z": 1, "a": 2},
+				Nested: struct {
+					B string `json:"b"`
+					A int    `json:"a"`
+				}{B: "x", A: 1},
+			},
+			expected: url.Values{
+				"filter": []string{`{"a":2,"z":1}`},
+				"nested": []string{`{"b":"x","a":1}`},
+			},
+		},
+		{
+			name: "unknown query format",
+			input: struct {
+				Mints []string `json:"mints" query:"pipes"`
+			}{
+				Mints: []string{"a"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported slice element",
+			input: struct {
+				Chans []chan int `json:"chans"`
+			}{
+				Chans: []chan int{make(chan int)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildQueryParams(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BuildQueryParams() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("BuildQueryParams() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildQueryParams_Deterministic(t *testing.T) {
+	input := struct {
+		Filter map[string]string `json:"filter"`
+		Mints  []string          `json:"mints" query:"repeat"`
+		Wallet string            `json:"wallet"`
+	}{
+		Filter: map[string]string{"c": "3", "a": "1", "b": "2"},
+		Mints:  []string{"m2", "m1"},
+		Wallet: "w",
+	}
+
+	first, err := BuildQueryParams(input)
+	if err != nil {
+		t.Fatalf("BuildQueryParams() error = %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		got, err := BuildQueryParams(input)
+		if err != nil {
+			t.Fatalf("BuildQueryParams() error = %v", err)
+		}
+		if got.Encode() != first.Encode() {
+			t.Fatalf("BuildQueryParams() not deterministic: %q vs %q", got.Encode(), first.Encode())
+		}
+	}
+}
+
 func TestValidateWalletAddress(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,192 @@
+// Package market decodes the raw JSON returned by the NFTs, user and
+// collections APIs into typed values with exact lamport prices, and answers
+// the questions trading code asks most: what is the floor, what is the best
+// bid, what does a wallet or pool hold.
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/srpvpn/tensor-go-sdk/api/tswap"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// NFT is an NFT as returned by the mint, listing and inventory endpoints
+type NFT struct {
+	Mint       string      `json:"mint"`
+	CollId     string      `json:"collId"`
+	Name       string      `json:"name"`
+	Owner      string      `json:"owner"`
+	RarityRank int32       `json:"rarityRank,omitempty"`
+	Attributes []Attribute `json:"attributes,omitempty"`
+	Listing    *Listing    `json:"listing"` // nil when the NFT is not listed
+}
+
+// Attribute is a single NFT trait
+type Attribute struct {
+	TraitType string `json:"trait_type"`
+	Value     string `json:"value"`
+}
+
+// Listing is an active listing of an NFT
+type Listing struct {
+	Price  money.Lamports `json:"price"`
+	Seller string         `json:"seller"`
+	Source string         `json:"source"`
+}
+
+// Listed reports whether the NFT has an active listing
+func (n NFT) Listed() bool {
+	return n.Listing != nil
+}
+
+// Trait returns the value of the named trait
+//
+// Returns:
+//   - The trait value
+//   - false if the NFT does not have the trait
+func (n NFT) Trait(traitType string) (string, bool) {
+	for _, a := range n.Attributes {
+		if a.TraitType == traitType {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// Bid targets
+const (
+	BidTargetNFT        = "NFT"
+	BidTargetCollection = "COLLECTION"
+	BidTargetTrait      = "TRAIT"
+)
+
+// Bid is an open single NFT, collection or trait bid
+type Bid struct {
+	Address        string         `json:"address"`
+	Owner          string         `json:"owner"`
+	Target         string         `json:"target"`   // One of the BidTarget constants
+	TargetId       string         `json:"targetId"` // Mint for NFT bids, collection ID otherwise
+	CollId         string         `json:"collId"`
+	Price          money.Lamports `json:"price"` // Price per item
+	Quantity       int32          `json:"quantity"`
+	FilledQuantity int32          `json:"filledQuantity"`
+	Traits         []string       `json:"traits,omitempty"`
+	SharedEscrow   bool           `json:"sharedEscrow"`
+	ExpiresAt      int64          `json:"expiresAt,omitempty"` // Unix seconds, 0 if the bid does not expire
+}
+
+// Remaining returns the number of items the bid can still buy
+func (b Bid) Remaining() int32 {
+	if n := b.Quantity - b.FilledQuantity; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Exposure returns the SOL the bid can still spend: its price times the remaining quantity
+func (b Bid) Exposure() money.Lamports {
+	return b.Price * money.Lamports(b.Remaining())
+}
+
+// EscrowAccount is a shared escrow account
+type EscrowAccount struct {
+	Address string         `json:"address"`
+	Owner   string         `json:"owner"`
+	Balance money.Lamports `json:"balance"`
+}
+
+// Pool is a TSwap pool as returned by the user pools endpoint
+type Pool struct {
+	Address       string         `json:"address"`
+	Owner         string         `json:"owner"`
+	CollId        string         `json:"collId"`
+	PoolType      string         `json:"poolType"`
+	CurveType     string         `json:"curveType"`
+	StartingPrice money.Lamports `json:"startingPrice"`
+	Delta         tswap.Delta    `json:"delta"` // Lamports or basis points, depending on CurveType
+	MmFeeBps      int32          `json:"mmFeeBps"`
+	SolBalance    money.Lamports `json:"solBalance"`
+	NftsHeld      []string       `json:"nftsHeld"`
+}
+
+// Transaction is a completed marketplace event
+type Transaction struct {
+	TxId      string         `json:"txId"`
+	TxType    string         `json:"txType"`
+	Mint      string         `json:"mint"`
+	CollId    string         `json:"collId"`
+	Price     money.Lamports `json:"price"`
+	Buyer     string         `json:"buyer,omitempty"`
+	Seller    string         `json:"seller,omitempty"`
+	BlockTime int64          `json:"blockTime"` // Unix seconds
+}
+
+// Page is the pagination block of list responses
+type Page struct {
+	EndCursor string `json:"endCursor,omitempty"`
+	HasMore   bool   `json:"hasMore"`
+}
+
+// DecodeNFTs decodes a list of NFTs. It accepts the mints by collection,
+// active listings and inventory responses as well as a bare JSON array.
+//
+// Returns:
+//   - The NFTs and the pagination block, which is empty for bare arrays
+//   - An error if the body is not a recognised NFT list
+func DecodeNFTs(data []byte) ([]NFT, Page, error) {
+	return decodeList[NFT](data, "NFTs", "mints", "listings", "nfts")
+}
+
+// DecodeBids decodes a user bids response
+func DecodeBids(data []byte) ([]Bid, Page, error) {
+	return decodeList[Bid](data, "bids", "bids")
+}
+
+// DecodeEscrowAccounts decodes a user escrow accounts response
+func DecodeEscrowAccounts(data []byte) ([]EscrowAccount, error) {
+	items, _, err := decodeList[EscrowAccount](data, "escrow accounts", "escrowAccounts")
+	return items, err
+}
+
+// DecodePools decodes a user TSwap pools response
+func DecodePools(data []byte) ([]Pool, Page, error) {
+	return decodeList[Pool](data, "pools", "pools")
+}
+
+// DecodeTransactions decodes a user transactions response
+func DecodeTransactions(data []byte) ([]Transaction, Page, error) {
+	return decodeList[Transaction](data, "transactions", "txs")
+}
+
+// decodeList decodes either a bare array or an object holding the array under
+// one of keys, alongside an optional "page" block
+func decodeList[T any](data []byte, what string, keys ...string) ([]T, Page, error) {
+	var items []T
+	if err := json.Unmarshal(data, &items); err == nil {
+		return items, Page{}, nil
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, Page{}, fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+	var page Page
+	if raw, ok := envelope["page"]; ok {
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, Page{}, fmt.Errorf("failed to decode %s page: %w", what, err)
+		}
+	}
+	for _, key := range keys {
+		raw, ok := envelope[key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, Page{}, fmt.Errorf("failed to decode %s: %w", what, err)
+		}
+		return items, page, nil
+	}
+	return nil, Page{}, fmt.Errorf("failed to decode %s: response has none of %v", what, keys)
+}
@@ -0,0 +1,220 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/tensortest"
+)
+
+var (
+	alice = tensortest.Address("alice")
+	bob   = tensortest.Address("bob")
+)
+
+func newTestServer(t *testing.T) (*tensortest.Server, *client.Client) {
+	t.Helper()
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID, Name: "Market"})
+	return srv, client.New(&client.Config{BaseURL: srv.URL})
+}
+
+func TestDecodeNFTs(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantLen  int
+		wantPage Page
+		wantErr  string
+	}{
+		{
+			name:     "mints by collection",
+			body:     `{"mints":[{"mint":"a","listing":{"price":"1500000000","seller":"s"}}],"page":{"endCursor":"c","hasMore":true}}`,
+			wantLen:  1,
+			wantPage: Page{EndCursor: "c", HasMore: true},
+		},
+		{name: "listings", body: `{"listings":[{"mint":"a"},{"mint":"b"}],"page":{}}`, wantLen: 2},
+		{name: "inventory", body: `{"nfts":[]}`, wantLen: 0},
+		{name: "bare array", body: `[{"mint":"a","listing":null}]`, wantLen: 1},
+		{name: "unknown envelope", body: `{"items":[]}`, wantErr: "response has none of"},
+		{name: "bad price", body: `{"mints":[{"mint":"a","listing":{"price":"cheap"}}]}`, wantErr: "failed to decode NFTs"},
+		{name: "not json", body: `<html>`, wantErr: "failed to decode NFTs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, page, err := DecodeNFTs([]byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("DecodeNFTs() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeNFTs() error = %v", err)
+			}
+			if len(items) != tt.wantLen || page != tt.wantPage {
+				t.Errorf("DecodeNFTs() = %d items, %+v; want %d, %+v", len(items), page, tt.wantLen, tt.wantPage)
+			}
+		})
+	}
+
+	items, _, _ := DecodeNFTs([]byte(`{"mints":[{"mint":"a","attributes":[{"trait_type":"Hat","value":"Cap"}],"listing":{"price":1500000000}}]}`))
+	if !items[0].Listed() || items[0].Listing.Price != money.MustParseSOL("1.5") {
+		t.Errorf("listing = %+v, want 1.5 SOL", items[0].Listing)
+	}
+	if v, ok := items[0].Trait("Hat"); !ok || v != "Cap" {
+		t.Errorf("Trait(Hat) = %q, %v", v, ok)
+	}
+	if _, ok := items[0].Trait("Eyes"); ok {
+		t.Error("Trait(Eyes) ok = true")
+	}
+}
+
+func TestDecodeOthers(t *testing.T) {
+	bids, _, err := DecodeBids([]byte(`{"bids":[{"address":"b","price":"2000000000","quantity":5,"filledQuantity":2}]}`))
+	if err != nil || len(bids) != 1 {
+		t.Fatalf("DecodeBids() = %v, %v", bids, err)
+	}
+	if bids[0].Remaining() != 3 || bids[0].Exposure() != money.MustParseSOL("6") {
+		t.Errorf("remaining, exposure = %d, %v; want 3, 6 SOL", bids[0].Remaining(), bids[0].Exposure())
+	}
+	if overfilled := (Bid{Quantity: 1, FilledQuantity: 2}); overfilled.Remaining() != 0 {
+		t.Errorf("overfilled Remaining() = %d, want 0", overfilled.Remaining())
+	}
+
+	escrows, err := DecodeEscrowAccounts([]byte(`{"escrowAccounts":[{"address":"e","balance":"42"}]}`))
+	if err != nil || len(escrows) != 1 || escrows[0].Balance != 42 {
+		t.Errorf("DecodeEscrowAccounts() = %v, %v", escrows, err)
+	}
+	pools, _, err := DecodePools([]byte(`{"pools":[{"address":"p","startingPrice":"100","delta":"200","solBalance":"0","nftsHeld":["m"]}]}`))
+	if err != nil || len(pools) != 1 || pools[0].Delta != 200 || len(pools[0].NftsHeld) != 1 {
+		t.Errorf("DecodePools() = %v, %v", pools, err)
+	}
+	txs, _, err := DecodeTransactions([]byte(`{"txs":[{"txId":"t","price":"7","blockTime":1700000000}]}`))
+	if err != nil || len(txs) != 1 || txs[0].Price != 7 {
+		t.Errorf("DecodeTransactions() = %v, %v", txs, err)
+	}
+}
+
+func TestFloor(t *testing.T) {
+	srv, c := newTestServer(t)
+	ctx := context.Background()
+
+	if _, ok, err := Floor(ctx, c.NFTs, tensortest.CollID); err != nil || ok {
+		t.Fatalf("Floor() on empty collection = %v, %v; want no floor", ok, err)
+	}
+
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("own"), CollId: tensortest.CollID, Owner: alice, Listing: &tensortest.Listing{Price: "1000000000"}})
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("other"), CollId: tensortest.CollID, Owner: bob, Listing: &tensortest.Listing{Price: "1200000000"}})
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("unlisted"), CollId: tensortest.CollID, Owner: bob})
+
+	floor, ok, err := Floor(ctx, c.NFTs, tensortest.CollID)
+	if err != nil || !ok || floor.Listing.Price != money.MustParseSOL("1") {
+		t.Errorf("Floor() = %+v, %v, %v; want 1 SOL", floor, ok, err)
+	}
+	floor, ok, err = Floor(ctx, c.NFTs, tensortest.CollID, alice)
+	if err != nil || !ok || floor.Owner != bob {
+		t.Errorf("Floor() excluding alice = %+v, %v, %v; want bob's listing", floor, ok, err)
+	}
+}
+
+func TestCollectionAndStats(t *testing.T) {
+	srv, c := newTestServer(t)
+	ctx := context.Background()
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("listed"), CollId: tensortest.CollID, Owner: bob, Listing: &tensortest.Listing{Price: "3000000000"}})
+	srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: "2500000000"})
+
+	coll, err := Collection(ctx, c.Collections, tensortest.CollID)
+	if err != nil {
+		t.Fatalf("Collection() error = %v", err)
+	}
+	if bid, ok := BestBid(coll.Stats); !ok || bid != money.MustParseSOL("2.5") {
+		t.Errorf("BestBid() = %v, %v; want 2.5 SOL", bid, ok)
+	}
+	if floor, ok := StatsFloor(coll.Stats); !ok || floor != money.MustParseSOL("3") {
+		t.Errorf("StatsFloor() = %v, %v; want 3 SOL", floor, ok)
+	}
+	if _, ok := BestBid(collections.CollectionStats{}); ok {
+		t.Error("BestBid() of empty stats ok = true")
+	}
+
+	if _, err := Collection(ctx, c.Collections, "missing"); !errors.Is(err, ErrCollectionNotFound) {
+		t.Errorf("Collection(missing) error = %v, want ErrCollectionNotFound", err)
+	}
+}
+
+func TestInventoryAndPoolsFollowPages(t *testing.T) {
+	srv, c := newTestServer(t)
+	ctx := context.Background()
+	for i := 0; i < 150; i++ {
+		srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("nft-" + string(rune('a'+i%26)) + string(rune('a'+i/26))), CollId: tensortest.CollID, Owner: alice})
+	}
+	inventory, err := Inventory(ctx, c.User, alice, tensortest.CollID)
+	if err != nil || len(inventory) != 150 {
+		t.Errorf("Inventory() = %d NFTs, %v; want 150", len(inventory), err)
+	}
+	if n := srv.RequestCount("/api/v1/user/inventory_by_collection"); n != 2 {
+		t.Errorf("Inventory() made %d requests, want 2", n)
+	}
+
+	for i := 0; i < 101; i++ {
+		srv.AddPool(tensortest.Pool{Owner: bob, CollId: tensortest.CollID, PoolType: "TRADE", CurveType: "linear", StartingPrice: "1", Delta: "0"})
+	}
+	pools, err := Pools(ctx, c.User, bob)
+	if err != nil || len(pools) != 101 {
+		t.Errorf("Pools() = %d pools, %v; want 101", len(pools), err)
+	}
+}
+
+func TestBids(t *testing.T) {
+	srv, c := newTestServer(t)
+	ctx := context.Background()
+	for i := 0; i < 120; i++ {
+		srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: "1000"})
+	}
+	srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetTrait, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: "2000", Traits: []string{"Hat:Cap"}})
+	srv.AddBid(tensortest.Bid{Owner: alice, Target: tensortest.BidTargetCollection, TargetId: "other", CollId: "other", Price: "3000"})
+	srv.AddBid(tensortest.Bid{Owner: bob, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: "4000"})
+
+	tests := []struct {
+		target, collID string
+		want           int
+	}{
+		{BidTargetCollection, tensortest.CollID, 120},
+		{BidTargetCollection, "", 121},
+		{BidTargetTrait, tensortest.CollID, 1},
+		{BidTargetNFT, tensortest.CollID, 0},
+	}
+	for _, tt := range tests {
+		bids, err := Bids(ctx, c.User, tt.target, alice, tt.collID)
+		if err != nil || len(bids) != tt.want {
+			t.Errorf("Bids(%s, %q) = %d bids, %v; want %d", tt.target, tt.collID, len(bids), err, tt.want)
+		}
+	}
+	if _, err := Bids(ctx, c.User, "POOL", alice, ""); err == nil {
+		t.Error("Bids(POOL) error = nil")
+	}
+}
+
+func TestListings(t *testing.T) {
+	srv, c := newTestServer(t)
+	for i := 0; i < 105; i++ {
+		srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("listed-" + string(rune('a'+i%26)) + string(rune('a'+i/26))), CollId: tensortest.CollID, Owner: alice, Listing: &tensortest.Listing{Price: "1000", Seller: alice}})
+	}
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("unlisted"), CollId: tensortest.CollID, Owner: alice})
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("elsewhere"), CollId: "other", Owner: alice, Listing: &tensortest.Listing{Price: "1000", Seller: alice}})
+
+	listings, err := Listings(context.Background(), c.User, alice, tensortest.CollID)
+	if err != nil || len(listings) != 105 {
+		t.Errorf("Listings() = %d listings, %v; want 105", len(listings), err)
+	}
+	if all, err := Listings(context.Background(), c.User, alice, ""); err != nil || len(all) != 106 {
+		t.Errorf("Listings() in every collection = %d listings, %v; want 106", len(all), err)
+	}
+}
@@ -0,0 +1,234 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// ErrCollectionNotFound is returned when a collection ID is not among the verified collections
+var ErrCollectionNotFound = errors.New("collection not found")
+
+// pageLimit is the page size used when reading every page of a user endpoint
+const pageLimit = 100
+
+// Collection fetches a verified collection with its current stats
+//
+// Returns:
+//   - The collection
+//   - ErrCollectionNotFound if the ID is unknown
+//   - An error if the request failed
+func Collection(ctx context.Context, api collections.CollectionsAPI, collID string) (*collections.CollectionDetailed, error) {
+	data, _, err := api.GetVerifiedCollections(ctx, &collections.GetVerifiedCollectionsRequest{
+		SortBy:  "statsV2.volume24h:desc",
+		Limit:   1,
+		CollIds: []string{collID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp collections.GetVerifiedCollectionsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode collections: %w", err)
+	}
+	for i := range resp.Collections {
+		if resp.Collections[i].CollId == collID {
+			return &resp.Collections[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrCollectionNotFound, collID)
+}
+
+// BestBid returns the highest collection bid from a collection's stats
+//
+// Returns:
+//   - The bid price
+//   - false if the collection has no bids
+func BestBid(stats collections.CollectionStats) (money.Lamports, bool) {
+	return statPrice(stats.SellNowPrice)
+}
+
+// StatsFloor returns the cheapest listing price from a collection's stats.
+// The stats can lag behind the listings; Floor reads the listings directly.
+//
+// Returns:
+//   - The floor price
+//   - false if nothing is listed
+func StatsFloor(stats collections.CollectionStats) (money.Lamports, bool) {
+	return statPrice(stats.BuyNowPrice)
+}
+
+// statPrice parses a lamport price from a stats field, which is empty when there is no price
+func statPrice(s string) (money.Lamports, bool) {
+	if s == "" {
+		return 0, false
+	}
+	l, err := money.ParseLamports(s)
+	if err != nil || l <= 0 {
+		return 0, false
+	}
+	return l, true
+}
+
+// Floor fetches the cheapest listed NFT of a collection, ignoring listings
+// owned or sold by any of exclude, such as the caller's own wallet and pools
+//
+// Returns:
+//   - The NFT, whose Listing holds the floor price
+//   - false if nothing is listed
+//   - An error if the request failed
+func Floor(ctx context.Context, api nfts.NFTsAPI, collID string, exclude ...string) (NFT, bool, error) {
+	onlyListings := true
+	data, _, err := api.GetNFTsByCollection(ctx, &nfts.NFTsByCollectionRequest{
+		CollId:        collID,
+		SortBy:        "PriceAsc",
+		Limit:         int32(len(exclude) + 1),
+		OnlyListings:  &onlyListings,
+		ExcludeOwners: exclude,
+	})
+	if err != nil {
+		return NFT{}, false, err
+	}
+	items, _, err := DecodeNFTs(data)
+	if err != nil {
+		return NFT{}, false, err
+	}
+	for _, n := range items {
+		if n.Listing != nil && !contains(exclude, n.Owner) && !contains(exclude, n.Listing.Seller) {
+			return n, true, nil
+		}
+	}
+	return NFT{}, false, nil
+}
+
+// Inventory fetches every NFT of a collection held by wallet, following pagination
+func Inventory(ctx context.Context, api user.UserAPI, wallet, collID string) ([]NFT, error) {
+	limit := int32(pageLimit)
+	req := &user.InventoryForCollectionRequest{Wallets: []string{wallet}, CollId: &collID, Limit: &limit}
+	var out []NFT
+	for {
+		data, _, err := api.GetInventoryForCollection(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		items, page, err := DecodeNFTs(data)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+		if !page.HasMore || page.EndCursor == "" {
+			return out, nil
+		}
+		req.Cursor = page.EndCursor
+	}
+}
+
+// Pools fetches the TSwap pools owned by owner, optionally restricted to addresses, following pagination
+func Pools(ctx context.Context, api user.UserAPI, owner string, addresses ...string) ([]Pool, error) {
+	req := &user.TSwapsPoolsRequest{Owner: owner, PoolAddresses: addresses, Limit: pageLimit}
+	var out []Pool
+	for {
+		data, _, err := api.GetTSwapPools(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		items, page, err := DecodePools(data)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+		if !page.HasMore || page.EndCursor == "" {
+			return out, nil
+		}
+		cursor := page.EndCursor
+		req.Cursor = &cursor
+	}
+}
+
+func contains(items []string, v string) bool {
+	for _, item := range items {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Bids fetches owner's active bids of one target kind, optionally restricted to collID, following pagination
+//
+// Returns:
+//   - The bids
+//   - An error if target is not one of the BidTarget constants or a request fails
+func Bids(ctx context.Context, api user.UserAPI, target, owner, collID string) ([]Bid, error) {
+	var coll *string
+	if collID != "" {
+		coll = &collID
+	}
+	var fetch func(cursor *string) ([]byte, int, error)
+	switch target {
+	case BidTargetNFT:
+		fetch = func(cursor *string) ([]byte, int, error) {
+			return api.GetNFTBids(ctx, &user.NFTBidsRequest{Owner: owner, Limit: pageLimit, CollId: coll, Cursor: cursor})
+		}
+	case BidTargetCollection:
+		fetch = func(cursor *string) ([]byte, int, error) {
+			return api.GetCollectionBids(ctx, &user.CollectionBidsRequest{Owner: owner, Limit: pageLimit, CollId: coll, Cursor: cursor})
+		}
+	case BidTargetTrait:
+		fetch = func(cursor *string) ([]byte, int, error) {
+			return api.GetTraitBids(ctx, &user.TraitBidsRequest{Owner: owner, Limit: pageLimit, CollId: coll, Cursor: cursor})
+		}
+	default:
+		return nil, fmt.Errorf("unknown bid target %q", target)
+	}
+
+	var out []Bid
+	var cursor *string
+	for {
+		data, _, err := fetch(cursor)
+		if err != nil {
+			return nil, err
+		}
+		items, page, err := DecodeBids(data)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+		if !page.HasMore || page.EndCursor == "" {
+			return out, nil
+		}
+		next := page.EndCursor
+		cursor = &next
+	}
+}
+
+// Listings fetches wallet's active listings, optionally restricted to collID, following pagination
+func Listings(ctx context.Context, api user.UserAPI, wallet, collID string) ([]NFT, error) {
+	req := &user.ListingsRequest{Wallets: []string{wallet}, SortBy: "PriceAsc", Limit: pageLimit}
+	if collID != "" {
+		req.CollId = &collID
+	}
+	var out []NFT
+	for {
+		data, _, err := api.GetListings(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		items, page, err := DecodeNFTs(data)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+		if !page.HasMore || page.EndCursor == "" {
+			return out, nil
+		}
+		cursor := page.EndCursor
+		req.Cursor = &cursor
+	}
+}
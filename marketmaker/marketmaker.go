@@ -0,0 +1,535 @@
+// Package marketmaker runs a TSwap trade pool around a collection's market.
+// Each Step reads the floor and the best bid, re-prices the pool so its buy
+// and sell quotes sit a target spread around the mid price, and moves NFTs
+// and SOL between the pool and the owner's wallet so the pool can fill a
+// configured number of trades on each side. Hard risk limits cap the pool's
+// inventory, its SOL and how often it is edited; in dry-run mode the engine
+// only logs what it would do.
+package marketmaker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/tswap"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/pricing"
+)
+
+// Defaults applied by New to unset Config fields
+const (
+	DefaultRepriceThresholdBps = 100
+	DefaultMinTransfer         = 10_000_000 // 0.01 SOL
+)
+
+// Config configures an Engine
+type Config struct {
+	Owner       string // Wallet that owns the pool and holds the NFTs outside it
+	PoolAddress string // TRADE pool to manage
+	CollId      string
+
+	// SpreadBps is the target gap between the pool's ask (the price takers
+	// pay to buy) and its bid (the price takers get when selling), in basis
+	// points of the mid price. The MM fee is charged on top.
+	SpreadBps int64
+	// Curve is the pool's curve type. Defaults to linear.
+	Curve pricing.CurveType
+	// MmFeeBps is the MM fee set on every edit
+	MmFeeBps int64
+	// RepriceThresholdBps is how far the pool's ask or bid may drift from
+	// target, in basis points of the target, before the pool is edited
+	RepriceThresholdBps int64
+
+	// AskDepth is the number of NFTs to keep in the pool for takers to buy
+	AskDepth int
+	// BidDepth is the number of NFTs the pool's SOL should be able to buy along its curve
+	BidDepth int
+	// MinTransfer is the smallest SOL deposit or withdrawal worth making
+	MinTransfer money.Lamports
+
+	Limits Limits
+
+	// DryRun makes the engine log and report its actions without calling the
+	// TSwap API. Dry-run edits do not count toward MaxEditsPerHour.
+	DryRun bool
+	// Logger receives one event per action. Nothing is logged when nil.
+	Logger *slog.Logger
+}
+
+// Limits are hard caps the engine never exceeds, whatever the market does
+type Limits struct {
+	MaxInventory    int            // Most NFTs the pool may hold; excess is withdrawn
+	MaxSOL          money.Lamports // Most SOL the pool may hold; excess is withdrawn
+	MaxEditsPerHour int            // Most EditTSwapPool calls in any rolling hour
+}
+
+// APIs are the SDK services the engine uses. The fields match those of client.Client.
+type APIs struct {
+	Collections collections.CollectionsAPI
+	NFTs        nfts.NFTsAPI
+	User        user.UserAPI
+	TSwap       tswap.TSwapAPI
+}
+
+// ActionKind identifies what an Action does
+type ActionKind string
+
+// Action kinds
+const (
+	ActionEditPool    ActionKind = "edit_pool"
+	ActionDepositNFT  ActionKind = "deposit_nft"
+	ActionWithdrawNFT ActionKind = "withdraw_nft"
+	ActionDepositSOL  ActionKind = "deposit_sol"
+	ActionWithdrawSOL ActionKind = "withdraw_sol"
+)
+
+// Action is one transaction the engine built, or would build in dry-run mode.
+// Exactly one of Edit, NFT and SOL is set.
+type Action struct {
+	Kind   ActionKind
+	Reason string
+	Edit   *tswap.EditTSwapPoolRequest
+	NFT    *tswap.DepositWithdrawNFTRequest
+	SOL    *tswap.DepositWithdrawSOLRequest
+	// Txs are the unsigned transactions returned by the API; empty in dry-run mode
+	Txs []tswap.Transaction
+}
+
+// Report describes one Step
+type Report struct {
+	Time    time.Time
+	Floor   money.Lamports // Cheapest listing not owned by the pool or wallet; zero if none
+	BestBid money.Lamports // Best collection bid other than the pool's own; zero if none
+	Mid     money.Lamports
+
+	Current pricing.Pool // The pool's curve before the step
+	Target  pricing.Pool // The curve the pool should have
+	Ask     money.Lamports
+	Bid     money.Lamports
+
+	PoolNFTs int
+	PoolSOL  money.Lamports
+
+	Actions []Action
+	// Skipped lists the adjustments the risk limits or market prevented
+	Skipped []string
+	DryRun  bool
+}
+
+// Engine runs one trade pool. It is safe for concurrent use, but Steps are serialized.
+type Engine struct {
+	cfg         Config
+	apis        APIs
+	blockhashes cluster.BlockhashSource
+	logger      *slog.Logger
+	now         func() time.Time
+
+	mu    sync.Mutex
+	edits []time.Time // Times of recent edits, oldest first
+}
+
+// New creates an Engine
+//
+// Returns:
+//   - The engine
+//   - An error if the config is incomplete or a required API is nil
+func New(cfg Config, apis APIs, blockhashes cluster.BlockhashSource) (*Engine, error) {
+	if cfg.Curve == "" {
+		cfg.Curve = pricing.CurveLinear
+	}
+	if cfg.RepriceThresholdBps == 0 {
+		cfg.RepriceThresholdBps = DefaultRepriceThresholdBps
+	}
+	if cfg.MinTransfer == 0 {
+		cfg.MinTransfer = DefaultMinTransfer
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if apis.Collections == nil || apis.NFTs == nil || apis.User == nil || apis.TSwap == nil {
+		return nil, fmt.Errorf("collections, NFTs, user and TSwap APIs are required")
+	}
+	if blockhashes == nil {
+		return nil, fmt.Errorf("blockhash source is required")
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return &Engine{
+		cfg:         cfg,
+		apis:        apis,
+		blockhashes: blockhashes,
+		logger:      logger.With("pool", cfg.PoolAddress, "collection", cfg.CollId),
+		now:         time.Now,
+	}, nil
+}
+
+// validate checks the config fields that have no default
+func (c *Config) validate() error {
+	if c.Owner == "" || c.PoolAddress == "" || c.CollId == "" {
+		return fmt.Errorf("owner, poolAddress and collId are required")
+	}
+	if c.SpreadBps <= 0 || c.SpreadBps >= 20_000 {
+		return fmt.Errorf("spreadBps must be between 1 and 19999")
+	}
+	if c.Curve != pricing.CurveLinear && c.Curve != pricing.CurveExponential {
+		return fmt.Errorf("invalid curve: %s", c.Curve)
+	}
+	if c.MmFeeBps < 0 || c.MmFeeBps >= 10_000 {
+		return fmt.Errorf("mmFeeBps must be between 0 and 9999")
+	}
+	if c.RepriceThresholdBps < 0 {
+		return fmt.Errorf("repriceThresholdBps must be >= 0")
+	}
+	if c.AskDepth < 0 || c.BidDepth < 0 {
+		return fmt.Errorf("askDepth and bidDepth must be >= 0")
+	}
+	if c.MinTransfer < 0 {
+		return fmt.Errorf("minTransfer must be >= 0")
+	}
+	if c.Limits.MaxInventory <= 0 || c.Limits.MaxSOL <= 0 || c.Limits.MaxEditsPerHour <= 0 {
+		return fmt.Errorf("maxInventory, maxSOL and maxEditsPerHour limits must all be > 0")
+	}
+	return nil
+}
+
+// Step reads the market and the pool, then edits and rebalances the pool as
+// needed. Actions run in order: the edit, NFT transfers, then the SOL
+// transfer; the step stops at the first failure.
+//
+// Returns:
+//   - The report, including the actions completed before any failure
+//   - An error if the market or pool could not be read, or an action failed
+func (e *Engine) Step(ctx context.Context) (*Report, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	report := &Report{Time: e.now(), DryRun: e.cfg.DryRun}
+	pool, err := e.readPool(ctx)
+	if err != nil {
+		return report, err
+	}
+	current := pricing.Pool{
+		Type:          pricing.PoolType(pool.PoolType),
+		Curve:         pricing.CurveType(pool.CurveType),
+		StartingPrice: pool.StartingPrice,
+		MmFeeBps:      int64(pool.MmFeeBps),
+	}
+	current.SetDelta(pool.Delta)
+	report.Current = current
+	report.PoolNFTs, report.PoolSOL = len(pool.NftsHeld), pool.SolBalance
+
+	if err := e.readMarket(ctx, current, report); err != nil {
+		return report, err
+	}
+
+	// The curve the pool will have after this step sizes the SOL it needs
+	curve := current
+	if report.Mid > 0 {
+		report.Ask, report.Bid = quotes(report.Mid, e.cfg.SpreadBps)
+		report.Target = e.targetCurve(report.Ask, report.Bid)
+		if edit := e.planEdit(current, report); edit != nil {
+			report.Actions = append(report.Actions, *edit)
+			curve = report.Target
+		}
+	} else {
+		report.Skipped = append(report.Skipped, "no listings or bids to price against")
+	}
+
+	nftActions, err := e.planNFTs(ctx, pool, report)
+	if err != nil {
+		return report, err
+	}
+	report.Actions = append(report.Actions, nftActions...)
+	if sol := e.planSOL(pool, curve, report); sol != nil {
+		report.Actions = append(report.Actions, *sol)
+	}
+
+	planned := report.Actions
+	report.Actions = nil
+	if len(planned) == 0 {
+		return report, nil
+	}
+	blockhash := ""
+	if !e.cfg.DryRun {
+		if blockhash, _, err = e.blockhashes.LatestBlockhash(ctx); err != nil {
+			return report, fmt.Errorf("failed to get blockhash: %w", err)
+		}
+	}
+	for _, action := range planned {
+		if err := e.execute(ctx, &action, blockhash); err != nil {
+			return report, fmt.Errorf("%s: %w", action.Kind, err)
+		}
+		report.Actions = append(report.Actions, action)
+	}
+	return report, nil
+}
+
+// readPool fetches the managed pool
+func (e *Engine) readPool(ctx context.Context) (market.Pool, error) {
+	pools, err := market.Pools(ctx, e.apis.User, e.cfg.Owner, e.cfg.PoolAddress)
+	if err != nil {
+		return market.Pool{}, fmt.Errorf("failed to read pool: %w", err)
+	}
+	for _, p := range pools {
+		if p.Address != e.cfg.PoolAddress {
+			continue
+		}
+		if p.PoolType != string(pricing.PoolTrade) {
+			return market.Pool{}, fmt.Errorf("pool %s is a %s pool, want TRADE", p.Address, p.PoolType)
+		}
+		return p, nil
+	}
+	return market.Pool{}, fmt.Errorf("pool %s not found for owner %s", e.cfg.PoolAddress, e.cfg.Owner)
+}
+
+// readMarket fills in the floor, best bid and mid price. Listings by the
+// pool or the wallet are ignored, and so is a best bid equal to the pool's
+// own bid, so the engine does not chase its own quotes.
+func (e *Engine) readMarket(ctx context.Context, current pricing.Pool, report *Report) error {
+	floor, ok, err := market.Floor(ctx, e.apis.NFTs, e.cfg.CollId, e.cfg.PoolAddress, e.cfg.Owner)
+	if err != nil {
+		return fmt.Errorf("failed to read floor: %w", err)
+	}
+	if ok {
+		report.Floor = floor.Listing.Price
+	}
+
+	coll, err := market.Collection(ctx, e.apis.Collections, e.cfg.CollId)
+	if err != nil {
+		return fmt.Errorf("failed to read best bid: %w", err)
+	}
+	if bid, ok := market.BestBid(coll.Stats); ok {
+		own, err := current.SellQuotes(1, pricing.Fees{})
+		if err != nil || len(own) == 0 || own[0].Price != bid {
+			report.BestBid = bid
+		}
+	}
+
+	switch {
+	case report.Floor > 0 && report.BestBid > 0:
+		report.Mid = (report.Floor + report.BestBid) / 2
+	case report.Floor > 0:
+		report.Mid = report.Floor
+	default:
+		report.Mid = report.BestBid
+	}
+	return nil
+}
+
+// quotes returns the ask and bid spreadBps apart around mid
+func quotes(mid money.Lamports, spreadBps int64) (ask, bid money.Lamports) {
+	half := mid.MulDiv(spreadBps, 20_000)
+	return mid + half, mid - half
+}
+
+// targetCurve returns a curve whose first buy quote is ask and first sell quote is bid
+func (e *Engine) targetCurve(ask, bid money.Lamports) pricing.Pool {
+	target := pricing.Pool{
+		Type:          pricing.PoolTrade,
+		Curve:         e.cfg.Curve,
+		StartingPrice: ask,
+		MmFeeBps:      e.cfg.MmFeeBps,
+	}
+	if e.cfg.Curve == pricing.CurveExponential {
+		// ask / (1 + delta/10000) = bid, rounding delta up so the bid never exceeds target
+		num := int64(ask-bid) * 10_000
+		target.DeltaBps = num / int64(bid)
+		if num%int64(bid) != 0 {
+			target.DeltaBps++
+		}
+	} else {
+		target.DeltaLamports = ask - bid
+	}
+	return target
+}
+
+// planEdit returns the pool edit needed to reach the target curve, or nil
+func (e *Engine) planEdit(current pricing.Pool, report *Report) *Action {
+	target := report.Target
+	var curAsk, curBid money.Lamports
+	if ask, ok := current.SpotPrice(); ok {
+		curAsk = ask
+	}
+	if sells, err := current.SellQuotes(1, pricing.Fees{}); err == nil && len(sells) > 0 {
+		curBid = sells[0].Price
+	}
+	drifted := func(cur, want money.Lamports) bool {
+		diff := cur - want
+		if diff < 0 {
+			diff = -diff
+		}
+		return diff > want.MulBps(e.cfg.RepriceThresholdBps)
+	}
+	if current.Curve == target.Curve && current.MmFeeBps == target.MmFeeBps &&
+		!drifted(curAsk, report.Ask) && !drifted(curBid, report.Bid) {
+		return nil
+	}
+
+	now := e.now()
+	cutoff := now.Add(-time.Hour)
+	for len(e.edits) > 0 && !e.edits[0].After(cutoff) {
+		e.edits = e.edits[1:]
+	}
+	if len(e.edits) >= e.cfg.Limits.MaxEditsPerHour {
+		report.Skipped = append(report.Skipped, fmt.Sprintf("edit: %d edits in the last hour, limit is %d", len(e.edits), e.cfg.Limits.MaxEditsPerHour))
+		return nil
+	}
+
+	mmFee := float64(target.MmFeeBps)
+	return &Action{
+		Kind:   ActionEditPool,
+		Reason: fmt.Sprintf("ask %s -> %s, bid %s -> %s", curAsk, report.Ask, curBid, report.Bid),
+		Edit: &tswap.EditTSwapPoolRequest{
+			PoolAddress:   e.cfg.PoolAddress,
+			PoolType:      string(target.Type),
+			CurveType:     string(target.Curve),
+			StartingPrice: target.StartingPrice,
+			Delta:         target.Delta(),
+			MmFeeBps:      &mmFee,
+		},
+	}
+}
+
+// planNFTs moves NFTs so the pool holds AskDepth of them, capped at MaxInventory
+func (e *Engine) planNFTs(ctx context.Context, pool market.Pool, report *Report) ([]Action, error) {
+	want := min(e.cfg.AskDepth, e.cfg.Limits.MaxInventory)
+	held := len(pool.NftsHeld)
+	var actions []Action
+
+	if held > want {
+		reason := fmt.Sprintf("pool holds %d NFTs, target is %d", held, want)
+		for _, mint := range pool.NftsHeld[want:] {
+			actions = append(actions, e.nftAction(ActionWithdrawNFT, mint, reason))
+		}
+		return actions, nil
+	}
+	if held == want {
+		return nil, nil
+	}
+
+	inventory, err := market.Inventory(ctx, e.apis.User, e.cfg.Owner, e.cfg.CollId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet inventory: %w", err)
+	}
+	reason := fmt.Sprintf("pool holds %d NFTs, target is %d", held, want)
+	for _, n := range inventory {
+		if held+len(actions) == want {
+			break
+		}
+		if n.Listed() {
+			continue // Listed elsewhere; it cannot be deposited
+		}
+		actions = append(actions, e.nftAction(ActionDepositNFT, n.Mint, reason))
+	}
+	if missing := want - held - len(actions); missing > 0 {
+		report.Skipped = append(report.Skipped, fmt.Sprintf("deposit: wallet is %d unlisted NFTs short of the target", missing))
+	}
+	return actions, nil
+}
+
+// nftAction builds a deposit or withdrawal of one NFT
+func (e *Engine) nftAction(kind ActionKind, mint, reason string) Action {
+	action := "DEPOSIT"
+	if kind == ActionWithdrawNFT {
+		action = "WITHDRAW"
+	}
+	return Action{
+		Kind:   kind,
+		Reason: reason,
+		NFT:    &tswap.DepositWithdrawNFTRequest{Action: action, PoolAddress: e.cfg.PoolAddress, Mint: mint},
+	}
+}
+
+// planSOL moves SOL so the pool can buy BidDepth NFTs along curve, capped at MaxSOL
+func (e *Engine) planSOL(pool market.Pool, curve pricing.Pool, report *Report) *Action {
+	var want money.Lamports
+	if sells, err := curve.SellQuotes(e.cfg.BidDepth, pricing.Fees{}); err == nil {
+		for _, q := range sells {
+			want += q.Price
+		}
+	}
+	if want > e.cfg.Limits.MaxSOL {
+		report.Skipped = append(report.Skipped, fmt.Sprintf("deposit: bid depth needs %s, capped at the %s limit", want, e.cfg.Limits.MaxSOL))
+		want = e.cfg.Limits.MaxSOL
+	}
+
+	have := pool.SolBalance
+	reason := fmt.Sprintf("pool holds %s, target is %s", have, want)
+	switch diff := want - have; {
+	case have > e.cfg.Limits.MaxSOL:
+		return e.solAction(ActionWithdrawSOL, have-want, reason)
+	case diff >= e.cfg.MinTransfer && diff > 0:
+		return e.solAction(ActionDepositSOL, diff, reason)
+	case -diff >= e.cfg.MinTransfer && diff < 0:
+		return e.solAction(ActionWithdrawSOL, -diff, reason)
+	}
+	return nil
+}
+
+// solAction builds a SOL deposit or withdrawal
+func (e *Engine) solAction(kind ActionKind, amount money.Lamports, reason string) *Action {
+	action := "DEPOSIT"
+	if kind == ActionWithdrawSOL {
+		action = "WITHDRAW"
+	}
+	return &Action{
+		Kind:   kind,
+		Reason: reason,
+		SOL:    &tswap.DepositWithdrawSOLRequest{Action: action, PoolAddress: e.cfg.PoolAddress, Lamports: amount},
+	}
+}
+
+// execute logs the action and, unless in dry-run mode, builds its transactions
+func (e *Engine) execute(ctx context.Context, action *Action, blockhash string) error {
+	attrs := []any{"action", string(action.Kind), "reason", action.Reason, "dry_run", e.cfg.DryRun}
+	switch {
+	case action.Edit != nil:
+		attrs = append(attrs, "starting_price", action.Edit.StartingPrice, "delta", int64(action.Edit.Delta))
+	case action.NFT != nil:
+		attrs = append(attrs, "mint", action.NFT.Mint)
+	case action.SOL != nil:
+		attrs = append(attrs, "lamports", action.SOL.Lamports)
+	}
+	e.logger.InfoContext(ctx, "market maker action", attrs...)
+	if e.cfg.DryRun {
+		return nil
+	}
+
+	var err error
+	switch {
+	case action.Edit != nil:
+		action.Edit.Blockhash = blockhash
+		var resp *tswap.EditTSwapPoolResponse
+		if resp, _, err = e.apis.TSwap.EditTSwapPool(ctx, action.Edit); err == nil {
+			action.Txs = resp.Txs
+			e.edits = append(e.edits, e.now())
+		}
+	case action.NFT != nil:
+		action.NFT.Blockhash = blockhash
+		var resp *tswap.DepositWithdrawNFTResponse
+		if resp, _, err = e.apis.TSwap.DepositWithdrawNFT(ctx, action.NFT); err == nil {
+			action.Txs = resp.Txs
+		}
+	case action.SOL != nil:
+		action.SOL.Blockhash = blockhash
+		var resp *tswap.DepositWithdrawSOLResponse
+		if resp, _, err = e.apis.TSwap.DepositWithdrawSOL(ctx, action.SOL); err == nil {
+			action.Txs = resp.Txs
+		}
+	}
+	if err != nil {
+		e.logger.ErrorContext(ctx, "market maker action failed", "action", string(action.Kind), "error", err)
+	}
+	return err
+}
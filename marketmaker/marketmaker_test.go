@@ -0,0 +1,342 @@
+package marketmaker
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/tswap"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/pricing"
+	"github.com/srpvpn/tensor-go-sdk/tensortest"
+)
+
+var (
+	owner  = tensortest.Address("market-maker")
+	seller = tensortest.Address("seller")
+	bidder = tensortest.Address("bidder")
+)
+
+// newMarket seeds a collection with a 10 SOL floor, an 8 SOL collection bid,
+// an empty trade pool and three unlisted NFTs in the owner's wallet
+func newMarket(t *testing.T) (*tensortest.Server, *client.Client, string) {
+	t.Helper()
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID, Name: "MM"})
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("listed-1"), CollId: tensortest.CollID, Owner: seller, Listing: &tensortest.Listing{Price: "10000000000"}})
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("listed-2"), CollId: tensortest.CollID, Owner: seller, Listing: &tensortest.Listing{Price: "12000000000"}})
+	srv.AddBid(tensortest.Bid{Owner: bidder, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: "8000000000"})
+	for _, seed := range []string{"own-1", "own-2", "own-3"} {
+		srv.AddNFT(tensortest.NFT{Mint: tensortest.Address(seed), CollId: tensortest.CollID, Owner: owner})
+	}
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("own-listed"), CollId: tensortest.CollID, Owner: owner, Listing: &tensortest.Listing{Price: "9000000000"}})
+	pool := srv.AddPool(tensortest.Pool{
+		Owner: owner, CollId: tensortest.CollID, PoolType: "TRADE", CurveType: "linear",
+		StartingPrice: "5000000000", Delta: "1000000000",
+	})
+	return srv, client.New(&client.Config{BaseURL: srv.URL}), pool
+}
+
+func newEngine(t *testing.T, c *client.Client, cfg Config) *Engine {
+	t.Helper()
+	e, err := New(cfg, APIs{Collections: c.Collections, NFTs: c.NFTs, User: c.User, TSwap: c.TSwap}, cluster.StaticBlockhash("11111111111111111111111111111111"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return e
+}
+
+func baseConfig(pool string) Config {
+	return Config{
+		Owner:       owner,
+		PoolAddress: pool,
+		CollId:      tensortest.CollID,
+		SpreadBps:   2000,
+		AskDepth:    2,
+		BidDepth:    2,
+		Limits:      Limits{MaxInventory: 5, MaxSOL: money.MustParseSOL("100"), MaxEditsPerHour: 3},
+	}
+}
+
+func readPool(t *testing.T, c *client.Client, address string) market.Pool {
+	t.Helper()
+	pools, err := market.Pools(context.Background(), c.User, owner, address)
+	if err != nil || len(pools) != 1 {
+		t.Fatalf("Pools() = %v, %v", pools, err)
+	}
+	return pools[0]
+}
+
+func kinds(actions []Action) []ActionKind {
+	out := make([]ActionKind, len(actions))
+	for i, a := range actions {
+		out[i] = a.Kind
+	}
+	return out
+}
+
+func TestEngine_StepPricesAndFundsPool(t *testing.T) {
+	srv, c, poolAddr := newMarket(t)
+	e := newEngine(t, c, baseConfig(poolAddr))
+
+	report, err := e.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if report.Floor != money.MustParseSOL("10") || report.BestBid != money.MustParseSOL("8") || report.Mid != money.MustParseSOL("9") {
+		t.Errorf("floor, best bid, mid = %v, %v, %v; want 10, 8, 9 SOL", report.Floor, report.BestBid, report.Mid)
+	}
+	if report.Ask != money.MustParseSOL("9.9") || report.Bid != money.MustParseSOL("8.1") {
+		t.Errorf("ask, bid = %v, %v; want 9.9, 8.1 SOL", report.Ask, report.Bid)
+	}
+	want := []ActionKind{ActionEditPool, ActionDepositNFT, ActionDepositNFT, ActionDepositSOL}
+	if got := kinds(report.Actions); len(got) != len(want) || got[0] != want[0] || got[3] != want[3] {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	for _, a := range report.Actions {
+		if len(a.Txs) == 0 {
+			t.Errorf("%s action has no transactions", a.Kind)
+		}
+	}
+
+	pool := readPool(t, c, poolAddr)
+	if pool.StartingPrice != money.MustParseSOL("9.9") || pool.Delta.Lamports() != money.MustParseSOL("1.8") {
+		t.Errorf("pool curve = %v ± %v, want 9.9 ± 1.8 SOL", pool.StartingPrice, pool.Delta)
+	}
+	if len(pool.NftsHeld) != 2 {
+		t.Errorf("pool holds %d NFTs, want 2", len(pool.NftsHeld))
+	}
+	for _, mint := range pool.NftsHeld {
+		if mint == tensortest.Address("own-listed") {
+			t.Errorf("listed NFT %s was deposited", mint)
+		}
+	}
+	// Two sells along the new curve: 8.1 + 6.3
+	if pool.SolBalance != money.MustParseSOL("14.4") {
+		t.Errorf("pool SOL = %v, want 14.4 SOL", pool.SolBalance)
+	}
+
+	// A second step finds the pool on target and does nothing
+	before := srv.RequestCount("/api/v1/tx/")
+	report, err = e.Step(context.Background())
+	if err != nil {
+		t.Fatalf("second Step() error = %v", err)
+	}
+	if len(report.Actions) != 0 {
+		t.Errorf("second step actions = %v, want none", kinds(report.Actions))
+	}
+	if report.BestBid != money.MustParseSOL("8") {
+		t.Errorf("second step best bid = %v, want the 8 SOL bid", report.BestBid)
+	}
+	if n := srv.RequestCount("/api/v1/tx/"); n != before {
+		t.Errorf("second step made %d transaction requests", n-before)
+	}
+}
+
+func TestEngine_DryRun(t *testing.T) {
+	srv, c, poolAddr := newMarket(t)
+	var logs bytes.Buffer
+	cfg := baseConfig(poolAddr)
+	cfg.DryRun = true
+	cfg.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+	e := newEngine(t, c, cfg)
+
+	report, err := e.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if !report.DryRun || len(report.Actions) != 4 {
+		t.Fatalf("dry run report = %+v, want 4 actions", report)
+	}
+	if report.Actions[0].Edit.StartingPrice != money.MustParseSOL("9.9") {
+		t.Errorf("planned starting price = %v, want 9.9 SOL", report.Actions[0].Edit.StartingPrice)
+	}
+	if n := srv.RequestCount("/api/v1/tx/"); n != 0 {
+		t.Errorf("dry run made %d transaction requests", n)
+	}
+	if got := strings.Count(logs.String(), "market maker action"); got != 4 {
+		t.Errorf("logged %d actions, want 4:\n%s", got, logs.String())
+	}
+	if pool := readPool(t, c, poolAddr); pool.StartingPrice != money.MustParseSOL("5") {
+		t.Errorf("dry run changed the pool to %v", pool.StartingPrice)
+	}
+}
+
+func TestEngine_EditsPerHourLimit(t *testing.T) {
+	srv, c, poolAddr := newMarket(t)
+	cfg := baseConfig(poolAddr)
+	cfg.Limits.MaxEditsPerHour = 1
+	e := newEngine(t, c, cfg)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+
+	if _, err := e.Step(context.Background()); err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+
+	// The floor drops and the pool should follow, but the limit is spent
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("cheap"), CollId: tensortest.CollID, Owner: seller, Listing: &tensortest.Listing{Price: "9000000000"}})
+	now = now.Add(30 * time.Minute)
+	report, err := e.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	for _, a := range report.Actions {
+		if a.Kind == ActionEditPool {
+			t.Fatal("edited the pool beyond the hourly limit")
+		}
+	}
+	if len(report.Skipped) == 0 || !strings.Contains(report.Skipped[0], "1 edits in the last hour") {
+		t.Errorf("skipped = %v, want the edit limit", report.Skipped)
+	}
+
+	now = now.Add(31 * time.Minute)
+	report, err = e.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if len(report.Actions) == 0 || report.Actions[0].Kind != ActionEditPool {
+		t.Fatalf("actions = %v, want an edit once the hour has passed", kinds(report.Actions))
+	}
+	if pool := readPool(t, c, poolAddr); pool.StartingPrice != money.MustParseSOL("9.35") {
+		t.Errorf("pool starting price = %v, want 9.35 SOL", pool.StartingPrice)
+	}
+}
+
+func TestEngine_RiskLimitsWithdrawExcess(t *testing.T) {
+	_, c, poolAddr := newMarket(t)
+	for _, seed := range []string{"own-1", "own-2", "own-3"} {
+		if _, _, err := c.TSwap.DepositWithdrawNFT(context.Background(), nftDeposit(poolAddr, tensortest.Address(seed))); err != nil {
+			t.Fatalf("seed deposit: %v", err)
+		}
+	}
+	if _, _, err := c.TSwap.DepositWithdrawSOL(context.Background(), solDeposit(poolAddr, money.MustParseSOL("50"))); err != nil {
+		t.Fatalf("seed deposit: %v", err)
+	}
+
+	cfg := baseConfig(poolAddr)
+	cfg.AskDepth = 5
+	cfg.BidDepth = 10
+	cfg.Limits.MaxInventory = 1
+	cfg.Limits.MaxSOL = money.MustParseSOL("10")
+	e := newEngine(t, c, cfg)
+
+	report, err := e.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	want := []ActionKind{ActionEditPool, ActionWithdrawNFT, ActionWithdrawNFT, ActionWithdrawSOL}
+	got := kinds(report.Actions)
+	if len(got) != len(want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("actions = %v, want %v", got, want)
+		}
+	}
+	pool := readPool(t, c, poolAddr)
+	if len(pool.NftsHeld) != 1 || pool.SolBalance != money.MustParseSOL("10") {
+		t.Errorf("pool holds %d NFTs and %v, want 1 NFT and 10 SOL", len(pool.NftsHeld), pool.SolBalance)
+	}
+	if len(report.Skipped) == 0 || !strings.Contains(report.Skipped[0], "capped at the 10 SOL limit") {
+		t.Errorf("skipped = %v, want the SOL cap", report.Skipped)
+	}
+}
+
+func TestEngine_IgnoresOwnBid(t *testing.T) {
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID})
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("listed"), CollId: tensortest.CollID, Owner: seller, Listing: &tensortest.Listing{Price: "10000000000"}})
+	// Stats report the pool's own 9.5 SOL bid as the best bid
+	srv.AddBid(tensortest.Bid{Owner: owner, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: "9500000000"})
+	poolAddr := srv.AddPool(tensortest.Pool{
+		Owner: owner, CollId: tensortest.CollID, PoolType: "TRADE", CurveType: "linear",
+		StartingPrice: "10500000000", Delta: "1000000000",
+	})
+	c := client.New(&client.Config{BaseURL: srv.URL})
+	cfg := baseConfig(poolAddr)
+	cfg.DryRun = true
+	e := newEngine(t, c, cfg)
+
+	report, err := e.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if report.BestBid != 0 || report.Mid != money.MustParseSOL("10") {
+		t.Errorf("best bid, mid = %v, %v; want the own bid ignored and mid at the floor", report.BestBid, report.Mid)
+	}
+}
+
+func TestEngine_Errors(t *testing.T) {
+	srv, c, poolAddr := newMarket(t)
+	tokenPool := srv.AddPool(tensortest.Pool{Owner: owner, CollId: tensortest.CollID, PoolType: "TOKEN", CurveType: "linear", StartingPrice: "1", Delta: "0"})
+
+	cfg := baseConfig(tokenPool)
+	if _, err := newEngine(t, c, cfg).Step(context.Background()); err == nil || !strings.Contains(err.Error(), "want TRADE") {
+		t.Errorf("Step() on token pool error = %v", err)
+	}
+	cfg.PoolAddress = tensortest.Address("missing")
+	if _, err := newEngine(t, c, cfg).Step(context.Background()); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Step() on missing pool error = %v", err)
+	}
+
+	apis := APIs{Collections: c.Collections, NFTs: c.NFTs, User: c.User, TSwap: c.TSwap}
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{"missing owner", func(c *Config) { c.Owner = "" }, "owner, poolAddress and collId are required"},
+		{"zero spread", func(c *Config) { c.SpreadBps = 0 }, "spreadBps"},
+		{"bad curve", func(c *Config) { c.Curve = "log" }, "invalid curve"},
+		{"negative depth", func(c *Config) { c.BidDepth = -1 }, "bidDepth"},
+		{"no limits", func(c *Config) { c.Limits = Limits{} }, "limits must all be > 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := baseConfig(poolAddr)
+			tt.mutate(&cfg)
+			if _, err := New(cfg, apis, cluster.StaticBlockhash("x")); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := New(baseConfig(poolAddr), APIs{}, cluster.StaticBlockhash("x")); err == nil {
+		t.Error("New() without APIs error = nil")
+	}
+	if _, err := New(baseConfig(poolAddr), apis, nil); err == nil {
+		t.Error("New() without blockhash source error = nil")
+	}
+}
+
+func TestTargetCurve(t *testing.T) {
+	e := &Engine{cfg: Config{Curve: pricing.CurveExponential, MmFeeBps: 100}}
+	target := e.targetCurve(money.MustParseSOL("11"), money.MustParseSOL("9"))
+	if target.DeltaBps != 2223 {
+		t.Errorf("exponential delta = %d bps, want 2223", target.DeltaBps)
+	}
+	sells, err := target.SellQuotes(1, pricing.Fees{})
+	if err != nil {
+		t.Fatalf("SellQuotes() error = %v", err)
+	}
+	if sells[0].Price > money.MustParseSOL("9") || sells[0].Price < money.MustParseSOL("8.999") {
+		t.Errorf("first sell = %v, want just under 9 SOL", sells[0].Price)
+	}
+}
+
+func nftDeposit(pool, mint string) *tswap.DepositWithdrawNFTRequest {
+	return &tswap.DepositWithdrawNFTRequest{Action: "DEPOSIT", PoolAddress: pool, Mint: mint, Blockhash: "11111111111111111111111111111111"}
+}
+
+func solDeposit(pool string, amount money.Lamports) *tswap.DepositWithdrawSOLRequest {
+	return &tswap.DepositWithdrawSOLRequest{Action: "DEPOSIT", PoolAddress: pool, Lamports: amount, Blockhash: "11111111111111111111111111111111"}
+}
@@ -0,0 +1,240 @@
+// Package money provides exact amounts for prices and balances: integer
+// Lamports, decimal SOL parsing and formatting, and SPL token Amounts that
+// carry their mint's decimals. Nothing here goes through float64 unless a
+// function says so, so a price of 1.1 SOL is always 1_100_000_000 lamports.
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// SOLDecimals is the number of fractional digits of SOL
+const SOLDecimals = 9
+
+// Lamports is an exact amount of SOL in its smallest unit
+type Lamports int64
+
+// Common amounts
+const (
+	Lamport        Lamports = 1
+	LamportsPerSOL Lamports = 1_000_000_000
+)
+
+// ParseSOL parses a decimal SOL amount such as "1.5" or "0.000000001" exactly.
+// A " SOL" suffix is accepted, so String output round-trips.
+//
+// Returns:
+//   - The amount in lamports
+//   - An error if s is not a decimal number or has more than 9 fractional digits
+func ParseSOL(s string) (Lamports, error) {
+	raw, err := parseDecimal(strings.TrimSuffix(strings.TrimSpace(s), " SOL"), SOLDecimals)
+	if err != nil {
+		return 0, fmt.Errorf("invalid SOL amount %q: %w", s, err)
+	}
+	return Lamports(raw), nil
+}
+
+// MustParseSOL is like ParseSOL but panics on error. Intended for constants and tests.
+func MustParseSOL(s string) Lamports {
+	l, err := ParseSOL(s)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// ParseLamports parses an integer lamport amount such as "1500000000"
+func ParseLamports(s string) (Lamports, error) {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid lamport amount %q", s)
+	}
+	return Lamports(n), nil
+}
+
+// FromSOL converts a floating point SOL amount, rounding to the nearest lamport.
+// Prefer ParseSOL for user input; this exists for values that are already floats.
+func FromSOL(sol float64) Lamports {
+	return Lamports(math.Round(sol * float64(LamportsPerSOL)))
+}
+
+// SOL returns the amount as a decimal SOL string without trailing zeros, e.g. "1.5"
+func (l Lamports) SOL() string {
+	return formatDecimal(int64(l), SOLDecimals)
+}
+
+// Float64 returns the amount in SOL as a float, for display and ratios only
+func (l Lamports) Float64() float64 {
+	return float64(l) / float64(LamportsPerSOL)
+}
+
+// String formats the amount in SOL, e.g. "1.5 SOL"
+func (l Lamports) String() string {
+	return l.SOL() + " SOL"
+}
+
+// MulBps returns l scaled by bps basis points (1/100 of a percent), rounding
+// toward zero, e.g. a 150 bps fee on 2 SOL is 0.03 SOL
+func (l Lamports) MulBps(bps int64) Lamports {
+	return l.MulDiv(bps, 10_000)
+}
+
+// MulDiv returns l*num/den rounded toward zero, without intermediate overflow.
+// It panics if den is zero or the result does not fit in Lamports.
+func (l Lamports) MulDiv(num, den int64) Lamports {
+	if den == 0 {
+		panic("money: division by zero")
+	}
+	r := new(big.Int).Mul(big.NewInt(int64(l)), big.NewInt(num))
+	r.Quo(r, big.NewInt(den))
+	if !r.IsInt64() {
+		panic("money: lamport overflow")
+	}
+	return Lamports(r.Int64())
+}
+
+// MarshalJSON encodes the amount as a JSON integer, the form the Tensor API uses
+func (l Lamports) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(l), 10)), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string of lamports.
+// Numbers in exponent form such as 1.5e9 are accepted when they are whole.
+func (l *Lamports) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		return nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*l = Lamports(n)
+		return nil
+	}
+	f, ok := new(big.Float).SetPrec(128).SetString(s)
+	if !ok {
+		return fmt.Errorf("invalid lamport amount %s", data)
+	}
+	n, acc := f.Int64()
+	if acc != big.Exact {
+		return fmt.Errorf("lamport amount %s is not a whole number", data)
+	}
+	*l = Lamports(n)
+	return nil
+}
+
+// Amount is an exact SPL token amount: Raw base units of a mint with Decimals fractional digits
+type Amount struct {
+	Raw      int64
+	Decimals uint8
+}
+
+// NewAmount creates an Amount from base units
+func NewAmount(raw int64, decimals uint8) Amount {
+	return Amount{Raw: raw, Decimals: decimals}
+}
+
+// ParseAmount parses a decimal token amount such as "12.5" for a mint with the given decimals
+//
+// Returns:
+//   - The amount
+//   - An error if s is not a decimal number or has more fractional digits than decimals
+func ParseAmount(s string, decimals uint8) (Amount, error) {
+	raw, err := parseDecimal(strings.TrimSpace(s), int(decimals))
+	if err != nil {
+		return Amount{}, fmt.Errorf("invalid token amount %q: %w", s, err)
+	}
+	return Amount{Raw: raw, Decimals: decimals}, nil
+}
+
+// String formats the amount as a decimal without trailing zeros, e.g. "12.5"
+func (a Amount) String() string {
+	return formatDecimal(a.Raw, int(a.Decimals))
+}
+
+// Float64 returns the amount as a float, for display and ratios only
+func (a Amount) Float64() float64 {
+	return float64(a.Raw) / math.Pow10(int(a.Decimals))
+}
+
+// Add returns a+b. It panics if the decimals differ, as the amounts are of different mints.
+func (a Amount) Add(b Amount) Amount {
+	if a.Decimals != b.Decimals {
+		panic(fmt.Sprintf("money: adding amounts with %d and %d decimals", a.Decimals, b.Decimals))
+	}
+	return Amount{Raw: a.Raw + b.Raw, Decimals: a.Decimals}
+}
+
+// Lamports converts a wrapped SOL amount to Lamports
+//
+// Returns:
+//   - The amount in lamports
+//   - false if the amount does not have 9 decimals
+func (a Amount) Lamports() (Lamports, bool) {
+	if a.Decimals != SOLDecimals {
+		return 0, false
+	}
+	return Lamports(a.Raw), true
+}
+
+// MarshalJSON encodes the amount as its decimal string, e.g. "12.5"
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// parseDecimal parses a decimal string into an integer scaled by 10^decimals
+func parseDecimal(s string, decimals int) (int64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty amount")
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("no digits")
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > decimals {
+		return 0, fmt.Errorf("more than %d decimal places", decimals)
+	}
+	digits := whole + frac + strings.Repeat("0", decimals-len(frac))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid character %q", c)
+		}
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("out of range")
+	}
+	if neg {
+		n = -n
+	}
+	return n, nil
+}
+
+// formatDecimal formats an integer scaled by 10^decimals without trailing zeros
+func formatDecimal(n int64, decimals int) string {
+	sign := ""
+	u := uint64(n)
+	if n < 0 {
+		sign = "-"
+		u = uint64(-n)
+	}
+	s := strconv.FormatUint(u, 10)
+	if decimals == 0 {
+		return sign + s
+	}
+	if len(s) <= decimals {
+		s = strings.Repeat("0", decimals-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-decimals], strings.TrimRight(s[len(s)-decimals:], "0")
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
@@ -0,0 +1,181 @@
+package money
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/srpvpn/tensor-go-sdk/internal/utils"
+)
+
+func TestParseSOL(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Lamports
+		wantErr string
+	}{
+		{in: "1.5", want: 1_500_000_000},
+		{in: "0.000000001", want: 1},
+		{in: "1.1", want: 1_100_000_000},
+		{in: "42", want: 42_000_000_000},
+		{in: ".25", want: 250_000_000},
+		{in: "3.", want: 3_000_000_000},
+		{in: "-0.5", want: -500_000_000},
+		{in: "2.50 SOL", want: 2_500_000_000},
+		{in: "1.0000000010", want: 1_000_000_001},
+		{in: "0.0000000001", wantErr: "more than 9 decimal places"},
+		{in: "1,5", wantErr: "invalid character"},
+		{in: "", wantErr: "empty amount"},
+		{in: ".", wantErr: "no digits"},
+		{in: "99999999999", wantErr: "out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSOL(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseSOL(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("ParseSOL(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+			}
+			if back, _ := ParseSOL(got.String()); back != got {
+				t.Errorf("ParseSOL(%q) does not round-trip: %d", got.String(), back)
+			}
+		})
+	}
+}
+
+func TestLamports_Format(t *testing.T) {
+	tests := []struct {
+		in   Lamports
+		want string
+	}{
+		{0, "0"},
+		{1, "0.000000001"},
+		{1_500_000_000, "1.5"},
+		{2_000_000_000, "2"},
+		{-1_250_000_000, "-1.25"},
+		{123_456_789_012, "123.456789012"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.SOL(); got != tt.want {
+			t.Errorf("Lamports(%d).SOL() = %q, want %q", int64(tt.in), got, tt.want)
+		}
+	}
+	if got := Lamports(1_500_000_000).String(); got != "1.5 SOL" {
+		t.Errorf("String() = %q, want 1.5 SOL", got)
+	}
+}
+
+func TestFromSOL_RoundsToNearestLamport(t *testing.T) {
+	// 1.001 * 1e9 is 1000999999.9999999 in float64; truncating it lists one lamport short
+	for _, tt := range []struct {
+		in   float64
+		want Lamports
+	}{
+		{1.001, 1_001_000_000},
+		{0.1 + 0.2, 300_000_000},
+		{4.35, 4_350_000_000},
+	} {
+		if got := FromSOL(tt.in); got != tt.want {
+			t.Errorf("FromSOL(%v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLamports_MulBps(t *testing.T) {
+	if got := MustParseSOL("2").MulBps(150); got != MustParseSOL("0.03") {
+		t.Errorf("MulBps(150) = %v, want 0.03 SOL", got)
+	}
+	// No overflow for amounts whose product exceeds int64
+	if got := Lamports(9_000_000_000_000_000_000).MulDiv(3, 4); got != 6_750_000_000_000_000_000 {
+		t.Errorf("MulDiv() = %d", got)
+	}
+}
+
+func TestLamports_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Price Lamports `json:"price"`
+	}{Price: 1_500_000_000})
+	if err != nil || string(data) != `{"price":1500000000}` {
+		t.Fatalf("Marshal() = %s, %v", data, err)
+	}
+
+	tests := []struct {
+		in      string
+		want    Lamports
+		wantErr bool
+	}{
+		{in: `1500000000`, want: 1_500_000_000},
+		{in: `"1500000000"`, want: 1_500_000_000},
+		{in: `1.5e9`, want: 1_500_000_000},
+		{in: `1500000000.0`, want: 1_500_000_000},
+		{in: `null`, want: 0},
+		{in: `1.5`, wantErr: true},
+		{in: `"abc"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var got Lamports
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, %v, want %d (err %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLamports_QueryParamsMatchFloatEncoding(t *testing.T) {
+	// Requests used to carry float64 lamports; the query encoding must not change
+	type oldReq struct {
+		Price float64  `json:"price"`
+		TopUp *float64 `json:"topUp,omitempty"`
+	}
+	type newReq struct {
+		Price Lamports  `json:"price"`
+		TopUp *Lamports `json:"topUp,omitempty"`
+	}
+	topUpOld, topUpNew := 250_000_000.0, Lamports(250_000_000)
+	old, err := utils.BuildQueryParams(&oldReq{Price: 1_500_000_000, TopUp: &topUpOld})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := utils.BuildQueryParams(&newReq{Price: 1_500_000_000, TopUp: &topUpNew})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Encode() != old.Encode() {
+		t.Errorf("query = %s, want %s", got.Encode(), old.Encode())
+	}
+}
+
+func TestAmount(t *testing.T) {
+	usdc, err := ParseAmount("12.5", 6)
+	if err != nil || usdc.Raw != 12_500_000 || usdc.String() != "12.5" {
+		t.Fatalf("ParseAmount(12.5, 6) = %+v, %v", usdc, err)
+	}
+	if _, err := ParseAmount("0.0000001", 6); err == nil {
+		t.Error("ParseAmount() accepted more decimals than the mint has")
+	}
+	if got := usdc.Add(NewAmount(500_000, 6)).String(); got != "13" {
+		t.Errorf("Add() = %s, want 13", got)
+	}
+	if whole := NewAmount(7, 0).String(); whole != "7" {
+		t.Errorf("String() with no decimals = %s", whole)
+	}
+
+	if _, ok := usdc.Lamports(); ok {
+		t.Error("Lamports() converted a 6 decimal amount")
+	}
+	if l, ok := NewAmount(1_000_000_000, SOLDecimals).Lamports(); !ok || l != LamportsPerSOL {
+		t.Errorf("Lamports() = %d, %v", l, ok)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Add() of different mints did not panic")
+		}
+	}()
+	usdc.Add(NewAmount(1, 9))
+}
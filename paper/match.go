@@ -0,0 +1,363 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/pricing"
+)
+
+// Side is the wallet's side of a fill
+type Side string
+
+// Fill sides
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+// Venue is how a fill came about
+type Venue string
+
+// Fill venues
+const (
+	VenueBuyNow    Venue = "buy_now"    // BuyNFT took a live listing
+	VenueAcceptBid Venue = "accept_bid" // SellNFT sold into the best live bid
+	VenueListing   Venue = "listing"    // A live buyer took one of the wallet's listings
+	VenueBid       Venue = "bid"        // A live listing filled one of the wallet's bids
+	VenuePool      Venue = "pool"       // One of the wallet's pools bought or sold
+)
+
+// Fill is one simulated trade
+type Fill struct {
+	Time   time.Time
+	Side   Side
+	Venue  Venue
+	Mint   string
+	CollId string
+	Price  money.Lamports // Trade price: the listing, bid or curve price
+	Fees   money.Lamports // Royalty and taker fee paid by the wallet; zero for maker fills
+	// Amount is the SOL paid on buys or received on sells, fees and MM fees included
+	Amount money.Lamports
+	Order  string // Bid or pool address; empty for listings and taker trades
+	// Realized is the sale's profit over the NFT's cost; zero for buys
+	Realized money.Lamports
+}
+
+// Match fills the wallet's resting orders against the live market. For each
+// collection with open bids, listings or priced pools it reads the cheapest
+// live listings and the best collection bid, then:
+//   - fills each live listing into the highest bid or pool buy quote at or above its price
+//   - fills the cheapest listing or pool sell quote at or under the best bid,
+//     unless the wallet already sold into that bid
+//   - fills the cheapest listing or pool sell quote at or under the previous
+//     Match's cheapest live listing, if that listing has since gone, unless
+//     Config.ObservedTrades is set
+//
+// Expired bids are closed first. The wallet is locked only while filling a
+// collection, not while its live market is read.
+//
+// Returns:
+//   - The fills made by this call
+//   - An error if reading a collection failed; fills made before it are kept
+func (w *Wallet) Match(ctx context.Context) ([]Fill, error) {
+	w.mu.Lock()
+	w.expireBids()
+	active := w.activeCollections()
+	w.mu.Unlock()
+	var fills []Fill
+	for _, collID := range active {
+		got, err := w.matchCollection(ctx, collID)
+		fills = append(fills, got...)
+		if err != nil {
+			return fills, fmt.Errorf("failed to match collection %s: %w", collID, err)
+		}
+	}
+	return fills, nil
+}
+
+// Observe fills the wallet's resting orders against a sale made elsewhere,
+// such as a recorded transaction, as if the wallet's orders had been in the
+// market at the time. A buyer (a buy now or pool buy) takes the wallet's
+// cheapest listing or pool sell quote at or under the sale price; a seller
+// (an accepted bid or pool sell) hits the wallet's best matching bid or pool
+// buy quote at or above it. Other transaction types are ignored.
+//
+// Returns:
+//   - The fill, if the sale filled one of the wallet's orders
+func (w *Wallet) Observe(tx market.Transaction) []Fill {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expireBids()
+	before := len(w.fills)
+	switch tx.TxType {
+	case "SALE_BUY_NOW", "SWAP_BUY_NFT", "SWAP_BUY_SINGLE_LISTING":
+		w.fillBuyer(tx.CollId, tx.Price)
+	case "SALE_ACCEPT_BID", "SWAP_SELL_NFT":
+		if !w.taken[tx.Mint] {
+			w.fillListing(market.NFT{Mint: tx.Mint, CollId: tx.CollId, Owner: tx.Seller, Listing: &market.Listing{Price: tx.Price, Seller: tx.Seller}})
+		}
+	}
+	return slices.Clone(w.fills[before:])
+}
+
+// activeCollections returns the collections with resting orders, sorted
+func (w *Wallet) activeCollections() []string {
+	seen := make(map[string]bool)
+	for _, o := range w.bids {
+		seen[o.bid.CollId] = true
+	}
+	for _, h := range w.holdings {
+		if h.listed != 0 {
+			seen[h.nft.CollId] = true
+		}
+	}
+	for _, p := range w.pools {
+		if p.priced {
+			seen[p.collID] = true
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for id := range seen {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// matchCollection reads one collection's live market, then fills its orders
+func (w *Wallet) matchCollection(ctx context.Context, collID string) ([]Fill, error) {
+	listings, err := w.liveListings(ctx, collID)
+	if err != nil {
+		return nil, err
+	}
+	coll, err := market.Collection(ctx, w.apis.Collections, collID)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expireBids()
+	before := len(w.fills)
+	for _, n := range listings {
+		if !w.taken[n.Mint] {
+			w.fillListing(n)
+		}
+	}
+	if bid, ok := w.liveBid(collID, coll.Stats); ok && w.fillBuyer(collID, bid) {
+		w.hitBids[collID] = bid
+	}
+	if prev, ok := w.floors[collID]; ok && !w.cfg.ObservedTrades && !slices.ContainsFunc(listings, func(n market.NFT) bool { return n.Mint == prev.Mint }) {
+		w.fillBuyer(collID, prev.Listing.Price)
+	}
+
+	delete(w.floors, collID)
+	for _, n := range listings {
+		if !w.taken[n.Mint] {
+			w.floors[collID] = n
+			break
+		}
+	}
+	return slices.Clone(w.fills[before:]), nil
+}
+
+// liveListings reads the cheapest live listings of a collection not sold by the wallet
+func (w *Wallet) liveListings(ctx context.Context, collID string) ([]market.NFT, error) {
+	onlyListings := true
+	data, _, err := w.apis.NFTs.GetNFTsByCollection(ctx, &nfts.NFTsByCollectionRequest{
+		CollId:        collID,
+		SortBy:        "PriceAsc",
+		Limit:         int32(w.cfg.MatchDepth),
+		OnlyListings:  &onlyListings,
+		ExcludeOwners: []string{w.cfg.Wallet},
+	})
+	if err != nil {
+		return nil, err
+	}
+	items, _, err := market.DecodeNFTs(data)
+	if err != nil {
+		return nil, err
+	}
+	out := items[:0]
+	for _, n := range items {
+		if n.Listing != nil && n.Listing.Seller != w.cfg.Wallet {
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+// fillListing sells live listing n into the wallet's best bid or pool buy quote at or above its price
+func (w *Wallet) fillListing(n market.NFT) {
+	if _, held := w.holdings[n.Mint]; held {
+		return
+	}
+	var best *order
+	for _, o := range w.sortedBids() {
+		if o.bid.Price >= n.Listing.Price && bidMatches(o.bid, n) && (best == nil || o.bid.Price > best.bid.Price) {
+			best = o
+		}
+	}
+	var bestPool *pool
+	var bestQuote pricing.Quote
+	for _, p := range w.sortedPools() {
+		q, ok := p.nextSell()
+		if ok && p.collID == n.CollId && q.Price >= n.Listing.Price && -q.PoolChange <= p.sol && (bestPool == nil || q.Price > bestQuote.Price) {
+			bestPool, bestQuote = p, q
+		}
+	}
+
+	switch {
+	case best != nil && (bestPool == nil || best.bid.Price >= bestQuote.Price):
+		price := best.bid.Price
+		w.locked -= price
+		best.bid.FilledQuantity++
+		if best.bid.Remaining() == 0 {
+			delete(w.bids, best.bid.Address)
+		}
+		w.acquire(n, price, "")
+		w.record(Fill{Side: Buy, Venue: VenueBid, Mint: n.Mint, CollId: n.CollId, Price: price, Amount: price, Order: best.bid.Address})
+	case bestPool != nil:
+		cost := -bestQuote.PoolChange
+		bestPool.sol -= cost
+		bestPool.curve.Offset--
+		bestPool.nfts = append(bestPool.nfts, n.Mint)
+		w.acquire(n, cost, bestPool.address)
+		w.record(Fill{Side: Buy, Venue: VenuePool, Mint: n.Mint, CollId: n.CollId, Price: bestQuote.Price, Amount: cost, Order: bestPool.address})
+	default:
+		return
+	}
+	w.taken[n.Mint] = true
+}
+
+// liveBid returns the collection's best live bid from its stats, unless the
+// wallet already sold into it. A different best bid means the one sold into is gone.
+func (w *Wallet) liveBid(collID string, stats collections.CollectionStats) (money.Lamports, bool) {
+	price, ok := market.BestBid(stats)
+	if hit, seen := w.hitBids[collID]; seen {
+		if ok && hit == price {
+			return 0, false
+		}
+		delete(w.hitBids, collID)
+	}
+	return price, ok
+}
+
+// fillBuyer sells the wallet's cheapest listing or pool sell quote at or under
+// price to a live buyer, reporting whether anything filled
+func (w *Wallet) fillBuyer(collID string, price money.Lamports) bool {
+	var best *position
+	for _, h := range w.sortedHoldings() {
+		if h.nft.CollId == collID && h.listed != 0 && h.listed <= price && (best == nil || h.listed < best.listed) {
+			best = h
+		}
+	}
+	var bestPool *pool
+	var bestQuote pricing.Quote
+	for _, p := range w.sortedPools() {
+		q, ok := p.nextBuy()
+		if ok && p.collID == collID && q.Price <= price && (bestPool == nil || q.Price < bestQuote.Price) {
+			bestPool, bestQuote = p, q
+		}
+	}
+
+	switch {
+	case best != nil && (bestPool == nil || best.listed <= bestQuote.Price):
+		proceeds := best.listed
+		w.cash += proceeds
+		realized := w.release(best, proceeds)
+		w.record(Fill{Side: Sell, Venue: VenueListing, Mint: best.nft.Mint, CollId: collID, Price: proceeds, Amount: proceeds, Realized: realized})
+	case bestPool != nil:
+		mint := bestPool.nfts[0]
+		bestPool.nfts = bestPool.nfts[1:]
+		bestPool.sol += bestQuote.PoolChange
+		bestPool.curve.Offset++
+		realized := w.release(w.holdings[mint], bestQuote.PoolChange)
+		w.record(Fill{Side: Sell, Venue: VenuePool, Mint: mint, CollId: collID, Price: bestQuote.Price, Amount: bestQuote.PoolChange, Order: bestPool.address, Realized: realized})
+	default:
+		return false
+	}
+	return true
+}
+
+// nextSell quotes the next NFT a taker can sell to the pool
+func (p *pool) nextSell() (pricing.Quote, bool) {
+	if !p.priced || p.curve.Type == pricing.PoolNFT {
+		return pricing.Quote{}, false
+	}
+	quotes, err := p.curve.SellQuotes(1, pricing.Fees{})
+	if err != nil || len(quotes) == 0 {
+		return pricing.Quote{}, false
+	}
+	return quotes[0], true
+}
+
+// nextBuy quotes the next NFT a taker can buy from the pool
+func (p *pool) nextBuy() (pricing.Quote, bool) {
+	if !p.priced || p.curve.Type == pricing.PoolToken || len(p.nfts) == 0 {
+		return pricing.Quote{}, false
+	}
+	quotes, err := p.curve.BuyQuotes(1, pricing.Fees{})
+	if err != nil || len(quotes) == 0 {
+		return pricing.Quote{}, false
+	}
+	return quotes[0], true
+}
+
+// bidMatches reports whether n is what bid asks for
+func bidMatches(bid market.Bid, n market.NFT) bool {
+	switch bid.Target {
+	case market.BidTargetNFT:
+		return bid.TargetId == n.Mint
+	case market.BidTargetTrait:
+		if bid.CollId != n.CollId {
+			return false
+		}
+		for _, trait := range bid.Traits {
+			if !slices.ContainsFunc(n.Attributes, func(a market.Attribute) bool { return a.TraitType+":"+a.Value == trait }) {
+				return false
+			}
+		}
+		return true
+	default:
+		return bid.CollId == n.CollId
+	}
+}
+
+// sortedBids returns the open bids in address order, so fills are deterministic
+func (w *Wallet) sortedBids() []*order {
+	out := make([]*order, 0, len(w.bids))
+	for _, o := range w.bids {
+		out = append(out, o)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].bid.Address < out[j].bid.Address })
+	return out
+}
+
+// sortedPools returns the pools in address order
+func (w *Wallet) sortedPools() []*pool {
+	out := make([]*pool, 0, len(w.pools))
+	for _, p := range w.pools {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].address < out[j].address })
+	return out
+}
+
+// sortedHoldings returns the holdings in mint order
+func (w *Wallet) sortedHoldings() []*position {
+	out := make([]*position, 0, len(w.holdings))
+	for _, h := range w.holdings {
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].nft.Mint < out[j].nft.Mint })
+	return out
+}
@@ -0,0 +1,736 @@
+// Package paper is a paper-trading sandbox for strategies. Wallet implements
+// marketplace.MarketplaceAPI and tswap.TSwapAPI against a simulated wallet:
+// purchases, sales, listings, bids and pool edits change simulated balances,
+// holdings, open orders and pools instead of building transactions. Market
+// data still comes from the live (or recorded) NFTs and collections APIs, so
+// a strategy sees the real market while trading with play money.
+//
+// Taker trades fill immediately against live data: BuyNFT buys the live
+// listing of the mint, SellNFT sells into the collection's best live bid.
+// Resting orders fill when Match is called: open bids and pool buy quotes
+// fill against live listings at or under their price, while open listings and
+// pool sell quotes fill when a live buyer shows up, that is when the best
+// collection bid reaches them or the cheapest live listing seen by the
+// previous Match is gone. Every live listing fills at most one simulated
+// order, and so does every best collection bid: once the wallet sells into it,
+// SellNFT and Match leave the collection alone until the best bid changes.
+// Observe fills resting orders against sales seen elsewhere, such as recorded
+// transactions replayed by a backtest.
+//
+// The taker pays the royalty and taker fee in Config.Fees; maker orders fill
+// at their own price. PnL marks holdings at the live floor.
+//
+// NewClient swaps the simulated APIs into a client.Client, so moving a
+// strategy between paper and live trading is a single constructor change.
+package paper
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/tswap"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	apierrors "github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/internal/utils"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/pricing"
+)
+
+var (
+	_ marketplace.MarketplaceAPI = (*Wallet)(nil)
+	_ tswap.TSwapAPI             = (*Wallet)(nil)
+)
+
+// DefaultMatchDepth is how many live listings per collection Match reads when Config.MatchDepth is zero
+const DefaultMatchDepth = 50
+
+// DefaultFees are the taker charges used when Config.Fees is nil
+var DefaultFees = pricing.Fees{TakerFeeBps: 200}
+
+// Holding is an NFT in the simulated wallet
+type Holding struct {
+	Mint   string
+	CollId string
+	Cost   money.Lamports // What was paid for the NFT, fees included
+	Listed money.Lamports // List price; zero when the NFT is not listed
+	Pool   string         // Address of the pool holding the NFT; empty when it is in the wallet
+}
+
+// Pool is a simulated TSwap pool
+type Pool struct {
+	Address string
+	CollId  string
+	Curve   pricing.Pool // Zero until the first EditTSwapPool
+	SOL     money.Lamports
+	NFTs    []string
+}
+
+// Config configures a Wallet
+type Config struct {
+	Wallet  string         // Address the strategy trades as; requests for other wallets are rejected
+	Balance money.Lamports // Starting SOL
+
+	// Holdings are the starting NFTs. Only Mint, CollId and Cost are read.
+	Holdings []Holding
+	// Pools are the TSwap pools the strategy may edit and fund. Only Address
+	// and CollId are read; the pools start empty and unpriced.
+	Pools []Pool
+
+	// Fees are charged to the taker of every trade. Defaults to DefaultFees.
+	Fees *pricing.Fees
+	// Royalties overrides Fees.RoyaltyBps per collection ID
+	Royalties map[string]int64
+	// MatchDepth is how many live listings per collection Match reads
+	MatchDepth int
+	// ObservedTrades tells Match that real sales are fed to Observe, so it
+	// no longer treats a vanished floor listing as a buyer
+	ObservedTrades bool
+
+	// Clock returns the simulation time. Defaults to time.Now.
+	Clock func() time.Time
+
+	// Logger receives one event per fill. Nothing is logged when nil.
+	Logger *slog.Logger
+}
+
+// validate checks the config fields that have no default
+func (c *Config) validate() error {
+	if err := utils.ValidateWalletAddress(c.Wallet); err != nil {
+		return fmt.Errorf("invalid wallet: %w", err)
+	}
+	if c.Balance < 0 {
+		return fmt.Errorf("balance must be >= 0")
+	}
+	if c.MatchDepth < 0 {
+		return fmt.Errorf("matchDepth must be >= 0")
+	}
+	if c.Fees != nil {
+		if err := c.Fees.Validate(); err != nil {
+			return err
+		}
+	}
+	for collID, bps := range c.Royalties {
+		if bps < 0 || bps > 10_000 {
+			return fmt.Errorf("royalty of %s must be between 0 and 10000 basis points", collID)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, h := range c.Holdings {
+		if h.Mint == "" || h.CollId == "" {
+			return fmt.Errorf("holdings need a mint and collId")
+		}
+		if seen[h.Mint] {
+			return fmt.Errorf("duplicate holding %s", h.Mint)
+		}
+		seen[h.Mint] = true
+	}
+	for _, p := range c.Pools {
+		if p.Address == "" || p.CollId == "" {
+			return fmt.Errorf("pools need an address and collId")
+		}
+		if seen[p.Address] {
+			return fmt.Errorf("duplicate pool %s", p.Address)
+		}
+		seen[p.Address] = true
+	}
+	return nil
+}
+
+// APIs are the live SDK services the wallet reads market data from. The fields match those of client.Client.
+type APIs struct {
+	NFTs        nfts.NFTsAPI
+	Collections collections.CollectionsAPI
+}
+
+// Wallet is a simulated wallet that implements the marketplace and TSwap
+// APIs. It is safe for concurrent use; changes to the wallet are serialized,
+// but live market reads run outside the lock so a slow API blocks only its caller.
+type Wallet struct {
+	cfg    Config
+	apis   APIs
+	fees   pricing.Fees
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu       sync.Mutex
+	cash     money.Lamports // SOL in the wallet, not locked in bids or pools
+	locked   money.Lamports // SOL reserved by open bids
+	start    money.Lamports // Starting equity
+	holdings map[string]*position
+	bids     map[string]*order
+	bidSeq   int
+	pools    map[string]*pool
+	taken    map[string]bool           // Live listings already filled against
+	hitBids  map[string]money.Lamports // Best live bid per collection already sold into
+	floors   map[string]market.NFT     // Cheapest live listing per collection at the last Match
+	fills    []Fill
+	realized money.Lamports
+	paid     money.Lamports // Royalties and taker fees
+}
+
+// position is an NFT held by the wallet
+type position struct {
+	nft    market.NFT
+	cost   money.Lamports
+	listed money.Lamports
+	pool   string
+}
+
+// order is an open bid
+type order struct {
+	bid       market.Bid
+	expiresAt time.Time // Zero if the bid does not expire
+}
+
+// pool is a simulated TSwap pool
+type pool struct {
+	address string
+	collID  string
+	curve   pricing.Pool
+	priced  bool
+	sol     money.Lamports
+	nfts    []string
+}
+
+// New creates a Wallet
+//
+// Returns:
+//   - The wallet
+//   - An error if the config is invalid or a required API is nil
+func New(cfg Config, apis APIs) (*Wallet, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if apis.NFTs == nil || apis.Collections == nil {
+		return nil, fmt.Errorf("NFTs and collections APIs are required")
+	}
+	fees := DefaultFees
+	if cfg.Fees != nil {
+		fees = *cfg.Fees
+	}
+	if cfg.MatchDepth == 0 {
+		cfg.MatchDepth = DefaultMatchDepth
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	w := &Wallet{
+		cfg:      cfg,
+		apis:     apis,
+		fees:     fees,
+		logger:   logger.With("wallet", cfg.Wallet),
+		now:      cfg.Clock,
+		cash:     cfg.Balance,
+		start:    cfg.Balance,
+		holdings: make(map[string]*position),
+		bids:     make(map[string]*order),
+		pools:    make(map[string]*pool),
+		taken:    make(map[string]bool),
+		hitBids:  make(map[string]money.Lamports),
+		floors:   make(map[string]market.NFT),
+	}
+	for _, h := range cfg.Holdings {
+		w.holdings[h.Mint] = &position{nft: market.NFT{Mint: h.Mint, CollId: h.CollId, Owner: cfg.Wallet}, cost: h.Cost}
+		w.start += h.Cost
+	}
+	for _, p := range cfg.Pools {
+		w.pools[p.Address] = &pool{address: p.Address, collID: p.CollId}
+	}
+	return w, nil
+}
+
+// NewClient returns a copy of live whose Marketplace and TSwap APIs trade
+// against a new simulated wallet and whose User API reports that wallet's
+// holdings, listings, bids and pools. Every other read goes to live.
+//
+// Returns:
+//   - The paper client
+//   - The wallet, for Match and PnL
+//   - An error if the config is invalid
+func NewClient(live *client.Client, cfg Config) (*client.Client, *Wallet, error) {
+	if live == nil {
+		return nil, nil, fmt.Errorf("live client is required")
+	}
+	w, err := New(cfg, APIs{NFTs: live.NFTs, Collections: live.Collections})
+	if err != nil {
+		return nil, nil, err
+	}
+	c := *live
+	c.Marketplace = w
+	c.TSwap = w
+	c.User = w.UserAPI(live.User)
+	return &c, w, nil
+}
+
+// Balance returns the wallet's free SOL and the SOL locked in open bids
+func (w *Wallet) Balance() (free, locked money.Lamports) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.cash, w.locked
+}
+
+// Holdings returns the NFTs the wallet holds, sorted by mint
+func (w *Wallet) Holdings() []Holding {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make([]Holding, 0, len(w.holdings))
+	for _, p := range w.sortedHoldings() {
+		out = append(out, Holding{Mint: p.nft.Mint, CollId: p.nft.CollId, Cost: p.cost, Listed: p.listed, Pool: p.pool})
+	}
+	return out
+}
+
+// Bids returns the wallet's open bids, sorted by address
+func (w *Wallet) Bids() []market.Bid {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expireBids()
+	out := make([]market.Bid, 0, len(w.bids))
+	for _, o := range w.sortedBids() {
+		out = append(out, o.bid)
+	}
+	return out
+}
+
+// Pools returns the wallet's pools, sorted by address
+func (w *Wallet) Pools() []Pool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make([]Pool, 0, len(w.pools))
+	for _, p := range w.sortedPools() {
+		out = append(out, Pool{Address: p.address, CollId: p.collID, Curve: p.curve, SOL: p.sol, NFTs: append([]string(nil), p.nfts...)})
+	}
+	return out
+}
+
+// Fills returns every fill so far, oldest first
+func (w *Wallet) Fills() []Fill {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Fill(nil), w.fills...)
+}
+
+// BuyNFT buys the live listing of req.Mint if its price is at most req.MaxPrice
+func (w *Wallet) BuyNFT(ctx context.Context, req *marketplace.BuyNFTRequest) (*marketplace.BuyNFTResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	if err := w.checkWallet(req.Buyer); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	n, err := w.liveNFT(ctx, req.Mint)
+	if err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.holdings[req.Mint]; ok {
+		return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "mint %s is already held", req.Mint)
+	}
+	if n == nil || n.Listing == nil || w.taken[n.Mint] {
+		return nil, http.StatusNotFound, failure(http.StatusNotFound, "mint %s is not listed", req.Mint)
+	}
+	price := n.Listing.Price
+	if price > req.MaxPrice {
+		return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "listing price %s exceeds max price %s", price.SOL(), req.MaxPrice.SOL())
+	}
+	fees := w.takerFees(n.CollId, price)
+	if price+fees > w.cash {
+		return nil, http.StatusBadRequest, w.insufficient(price + fees)
+	}
+	w.taken[n.Mint] = true
+	w.cash -= price + fees
+	w.paid += fees
+	w.acquire(*n, price+fees, "")
+	w.record(Fill{Side: Buy, Venue: VenueBuyNow, Mint: n.Mint, CollId: n.CollId, Price: price, Fees: fees, Amount: price + fees})
+	return &marketplace.BuyNFTResponse{}, http.StatusOK, nil
+}
+
+// SellNFT sells req.Mint into the collection's best live bid if it is at least req.MinPrice
+func (w *Wallet) SellNFT(ctx context.Context, req *marketplace.SellNFTRequest) (*marketplace.SellNFTResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	if err := w.checkWallet(req.Seller); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	w.mu.Lock()
+	p, status, err := w.unlistedNFT(req.Mint)
+	w.mu.Unlock()
+	if err != nil {
+		return nil, status, err
+	}
+	coll, err := market.Collection(ctx, w.apis.Collections, p.nft.CollId)
+	if err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// The NFT may have been sold or listed while the bid was read
+	if p, status, err = w.unlistedNFT(req.Mint); err != nil {
+		return nil, status, err
+	}
+	price, ok := w.liveBid(p.nft.CollId, coll.Stats)
+	if !ok || price < req.MinPrice {
+		return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "no bid at or above %s", req.MinPrice.SOL())
+	}
+	fees := w.takerFees(p.nft.CollId, price)
+	w.hitBids[p.nft.CollId] = price
+	w.cash += price - fees
+	w.paid += fees
+	realized := w.release(p, price-fees)
+	w.record(Fill{Side: Sell, Venue: VenueAcceptBid, Mint: p.nft.Mint, CollId: p.nft.CollId, Price: price, Fees: fees, Amount: price - fees, Realized: realized})
+	return &marketplace.SellNFTResponse{}, http.StatusOK, nil
+}
+
+// ListNFT lists a held NFT. Match fills the listing.
+func (w *Wallet) ListNFT(_ context.Context, req *marketplace.ListNFTRequest) (*marketplace.ListNFTResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkWallet(req.Owner); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	p, status, err := w.walletNFT(req.Mint)
+	if err != nil {
+		return nil, status, err
+	}
+	if p.listed != 0 {
+		return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "mint %s is already listed", req.Mint)
+	}
+	p.listed = req.Price
+	return &marketplace.ListNFTResponse{}, http.StatusOK, nil
+}
+
+// DelistNFT removes a listing
+func (w *Wallet) DelistNFT(_ context.Context, req *marketplace.DelistNFTRequest) (*marketplace.DelistNFTResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkWallet(req.Owner); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	p, status, err := w.listing(req.Mint)
+	if err != nil {
+		return nil, status, err
+	}
+	p.listed = 0
+	return &marketplace.DelistNFTResponse{}, http.StatusOK, nil
+}
+
+// EditListing changes the price of a listing
+func (w *Wallet) EditListing(_ context.Context, req *marketplace.EditListingRequest) (*marketplace.EditListingResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkWallet(req.Owner); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	p, status, err := w.listing(req.Mint)
+	if err != nil {
+		return nil, status, err
+	}
+	p.listed = req.Price
+	return &marketplace.EditListingResponse{}, http.StatusOK, nil
+}
+
+// PlaceNFTBid places a bid on one mint, locking its price
+func (w *Wallet) PlaceNFTBid(ctx context.Context, req *marketplace.PlaceNFTBidRequest) (*marketplace.PlaceNFTBidResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	if err := w.checkWallet(req.Owner); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	n, err := w.liveNFT(ctx, req.Mint)
+	if err != nil {
+		return nil, 0, err
+	}
+	if n == nil {
+		return nil, http.StatusNotFound, failure(http.StatusNotFound, "mint %s not found", req.Mint)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	bid := market.Bid{Target: market.BidTargetNFT, TargetId: req.Mint, CollId: n.CollId, Price: req.Price, Quantity: 1, SharedEscrow: deref(req.UseSharedEscrow)}
+	address, status, err := w.placeBid(bid, req.ExpireIn)
+	if err != nil {
+		return nil, status, err
+	}
+	return &marketplace.PlaceNFTBidResponse{Message: "paper bid " + address + " placed"}, http.StatusOK, nil
+}
+
+// PlaceTraitBid places a trait bid, locking its price times its quantity
+func (w *Wallet) PlaceTraitBid(_ context.Context, req *marketplace.PlaceTraitBidRequest) (*marketplace.PlaceTraitBidResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkWallet(req.Owner); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	bid := market.Bid{Target: market.BidTargetTrait, TargetId: req.CollId, CollId: req.CollId, Price: req.Price, Quantity: req.Quantity,
+		Traits: append([]string(nil), req.Traits...), SharedEscrow: deref(req.UseSharedEscrow)}
+	address, status, err := w.placeBid(bid, req.ExpireIn)
+	if err != nil {
+		return nil, status, err
+	}
+	return &marketplace.PlaceTraitBidResponse{Message: "paper bid " + address + " placed"}, http.StatusOK, nil
+}
+
+// PlaceCollectionBid places a collection bid, locking its price times its quantity
+func (w *Wallet) PlaceCollectionBid(_ context.Context, req *marketplace.PlaceCollectionBidRequest) (*marketplace.PlaceCollectionBidResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkWallet(req.Owner); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	bid := market.Bid{Target: market.BidTargetCollection, TargetId: req.CollId, CollId: req.CollId, Price: req.Price, Quantity: req.Quantity,
+		SharedEscrow: deref(req.UseSharedEscrow)}
+	address, status, err := w.placeBid(bid, req.ExpireIn)
+	if err != nil {
+		return nil, status, err
+	}
+	return &marketplace.PlaceCollectionBidResponse{Message: "paper bid " + address + " placed"}, http.StatusOK, nil
+}
+
+// EditBid changes the price, quantity, expiry or escrow setting of an open bid, relocking its SOL
+func (w *Wallet) EditBid(_ context.Context, req *marketplace.EditBidRequest) (*marketplace.EditBidResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expireBids()
+	o, ok := w.bids[req.BidStateAddress]
+	if !ok {
+		return nil, http.StatusNotFound, failure(http.StatusNotFound, "bid %s not found", req.BidStateAddress)
+	}
+	edited := o.bid
+	if req.Price != nil {
+		edited.Price = *req.Price
+	}
+	if req.Quantity != nil {
+		if *req.Quantity < edited.FilledQuantity {
+			return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "quantity %d is under the %d already filled", *req.Quantity, edited.FilledQuantity)
+		}
+		edited.Quantity = *req.Quantity
+	}
+	if req.UseSharedEscrow != nil {
+		edited.SharedEscrow = *req.UseSharedEscrow
+	}
+	if edited.Price <= 0 || edited.Remaining() <= 0 {
+		return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "bid needs a positive price and quantity")
+	}
+	if delta := edited.Exposure() - o.bid.Exposure(); delta > w.cash {
+		return nil, http.StatusBadRequest, w.insufficient(delta)
+	}
+	w.unlock(o.bid.Exposure())
+	w.lock(edited.Exposure())
+	o.bid = edited
+	if req.ExpireIn != nil {
+		o.expiresAt = w.expiry(req.ExpireIn)
+		o.bid.ExpiresAt = unix(o.expiresAt)
+	}
+	return &marketplace.EditBidResponse{BidState: o.bid.Address}, http.StatusOK, nil
+}
+
+// CancelBid cancels an open bid and unlocks its SOL
+func (w *Wallet) CancelBid(_ context.Context, req *marketplace.CancelBidRequest) (*marketplace.CancelBidResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expireBids()
+	o, ok := w.bids[req.BidStateAddress]
+	if !ok {
+		return nil, http.StatusNotFound, failure(http.StatusNotFound, "bid %s not found", req.BidStateAddress)
+	}
+	w.unlock(o.bid.Exposure())
+	delete(w.bids, req.BidStateAddress)
+	return &marketplace.CancelBidResponse{BidState: o.bid.Address}, http.StatusOK, nil
+}
+
+// placeBid locks the bid's SOL and opens it under a new address
+func (w *Wallet) placeBid(bid market.Bid, expireIn *int32) (string, int, error) {
+	w.expireBids()
+	if bid.Price <= 0 || bid.Quantity <= 0 {
+		return "", http.StatusBadRequest, failure(http.StatusBadRequest, "bid needs a positive price and quantity")
+	}
+	if bid.Exposure() > w.cash {
+		return "", http.StatusBadRequest, w.insufficient(bid.Exposure())
+	}
+	w.bidSeq++
+	sum := sha256.Sum256([]byte(fmt.Sprintf("paper-bid:%s:%d", w.cfg.Wallet, w.bidSeq)))
+	bid.Address = solana.PublicKeyFromBytes(sum[:]).String()
+	bid.Owner = w.cfg.Wallet
+	o := &order{bid: bid, expiresAt: w.expiry(expireIn)}
+	o.bid.ExpiresAt = unix(o.expiresAt)
+	w.bids[bid.Address] = o
+	w.lock(bid.Exposure())
+	return bid.Address, http.StatusOK, nil
+}
+
+// expireBids closes expired bids and unlocks their SOL
+func (w *Wallet) expireBids() {
+	now := w.now()
+	for address, o := range w.bids {
+		if !o.expiresAt.IsZero() && !now.Before(o.expiresAt) {
+			w.unlock(o.bid.Exposure())
+			delete(w.bids, address)
+		}
+	}
+}
+
+// expiry returns when a bid placed now with expireIn seconds expires; zero if it never does
+func (w *Wallet) expiry(expireIn *int32) time.Time {
+	if expireIn == nil || *expireIn <= 0 {
+		return time.Time{}
+	}
+	return w.now().Add(time.Duration(*expireIn) * time.Second)
+}
+
+func (w *Wallet) lock(amount money.Lamports) {
+	w.cash -= amount
+	w.locked += amount
+}
+
+func (w *Wallet) unlock(amount money.Lamports) {
+	w.cash += amount
+	w.locked -= amount
+}
+
+// liveNFT reads a mint from the live NFTs API; nil if it does not exist
+func (w *Wallet) liveNFT(ctx context.Context, mint string) (*market.NFT, error) {
+	data, _, err := w.apis.NFTs.GetNFTsInfo(ctx, &nfts.NFTsInfoRequest{Mints: []string{mint}})
+	if err != nil {
+		return nil, err
+	}
+	items, _, err := market.DecodeNFTs(data)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if items[i].Mint == mint {
+			return &items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// unlistedNFT returns a held NFT that is in the wallet and not listed
+func (w *Wallet) unlistedNFT(mint string) (*position, int, error) {
+	p, status, err := w.walletNFT(mint)
+	if err != nil {
+		return nil, status, err
+	}
+	if p.listed != 0 {
+		return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "mint %s is listed", mint)
+	}
+	return p, http.StatusOK, nil
+}
+
+// walletNFT returns a held NFT that is in the wallet rather than a pool
+func (w *Wallet) walletNFT(mint string) (*position, int, error) {
+	p, ok := w.holdings[mint]
+	if !ok {
+		return nil, http.StatusNotFound, failure(http.StatusNotFound, "mint %s is not held", mint)
+	}
+	if p.pool != "" {
+		return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "mint %s is in pool %s", mint, p.pool)
+	}
+	return p, http.StatusOK, nil
+}
+
+// listing returns a held NFT that is listed
+func (w *Wallet) listing(mint string) (*position, int, error) {
+	p, ok := w.holdings[mint]
+	if !ok || p.listed == 0 {
+		return nil, http.StatusNotFound, failure(http.StatusNotFound, "mint %s is not listed", mint)
+	}
+	return p, http.StatusOK, nil
+}
+
+// acquire adds an NFT to the wallet, or to pool when set
+func (w *Wallet) acquire(n market.NFT, cost money.Lamports, pool string) {
+	n.Owner, n.Listing = w.cfg.Wallet, nil
+	w.holdings[n.Mint] = &position{nft: n, cost: cost, pool: pool}
+}
+
+// release removes a sold NFT and books the realized PnL of selling it for proceeds
+func (w *Wallet) release(p *position, proceeds money.Lamports) money.Lamports {
+	delete(w.holdings, p.nft.Mint)
+	realized := proceeds - p.cost
+	w.realized += realized
+	return realized
+}
+
+// record stamps and stores a fill
+func (w *Wallet) record(f Fill) {
+	f.Time = w.now()
+	w.fills = append(w.fills, f)
+	w.logger.Info("paper fill", "side", f.Side, "venue", f.Venue, "mint", f.Mint, "collection", f.CollId, "price", f.Price.SOL(), "order", f.Order)
+}
+
+// takerFees returns the royalty and taker fee on a trade in collID at price
+func (w *Wallet) takerFees(collID string, price money.Lamports) money.Lamports {
+	royalty, ok := w.cfg.Royalties[collID]
+	if !ok {
+		royalty = w.fees.RoyaltyBps
+	}
+	return price.MulBps(royalty) + price.MulBps(w.fees.TakerFeeBps)
+}
+
+// checkWallet rejects requests made for another wallet
+func (w *Wallet) checkWallet(wallet string) error {
+	if wallet != w.cfg.Wallet {
+		return failure(http.StatusBadRequest, "paper wallet is %s, request is for %s", w.cfg.Wallet, wallet)
+	}
+	return nil
+}
+
+func (w *Wallet) insufficient(need money.Lamports) error {
+	return failure(http.StatusBadRequest, "insufficient balance: need %s SOL, have %s SOL", need.SOL(), w.cash.SOL())
+}
+
+// failure builds the API error the live service would return
+func failure(code int, format string, args ...any) error {
+	return &apierrors.APIError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func deref(b *bool) bool {
+	return b != nil && *b
+}
+
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,604 @@
+package paper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/tswap"
+	"github.com/srpvpn/tensor-go-sdk/bidladder"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	apierrors "github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/pricing"
+	"github.com/srpvpn/tensor-go-sdk/tensortest"
+)
+
+var (
+	trader = tensortest.Address("trader")
+	seller = tensortest.Address("seller")
+	rival  = tensortest.Address("rival")
+	start  = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func mint(name string) string { return tensortest.Address("mint-" + name) }
+
+// listing seeds a live NFT listed by seller
+func listing(name, price string, attrs ...tensortest.Attribute) tensortest.NFT {
+	return tensortest.NFT{Mint: mint(name), CollId: tensortest.CollID, Owner: seller, Attributes: attrs, Listing: &tensortest.Listing{Price: tensortest.Lamports(price)}}
+}
+
+// newTestWallet returns a live market and a paper client trading as trader
+func newTestWallet(t *testing.T, cfg Config, nfts ...tensortest.NFT) (*tensortest.Server, *client.Client, *Wallet) {
+	t.Helper()
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID, Name: "Paper"})
+	for _, n := range nfts {
+		srv.AddNFT(n)
+	}
+	cfg.Wallet = trader
+	if cfg.Fees == nil {
+		cfg.Fees = &pricing.Fees{}
+	}
+	c, w, err := NewClient(client.New(&client.Config{BaseURL: srv.URL}), cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	clock := start
+	w.now = func() time.Time { return clock }
+	return srv, c, w
+}
+
+func match(t *testing.T, w *Wallet) []Fill {
+	t.Helper()
+	fills, err := w.Match(context.Background())
+	if err != nil {
+		t.Fatalf("Match() error = %v", err)
+	}
+	return fills
+}
+
+func apiCode(err error) int {
+	var apiErr *apierrors.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
+
+func TestBuyAndSellAsTaker(t *testing.T) {
+	ctx := context.Background()
+	srv, c, w := newTestWallet(t, Config{Balance: money.MustParseSOL("3"), Fees: &pricing.Fees{RoyaltyBps: 500, TakerFeeBps: 200}},
+		listing("a", "1"), listing("b", "5"))
+	srv.AddBid(tensortest.Bid{Owner: rival, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: tensortest.Lamports("1.2")})
+
+	buy := func(name, maxPrice string) error {
+		_, _, err := c.Marketplace.BuyNFT(ctx, &marketplace.BuyNFTRequest{Buyer: trader, Mint: mint(name), Owner: seller, MaxPrice: money.MustParseSOL(maxPrice), Blockhash: tensortest.Blockhash})
+		return err
+	}
+	if err := buy("a", "0.9"); apiCode(err) != 400 || !strings.Contains(err.Error(), "exceeds max price") {
+		t.Errorf("BuyNFT() under the listing price error = %v", err)
+	}
+	if err := buy("b", "5"); apiCode(err) != 400 || !strings.Contains(err.Error(), "insufficient balance") {
+		t.Errorf("BuyNFT() over the balance error = %v", err)
+	}
+	if err := buy("a", "1"); err != nil {
+		t.Fatalf("BuyNFT() error = %v", err)
+	}
+	if err := buy("a", "1"); apiCode(err) != 400 {
+		t.Errorf("second BuyNFT() of the same mint error = %v, want 400", err)
+	}
+	if free, _ := w.Balance(); free != money.MustParseSOL("1.93") {
+		t.Errorf("balance after buying = %v, want 1.93 SOL (1 SOL plus 7%% fees)", free)
+	}
+
+	sell := func(minPrice string) error {
+		_, _, err := c.Marketplace.SellNFT(ctx, &marketplace.SellNFTRequest{Seller: trader, Mint: mint("a"), BidAddress: rival, MinPrice: money.MustParseSOL(minPrice), Blockhash: tensortest.Blockhash})
+		return err
+	}
+	if err := sell("1.5"); apiCode(err) != 400 {
+		t.Errorf("SellNFT() over the best bid error = %v, want 400", err)
+	}
+	if err := sell("1.2"); err != nil {
+		t.Fatalf("SellNFT() error = %v", err)
+	}
+	if free, _ := w.Balance(); free != money.MustParseSOL("3.046") {
+		t.Errorf("balance after selling = %v, want 3.046 SOL", free)
+	}
+
+	fills := w.Fills()
+	if len(fills) != 2 || fills[0].Venue != VenueBuyNow || fills[1].Venue != VenueAcceptBid {
+		t.Fatalf("fills = %+v, want a buy now and an accepted bid", fills)
+	}
+	if fills[1].Realized != money.MustParseSOL("0.046") || fills[1].Fees != money.MustParseSOL("0.084") {
+		t.Errorf("sale realized %v with %v fees, want 0.046 SOL and 0.084 SOL", fills[1].Realized, fills[1].Fees)
+	}
+	if got := srv.RequestCount("/api/v1/tx"); got != 0 {
+		t.Errorf("%d transaction requests reached the live API, want none", got)
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	ctx := context.Background()
+	_, c, _ := newTestWallet(t, Config{Balance: money.MustParseSOL("1"), Holdings: []Holding{{Mint: mint("held"), CollId: tensortest.CollID, Cost: money.MustParseSOL("1")}}})
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode int
+	}{
+		{name: "other wallet", wantCode: 400, call: func() error {
+			_, _, err := c.Marketplace.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: mint("held"), Owner: rival, Price: money.MustParseSOL("1"), Blockhash: tensortest.Blockhash})
+			return err
+		}},
+		{name: "list unheld mint", wantCode: 404, call: func() error {
+			_, _, err := c.Marketplace.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: mint("other"), Owner: trader, Price: money.MustParseSOL("1"), Blockhash: tensortest.Blockhash})
+			return err
+		}},
+		{name: "delist unlisted mint", wantCode: 404, call: func() error {
+			_, _, err := c.Marketplace.DelistNFT(ctx, &marketplace.DelistNFTRequest{Mint: mint("held"), Owner: trader, Blockhash: tensortest.Blockhash})
+			return err
+		}},
+		{name: "cancel unknown bid", wantCode: 404, call: func() error {
+			_, _, err := c.Marketplace.CancelBid(ctx, &marketplace.CancelBidRequest{BidStateAddress: rival, Blockhash: tensortest.Blockhash})
+			return err
+		}},
+		{name: "bid over balance", wantCode: 400, call: func() error {
+			_, _, err := c.Marketplace.PlaceCollectionBid(ctx, &marketplace.PlaceCollectionBidRequest{Owner: trader, Price: money.MustParseSOL("0.6"), Quantity: 2, CollId: tensortest.CollID, Blockhash: tensortest.Blockhash})
+			return err
+		}},
+		{name: "unknown pool", wantCode: 404, call: func() error {
+			_, _, err := c.TSwap.DepositWithdrawSOL(ctx, &tswap.DepositWithdrawSOLRequest{Action: "deposit", PoolAddress: rival, Lamports: money.MustParseSOL("1"), Blockhash: tensortest.Blockhash})
+			return err
+		}},
+		{name: "invalid request", call: func() error {
+			_, _, err := c.Marketplace.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: mint("held"), Owner: trader, Price: money.MustParseSOL("1")})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil || apiCode(err) != tt.wantCode {
+				t.Errorf("error = %v, want code %d", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestBidsFillAgainstLiveListings(t *testing.T) {
+	ctx := context.Background()
+	capped := tensortest.Attribute{TraitType: "Hat", Value: "Cap"}
+	srv, c, w := newTestWallet(t, Config{Balance: money.MustParseSOL("10")},
+		listing("a", "1", capped), listing("b", "1.2"), listing("c", "2"))
+
+	if _, _, err := c.Marketplace.PlaceCollectionBid(ctx, &marketplace.PlaceCollectionBidRequest{Owner: trader, Price: money.MustParseSOL("1.1"), Quantity: 2, CollId: tensortest.CollID, Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("PlaceCollectionBid() error = %v", err)
+	}
+	if _, _, err := c.Marketplace.PlaceTraitBid(ctx, &marketplace.PlaceTraitBidRequest{Owner: trader, Price: money.MustParseSOL("1.3"), Quantity: 1, CollId: tensortest.CollID, Traits: []string{"Hat:Cap"}, Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("PlaceTraitBid() error = %v", err)
+	}
+	if free, locked := w.Balance(); free != money.MustParseSOL("6.5") || locked != money.MustParseSOL("3.5") {
+		t.Errorf("balance = %v free, %v locked; want 6.5 and 3.5 SOL", free, locked)
+	}
+
+	// The capped NFT goes to the higher trait bid; nothing else is under 1.1 SOL.
+	fills := match(t, w)
+	if len(fills) != 1 || fills[0].Mint != mint("a") || fills[0].Price != money.MustParseSOL("1.3") || fills[0].Venue != VenueBid {
+		t.Fatalf("first Match() fills = %+v, want the trait bid buying mint a at 1.3 SOL", fills)
+	}
+	if fills := match(t, w); len(fills) != 0 {
+		t.Errorf("second Match() fills = %+v, want none: mint a is already taken", fills)
+	}
+
+	srv.AddNFT(listing("d", "1.05"))
+	fills = match(t, w)
+	if len(fills) != 1 || fills[0].Mint != mint("d") || fills[0].Price != money.MustParseSOL("1.1") {
+		t.Fatalf("third Match() fills = %+v, want the collection bid buying mint d at 1.1 SOL", fills)
+	}
+
+	// The strategy sees its paper bids through the user API.
+	open, err := market.Bids(ctx, c.User, market.BidTargetCollection, trader, tensortest.CollID)
+	if err != nil {
+		t.Fatalf("market.Bids() error = %v", err)
+	}
+	if len(open) != 1 || open[0].Remaining() != 1 {
+		t.Errorf("open collection bids = %+v, want one with 1 remaining", open)
+	}
+	if traits, _ := market.Bids(ctx, c.User, market.BidTargetTrait, trader, tensortest.CollID); len(traits) != 0 {
+		t.Errorf("open trait bids = %+v, want the filled bid closed", traits)
+	}
+	if free, locked := w.Balance(); free != money.MustParseSOL("6.5") || locked != money.MustParseSOL("1.1") {
+		t.Errorf("balance = %v free, %v locked; want 6.5 and 1.1 SOL", free, locked)
+	}
+	inventory, err := market.Inventory(ctx, c.User, trader, tensortest.CollID)
+	if err != nil || len(inventory) != 2 {
+		t.Errorf("inventory = %+v, %v; want mints a and d", inventory, err)
+	}
+}
+
+func TestListingsFillOnLiveDemand(t *testing.T) {
+	ctx := context.Background()
+	srv, c, w := newTestWallet(t, Config{Balance: money.MustParseSOL("1"), Holdings: []Holding{
+		{Mint: mint("x"), CollId: tensortest.CollID, Cost: money.MustParseSOL("1")},
+		{Mint: mint("y"), CollId: tensortest.CollID, Cost: money.MustParseSOL("2")},
+	}}, listing("floor", "2.5"), listing("high", "4"))
+	srv.AddBid(tensortest.Bid{Owner: rival, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: tensortest.Lamports("1.6")})
+
+	for name, price := range map[string]string{"x": "1.5", "y": "2.4"} {
+		if _, _, err := c.Marketplace.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: mint(name), Owner: trader, Price: money.MustParseSOL(price), Blockhash: tensortest.Blockhash}); err != nil {
+			t.Fatalf("ListNFT(%s) error = %v", name, err)
+		}
+	}
+	listed, err := market.Listings(ctx, c.User, trader, tensortest.CollID)
+	if err != nil || len(listed) != 2 || listed[0].Listing.Price != money.MustParseSOL("1.5") {
+		t.Fatalf("listings = %+v, %v; want both, cheapest first", listed, err)
+	}
+
+	// The 1.6 SOL bid takes the 1.5 SOL listing.
+	fills := match(t, w)
+	if len(fills) != 1 || fills[0].Mint != mint("x") || fills[0].Venue != VenueListing || fills[0].Realized != money.MustParseSOL("0.5") {
+		t.Fatalf("first Match() fills = %+v, want mint x sold for a 0.5 SOL profit", fills)
+	}
+
+	// The 2.5 SOL floor disappears: a buyer took it, and would have taken ours first.
+	srv.AddNFT(tensortest.NFT{Mint: mint("floor"), CollId: tensortest.CollID, Owner: rival})
+	fills = match(t, w)
+	if len(fills) != 1 || fills[0].Mint != mint("y") || fills[0].Price != money.MustParseSOL("2.4") {
+		t.Fatalf("second Match() fills = %+v, want mint y sold at 2.4 SOL", fills)
+	}
+	if free, _ := w.Balance(); free != money.MustParseSOL("4.9") {
+		t.Errorf("balance = %v, want 4.9 SOL", free)
+	}
+	if fills := match(t, w); len(fills) != 0 {
+		t.Errorf("third Match() fills = %+v, want none", fills)
+	}
+}
+
+func TestLiveBidFillsOnce(t *testing.T) {
+	ctx := context.Background()
+	srv, c, w := newTestWallet(t, Config{Holdings: []Holding{
+		{Mint: mint("x"), CollId: tensortest.CollID, Cost: money.MustParseSOL("1")},
+		{Mint: mint("y"), CollId: tensortest.CollID, Cost: money.MustParseSOL("1")},
+		{Mint: mint("z"), CollId: tensortest.CollID, Cost: money.MustParseSOL("1")},
+	}})
+	srv.AddBid(tensortest.Bid{Owner: rival, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: tensortest.Lamports("1.2")})
+
+	sell := func(name string) error {
+		_, _, err := c.Marketplace.SellNFT(ctx, &marketplace.SellNFTRequest{Seller: trader, Mint: mint(name), BidAddress: rival, MinPrice: money.MustParseSOL("1"), Blockhash: tensortest.Blockhash})
+		return err
+	}
+	if err := sell("x"); err != nil {
+		t.Fatalf("SellNFT(x) error = %v", err)
+	}
+	if err := sell("y"); apiCode(err) != 400 {
+		t.Errorf("SellNFT(y) into the bid x took error = %v, want 400", err)
+	}
+	if _, _, err := c.Marketplace.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: mint("z"), Owner: trader, Price: money.MustParseSOL("1.1"), Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("ListNFT(z) error = %v", err)
+	}
+	if fills := match(t, w); len(fills) != 0 {
+		t.Errorf("Match() fills = %+v, want none against the bid x took", fills)
+	}
+
+	// A new best bid is a new buyer.
+	srv.AddBid(tensortest.Bid{Owner: rival, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: tensortest.Lamports("1.3")})
+	fills := match(t, w)
+	if len(fills) != 1 || fills[0].Mint != mint("z") || fills[0].Price != money.MustParseSOL("1.1") {
+		t.Fatalf("Match() after a new bid fills = %+v, want mint z sold at 1.1 SOL", fills)
+	}
+	if err := sell("y"); apiCode(err) != 400 {
+		t.Errorf("SellNFT(y) into the bid z took error = %v, want 400", err)
+	}
+}
+
+func TestPoolFills(t *testing.T) {
+	ctx := context.Background()
+	pool := tensortest.Address("pool")
+	srv, c, w := newTestWallet(t, Config{
+		Balance:  money.MustParseSOL("5"),
+		Holdings: []Holding{{Mint: mint("stock"), CollId: tensortest.CollID, Cost: money.MustParseSOL("1")}},
+		Pools:    []Pool{{Address: pool, CollId: tensortest.CollID}},
+	}, listing("cheap", "0.85"))
+	srv.AddBid(tensortest.Bid{Owner: rival, Target: tensortest.BidTargetCollection, TargetId: tensortest.CollID, CollId: tensortest.CollID, Price: tensortest.Lamports("1.05")})
+
+	steps := []func() error{
+		func() error {
+			_, _, err := c.TSwap.DepositWithdrawSOL(ctx, &tswap.DepositWithdrawSOLRequest{Action: "deposit", PoolAddress: pool, Lamports: money.MustParseSOL("2"), Blockhash: tensortest.Blockhash})
+			return err
+		},
+		func() error {
+			_, _, err := c.TSwap.DepositWithdrawNFT(ctx, &tswap.DepositWithdrawNFTRequest{Action: "deposit", PoolAddress: pool, Mint: mint("stock"), Blockhash: tensortest.Blockhash})
+			return err
+		},
+		func() error {
+			_, _, err := c.TSwap.EditTSwapPool(ctx, &tswap.EditTSwapPoolRequest{PoolAddress: pool, PoolType: "TRADE", CurveType: "linear",
+				StartingPrice: money.MustParseSOL("1"), Delta: tswap.LinearDelta(money.MustParseSOL("0.1")), Blockhash: tensortest.Blockhash})
+			return err
+		},
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d error = %v", i, err)
+		}
+	}
+
+	// The pool bids 0.9 SOL, over the 0.85 SOL listing, then asks 0.9 SOL, under the 1.05 SOL bid.
+	fills := match(t, w)
+	if len(fills) != 2 || fills[0].Side != Buy || fills[0].Mint != mint("cheap") || fills[1].Side != Sell || fills[1].Mint != mint("stock") {
+		t.Fatalf("Match() fills = %+v, want the pool to buy mint cheap and sell mint stock", fills)
+	}
+	if fills[0].Price != money.MustParseSOL("0.9") || fills[1].Price != money.MustParseSOL("0.9") || fills[1].Realized != money.MustParseSOL("-0.1") {
+		t.Errorf("fills = %+v, want both at 0.9 SOL and a 0.1 SOL loss on stock", fills)
+	}
+
+	pools, err := market.Pools(ctx, c.User, trader, pool)
+	if err != nil || len(pools) != 1 {
+		t.Fatalf("pools = %+v, %v", pools, err)
+	}
+	if p := pools[0]; p.SolBalance != money.MustParseSOL("2") || len(p.NftsHeld) != 1 || p.NftsHeld[0] != mint("cheap") || p.StartingPrice != money.MustParseSOL("1") {
+		t.Errorf("pool = %+v, want 2 SOL, mint cheap and a 1 SOL spot price", p)
+	}
+
+	if _, _, err := c.TSwap.CloseTSwapPool(ctx, &tswap.CloseTSwapPoolRequest{PoolAddress: pool, Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("CloseTSwapPool() error = %v", err)
+	}
+	if free, _ := w.Balance(); free != money.MustParseSOL("5") {
+		t.Errorf("balance after closing = %v, want 5 SOL", free)
+	}
+	if h := w.Holdings(); len(h) != 1 || h[0].Pool != "" || h[0].Cost != money.MustParseSOL("0.9") {
+		t.Errorf("holdings = %+v, want mint cheap back in the wallet at a 0.9 SOL cost", h)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	ctx := context.Background()
+	_, c, w := newTestWallet(t, Config{Balance: money.MustParseSOL("2"), ObservedTrades: true, Holdings: []Holding{{Mint: mint("held"), CollId: tensortest.CollID, Cost: money.MustParseSOL("1")}}})
+	if _, _, err := c.Marketplace.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: mint("held"), Owner: trader, Price: money.MustParseSOL("2"), Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("ListNFT() error = %v", err)
+	}
+	if _, _, err := c.Marketplace.PlaceCollectionBid(ctx, &marketplace.PlaceCollectionBidRequest{Owner: trader, Price: money.MustParseSOL("1"), Quantity: 1, CollId: tensortest.CollID, Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("PlaceCollectionBid() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tx       market.Transaction
+		wantMint string
+		wantSide Side
+	}{
+		{name: "buyer under the ask", tx: market.Transaction{TxType: "SALE_BUY_NOW", CollId: tensortest.CollID, Mint: mint("other"), Price: money.MustParseSOL("1.9")}},
+		{name: "buyer over the ask", tx: market.Transaction{TxType: "SALE_BUY_NOW", CollId: tensortest.CollID, Mint: mint("other"), Price: money.MustParseSOL("2.1")}, wantMint: mint("held"), wantSide: Sell},
+		{name: "listing event", tx: market.Transaction{TxType: "LIST", CollId: tensortest.CollID, Mint: mint("sold"), Price: money.MustParseSOL("0.5")}},
+		{name: "seller under the bid", tx: market.Transaction{TxType: "SALE_ACCEPT_BID", CollId: tensortest.CollID, Mint: mint("sold"), Price: money.MustParseSOL("0.9"), Seller: seller}, wantMint: mint("sold"), wantSide: Buy},
+		{name: "bid already filled", tx: market.Transaction{TxType: "SALE_ACCEPT_BID", CollId: tensortest.CollID, Mint: mint("later"), Price: money.MustParseSOL("0.9"), Seller: seller}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fills := w.Observe(tt.tx)
+			if tt.wantMint == "" {
+				if len(fills) != 0 {
+					t.Errorf("Observe() fills = %+v, want none", fills)
+				}
+				return
+			}
+			if len(fills) != 1 || fills[0].Mint != tt.wantMint || fills[0].Side != tt.wantSide {
+				t.Errorf("Observe() fills = %+v, want a %s of %s", fills, tt.wantSide, tt.wantMint)
+			}
+		})
+	}
+	if free, locked := w.Balance(); free != money.MustParseSOL("3") || locked != 0 {
+		t.Errorf("balance = %v free, %v locked; want 3 SOL free", free, locked)
+	}
+}
+
+func TestCollectionRoyalties(t *testing.T) {
+	ctx := context.Background()
+	_, c, w := newTestWallet(t, Config{Balance: money.MustParseSOL("2"), Fees: &pricing.Fees{RoyaltyBps: 100}, Royalties: map[string]int64{tensortest.CollID: 500}}, listing("a", "1"))
+	if _, _, err := c.Marketplace.BuyNFT(ctx, &marketplace.BuyNFTRequest{Buyer: trader, Mint: mint("a"), Owner: seller, MaxPrice: money.MustParseSOL("1"), Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("BuyNFT() error = %v", err)
+	}
+	if fills := w.Fills(); len(fills) != 1 || fills[0].Fees != money.MustParseSOL("0.05") {
+		t.Errorf("fills = %+v, want the collection's 5%% royalty", fills)
+	}
+}
+
+func TestBidExpiry(t *testing.T) {
+	ctx := context.Background()
+	_, c, w := newTestWallet(t, Config{Balance: money.MustParseSOL("2")})
+	clock := start
+	w.now = func() time.Time { return clock }
+
+	expireIn := int32(60)
+	if _, _, err := c.Marketplace.PlaceCollectionBid(ctx, &marketplace.PlaceCollectionBidRequest{Owner: trader, Price: money.MustParseSOL("1"), Quantity: 1, CollId: tensortest.CollID, Blockhash: tensortest.Blockhash, ExpireIn: &expireIn}); err != nil {
+		t.Fatalf("PlaceCollectionBid() error = %v", err)
+	}
+	if _, locked := w.Balance(); locked != money.MustParseSOL("1") {
+		t.Errorf("locked = %v, want 1 SOL", locked)
+	}
+	clock = clock.Add(time.Minute)
+	if bids := w.Bids(); len(bids) != 0 {
+		t.Errorf("bids after expiry = %+v, want none", bids)
+	}
+	if free, locked := w.Balance(); free != money.MustParseSOL("2") || locked != 0 {
+		t.Errorf("balance after expiry = %v free, %v locked; want everything free", free, locked)
+	}
+}
+
+func TestEditAndCancelBid(t *testing.T) {
+	ctx := context.Background()
+	_, c, w := newTestWallet(t, Config{Balance: money.MustParseSOL("3")})
+	if _, _, err := c.Marketplace.PlaceCollectionBid(ctx, &marketplace.PlaceCollectionBidRequest{Owner: trader, Price: money.MustParseSOL("1"), Quantity: 2, CollId: tensortest.CollID, Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("PlaceCollectionBid() error = %v", err)
+	}
+	address := w.Bids()[0].Address
+
+	edit := func(price string, quantity int32) error {
+		p := money.MustParseSOL(price)
+		_, _, err := c.Marketplace.EditBid(ctx, &marketplace.EditBidRequest{BidStateAddress: address, Blockhash: tensortest.Blockhash, Price: &p, Quantity: &quantity})
+		return err
+	}
+	if err := edit("1", 4); apiCode(err) != 400 {
+		t.Errorf("EditBid() over the balance error = %v, want 400", err)
+	}
+	if err := edit("0.5", 3); err != nil {
+		t.Fatalf("EditBid() error = %v", err)
+	}
+	if free, locked := w.Balance(); free != money.MustParseSOL("1.5") || locked != money.MustParseSOL("1.5") {
+		t.Errorf("balance after edit = %v free, %v locked; want 1.5 and 1.5 SOL", free, locked)
+	}
+
+	resp, _, err := c.Marketplace.CancelBid(ctx, &marketplace.CancelBidRequest{BidStateAddress: address, Blockhash: tensortest.Blockhash})
+	if err != nil || resp.BidState != address {
+		t.Fatalf("CancelBid() = %+v, %v", resp, err)
+	}
+	if free, locked := w.Balance(); free != money.MustParseSOL("3") || locked != 0 {
+		t.Errorf("balance after cancel = %v free, %v locked; want everything free", free, locked)
+	}
+}
+
+func TestPnL(t *testing.T) {
+	ctx := context.Background()
+	_, c, w := newTestWallet(t, Config{Balance: money.MustParseSOL("5"), Fees: &pricing.Fees{TakerFeeBps: 200}, Holdings: []Holding{
+		{Mint: mint("held"), CollId: tensortest.CollID, Cost: money.MustParseSOL("1")},
+		{Mint: mint("unlisted"), CollId: "empty-collection", Cost: money.MustParseSOL("0.5")},
+	}}, listing("a", "1.5"), listing("b", "2"))
+
+	if _, _, err := c.Marketplace.BuyNFT(ctx, &marketplace.BuyNFTRequest{Buyer: trader, Mint: mint("a"), Owner: seller, MaxPrice: money.MustParseSOL("1.5"), Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("BuyNFT() error = %v", err)
+	}
+	if _, _, err := c.Marketplace.PlaceCollectionBid(ctx, &marketplace.PlaceCollectionBidRequest{Owner: trader, Price: money.MustParseSOL("1"), Quantity: 1, CollId: tensortest.CollID, Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("PlaceCollectionBid() error = %v", err)
+	}
+
+	pnl, err := w.PnL(ctx)
+	if err != nil {
+		t.Fatalf("PnL() error = %v", err)
+	}
+	// Mint a is still listed live, but the wallet took it, so the floor is 2 SOL.
+	want := PnL{
+		Time: start, Cash: money.MustParseSOL("2.47"), Locked: money.MustParseSOL("1"), Holdings: 3, Cost: money.MustParseSOL("3.03"), Value: money.MustParseSOL("4.5"), Unmarked: 1,
+		Unrealized: money.MustParseSOL("1.47"), Fees: money.MustParseSOL("0.03"), Start: money.MustParseSOL("6.5"), Equity: money.MustParseSOL("7.97"), Total: money.MustParseSOL("1.47"),
+	}
+	if *pnl != want {
+		t.Errorf("PnL() = %+v\nwant %+v", *pnl, want)
+	}
+	if got := pnl.String(); got != "equity 7.97 SOL (start 6.5 SOL, pnl 1.47 SOL): realized 0 SOL, unrealized 1.47 SOL, fees 0.03 SOL" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+// stalledNFTs blocks collection reads until release is closed
+type stalledNFTs struct {
+	nfts.NFTsAPI
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *stalledNFTs) GetNFTsByCollection(ctx context.Context, req *nfts.NFTsByCollectionRequest) ([]byte, int, error) {
+	s.entered <- struct{}{}
+	<-s.release
+	return s.NFTsAPI.GetNFTsByCollection(ctx, req)
+}
+
+func TestLiveReadsDoNotBlockWallet(t *testing.T) {
+	_, c, _ := newTestWallet(t, Config{}, listing("a", "1"))
+	stalled := &stalledNFTs{NFTsAPI: c.NFTs, entered: make(chan struct{}), release: make(chan struct{})}
+	w, err := New(Config{Wallet: trader, Balance: money.MustParseSOL("1"), Holdings: []Holding{{Mint: mint("held"), CollId: tensortest.CollID, Cost: money.MustParseSOL("1")}}},
+		APIs{NFTs: stalled, Collections: c.Collections})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, _, err := w.PlaceCollectionBid(context.Background(), &marketplace.PlaceCollectionBidRequest{Owner: trader, Price: money.MustParseSOL("0.5"), Quantity: 1, CollId: tensortest.CollID, Blockhash: tensortest.Blockhash}); err != nil {
+		t.Fatalf("PlaceCollectionBid() error = %v", err)
+	}
+
+	for name, read := range map[string]func() error{
+		"Match": func() error { _, err := w.Match(context.Background()); return err },
+		"PnL":   func() error { _, err := w.PnL(context.Background()); return err },
+	} {
+		done := make(chan error, 1)
+		go func() { done <- read() }()
+		<-stalled.entered
+		balance := make(chan struct{})
+		go func() { w.Balance(); close(balance) }()
+		select {
+		case <-balance:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Balance() blocked behind a live read in %s", name)
+		}
+		close(stalled.release)
+		if err := <-done; err != nil {
+			t.Errorf("%s() error = %v", name, err)
+		}
+		stalled.release = make(chan struct{})
+	}
+}
+
+// TestDropInForLive runs an unmodified bid ladder against the paper client
+func TestDropInForLive(t *testing.T) {
+	ctx := context.Background()
+	srv, c, w := newTestWallet(t, Config{Balance: money.MustParseSOL("10")}, listing("a", "0.7"))
+
+	mgr, err := bidladder.New(bidladder.APIs{User: c.User, Marketplace: c.Marketplace}, cluster.StaticBlockhash(tensortest.Blockhash))
+	if err != nil {
+		t.Fatalf("bidladder.New() error = %v", err)
+	}
+	ladder := bidladder.Ladder{Owner: trader, CollId: tensortest.CollID, Levels: []bidladder.Level{
+		{Price: money.MustParseSOL("0.9"), Quantity: 1}, {Price: money.MustParseSOL("0.8"), Quantity: 2},
+	}}
+	for round := 0; round < 2; round++ {
+		d, err := mgr.Plan(ctx, ladder)
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
+		}
+		if _, err := mgr.Apply(ctx, d); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if round == 0 {
+			match(t, w)
+		}
+	}
+
+	// The 0.9 SOL bid bought the 0.7 SOL listing and the ladder topped it back up.
+	bids := w.Bids()
+	if len(bids) != 2 {
+		t.Fatalf("bids = %+v, want two levels", bids)
+	}
+	if h := w.Holdings(); len(h) != 1 || h[0].Cost != money.MustParseSOL("0.9") {
+		t.Errorf("holdings = %+v, want mint a at 0.9 SOL", h)
+	}
+	if got := srv.RequestCount("/api/v1/tx"); got != 0 {
+		t.Errorf("%d transaction requests reached the live API, want none", got)
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{name: "no wallet", cfg: Config{}, wantErr: "invalid wallet"},
+		{name: "negative balance", cfg: Config{Wallet: trader, Balance: -1}, wantErr: "balance"},
+		{name: "duplicate holding", cfg: Config{Wallet: trader, Holdings: []Holding{{Mint: "m", CollId: "c"}, {Mint: "m", CollId: "c"}}}, wantErr: "duplicate"},
+		{name: "pool without collection", cfg: Config{Wallet: trader, Pools: []Pool{{Address: "p"}}}, wantErr: "collId"},
+		{name: "bad fees", cfg: Config{Wallet: trader, Fees: &pricing.Fees{TakerFeeBps: -1}}, wantErr: "takerFeeBps"},
+		{name: "bad royalty", cfg: Config{Wallet: trader, Royalties: map[string]int64{tensortest.CollID: 20_000}}, wantErr: "royalty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := client.New(nil)
+			if _, _, err := NewClient(c, tt.cfg); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewClient() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// PnL values the wallet against the live market
+type PnL struct {
+	Time time.Time
+
+	Cash    money.Lamports // Free SOL
+	Locked  money.Lamports // SOL locked in open bids
+	PoolSOL money.Lamports // SOL held by pools
+
+	Holdings int            // NFTs held, in the wallet or pools
+	Cost     money.Lamports // What the holdings cost
+	Value    money.Lamports // The holdings marked at the live floor, ignoring listings the wallet already took
+	// Unmarked counts holdings whose collection has no live listing; they are valued at cost
+	Unmarked int
+
+	Realized   money.Lamports // Profit on NFTs sold
+	Unrealized money.Lamports // Value minus Cost
+	Fees       money.Lamports // Royalties and taker fees paid, already included in the PnL
+
+	Start  money.Lamports // Starting SOL plus the cost of the starting holdings
+	Equity money.Lamports // Cash, Locked, PoolSOL and Value
+	Total  money.Lamports // Equity minus Start
+}
+
+// PnL marks the wallet to the live market, reading each held collection's
+// floor once. The floors are read without holding the wallet's lock.
+//
+// Returns:
+//   - The valuation
+//   - An error if reading a floor failed
+func (w *Wallet) PnL(ctx context.Context) (*PnL, error) {
+	listings := make(map[string][]market.NFT)
+	for {
+		w.mu.Lock()
+		unread := w.unreadCollections(listings)
+		if len(unread) == 0 {
+			break
+		}
+		// Holdings may change while the floors are read; the loop reads any new collection
+		w.mu.Unlock()
+		for _, collID := range unread {
+			got, err := w.liveListings(ctx, collID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read floor of %s: %w", collID, err)
+			}
+			listings[collID] = got
+		}
+	}
+	defer w.mu.Unlock()
+	w.expireBids()
+	r := &PnL{
+		Time:     w.now(),
+		Cash:     w.cash,
+		Locked:   w.locked,
+		Holdings: len(w.holdings),
+		Realized: w.realized,
+		Fees:     w.paid,
+		Start:    w.start,
+	}
+	for _, p := range w.pools {
+		r.PoolSOL += p.sol
+	}
+
+	floors := make(map[string]money.Lamports)
+	for _, h := range w.sortedHoldings() {
+		floor, ok := floors[h.nft.CollId]
+		if !ok {
+			for _, n := range listings[h.nft.CollId] {
+				if !w.taken[n.Mint] {
+					floor = n.Listing.Price
+					break
+				}
+			}
+			floors[h.nft.CollId] = floor
+		}
+		r.Cost += h.cost
+		if floor == 0 {
+			r.Value += h.cost
+			r.Unmarked++
+			continue
+		}
+		r.Value += floor
+	}
+	r.Unrealized = r.Value - r.Cost
+	r.Equity = r.Cash + r.Locked + r.PoolSOL + r.Value
+	r.Total = r.Equity - r.Start
+	return r, nil
+}
+
+// unreadCollections returns the collections of held NFTs missing from listings, sorted
+func (w *Wallet) unreadCollections(listings map[string][]market.NFT) []string {
+	seen := make(map[string]bool)
+	for _, h := range w.holdings {
+		if _, ok := listings[h.nft.CollId]; !ok {
+			seen[h.nft.CollId] = true
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for id := range seen {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// String summarizes the valuation, e.g. "equity 10.5 SOL (start 10 SOL, pnl 0.5 SOL): realized 0.2 SOL, unrealized 0.3 SOL, fees 0.04 SOL"
+func (r *PnL) String() string {
+	return fmt.Sprintf("equity %s (start %s, pnl %s): realized %s, unrealized %s, fees %s",
+		r.Equity, r.Start, r.Total, r.Realized, r.Unrealized, r.Fees)
+}
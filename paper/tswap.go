@@ -0,0 +1,120 @@
+package paper
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/srpvpn/tensor-go-sdk/api/tswap"
+	"github.com/srpvpn/tensor-go-sdk/pricing"
+)
+
+// CloseTSwapPool closes a pool, returning its SOL and NFTs to the wallet
+func (w *Wallet) CloseTSwapPool(_ context.Context, req *tswap.CloseTSwapPoolRequest) (*tswap.CloseTSwapPoolResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	p, status, err := w.pool(req.PoolAddress)
+	if err != nil {
+		return nil, status, err
+	}
+	for _, mint := range p.nfts {
+		w.holdings[mint].pool = ""
+	}
+	w.cash += p.sol
+	delete(w.pools, p.address)
+	return &tswap.CloseTSwapPoolResponse{}, http.StatusOK, nil
+}
+
+// EditTSwapPool sets a pool's type and curve, resetting its price to the new starting price
+func (w *Wallet) EditTSwapPool(_ context.Context, req *tswap.EditTSwapPoolRequest) (*tswap.EditTSwapPoolResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	curve, err := pricing.FromEditRequest(req)
+	if err != nil {
+		return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "%v", err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	p, status, err := w.pool(req.PoolAddress)
+	if err != nil {
+		return nil, status, err
+	}
+	p.curve, p.priced = curve, true
+	return &tswap.EditTSwapPoolResponse{}, http.StatusOK, nil
+}
+
+// DepositWithdrawNFT moves a held NFT between the wallet and a pool
+func (w *Wallet) DepositWithdrawNFT(_ context.Context, req *tswap.DepositWithdrawNFTRequest) (*tswap.DepositWithdrawNFTResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	p, status, err := w.pool(req.PoolAddress)
+	if err != nil {
+		return nil, status, err
+	}
+	if strings.EqualFold(req.Action, "deposit") {
+		h, status, err := w.walletNFT(req.Mint)
+		if err != nil {
+			return nil, status, err
+		}
+		if h.listed != 0 {
+			return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "mint %s is listed", req.Mint)
+		}
+		if h.nft.CollId != p.collID {
+			return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "mint %s is not in the pool's collection", req.Mint)
+		}
+		h.pool = p.address
+		p.nfts = append(p.nfts, req.Mint)
+	} else {
+		i := slices.Index(p.nfts, req.Mint)
+		if i < 0 {
+			return nil, http.StatusNotFound, failure(http.StatusNotFound, "mint %s is not in pool %s", req.Mint, p.address)
+		}
+		p.nfts = slices.Delete(p.nfts, i, i+1)
+		w.holdings[req.Mint].pool = ""
+	}
+	return &tswap.DepositWithdrawNFTResponse{}, http.StatusOK, nil
+}
+
+// DepositWithdrawSOL moves SOL between the wallet and a pool
+func (w *Wallet) DepositWithdrawSOL(_ context.Context, req *tswap.DepositWithdrawSOLRequest) (*tswap.DepositWithdrawSOLResponse, int, error) {
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	p, status, err := w.pool(req.PoolAddress)
+	if err != nil {
+		return nil, status, err
+	}
+	if strings.EqualFold(req.Action, "deposit") {
+		if req.Lamports > w.cash {
+			return nil, http.StatusBadRequest, w.insufficient(req.Lamports)
+		}
+		w.cash -= req.Lamports
+		p.sol += req.Lamports
+	} else {
+		if req.Lamports > p.sol {
+			return nil, http.StatusBadRequest, failure(http.StatusBadRequest, "pool %s holds %s SOL", p.address, p.sol.SOL())
+		}
+		p.sol -= req.Lamports
+		w.cash += req.Lamports
+	}
+	return &tswap.DepositWithdrawSOLResponse{}, http.StatusOK, nil
+}
+
+// pool returns a simulated pool by address
+func (w *Wallet) pool(address string) (*pool, int, error) {
+	p, ok := w.pools[address]
+	if !ok {
+		return nil, http.StatusNotFound, failure(http.StatusNotFound, "pool %s not found", address)
+	}
+	return p, http.StatusOK, nil
+}
@@ -0,0 +1,186 @@
+package paper
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/market"
+)
+
+// userView is a user.UserAPI that answers the wallet's own listing, bid,
+// inventory and pool queries from the simulation and forwards the rest
+type userView struct {
+	user.UserAPI
+	w *Wallet
+}
+
+// UserAPI returns a user.UserAPI that reports the simulated wallet's
+// listings, bids, inventory and pools, so strategies that read their own
+// state see their paper orders. Queries for other wallets, and every other
+// endpoint, go to live. Simulated results come back as a single page.
+func (w *Wallet) UserAPI(live user.UserAPI) user.UserAPI {
+	return &userView{UserAPI: live, w: w}
+}
+
+// GetListings reports the wallet's simulated listings when it is the only wallet queried
+func (u *userView) GetListings(ctx context.Context, req *user.ListingsRequest) ([]byte, int, error) {
+	if req == nil || !u.only(req.Wallets) {
+		return u.UserAPI.GetListings(ctx, req)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	u.w.mu.Lock()
+	defer u.w.mu.Unlock()
+	var items []market.NFT
+	for _, h := range u.w.sortedHoldings() {
+		if h.listed != 0 && matchesColl(req.CollId, h.nft.CollId) {
+			items = append(items, u.w.view(h))
+		}
+	}
+	slices.SortStableFunc(items, func(a, b market.NFT) int {
+		if req.SortBy == "PriceDesc" {
+			a, b = b, a
+		}
+		return cmp.Compare(a.Listing.Price, b.Listing.Price)
+	})
+	return page("listings", items)
+}
+
+// GetInventoryForCollection reports the wallet's simulated NFTs, listed or not, outside pools
+func (u *userView) GetInventoryForCollection(ctx context.Context, req *user.InventoryForCollectionRequest) ([]byte, int, error) {
+	if req == nil || !u.only(req.Wallets) {
+		return u.UserAPI.GetInventoryForCollection(ctx, req)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	u.w.mu.Lock()
+	defer u.w.mu.Unlock()
+	var items []market.NFT
+	for _, h := range u.w.sortedHoldings() {
+		if h.pool == "" && matchesColl(req.CollId, h.nft.CollId) {
+			items = append(items, u.w.view(h))
+		}
+	}
+	return page("nfts", items)
+}
+
+// GetNFTBids reports the wallet's simulated single NFT bids
+func (u *userView) GetNFTBids(ctx context.Context, req *user.NFTBidsRequest) ([]byte, int, error) {
+	if req == nil || req.Owner != u.w.cfg.Wallet {
+		return u.UserAPI.GetNFTBids(ctx, req)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return u.bids(market.BidTargetNFT, req.CollId, req.BidAddresses)
+}
+
+// GetCollectionBids reports the wallet's simulated collection bids
+func (u *userView) GetCollectionBids(ctx context.Context, req *user.CollectionBidsRequest) ([]byte, int, error) {
+	if req == nil || req.Owner != u.w.cfg.Wallet {
+		return u.UserAPI.GetCollectionBids(ctx, req)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return u.bids(market.BidTargetCollection, req.CollId, req.BidAddresses)
+}
+
+// GetTraitBids reports the wallet's simulated trait bids
+func (u *userView) GetTraitBids(ctx context.Context, req *user.TraitBidsRequest) ([]byte, int, error) {
+	if req == nil || req.Owner != u.w.cfg.Wallet {
+		return u.UserAPI.GetTraitBids(ctx, req)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return u.bids(market.BidTargetTrait, req.CollId, req.BidAddresses)
+}
+
+// GetTSwapPools reports the wallet's simulated pools. A pool's starting
+// price is reported as its current spot price, since fills move the curve.
+func (u *userView) GetTSwapPools(ctx context.Context, req *user.TSwapsPoolsRequest) ([]byte, int, error) {
+	if req == nil || req.Owner != u.w.cfg.Wallet {
+		return u.UserAPI.GetTSwapPools(ctx, req)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, 0, err
+	}
+	u.w.mu.Lock()
+	defer u.w.mu.Unlock()
+	var items []market.Pool
+	for _, p := range u.w.sortedPools() {
+		if len(req.PoolAddresses) > 0 && !slices.Contains(req.PoolAddresses, p.address) {
+			continue
+		}
+		spot, _ := p.curve.SpotPrice()
+		items = append(items, market.Pool{
+			Address:       p.address,
+			Owner:         u.w.cfg.Wallet,
+			CollId:        p.collID,
+			PoolType:      string(p.curve.Type),
+			CurveType:     string(p.curve.Curve),
+			StartingPrice: spot,
+			Delta:         p.curve.Delta(),
+			MmFeeBps:      int32(p.curve.MmFeeBps),
+			SolBalance:    p.sol,
+			NftsHeld:      append([]string{}, p.nfts...),
+		})
+	}
+	return page("pools", items)
+}
+
+// bids encodes the wallet's open bids of one target kind
+func (u *userView) bids(target string, collID *string, addresses []string) ([]byte, int, error) {
+	u.w.mu.Lock()
+	defer u.w.mu.Unlock()
+	u.w.expireBids()
+	var items []market.Bid
+	for _, o := range u.w.sortedBids() {
+		if o.bid.Target != target || !matchesColl(collID, o.bid.CollId) {
+			continue
+		}
+		if len(addresses) > 0 && !slices.Contains(addresses, o.bid.Address) {
+			continue
+		}
+		items = append(items, o.bid)
+	}
+	return page("bids", items)
+}
+
+// only reports whether wallets is exactly the simulated wallet
+func (u *userView) only(wallets []string) bool {
+	return len(wallets) == 1 && wallets[0] == u.w.cfg.Wallet
+}
+
+// view returns a holding as the user endpoints report it
+func (w *Wallet) view(h *position) market.NFT {
+	n := h.nft
+	n.Owner = w.cfg.Wallet
+	if h.listed != 0 {
+		n.Listing = &market.Listing{Price: h.listed, Seller: w.cfg.Wallet}
+	}
+	return n
+}
+
+func matchesColl(want *string, collID string) bool {
+	return want == nil || *want == "" || *want == collID
+}
+
+// page encodes items under key as a single, final page
+func page[T any](key string, items []T) ([]byte, int, error) {
+	if items == nil {
+		items = []T{}
+	}
+	data, err := json.Marshal(map[string]any{key: items, "page": market.Page{}})
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, http.StatusOK, nil
+}
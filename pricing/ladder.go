@@ -0,0 +1,90 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// LadderRow is one level of a price ladder: the Nth buy and Nth sell against the pool
+type LadderRow struct {
+	Level int
+	Buy   *Quote // nil if the pool does not sell NFTs or the curve ended
+	Sell  *Quote // nil if the pool does not buy NFTs or the curve ended
+
+	// CumulativeBuy is what a taker pays in total to buy every NFT up to this level
+	CumulativeBuy money.Lamports
+	// CumulativeSell is what a taker receives in total for selling every NFT up to this level
+	CumulativeSell money.Lamports
+}
+
+// Ladder is a table of the prices a pool will quote over its next trades
+type Ladder struct {
+	Pool Pool
+	Fees Fees
+	Rows []LadderRow
+}
+
+// Ladder quotes levels trades on each side the pool trades
+//
+// Returns:
+//   - The ladder, with one row per level
+//   - An error if the pool or fees are invalid or levels is negative
+func (p Pool) Ladder(levels int, fees Fees) (*Ladder, error) {
+	if err := p.check(levels, fees); err != nil {
+		return nil, err
+	}
+	var buys, sells []Quote
+	var err error
+	if p.Type != PoolToken {
+		if buys, err = p.BuyQuotes(levels, fees); err != nil {
+			return nil, err
+		}
+	}
+	if p.Type != PoolNFT {
+		if sells, err = p.SellQuotes(levels, fees); err != nil {
+			return nil, err
+		}
+	}
+
+	l := &Ladder{Pool: p, Fees: fees, Rows: make([]LadderRow, levels)}
+	var cumBuy, cumSell money.Lamports
+	for i := range l.Rows {
+		row := LadderRow{Level: i + 1}
+		if i < len(buys) {
+			row.Buy = &buys[i]
+			cumBuy += buys[i].Total
+		}
+		if i < len(sells) {
+			row.Sell = &sells[i]
+			cumSell += sells[i].Total
+		}
+		row.CumulativeBuy, row.CumulativeSell = cumBuy, cumSell
+		l.Rows[i] = row
+	}
+	return l, nil
+}
+
+// String renders the ladder as an aligned text table. Prices are in SOL;
+// "-" marks a side the pool does not quote at that level.
+func (l *Ladder) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", l.Pool)
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "level\tbuy price\ttaker pays\ttotal paid\tsell price\ttaker gets\ttotal received\t")
+	for _, row := range l.Rows {
+		buyPrice, buyTotal, cumBuy := "-", "-", "-"
+		if row.Buy != nil {
+			buyPrice, buyTotal, cumBuy = row.Buy.Price.SOL(), row.Buy.Total.SOL(), row.CumulativeBuy.SOL()
+		}
+		sellPrice, sellTotal, cumSell := "-", "-", "-"
+		if row.Sell != nil {
+			sellPrice, sellTotal, cumSell = row.Sell.Price.SOL(), row.Sell.Total.SOL(), row.CumulativeSell.SOL()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t\n", row.Level, buyPrice, buyTotal, cumBuy, sellPrice, sellTotal, cumSell)
+	}
+	w.Flush()
+	return b.String()
+}
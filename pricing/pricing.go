@@ -0,0 +1,347 @@
+// Package pricing simulates TSwap bonding curves so a pool edit can be
+// previewed before it is signed. It models linear and exponential curves for
+// token, NFT and trade pools, quotes the next buy and sell prices with MM fees
+// and royalties applied, and computes the SOL a pool needs to buy a given
+// number of NFTs. Everything is integer lamport math with no I/O.
+//
+// Prices move along the curve by one step per trade: a taker buying an NFT
+// from the pool raises the price by one step, a taker selling one lowers it.
+// Trade pools quote their sell side one step below the buy side, which is the
+// spread the pool owner earns on top of the MM fee.
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/srpvpn/tensor-go-sdk/api/tswap"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// PoolType is the side(s) of the market a pool trades
+type PoolType string
+
+// Pool types, as used by the TSwap API
+const (
+	PoolToken PoolType = "TOKEN" // Holds SOL and only buys NFTs from takers
+	PoolNFT   PoolType = "NFT"   // Holds NFTs and only sells them to takers
+	PoolTrade PoolType = "TRADE" // Buys and sells, charging the MM fee
+)
+
+// CurveType is how the price moves between trades
+type CurveType string
+
+// Curve types, as used by the TSwap API
+const (
+	CurveLinear      CurveType = "linear"      // DeltaLamports is added or subtracted per trade
+	CurveExponential CurveType = "exponential" // DeltaBps is applied multiplicatively per trade
+)
+
+// bpsDenominator is the number of basis points in 100%
+const bpsDenominator = 10_000
+
+// Side is the taker's side of a trade
+type Side string
+
+// Trade sides, from the taker's point of view
+const (
+	Buy  Side = "buy"  // The taker buys an NFT from the pool
+	Sell Side = "sell" // The taker sells an NFT to the pool
+)
+
+// Pool holds the pricing parameters of a TSwap pool
+type Pool struct {
+	Type          PoolType
+	Curve         CurveType
+	StartingPrice money.Lamports
+	DeltaLamports money.Lamports // Price step of a linear curve
+	DeltaBps      int64          // Price step of an exponential curve, in basis points
+	// MmFeeBps is the market making fee of a trade pool, in basis points
+	MmFeeBps int64
+	// Offset is the number of taker buys minus taker sells since StartingPrice
+	// was set. It is zero for a freshly edited pool.
+	Offset int64
+	// MaxTakerSellCount caps how many more NFTs takers may sell to the pool than
+	// they bought from it. Zero means no cap.
+	MaxTakerSellCount int64
+}
+
+// Fees are the charges on top of the curve price that do not go to the pool
+type Fees struct {
+	RoyaltyBps  int64 // Creator royalty, in basis points of the price
+	TakerFeeBps int64 // Marketplace taker fee, in basis points of the price
+}
+
+// Quote is the price of one trade against a pool
+type Quote struct {
+	Step     int            // Zero-based position in the sequence of trades
+	Side     Side           // The taker's side
+	Price    money.Lamports // Curve price
+	MMFee    money.Lamports // Market making fee, trade pools only
+	Royalty  money.Lamports // Creator royalty
+	TakerFee money.Lamports // Marketplace taker fee
+	// Total is what the taker pays when buying or receives when selling
+	Total money.Lamports
+	// PoolChange is the change in the pool's SOL balance: positive on buys,
+	// negative on sells. The MM fee stays with the pool owner either way.
+	PoolChange money.Lamports
+}
+
+// FromEditRequest builds the Pool an EditTSwapPoolRequest would produce.
+// The edit resets the curve, so the Offset is zero.
+//
+// Returns:
+//   - The pool
+//   - An error if the request's pricing parameters are invalid
+func FromEditRequest(req *tswap.EditTSwapPoolRequest) (Pool, error) {
+	if req == nil {
+		return Pool{}, fmt.Errorf("request cannot be nil")
+	}
+	p := Pool{
+		Type:          PoolType(req.PoolType),
+		Curve:         CurveType(req.CurveType),
+		StartingPrice: req.StartingPrice,
+	}
+	p.SetDelta(req.Delta)
+	if req.MmFeeBps != nil {
+		p.MmFeeBps = int64(math.Round(*req.MmFeeBps))
+	}
+	if req.MaxTakerSellCount != nil {
+		p.MaxTakerSellCount = int64(*req.MaxTakerSellCount)
+	}
+	if err := p.Validate(); err != nil {
+		return Pool{}, err
+	}
+	return p, nil
+}
+
+// Validate checks that the pool's parameters describe a usable curve
+func (p Pool) Validate() error {
+	switch p.Type {
+	case PoolToken, PoolNFT, PoolTrade:
+	default:
+		return fmt.Errorf("invalid poolType: %s, must be one of: [TOKEN NFT TRADE]", p.Type)
+	}
+	switch p.Curve {
+	case CurveLinear, CurveExponential:
+	default:
+		return fmt.Errorf("invalid curveType: %s, must be one of: [linear exponential]", p.Curve)
+	}
+	if p.StartingPrice <= 0 {
+		return fmt.Errorf("startingPrice must be > 0")
+	}
+	if p.DeltaLamports < 0 || p.DeltaBps < 0 {
+		return fmt.Errorf("delta must be >= 0")
+	}
+	if p.Curve == CurveLinear && p.DeltaBps != 0 {
+		return fmt.Errorf("deltaBps is only used by exponential curves")
+	}
+	if p.Curve == CurveExponential && p.DeltaLamports != 0 {
+		return fmt.Errorf("deltaLamports is only used by linear curves")
+	}
+	if p.MmFeeBps < 0 || p.MmFeeBps >= bpsDenominator {
+		return fmt.Errorf("mmFeeBps must be between 0 and 9999 basis points")
+	}
+	if p.MmFeeBps != 0 && p.Type != PoolTrade {
+		return fmt.Errorf("mmFeeBps is only charged by TRADE pools")
+	}
+	if p.MaxTakerSellCount < 0 {
+		return fmt.Errorf("maxTakerSellCount must be >= 0")
+	}
+	return nil
+}
+
+// Validate checks that the fees are within 0-100%
+func (f Fees) Validate() error {
+	if f.RoyaltyBps < 0 || f.RoyaltyBps > bpsDenominator {
+		return fmt.Errorf("royaltyBps must be between 0 and 10000 basis points")
+	}
+	if f.TakerFeeBps < 0 || f.TakerFeeBps > bpsDenominator {
+		return fmt.Errorf("takerFeeBps must be between 0 and 10000 basis points")
+	}
+	return nil
+}
+
+// SpotPrice returns the curve price at the pool's current Offset
+//
+// Returns:
+//   - The price
+//   - false if the curve has reached zero or overflowed at this offset
+func (p Pool) SpotPrice() (money.Lamports, bool) {
+	return p.priceAt(p.Offset)
+}
+
+// BuyQuotes quotes the next n NFTs a taker can buy from the pool, cheapest
+// first. Fewer than n quotes are returned if the curve overflows.
+//
+// Returns:
+//   - The quotes in trade order
+//   - An error if the pool or fees are invalid, n is negative, or the pool is a TOKEN pool
+func (p Pool) BuyQuotes(n int, fees Fees) ([]Quote, error) {
+	if err := p.check(n, fees); err != nil {
+		return nil, err
+	}
+	if p.Type == PoolToken {
+		return nil, fmt.Errorf("TOKEN pools do not sell NFTs")
+	}
+	quotes := make([]Quote, 0, n)
+	for i := 0; i < n; i++ {
+		price, ok := p.priceAt(p.Offset + int64(i))
+		if !ok {
+			break
+		}
+		q := p.quote(i, Buy, price, fees)
+		q.Total = q.Price + q.MMFee + q.Royalty + q.TakerFee
+		q.PoolChange = q.Price + q.MMFee
+		quotes = append(quotes, q)
+	}
+	return quotes, nil
+}
+
+// SellQuotes quotes the next n NFTs a taker can sell to the pool, best price
+// first. Fewer than n quotes are returned once the curve reaches zero or the
+// pool's MaxTakerSellCount is hit.
+//
+// Returns:
+//   - The quotes in trade order
+//   - An error if the pool or fees are invalid, n is negative, or the pool is an NFT pool
+func (p Pool) SellQuotes(n int, fees Fees) ([]Quote, error) {
+	if err := p.check(n, fees); err != nil {
+		return nil, err
+	}
+	if p.Type == PoolNFT {
+		return nil, fmt.Errorf("NFT pools do not buy NFTs")
+	}
+	start := p.Offset
+	if p.Type == PoolTrade {
+		start--
+	}
+	quotes := make([]Quote, 0, n)
+	for i := 0; i < n; i++ {
+		if p.MaxTakerSellCount > 0 && -(p.Offset-int64(i)) >= p.MaxTakerSellCount {
+			break
+		}
+		price, ok := p.priceAt(start - int64(i))
+		if !ok {
+			break
+		}
+		q := p.quote(i, Sell, price, fees)
+		q.Total = q.Price - q.MMFee - q.Royalty - q.TakerFee
+		q.PoolChange = -(q.Price - q.MMFee)
+		quotes = append(quotes, q)
+	}
+	return quotes, nil
+}
+
+// SOLNeeded returns the SOL the pool must hold to buy nfts NFTs from takers in
+// a row. The pool pays the full curve price of each NFT before the MM fee is
+// credited back, so fees do not reduce the amount.
+//
+// Returns:
+//   - The SOL balance needed
+//   - An error if the pool is invalid or cannot buy that many NFTs
+func (p Pool) SOLNeeded(nfts int) (money.Lamports, error) {
+	quotes, err := p.SellQuotes(nfts, Fees{})
+	if err != nil {
+		return 0, err
+	}
+	if len(quotes) < nfts {
+		return 0, fmt.Errorf("pool can only buy %d of %d NFTs before its price reaches zero or its sell cap", len(quotes), nfts)
+	}
+	var total money.Lamports
+	for _, q := range quotes {
+		total += q.Price
+	}
+	return total, nil
+}
+
+// check validates the inputs common to every quote
+func (p Pool) check(n int, fees Fees) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if err := fees.Validate(); err != nil {
+		return err
+	}
+	if n < 0 {
+		return fmt.Errorf("count must be >= 0")
+	}
+	return nil
+}
+
+// quote fills in the fee breakdown of a trade at price
+func (p Pool) quote(step int, side Side, price money.Lamports, fees Fees) Quote {
+	return Quote{
+		Step:     step,
+		Side:     side,
+		Price:    price,
+		MMFee:    price.MulBps(p.MmFeeBps),
+		Royalty:  price.MulBps(fees.RoyaltyBps),
+		TakerFee: price.MulBps(fees.TakerFeeBps),
+	}
+}
+
+// Delta returns the pool's price step as the TSwap API encodes it for its curve
+func (p Pool) Delta() tswap.Delta {
+	if p.Curve == CurveExponential {
+		return tswap.ExponentialDelta(p.DeltaBps)
+	}
+	return tswap.LinearDelta(p.DeltaLamports)
+}
+
+// SetDelta sets the pool's price step from d, read for the pool's Curve
+func (p *Pool) SetDelta(d tswap.Delta) {
+	p.DeltaLamports, p.DeltaBps = 0, 0
+	if p.Curve == CurveExponential {
+		p.DeltaBps = d.Bps()
+	} else {
+		p.DeltaLamports = d.Lamports()
+	}
+}
+
+// priceAt returns the curve price offset steps from StartingPrice. Linear
+// curves move by DeltaLamports per step; exponential curves multiply by
+// (1 + DeltaBps/10000) per step, rounding down once at the end.
+func (p Pool) priceAt(offset int64) (money.Lamports, bool) {
+	price := big.NewInt(int64(p.StartingPrice))
+	steps := big.NewInt(offset)
+	switch p.Curve {
+	case CurveExponential:
+		base := big.NewInt(bpsDenominator)
+		grown := big.NewInt(bpsDenominator + p.DeltaBps)
+		exp := new(big.Int).Abs(steps)
+		num, den := new(big.Int).Exp(grown, exp, nil), new(big.Int).Exp(base, exp, nil)
+		if offset < 0 {
+			num, den = den, num
+		}
+		price.Mul(price, num).Quo(price, den)
+	default:
+		price.Add(price, steps.Mul(steps, big.NewInt(int64(p.DeltaLamports))))
+	}
+	if price.Sign() <= 0 || !price.IsInt64() {
+		return 0, false
+	}
+	return money.Lamports(price.Int64()), true
+}
+
+// String describes the pool's curve, e.g. "TRADE linear 1 SOL ± 0.1 SOL, 2% MM fee"
+func (p Pool) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s", p.Type, p.Curve, p.StartingPrice)
+	if p.Curve == CurveExponential {
+		fmt.Fprintf(&b, " ± %s%%", formatBps(p.DeltaBps))
+	} else {
+		fmt.Fprintf(&b, " ± %s", p.DeltaLamports)
+	}
+	if p.MmFeeBps != 0 {
+		fmt.Fprintf(&b, ", %s%% MM fee", formatBps(p.MmFeeBps))
+	}
+	return b.String()
+}
+
+// formatBps formats basis points as a percentage without trailing zeros
+func formatBps(bps int64) string {
+	return money.NewAmount(bps, 2).String()
+}
@@ -0,0 +1,352 @@
+package pricing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/srpvpn/tensor-go-sdk/api/tswap"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+func prices(quotes []Quote) []money.Lamports {
+	out := make([]money.Lamports, len(quotes))
+	for i, q := range quotes {
+		out[i] = q.Price
+	}
+	return out
+}
+
+func equalLamports(a, b []money.Lamports) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPool_BuyAndSellPrices(t *testing.T) {
+	tests := []struct {
+		name      string
+		pool      Pool
+		n         int
+		wantBuys  []money.Lamports
+		wantSells []money.Lamports
+	}{
+		{
+			name:      "linear trade pool sells one step below buys",
+			pool:      Pool{Type: PoolTrade, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1"), DeltaLamports: money.MustParseSOL("0.1")},
+			n:         3,
+			wantBuys:  []money.Lamports{money.MustParseSOL("1"), money.MustParseSOL("1.1"), money.MustParseSOL("1.2")},
+			wantSells: []money.Lamports{money.MustParseSOL("0.9"), money.MustParseSOL("0.8"), money.MustParseSOL("0.7")},
+		},
+		{
+			name:      "linear token pool sells start at the spot price",
+			pool:      Pool{Type: PoolToken, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1"), DeltaLamports: money.MustParseSOL("0.25")},
+			n:         3,
+			wantSells: []money.Lamports{money.MustParseSOL("1"), money.MustParseSOL("0.75"), money.MustParseSOL("0.5")},
+		},
+		{
+			name:     "linear NFT pool",
+			pool:     Pool{Type: PoolNFT, Curve: CurveLinear, StartingPrice: money.MustParseSOL("2"), DeltaLamports: money.MustParseSOL("0.5")},
+			n:        3,
+			wantBuys: []money.Lamports{money.MustParseSOL("2"), money.MustParseSOL("2.5"), money.MustParseSOL("3")},
+		},
+		{
+			name:      "linear curve stops before reaching zero",
+			pool:      Pool{Type: PoolToken, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1"), DeltaLamports: money.MustParseSOL("0.4")},
+			n:         5,
+			wantSells: []money.Lamports{money.MustParseSOL("1"), money.MustParseSOL("0.6"), money.MustParseSOL("0.2")},
+		},
+		{
+			name:      "zero delta is a flat price",
+			pool:      Pool{Type: PoolTrade, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1")},
+			n:         2,
+			wantBuys:  []money.Lamports{money.MustParseSOL("1"), money.MustParseSOL("1")},
+			wantSells: []money.Lamports{money.MustParseSOL("1"), money.MustParseSOL("1")},
+		},
+		{
+			name:      "exponential trade pool",
+			pool:      Pool{Type: PoolTrade, Curve: CurveExponential, StartingPrice: money.MustParseSOL("1"), DeltaBps: 1000},
+			n:         3,
+			wantBuys:  []money.Lamports{money.MustParseSOL("1"), money.MustParseSOL("1.1"), money.MustParseSOL("1.21")},
+			wantSells: []money.Lamports{909_090_909, 826_446_280, 751_314_800},
+		},
+		{
+			name:      "exponential token pool never reaches zero",
+			pool:      Pool{Type: PoolToken, Curve: CurveExponential, StartingPrice: money.MustParseSOL("1"), DeltaBps: 5000},
+			n:         4,
+			wantSells: []money.Lamports{money.MustParseSOL("1"), 666_666_666, 444_444_444, 296_296_296},
+		},
+		{
+			name:      "offset continues an existing curve",
+			pool:      Pool{Type: PoolTrade, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1"), DeltaLamports: money.MustParseSOL("0.1"), Offset: 2},
+			n:         2,
+			wantBuys:  []money.Lamports{money.MustParseSOL("1.2"), money.MustParseSOL("1.3")},
+			wantSells: []money.Lamports{money.MustParseSOL("1.1"), money.MustParseSOL("1")},
+		},
+		{
+			name:      "sell cap limits taker sells",
+			pool:      Pool{Type: PoolToken, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1"), DeltaLamports: money.MustParseSOL("0.1"), Offset: -1, MaxTakerSellCount: 3},
+			n:         5,
+			wantSells: []money.Lamports{money.MustParseSOL("0.9"), money.MustParseSOL("0.8")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.pool.Type != PoolToken {
+				buys, err := tt.pool.BuyQuotes(tt.n, Fees{})
+				if err != nil {
+					t.Fatalf("BuyQuotes() error = %v", err)
+				}
+				if got := prices(buys); !equalLamports(got, tt.wantBuys) {
+					t.Errorf("buy prices = %v, want %v", got, tt.wantBuys)
+				}
+			}
+			if tt.pool.Type != PoolNFT {
+				sells, err := tt.pool.SellQuotes(tt.n, Fees{})
+				if err != nil {
+					t.Fatalf("SellQuotes() error = %v", err)
+				}
+				if got := prices(sells); !equalLamports(got, tt.wantSells) {
+					t.Errorf("sell prices = %v, want %v", got, tt.wantSells)
+				}
+			}
+		})
+	}
+}
+
+func TestPool_QuoteFees(t *testing.T) {
+	pool := Pool{Type: PoolTrade, Curve: CurveLinear, StartingPrice: money.MustParseSOL("2"), DeltaLamports: money.MustParseSOL("1"), MmFeeBps: 200}
+	fees := Fees{RoyaltyBps: 500, TakerFeeBps: 150}
+
+	buys, err := pool.BuyQuotes(1, fees)
+	if err != nil {
+		t.Fatalf("BuyQuotes() error = %v", err)
+	}
+	wantBuy := Quote{
+		Side:       Buy,
+		Price:      money.MustParseSOL("2"),
+		MMFee:      money.MustParseSOL("0.04"),
+		Royalty:    money.MustParseSOL("0.1"),
+		TakerFee:   money.MustParseSOL("0.03"),
+		Total:      money.MustParseSOL("2.17"),
+		PoolChange: money.MustParseSOL("2.04"),
+	}
+	if buys[0] != wantBuy {
+		t.Errorf("buy quote = %+v, want %+v", buys[0], wantBuy)
+	}
+
+	sells, err := pool.SellQuotes(1, fees)
+	if err != nil {
+		t.Fatalf("SellQuotes() error = %v", err)
+	}
+	wantSell := Quote{
+		Side:       Sell,
+		Price:      money.MustParseSOL("1"),
+		MMFee:      money.MustParseSOL("0.02"),
+		Royalty:    money.MustParseSOL("0.05"),
+		TakerFee:   money.MustParseSOL("0.015"),
+		Total:      money.MustParseSOL("0.915"),
+		PoolChange: -money.MustParseSOL("0.98"),
+	}
+	if sells[0] != wantSell {
+		t.Errorf("sell quote = %+v, want %+v", sells[0], wantSell)
+	}
+}
+
+func TestPool_Errors(t *testing.T) {
+	valid := Pool{Type: PoolTrade, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1")}
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr string
+	}{
+		{"invalid pool type", func() error { p := valid; p.Type = "SWAP"; return p.Validate() }, "invalid poolType"},
+		{"invalid curve", func() error { p := valid; p.Curve = "log"; return p.Validate() }, "invalid curveType"},
+		{"zero starting price", func() error { p := valid; p.StartingPrice = 0; return p.Validate() }, "startingPrice must be > 0"},
+		{"negative delta", func() error { p := valid; p.DeltaLamports = -1; return p.Validate() }, "delta must be >= 0"},
+		{"bps on linear curve", func() error { p := valid; p.DeltaBps = 100; return p.Validate() }, "deltaBps is only used by exponential curves"},
+		{"lamports on exponential curve", func() error {
+			p := valid
+			p.Curve, p.DeltaLamports = CurveExponential, money.MustParseSOL("0.1")
+			return p.Validate()
+		}, "deltaLamports is only used by linear curves"},
+		{"mm fee too high", func() error { p := valid; p.MmFeeBps = 10_000; return p.Validate() }, "mmFeeBps must be between"},
+		{"mm fee on NFT pool", func() error { p := valid; p.Type, p.MmFeeBps = PoolNFT, 100; return p.Validate() }, "only charged by TRADE pools"},
+		{"negative sell cap", func() error { p := valid; p.MaxTakerSellCount = -1; return p.Validate() }, "maxTakerSellCount must be >= 0"},
+		{"royalty too high", func() error { _, err := valid.BuyQuotes(1, Fees{RoyaltyBps: 10_001}); return err }, "royaltyBps must be between"},
+		{"negative taker fee", func() error { _, err := valid.SellQuotes(1, Fees{TakerFeeBps: -1}); return err }, "takerFeeBps must be between"},
+		{"negative count", func() error { _, err := valid.BuyQuotes(-1, Fees{}); return err }, "count must be >= 0"},
+		{"token pool buy", func() error { p := valid; p.Type = PoolToken; _, err := p.BuyQuotes(1, Fees{}); return err }, "TOKEN pools do not sell NFTs"},
+		{"NFT pool sell", func() error { p := valid; p.Type = PoolNFT; _, err := p.SellQuotes(1, Fees{}); return err }, "NFT pools do not buy NFTs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPool_SOLNeeded(t *testing.T) {
+	tests := []struct {
+		name    string
+		pool    Pool
+		nfts    int
+		want    money.Lamports
+		wantErr string
+	}{
+		{
+			name: "token pool",
+			pool: Pool{Type: PoolToken, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1"), DeltaLamports: money.MustParseSOL("0.1")},
+			nfts: 3,
+			want: money.MustParseSOL("2.7"),
+		},
+		{
+			name: "trade pool ignores the MM fee",
+			pool: Pool{Type: PoolTrade, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1"), DeltaLamports: money.MustParseSOL("0.1"), MmFeeBps: 500},
+			nfts: 2,
+			want: money.MustParseSOL("1.7"),
+		},
+		{
+			name: "exponential",
+			pool: Pool{Type: PoolToken, Curve: CurveExponential, StartingPrice: money.MustParseSOL("1"), DeltaBps: 5000},
+			nfts: 3,
+			want: money.MustParseSOL("2.11111111"),
+		},
+		{
+			name: "zero NFTs",
+			pool: Pool{Type: PoolToken, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1")},
+			want: 0,
+		},
+		{
+			name:    "curve runs out",
+			pool:    Pool{Type: PoolToken, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1"), DeltaLamports: money.MustParseSOL("0.5")},
+			nfts:    3,
+			wantErr: "pool can only buy 2 of 3 NFTs",
+		},
+		{
+			name:    "NFT pool",
+			pool:    Pool{Type: PoolNFT, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1")},
+			nfts:    1,
+			wantErr: "NFT pools do not buy NFTs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.pool.SOLNeeded(tt.nfts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SOLNeeded() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SOLNeeded() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SOLNeeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPool_SpotPriceOverflow(t *testing.T) {
+	pool := Pool{Type: PoolNFT, Curve: CurveExponential, StartingPrice: money.MustParseSOL("1000"), DeltaBps: 10_000, Offset: 40}
+	if _, ok := pool.SpotPrice(); ok {
+		t.Fatal("SpotPrice() ok = true, want overflow")
+	}
+	pool.Offset = 0
+	quotes, err := pool.BuyQuotes(100, Fees{})
+	if err != nil {
+		t.Fatalf("BuyQuotes() error = %v", err)
+	}
+	if len(quotes) == 0 || len(quotes) == 100 {
+		t.Errorf("BuyQuotes() returned %d quotes, want the curve to stop at overflow", len(quotes))
+	}
+}
+
+func TestFromEditRequest(t *testing.T) {
+	fee := 250.0
+	cap := int32(5)
+	req := &tswap.EditTSwapPoolRequest{
+		PoolAddress:       "pool",
+		PoolType:          "TRADE",
+		CurveType:         "exponential",
+		StartingPrice:     money.MustParseSOL("1.5"),
+		Delta:             300,
+		MmFeeBps:          &fee,
+		MaxTakerSellCount: &cap,
+	}
+	pool, err := FromEditRequest(req)
+	if err != nil {
+		t.Fatalf("FromEditRequest() error = %v", err)
+	}
+	want := Pool{Type: PoolTrade, Curve: CurveExponential, StartingPrice: money.MustParseSOL("1.5"), DeltaBps: 300, MmFeeBps: 250, MaxTakerSellCount: 5}
+	if pool != want {
+		t.Errorf("FromEditRequest() = %+v, want %+v", pool, want)
+	}
+	if got := pool.String(); got != "TRADE exponential 1.5 SOL ± 3%, 2.5% MM fee" {
+		t.Errorf("String() = %q", got)
+	}
+
+	req.CurveType = "sigmoid"
+	if _, err := FromEditRequest(req); err == nil {
+		t.Error("FromEditRequest() with invalid curve error = nil")
+	}
+	if _, err := FromEditRequest(nil); err == nil {
+		t.Error("FromEditRequest(nil) error = nil")
+	}
+}
+
+func TestPool_Ladder(t *testing.T) {
+	pool := Pool{Type: PoolTrade, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1"), DeltaLamports: money.MustParseSOL("0.4"), MmFeeBps: 100}
+	ladder, err := pool.Ladder(4, Fees{RoyaltyBps: 500})
+	if err != nil {
+		t.Fatalf("Ladder() error = %v", err)
+	}
+	if len(ladder.Rows) != 4 {
+		t.Fatalf("Ladder() rows = %d, want 4", len(ladder.Rows))
+	}
+
+	// Sells run 0.6, 0.2 and then the curve ends
+	if ladder.Rows[2].Sell != nil || ladder.Rows[3].Sell != nil {
+		t.Errorf("rows 3 and 4 should have no sell quote")
+	}
+	if got, want := ladder.Rows[1].CumulativeSell, money.MustParseSOL("0.564")+money.MustParseSOL("0.188"); got != want {
+		t.Errorf("CumulativeSell = %v, want %v", got, want)
+	}
+	if got, want := ladder.Rows[3].CumulativeBuy, money.MustParseSOL("1.06")+money.MustParseSOL("1.484")+money.MustParseSOL("1.908")+money.MustParseSOL("2.332"); got != want {
+		t.Errorf("CumulativeBuy = %v, want %v", got, want)
+	}
+	if ladder.Rows[3].CumulativeSell != ladder.Rows[1].CumulativeSell {
+		t.Errorf("CumulativeSell should carry over once the curve ends")
+	}
+
+	table := ladder.String()
+	for _, want := range []string{"TRADE linear 1 SOL ± 0.4 SOL, 1% MM fee", "level", "taker pays", "2.332", "0.564"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("table missing %q:\n%s", want, table)
+		}
+	}
+	if lines := strings.Count(table, "\n"); lines != 6 {
+		t.Errorf("table has %d lines, want 6:\n%s", lines, table)
+	}
+
+	tokenLadder, err := Pool{Type: PoolToken, Curve: CurveLinear, StartingPrice: money.MustParseSOL("1")}.Ladder(2, Fees{})
+	if err != nil {
+		t.Fatalf("Ladder() error = %v", err)
+	}
+	if tokenLadder.Rows[0].Buy != nil || tokenLadder.Rows[0].Sell == nil {
+		t.Errorf("token pool ladder should only quote sells")
+	}
+}
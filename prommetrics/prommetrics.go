@@ -0,0 +1,259 @@
+// Package prommetrics implements client.Meter with an in-memory registry that is
+// exposed in the Prometheus text exposition format.
+//
+//	reg := prommetrics.New()
+//	c := client.New(&client.Config{Meter: reg})
+//	http.Handle("/metrics", reg)
+package prommetrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/srpvpn/tensor-go-sdk/client"
+)
+
+// DefaultBuckets are the histogram upper bounds in seconds, matching the Prometheus client defaults
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry holds metric instruments and serves them over HTTP
+type Registry struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	counters   map[string]*counter
+	histograms map[string]*histogram
+}
+
+var (
+	_ client.Meter = (*Registry)(nil)
+	_ http.Handler = (*Registry)(nil)
+)
+
+// New creates an empty registry using DefaultBuckets
+func New() *Registry {
+	return NewWithBuckets(DefaultBuckets)
+}
+
+// NewWithBuckets creates an empty registry whose histograms use the given upper bounds
+func NewWithBuckets(buckets []float64) *Registry {
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	return &Registry{
+		buckets:    b,
+		counters:   make(map[string]*counter),
+		histograms: make(map[string]*histogram),
+	}
+}
+
+// Int64Counter returns the counter with the given name, creating it if needed
+func (r *Registry) Int64Counter(name, description string) client.Counter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.counters[name]
+	if !ok {
+		c = &counter{name: name, help: description, series: make(map[string]*counterSeries)}
+		r.counters[name] = c
+	}
+	return c
+}
+
+// Float64Histogram returns the histogram with the given name, creating it if needed.
+// The unit is informational; values are recorded as given.
+func (r *Registry) Float64Histogram(name, description, unit string) client.Histogram {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, ok := r.histograms[name]
+	if !ok {
+		h = &histogram{name: name, help: description, buckets: r.buckets, series: make(map[string]*histogramSeries)}
+		r.histograms[name] = h
+	}
+	return h
+}
+
+// ServeHTTP writes every metric in the Prometheus text format
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.WriteText(w)
+}
+
+// WriteText writes every metric in the Prometheus text format, sorted by name and labels
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	metrics := make([]metric, 0, len(r.counters)+len(r.histograms))
+	for _, c := range r.counters {
+		metrics = append(metrics, c)
+	}
+	for _, h := range r.histograms {
+		metrics = append(metrics, h)
+	}
+	r.mu.Unlock()
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].metricName() < metrics[j].metricName() })
+
+	var b strings.Builder
+	for _, m := range metrics {
+		m.write(&b)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// metric is a counter or histogram as WriteText sees it
+type metric interface {
+	metricName() string
+	write(b *strings.Builder)
+}
+
+type counterSeries struct {
+	labels string
+	value  int64
+}
+
+type counter struct {
+	name, help string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+// Add increments the series identified by attrs
+func (c *counter) Add(_ context.Context, delta int64, attrs ...client.Attribute) {
+	labels := formatLabels(attrs)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[labels]
+	if !ok {
+		s = &counterSeries{labels: labels}
+		c.series[labels] = s
+	}
+	s.value += delta
+}
+
+func (c *counter) metricName() string { return c.name }
+
+func (c *counter) write(b *strings.Builder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	writeHeader(b, c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		fmt.Fprintf(b, "%s%s %d\n", c.name, wrap(s.labels), s.value)
+	}
+}
+
+type histogramSeries struct {
+	labels string
+	counts []uint64 // Cumulative counts per bucket
+	count  uint64
+	sum    float64
+}
+
+type histogram struct {
+	name, help string
+	buckets    []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+// Record adds value to the series identified by attrs
+func (h *histogram) Record(_ context.Context, value float64, attrs ...client.Attribute) {
+	labels := formatLabels(attrs)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[labels]
+	if !ok {
+		s = &histogramSeries{labels: labels, counts: make([]uint64, len(h.buckets))}
+		h.series[labels] = s
+	}
+	for i, upper := range h.buckets {
+		if value <= upper {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+func (h *histogram) metricName() string { return h.name }
+
+func (h *histogram) write(b *strings.Builder) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	writeHeader(b, h.name, h.help, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		for i, upper := range h.buckets {
+			fmt.Fprintf(b, "%s_bucket%s %d\n", h.name, wrap(join(s.labels, `le="`+formatFloat(upper)+`"`)), s.counts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", h.name, wrap(join(s.labels, `le="+Inf"`)), s.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", h.name, wrap(s.labels), formatFloat(s.sum))
+		fmt.Fprintf(b, "%s_count%s %d\n", h.name, wrap(s.labels), s.count)
+	}
+}
+
+func writeHeader(b *strings.Builder, name, help, kind string) {
+	if help != "" {
+		fmt.Fprintf(b, "# HELP %s %s\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help))
+	}
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, kind)
+}
+
+// formatLabels renders attrs as a sorted, escaped label list without braces
+func formatLabels(attrs []client.Attribute) string {
+	sorted := append([]client.Attribute(nil), attrs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+	parts := make([]string, len(sorted))
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	for i, a := range sorted {
+		parts[i] = sanitizeName(a.Key) + `="` + escape.Replace(a.Value) + `"`
+	}
+	return strings.Join(parts, ",")
+}
+
+// sanitizeName replaces characters that are not valid in Prometheus label names
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func join(labels, extra string) string {
+	if labels == "" {
+		return extra
+	}
+	return labels + "," + extra
+}
+
+func wrap(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
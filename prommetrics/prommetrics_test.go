@@ -0,0 +1,112 @@
+package prommetrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/srpvpn/tensor-go-sdk/api/rpc"
+	"github.com/srpvpn/tensor-go-sdk/client"
+)
+
+func scrape(t *testing.T, reg *Registry) string {
+	t.Helper()
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("scrape failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestRegistry_TextFormat(t *testing.T) {
+	reg := NewWithBuckets([]float64{0.1, 1})
+	ctx := context.Background()
+
+	c := reg.Int64Counter("requests_total", "Requests")
+	c.Add(ctx, 2, client.Attribute{Key: "status", Value: "200"}, client.Attribute{Key: "endpoint", Value: "/a"})
+	c.Add(ctx, 1, client.Attribute{Key: "endpoint", Value: "/a"}, client.Attribute{Key: "status", Value: "200"})
+	c.Add(ctx, 1, client.Attribute{Key: "endpoint", Value: `/b"x`}, client.Attribute{Key: "status", Value: "500"})
+
+	h := reg.Float64Histogram("latency_seconds", "Latency", "s")
+	h.Record(ctx, 0.05)
+	h.Record(ctx, 0.5)
+	h.Record(ctx, 3)
+
+	want := `# HELP latency_seconds Latency
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 3.55
+latency_seconds_count 3
+# HELP requests_total Requests
+# TYPE requests_total counter
+requests_total{endpoint="/a",status="200"} 3
+requests_total{endpoint="/b\"x",status="500"} 1
+`
+	if got := scrape(t, reg); got != want {
+		t.Errorf("scrape =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRegistry_ClientMetrics(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"min":1,"low":2,"medium":3,"high":4,"veryHigh":5}`))
+	}))
+	defer api.Close()
+
+	reg := New()
+	c := client.New(&client.Config{BaseURL: api.URL, Meter: reg})
+	for i := 0; i < 3; i++ {
+		if _, _, err := c.RPC.GetPriorityFees(context.Background(), &rpc.PriorityFeesRequest{}); err != nil {
+			t.Fatalf("GetPriorityFees() error = %v", err)
+		}
+	}
+
+	body := scrape(t, reg)
+	for _, line := range []string{
+		`tensor_requests_total{endpoint="/api/v1/rpc/priority_fees",status="200"} 3`,
+		`tensor_request_duration_seconds_count{endpoint="/api/v1/rpc/priority_fees"} 3`,
+		`tensor_request_duration_seconds_bucket{endpoint="/api/v1/rpc/priority_fees",le="+Inf"} 3`,
+		`# TYPE tensor_request_errors_total counter`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("scrape is missing %q:\n%s", line, body)
+		}
+	}
+}
+
+func TestRegistry_WriteTextWhileRegistering(t *testing.T) {
+	reg := New()
+	reg.Int64Counter("counter", "")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 2000; i++ {
+			reg.Int64Counter(fmt.Sprintf("counter_%d", i), "")
+			reg.Float64Histogram(fmt.Sprintf("histogram_%d", i), "", "s")
+		}
+	}()
+	for {
+		if err := reg.WriteText(io.Discard); err != nil {
+			t.Fatalf("WriteText() error = %v", err)
+		}
+		select {
+		case <-done:
+			return
+		default:
+		}
+	}
+}
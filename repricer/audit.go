@@ -0,0 +1,92 @@
+package repricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// Action is what the repricer did with a listing
+type Action string
+
+// Actions
+const (
+	ActionHold Action = "hold" // The listing was left alone
+	ActionEdit Action = "edit" // The listing was moved to Target
+	ActionFail Action = "fail" // The listing should have been checked or moved but could not be
+)
+
+// Decision records how the rules handled one listing in one step
+type Decision struct {
+	Time   time.Time      `json:"time"`
+	Mint   string         `json:"mint"`
+	CollId string         `json:"collId"`
+	Price  money.Lamports `json:"price"`  // Listing price before the step
+	Floor  money.Lamports `json:"floor"`  // Cheapest competing listing, zero if none
+	Target money.Lamports `json:"target"` // Price the rules ask for, zero if there is no floor
+	Action Action         `json:"action"`
+	Reason string         `json:"reason"`
+	Error  string         `json:"error,omitempty"`
+
+	Txs []marketplace.Transaction `json:"-"` // Transactions of a successful edit
+}
+
+// fail marks the decision as failed
+func (d *Decision) fail(reason string, err error) {
+	d.Action, d.Reason, d.Error = ActionFail, reason, err.Error()
+}
+
+// AuditLog stores repricing decisions
+type AuditLog interface {
+	// Record stores one decision. Implementations must be safe for concurrent use.
+	Record(d Decision) error
+}
+
+// JSONAudit writes each decision as one line of JSON
+type JSONAudit struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONAudit creates an audit log that writes JSON lines to w
+func NewJSONAudit(w io.Writer) *JSONAudit {
+	return &JSONAudit{enc: json.NewEncoder(w)}
+}
+
+// Record writes d
+func (a *JSONAudit) Record(d Decision) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.enc.Encode(d); err != nil {
+		return fmt.Errorf("failed to write decision: %w", err)
+	}
+	return nil
+}
+
+// MemoryAudit keeps decisions in memory
+type MemoryAudit struct {
+	mu        sync.Mutex
+	decisions []Decision
+}
+
+// Record appends d
+func (a *MemoryAudit) Record(d Decision) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.decisions = append(a.decisions, d)
+	return nil
+}
+
+// Decisions returns a copy of the recorded decisions, oldest first
+func (a *MemoryAudit) Decisions() []Decision {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]Decision, len(a.decisions))
+	copy(out, a.decisions)
+	return out
+}
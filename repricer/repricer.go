@@ -0,0 +1,242 @@
+// Package repricer keeps a wallet's listings just under the competing floor.
+// Each Step reads the wallet's active listings and the cheapest listing of
+// each collection that belongs to someone else, then moves every listing to
+// the floor minus a tick, never below a minimum price and never more often
+// than a cooldown allows. Every decision, including the listings it leaves
+// alone, is written to an audit log.
+package repricer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// Config describes what the repricer manages and the rules it follows
+type Config struct {
+	Owner  string // Wallet whose listings are repriced
+	CollId string // Restricts repricing to one collection; empty for every collection the wallet lists in
+
+	Tick     money.Lamports // Amount to list under the competing floor; zero to match it
+	MinPrice money.Lamports // Lowest price a listing is ever moved to
+	Cooldown time.Duration  // Least time between two edits of the same listing
+
+	// Audit receives every decision. It is required.
+	Audit AuditLog
+	// Logger receives edits and failures. Nothing is logged when nil.
+	Logger *slog.Logger
+}
+
+// validate checks the config fields
+func (c *Config) validate() error {
+	if c.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if c.Tick < 0 {
+		return fmt.Errorf("tick must be >= 0")
+	}
+	if c.MinPrice <= 0 {
+		return fmt.Errorf("minPrice must be > 0")
+	}
+	if c.Cooldown < 0 {
+		return fmt.Errorf("cooldown must be >= 0")
+	}
+	if c.Audit == nil {
+		return fmt.Errorf("audit log is required")
+	}
+	return nil
+}
+
+// APIs are the SDK services the repricer uses. The fields match those of client.Client.
+type APIs struct {
+	User        user.UserAPI // Reads the wallet's listings
+	NFTs        nfts.NFTsAPI // Reads collection floors
+	Marketplace marketplace.MarketplaceAPI
+}
+
+// Repricer moves a wallet's listings toward the competing floor. It is safe
+// for concurrent use, but Steps are serialized.
+type Repricer struct {
+	cfg         Config
+	apis        APIs
+	blockhashes cluster.BlockhashSource
+	logger      *slog.Logger
+	now         func() time.Time
+
+	mu       sync.Mutex
+	lastEdit map[string]time.Time // Time of the last edit by mint
+}
+
+// New creates a Repricer
+//
+// Returns:
+//   - The repricer
+//   - An error if the config is incomplete or a required API is nil
+func New(cfg Config, apis APIs, blockhashes cluster.BlockhashSource) (*Repricer, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if apis.User == nil || apis.NFTs == nil || apis.Marketplace == nil {
+		return nil, fmt.Errorf("user, NFTs and marketplace APIs are required")
+	}
+	if blockhashes == nil {
+		return nil, fmt.Errorf("blockhash source is required")
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return &Repricer{
+		cfg:         cfg,
+		apis:        apis,
+		blockhashes: blockhashes,
+		logger:      logger.With("owner", cfg.Owner),
+		now:         time.Now,
+		lastEdit:    make(map[string]time.Time),
+	}, nil
+}
+
+// Target returns the price the rules give a listing when the competing floor
+// is floor: a tick under it, but never below the minimum
+func (r *Repricer) Target(floor money.Lamports) money.Lamports {
+	return max(floor-r.cfg.Tick, r.cfg.MinPrice)
+}
+
+// Step reprices every listing once. Listings are handled in mint order, and
+// each collection's floor is read once per step.
+//
+// Returns:
+//   - The decisions, one per listing
+//   - An error if the listings could not be read or a decision could not be
+//     audited; failed edits and floor reads are reported as decisions
+func (r *Repricer) Step(ctx context.Context) ([]Decision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	listings, err := market.Listings(ctx, r.apis.User, r.cfg.Owner, r.cfg.CollId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read listings: %w", err)
+	}
+	sort.Slice(listings, func(i, j int) bool { return listings[i].Mint < listings[j].Mint })
+
+	type floorResult struct {
+		price money.Lamports
+		err   error
+	}
+	floors := make(map[string]floorResult)
+	var blockhash string
+	var decisions []Decision
+	var errs []error
+	for _, n := range listings {
+		if n.Listing == nil {
+			continue
+		}
+		f, ok := floors[n.CollId]
+		if !ok {
+			nft, found, err := market.Floor(ctx, r.apis.NFTs, n.CollId, r.cfg.Owner)
+			if found {
+				f.price = nft.Listing.Price
+			}
+			f.err = err
+			floors[n.CollId] = f
+		}
+
+		d := r.decide(n, f.price, f.err)
+		if d.Action == ActionEdit && blockhash == "" {
+			if blockhash, _, err = r.blockhashes.LatestBlockhash(ctx); err != nil {
+				blockhash = ""
+				d.fail("blockhash unavailable", err)
+			}
+		}
+		if d.Action == ActionEdit {
+			r.edit(ctx, &d, blockhash)
+		}
+		if err := r.cfg.Audit.Record(d); err != nil {
+			errs = append(errs, fmt.Errorf("failed to audit %s: %w", d.Mint, err))
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions, errors.Join(errs...)
+}
+
+// decide applies the rules to one listing
+func (r *Repricer) decide(n market.NFT, floor money.Lamports, floorErr error) Decision {
+	now := r.now()
+	d := Decision{Time: now, Mint: n.Mint, CollId: n.CollId, Price: n.Listing.Price, Floor: floor}
+	switch {
+	case floorErr != nil:
+		d.fail("floor unavailable", floorErr)
+		return d
+	case floor == 0:
+		d.Action, d.Reason = ActionHold, "no competing listings"
+		return d
+	}
+
+	d.Target = r.Target(floor)
+	switch last, edited := r.lastEdit[n.Mint]; {
+	case d.Target == d.Price:
+		d.Action, d.Reason = ActionHold, "at target"
+	case edited && now.Sub(last) < r.cfg.Cooldown:
+		d.Action, d.Reason = ActionHold, fmt.Sprintf("cooldown until %s", last.Add(r.cfg.Cooldown).Format(time.RFC3339))
+	case floor-r.cfg.Tick < r.cfg.MinPrice:
+		d.Action, d.Reason = ActionEdit, "floor minus tick is under the minimum price"
+	case d.Target < d.Price:
+		d.Action, d.Reason = ActionEdit, "undercut"
+	default:
+		d.Action, d.Reason = ActionEdit, "floor rose"
+	}
+	return d
+}
+
+// edit moves the listing to its target price
+func (r *Repricer) edit(ctx context.Context, d *Decision, blockhash string) {
+	resp, _, err := r.apis.Marketplace.EditListing(ctx, &marketplace.EditListingRequest{
+		Mint:      d.Mint,
+		Owner:     r.cfg.Owner,
+		Price:     d.Target,
+		Blockhash: blockhash,
+	})
+	if err != nil {
+		d.fail("edit failed", err)
+		r.logger.Warn("listing edit failed", "mint", d.Mint, "target", d.Target.SOL(), "error", err)
+		return
+	}
+	d.Txs = resp.Txs
+	r.lastEdit[d.Mint] = d.Time
+	r.logger.Info("listing repriced", "mint", d.Mint, "from", d.Price.SOL(), "to", d.Target.SOL(), "floor", d.Floor.SOL(), "reason", d.Reason)
+}
+
+// Run calls Step every interval until ctx is done. Step errors are logged and
+// do not stop the loop.
+//
+// Returns:
+//   - The context's error
+func (r *Repricer) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be > 0")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Step(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("reprice step failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package repricer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	"github.com/srpvpn/tensor-go-sdk/fakes"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+const (
+	owner  = "owner"
+	collA  = "coll-a"
+	collB  = "coll-b"
+	rival  = "rival"
+	minute = time.Minute
+)
+
+var start = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// book is a fake market: our listings by mint and the competing floor by collection
+type book struct {
+	listings map[string]market.NFT
+	floors   map[string]money.Lamports
+}
+
+func (b *book) list(mint, collID, price string) {
+	b.listings[mint] = market.NFT{Mint: mint, CollId: collID, Owner: owner, Listing: &market.Listing{Price: money.MustParseSOL(price), Seller: owner}}
+}
+
+// fakeMarket wires fakes for the three APIs to a book
+func fakeMarket(t *testing.T) (*book, *fakes.User, *fakes.NFTs, *fakes.Marketplace) {
+	t.Helper()
+	b := &book{listings: make(map[string]market.NFT), floors: make(map[string]money.Lamports)}
+	u, n, m := &fakes.User{}, &fakes.NFTs{}, &fakes.Marketplace{}
+
+	u.GetListingsStub.Handle(func(_ context.Context, req *user.ListingsRequest) ([]byte, int, error) {
+		var out []market.NFT
+		for _, nft := range b.listings {
+			if req.CollId == nil || *req.CollId == nft.CollId {
+				out = append(out, nft)
+			}
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].Listing.Price < out[j].Listing.Price })
+		return mustJSON(t, map[string]any{"listings": out}), 200, nil
+	})
+	n.GetNFTsByCollectionStub.Handle(func(_ context.Context, req *nfts.NFTsByCollectionRequest) ([]byte, int, error) {
+		if req.SortBy != "PriceAsc" || len(req.ExcludeOwners) != 1 || req.ExcludeOwners[0] != owner {
+			t.Errorf("GetNFTsByCollection(%+v), want PriceAsc excluding the owner", req)
+		}
+		out := []market.NFT{}
+		if floor, ok := b.floors[req.CollId]; ok {
+			out = append(out, market.NFT{Mint: "rival-" + req.CollId, CollId: req.CollId, Owner: rival, Listing: &market.Listing{Price: floor, Seller: rival}})
+		}
+		return mustJSON(t, map[string]any{"mints": out}), 200, nil
+	})
+	m.EditListingStub.Handle(func(_ context.Context, req *marketplace.EditListingRequest) (*marketplace.EditListingResponse, int, error) {
+		nft := b.listings[req.Mint]
+		nft.Listing = &market.Listing{Price: req.Price, Seller: owner}
+		b.listings[req.Mint] = nft
+		return &marketplace.EditListingResponse{Txs: []marketplace.Transaction{{TxV0: "edit-" + req.Mint}}}, 200, nil
+	})
+	return b, u, n, m
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return data
+}
+
+func newTestRepricer(t *testing.T, cfg Config, u *fakes.User, n *fakes.NFTs, m *fakes.Marketplace, clock *time.Time) *Repricer {
+	t.Helper()
+	r, err := New(cfg, APIs{User: u, NFTs: n, Marketplace: m}, cluster.StaticBlockhash("hash"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r.now = func() time.Time { return *clock }
+	return r
+}
+
+// summary renders decisions as "mint:action:target" for comparison
+func summary(ds []Decision) string {
+	var out []string
+	for _, d := range ds {
+		out = append(out, d.Mint+":"+string(d.Action)+":"+d.Target.SOL())
+	}
+	return strings.Join(out, " ")
+}
+
+func TestStepFollowsRules(t *testing.T) {
+	b, u, n, m := fakeMarket(t)
+	b.list("a", collA, "2")
+	b.list("b", collA, "1.5")
+	b.floors[collA] = money.MustParseSOL("1.8")
+	audit := &MemoryAudit{}
+	clock := start
+	r := newTestRepricer(t, Config{Owner: owner, Tick: money.MustParseSOL("0.01"), MinPrice: money.MustParseSOL("1"), Cooldown: 10 * minute, Audit: audit}, u, n, m, &clock)
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		floor   string
+		want    string
+		reasons []string
+	}{
+		{name: "undercut and raise", floor: "1.8", want: "a:edit:1.79 b:edit:1.79", reasons: []string{"undercut", "floor rose"}},
+		{name: "cooldown", advance: 5 * minute, floor: "1.5", want: "a:hold:1.49 b:hold:1.49", reasons: []string{"cooldown until 2026-03-01T12:10:00Z"}},
+		{name: "after cooldown", advance: 6 * minute, floor: "1.5", want: "a:edit:1.49 b:edit:1.49", reasons: []string{"undercut"}},
+		{name: "minimum price", advance: 11 * minute, floor: "0.5", want: "a:edit:1 b:edit:1", reasons: []string{"floor minus tick is under the minimum price"}},
+		{name: "at minimum", advance: 11 * minute, floor: "0.5", want: "a:hold:1 b:hold:1", reasons: []string{"at target"}},
+		{name: "no floor", advance: time.Hour, want: "a:hold:0 b:hold:0", reasons: []string{"no competing listings"}},
+	}
+	for _, step := range steps {
+		clock = clock.Add(step.advance)
+		delete(b.floors, collA)
+		if step.floor != "" {
+			b.floors[collA] = money.MustParseSOL(step.floor)
+		}
+		ds, err := r.Step(context.Background())
+		if err != nil {
+			t.Fatalf("%s: Step() error = %v", step.name, err)
+		}
+		if got := summary(ds); got != step.want {
+			t.Errorf("%s: decisions = %s, want %s", step.name, got, step.want)
+		}
+		for i, d := range ds {
+			if want := step.reasons[min(i, len(step.reasons)-1)]; d.Reason != want {
+				t.Errorf("%s: %s reason = %q, want %q", step.name, d.Mint, d.Reason, want)
+			}
+		}
+	}
+
+	if got := len(audit.Decisions()); got != 2*len(steps) {
+		t.Errorf("audit has %d decisions, want %d", got, 2*len(steps))
+	}
+	if got := m.EditListingStub.CallCount(); got != 6 {
+		t.Errorf("EditListing called %d times, want 6", got)
+	}
+	if last := m.EditListingStub.LastCall(); last.Owner != owner || last.Blockhash != "hash" || last.Price != money.MustParseSOL("1") {
+		t.Errorf("last EditListing = %+v", last)
+	}
+}
+
+func TestStepReadsEachFloorOnce(t *testing.T) {
+	b, u, n, m := fakeMarket(t)
+	b.list("a1", collA, "3")
+	b.list("a2", collA, "3")
+	b.list("b1", collB, "3")
+	b.floors[collA] = money.MustParseSOL("2")
+	b.floors[collB] = money.MustParseSOL("2.5")
+	clock := start
+	r := newTestRepricer(t, Config{Owner: owner, Tick: money.MustParseSOL("0.1"), MinPrice: money.MustParseSOL("0.1"), Audit: &MemoryAudit{}}, u, n, m, &clock)
+
+	ds, err := r.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if got, want := summary(ds), "a1:edit:1.9 a2:edit:1.9 b1:edit:2.4"; got != want {
+		t.Errorf("decisions = %s, want %s", got, want)
+	}
+	if got := n.GetNFTsByCollectionStub.CallCount(); got != 2 {
+		t.Errorf("GetNFTsByCollection called %d times, want 2", got)
+	}
+	if len(ds[0].Txs) != 1 || ds[0].Txs[0].TxV0 != "edit-a1" {
+		t.Errorf("a1 txs = %+v", ds[0].Txs)
+	}
+}
+
+func TestStepRecordsFailures(t *testing.T) {
+	b, u, n, m := fakeMarket(t)
+	b.list("a", collA, "3")
+	b.list("b", collB, "3")
+	b.floors[collA] = money.MustParseSOL("2")
+	n.GetNFTsByCollectionStub.Reset()
+	n.GetNFTsByCollectionStub.Handle(func(_ context.Context, req *nfts.NFTsByCollectionRequest) ([]byte, int, error) {
+		if req.CollId == collB {
+			return nil, 503, errors.New("unavailable")
+		}
+		return mustJSON(t, map[string]any{"mints": []market.NFT{{Mint: "r", Owner: rival, Listing: &market.Listing{Price: money.MustParseSOL("2"), Seller: rival}}}}), 200, nil
+	})
+	m.EditListingStub.Reset()
+	m.EditListingStub.Fails(errors.New("rejected"), 400)
+
+	var buf bytes.Buffer
+	clock := start
+	r := newTestRepricer(t, Config{Owner: owner, Tick: money.MustParseSOL("0.1"), MinPrice: money.MustParseSOL("1"), Cooldown: time.Hour, Audit: NewJSONAudit(&buf)}, u, n, m, &clock)
+	ds, err := r.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if ds[0].Action != ActionFail || ds[0].Reason != "edit failed" || ds[0].Error != "rejected" {
+		t.Errorf("a = %+v, want failed edit", ds[0])
+	}
+	if ds[1].Action != ActionFail || ds[1].Reason != "floor unavailable" {
+		t.Errorf("b = %+v, want floor unavailable", ds[1])
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("audit log has %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	var logged Decision
+	if err := json.Unmarshal([]byte(lines[0]), &logged); err != nil {
+		t.Fatalf("audit line %q: %v", lines[0], err)
+	}
+	if logged.Mint != "a" || logged.Target != money.MustParseSOL("1.9") || logged.Error != "rejected" || !logged.Time.Equal(start) {
+		t.Errorf("audit line = %+v", logged)
+	}
+
+	// A failed edit does not start the cooldown.
+	m.EditListingStub.Reset()
+	m.EditListingStub.Returns(&marketplace.EditListingResponse{}, 200)
+	if ds, _ := r.Step(context.Background()); ds[0].Action != ActionEdit {
+		t.Errorf("retry = %+v, want edit", ds[0])
+	}
+}
+
+func TestStepListingsError(t *testing.T) {
+	u := &fakes.User{}
+	u.GetListingsStub.Fails(errors.New("down"), 503)
+	clock := start
+	r := newTestRepricer(t, Config{Owner: owner, MinPrice: 1, Audit: &MemoryAudit{}}, u, &fakes.NFTs{}, &fakes.Marketplace{}, &clock)
+	if _, err := r.Step(context.Background()); err == nil || !strings.Contains(err.Error(), "failed to read listings") {
+		t.Errorf("Step() error = %v", err)
+	}
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	b, u, n, m := fakeMarket(t)
+	b.list("a", collA, "3")
+	clock := start
+	r := newTestRepricer(t, Config{Owner: owner, MinPrice: 1, Audit: &MemoryAudit{}}, u, n, m, &clock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Millisecond)
+	defer cancel()
+	if err := r.Run(ctx, 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v", err)
+	}
+	if got := u.GetListingsStub.CallCount(); got < 2 {
+		t.Errorf("Run() stepped %d times, want at least 2", got)
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	apis := APIs{User: &fakes.User{}, NFTs: &fakes.NFTs{}, Marketplace: &fakes.Marketplace{}}
+	valid := Config{Owner: owner, MinPrice: 1, Audit: &MemoryAudit{}}
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{name: "no owner", mutate: func(c *Config) { c.Owner = "" }, wantErr: "owner"},
+		{name: "negative tick", mutate: func(c *Config) { c.Tick = -1 }, wantErr: "tick"},
+		{name: "no minimum", mutate: func(c *Config) { c.MinPrice = 0 }, wantErr: "minPrice"},
+		{name: "negative cooldown", mutate: func(c *Config) { c.Cooldown = -time.Second }, wantErr: "cooldown"},
+		{name: "no audit", mutate: func(c *Config) { c.Audit = nil }, wantErr: "audit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.mutate(&cfg)
+			if _, err := New(cfg, apis, cluster.StaticBlockhash("hash")); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := New(valid, APIs{}, cluster.StaticBlockhash("hash")); err == nil {
+		t.Error("New() without APIs succeeded")
+	}
+}
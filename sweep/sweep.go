@@ -0,0 +1,425 @@
+// Package sweep plans and builds floor sweeps: buying the cheapest listings
+// of a collection that fit a budget, an item count and a price cap, with
+// optional trait and rarity filters. Plans price every item with the taker
+// fee and royalty included. Execution builds the BuyNFT transactions with
+// bounded parallelism; listings that are gone by then are skipped and their
+// budget goes to the next cheapest listings.
+package sweep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/nfts"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	apierrors "github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// DefaultConcurrency is the number of transactions built at once when Request.Concurrency is zero
+const DefaultConcurrency = 4
+
+// pageLimit is the number of listings fetched per page
+const pageLimit = 100
+
+// Request describes a sweep
+type Request struct {
+	Buyer  string
+	CollId string
+
+	Budget   money.Lamports // Most SOL to spend, fees and royalties included
+	MaxItems int            // Most NFTs to buy
+	MaxPrice money.Lamports // Highest listing price to pay for one NFT; zero means no cap
+
+	// TakerFeeBps is the marketplace fee charged on each purchase
+	TakerFeeBps int64
+	// RoyaltyBps is the creator royalty charged on each purchase. When nil the
+	// collection's royalty is read through the collections API.
+	RoyaltyBps *int64
+
+	Filter Filter
+
+	// Backups is how many listings beyond the plan are kept to replace ones
+	// that sell before their transaction is built. Defaults to MaxItems.
+	Backups int
+	// Concurrency bounds how many BuyNFT calls run at once
+	Concurrency int
+}
+
+// Filter narrows the listings a sweep buys from
+type Filter struct {
+	// Traits are "type:value" pairs, passed to GetNFTsByCollection
+	Traits []string
+	// RaritySystem, RarityMin and RarityMax filter by rarity points, passed to GetNFTsByCollection
+	RaritySystem *string
+	RarityMin    *float64
+	RarityMax    *float64
+	// MaxRarityRank keeps only NFTs ranked this or better (a lower rank is rarer). Zero means any rank.
+	MaxRarityRank int32
+	// Match is an optional final filter applied to each listing
+	Match func(market.NFT) bool
+}
+
+// matches applies the filters that are evaluated locally
+func (f Filter) matches(n market.NFT) bool {
+	if f.MaxRarityRank > 0 && (n.RarityRank == 0 || n.RarityRank > f.MaxRarityRank) {
+		return false
+	}
+	return f.Match == nil || f.Match(n)
+}
+
+// Item is one listing in a plan, with the full cost of buying it
+type Item struct {
+	NFT      market.NFT
+	Price    money.Lamports // Listing price
+	TakerFee money.Lamports
+	Royalty  money.Lamports
+	Cost     money.Lamports // Price plus fees and royalty
+}
+
+// Plan is the set of listings a sweep will buy
+type Plan struct {
+	Request    Request
+	RoyaltyBps int64
+	Items      []Item // Cheapest first
+	Total      money.Lamports
+	// Backups are the next cheapest listings, used when planned items sell first
+	Backups []Item
+	// StopReason says why the plan has no more items: the item count, the
+	// budget, the price cap, or running out of listings
+	StopReason string
+}
+
+// APIs are the SDK services a Sweeper uses. The fields match those of client.Client.
+type APIs struct {
+	NFTs        nfts.NFTsAPI
+	Marketplace marketplace.MarketplaceAPI
+	// Collections is only needed for requests without RoyaltyBps
+	Collections collections.CollectionsAPI
+}
+
+// Sweeper plans and builds sweeps
+type Sweeper struct {
+	apis        APIs
+	blockhashes cluster.BlockhashSource
+}
+
+// New creates a Sweeper
+//
+// Returns:
+//   - The sweeper
+//   - An error if the NFTs or marketplace API or the blockhash source is nil
+func New(apis APIs, blockhashes cluster.BlockhashSource) (*Sweeper, error) {
+	if apis.NFTs == nil || apis.Marketplace == nil {
+		return nil, fmt.Errorf("NFTs and marketplace APIs are required")
+	}
+	if blockhashes == nil {
+		return nil, fmt.Errorf("blockhash source is required")
+	}
+	return &Sweeper{apis: apis, blockhashes: blockhashes}, nil
+}
+
+// Validate checks the request fields
+func (r *Request) Validate() error {
+	if r.Buyer == "" || r.CollId == "" {
+		return fmt.Errorf("buyer and collId are required")
+	}
+	if r.Budget <= 0 {
+		return fmt.Errorf("budget must be > 0")
+	}
+	if r.MaxItems <= 0 {
+		return fmt.Errorf("maxItems must be > 0")
+	}
+	if r.MaxPrice < 0 {
+		return fmt.Errorf("maxPrice must be >= 0")
+	}
+	if r.TakerFeeBps < 0 || r.TakerFeeBps > 10_000 {
+		return fmt.Errorf("takerFeeBps must be between 0 and 10000 basis points")
+	}
+	if r.RoyaltyBps != nil && (*r.RoyaltyBps < 0 || *r.RoyaltyBps > 10_000) {
+		return fmt.Errorf("royaltyBps must be between 0 and 10000 basis points")
+	}
+	if r.Backups < 0 || r.Concurrency < 0 {
+		return fmt.Errorf("backups and concurrency must be >= 0")
+	}
+	return nil
+}
+
+// Plan pages through the collection's listings, cheapest first, and picks
+// the items to buy
+//
+// Returns:
+//   - The plan, possibly with no items if nothing fits
+//   - An error if the request is invalid or the listings could not be read
+func (s *Sweeper) Plan(ctx context.Context, req Request) (*Plan, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Backups == 0 {
+		req.Backups = req.MaxItems
+	}
+	royaltyBps, err := s.royaltyBps(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Request: req, RoyaltyBps: royaltyBps}
+	onlyListings := true
+	listReq := &nfts.NFTsByCollectionRequest{
+		CollId:        req.CollId,
+		SortBy:        "PriceAsc",
+		Limit:         pageLimit,
+		OnlyListings:  &onlyListings,
+		ExcludeOwners: []string{req.Buyer},
+		Traits:        req.Filter.Traits,
+		RaritySystem:  req.Filter.RaritySystem,
+		RarityMin:     req.Filter.RarityMin,
+		RarityMax:     req.Filter.RarityMax,
+	}
+	if req.MaxPrice > 0 {
+		maxPrice := req.MaxPrice
+		listReq.MaxPrice = &maxPrice
+	}
+
+	for {
+		data, _, err := s.apis.NFTs.GetNFTsByCollection(ctx, listReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read listings: %w", err)
+		}
+		items, page, err := market.DecodeNFTs(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range items {
+			if done := plan.add(n); done {
+				return plan, nil
+			}
+		}
+		if !page.HasMore || page.EndCursor == "" {
+			if plan.StopReason == "" {
+				plan.StopReason = "no more listings"
+			}
+			return plan, nil
+		}
+		cursor := page.EndCursor
+		listReq.Cursor = &cursor
+	}
+}
+
+// royaltyBps returns the request's royalty, reading the collection's when unset
+func (s *Sweeper) royaltyBps(ctx context.Context, req Request) (int64, error) {
+	if req.RoyaltyBps != nil {
+		return *req.RoyaltyBps, nil
+	}
+	if s.apis.Collections == nil {
+		return 0, fmt.Errorf("royaltyBps is required without a collections API")
+	}
+	coll, err := market.Collection(ctx, s.apis.Collections, req.CollId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read royalty: %w", err)
+	}
+	return int64(coll.SellRoyaltyFeeBPS), nil
+}
+
+// add considers the next cheapest listing. It reports true once the plan and
+// its backups are complete, since every later listing costs at least as much.
+func (p *Plan) add(n market.NFT) bool {
+	req := p.Request
+	if n.Listing == nil || n.Owner == req.Buyer || !req.Filter.matches(n) {
+		return false
+	}
+	if req.MaxPrice > 0 && n.Listing.Price > req.MaxPrice {
+		if p.StopReason == "" {
+			p.StopReason = "max price"
+		}
+		return true
+	}
+	item := p.item(n)
+
+	if p.StopReason == "" {
+		switch {
+		case len(p.Items) == req.MaxItems:
+			p.StopReason = "max items"
+		case p.Total+item.Cost > req.Budget:
+			p.StopReason = "budget"
+		default:
+			p.Items = append(p.Items, item)
+			p.Total += item.Cost
+			return false
+		}
+	}
+	// Backups only help if they could ever fit the budget
+	if item.Cost > req.Budget || len(p.Backups) == req.Backups {
+		return true
+	}
+	p.Backups = append(p.Backups, item)
+	return false
+}
+
+// item prices a listing
+func (p *Plan) item(n market.NFT) Item {
+	price := n.Listing.Price
+	item := Item{
+		NFT:      n,
+		Price:    price,
+		TakerFee: price.MulBps(p.Request.TakerFeeBps),
+		Royalty:  price.MulBps(p.RoyaltyBps),
+	}
+	item.Cost = item.Price + item.TakerFee + item.Royalty
+	return item
+}
+
+// Status is the outcome of one purchase
+type Status string
+
+// Purchase outcomes
+const (
+	StatusBuilt  Status = "built"  // The buy transaction was built
+	StatusSold   Status = "sold"   // The listing was gone or repriced; its budget was redistributed
+	StatusFailed Status = "failed" // The transaction could not be built
+)
+
+// Result is the outcome of buying one item
+type Result struct {
+	Item   Item
+	Status Status
+	Txs    []marketplace.Transaction // Unsigned transactions, when built
+	Err    error
+}
+
+// Fill is the outcome of executing a plan
+type Fill struct {
+	Results []Result // In price order
+	Planned int      // Items in the plan
+	Built   int      // Items whose transactions were built
+	Spent   money.Lamports
+}
+
+// Partial reports whether fewer items were built than planned
+func (f *Fill) Partial() bool {
+	return f.Built < f.Planned
+}
+
+// Execute builds a BuyNFT transaction for each planned item, at most
+// Request.Concurrency at a time. When an item turns out to be sold, the next
+// backup that fits the remaining budget takes its place. The returned
+// transactions still need to be signed and sent.
+//
+// Returns:
+//   - The per-item results
+//   - An error if no blockhash could be fetched
+func (s *Sweeper) Execute(ctx context.Context, plan *Plan) (*Fill, error) {
+	blockhash, _, err := s.blockhashes.LatestBlockhash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blockhash: %w", err)
+	}
+	concurrency := plan.Request.Concurrency
+	if concurrency == 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	fill := &Fill{Planned: len(plan.Items)}
+	queue := append([]Item(nil), plan.Items...)
+	backups := append([]Item(nil), plan.Backups...)
+	committed := plan.Total
+	results := make(chan Result)
+	inflight := 0
+
+	for len(queue) > 0 || inflight > 0 {
+		for len(queue) > 0 && inflight < concurrency {
+			item := queue[0]
+			queue = queue[1:]
+			if err := ctx.Err(); err != nil {
+				fill.Results = append(fill.Results, Result{Item: item, Status: StatusFailed, Err: err})
+				committed -= item.Cost
+				continue
+			}
+			inflight++
+			go func() { results <- s.buy(ctx, plan.Request.Buyer, item, blockhash) }()
+		}
+		if inflight == 0 {
+			break
+		}
+
+		r := <-results
+		inflight--
+		fill.Results = append(fill.Results, r)
+		switch r.Status {
+		case StatusBuilt:
+			fill.Built++
+			fill.Spent += r.Item.Cost
+		case StatusSold:
+			committed -= r.Item.Cost
+			// Backups are cheapest first: once one does not fit, none will
+			for len(backups) > 0 {
+				next := backups[0]
+				backups = backups[1:]
+				if committed+next.Cost <= plan.Request.Budget {
+					queue = append(queue, next)
+					committed += next.Cost
+					break
+				}
+				backups = nil
+			}
+		case StatusFailed:
+			committed -= r.Item.Cost
+		}
+	}
+
+	sort.SliceStable(fill.Results, func(i, j int) bool {
+		a, b := fill.Results[i].Item, fill.Results[j].Item
+		if a.Price != b.Price {
+			return a.Price < b.Price
+		}
+		return a.NFT.Mint < b.NFT.Mint
+	})
+	return fill, nil
+}
+
+// buy builds the transaction for one item, capped at its planned price
+func (s *Sweeper) buy(ctx context.Context, buyer string, item Item, blockhash string) Result {
+	resp, _, err := s.apis.Marketplace.BuyNFT(ctx, &marketplace.BuyNFTRequest{
+		Buyer:     buyer,
+		Mint:      item.NFT.Mint,
+		Owner:     item.NFT.Owner,
+		MaxPrice:  item.Price,
+		Blockhash: blockhash,
+	})
+	switch {
+	case err == nil:
+		return Result{Item: item, Status: StatusBuilt, Txs: resp.Txs}
+	case unavailable(err):
+		return Result{Item: item, Status: StatusSold, Err: err}
+	default:
+		return Result{Item: item, Status: StatusFailed, Err: err}
+	}
+}
+
+// goneMessages are parts of the messages the API sends with a 400 when the
+// listing was sold, delisted or repriced above the planned price
+var goneMessages = []string{"is not listed", "is not owned by", "exceeds maxprice"}
+
+// unavailable reports whether err means the listing can no longer be bought
+// at its planned price: a 404 or 409, or a 400 whose message says so. Other
+// client errors, such as a malformed request, say nothing about the listing.
+func unavailable(err error) bool {
+	var apiErr *apierrors.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case 404, 409:
+		return true
+	case 400:
+		msg := strings.ToLower(apiErr.Message)
+		return slices.ContainsFunc(goneMessages, func(m string) bool { return strings.Contains(msg, m) })
+	}
+	return false
+}
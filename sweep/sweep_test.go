@@ -0,0 +1,437 @@
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	"github.com/srpvpn/tensor-go-sdk/cluster"
+	"github.com/srpvpn/tensor-go-sdk/fakes"
+	apierrors "github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/market"
+	"github.com/srpvpn/tensor-go-sdk/money"
+	"github.com/srpvpn/tensor-go-sdk/tensortest"
+)
+
+var (
+	buyer  = tensortest.Address("buyer")
+	seller = tensortest.Address("seller")
+	rival  = tensortest.Address("rival")
+)
+
+func bps(v int64) *int64 {
+	return &v
+}
+
+func mint(price string) string {
+	return tensortest.Address("mint-" + price)
+}
+
+// newMarket lists one NFT per price, all sold by seller
+func newMarket(t *testing.T, prices ...string) (*tensortest.Server, *client.Client) {
+	t.Helper()
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID, Name: "Sweep", SellRoyaltyFeeBPS: 500})
+	for i, price := range prices {
+		srv.AddNFT(tensortest.NFT{
+			Mint:       mint(price),
+			CollId:     tensortest.CollID,
+			Owner:      seller,
+			RarityRank: int32(len(prices) - i),
+			Listing:    &tensortest.Listing{Price: tensortest.Lamports(price)},
+		})
+	}
+	return srv, client.New(&client.Config{BaseURL: srv.URL})
+}
+
+func newSweeper(t *testing.T, c *client.Client) *Sweeper {
+	t.Helper()
+	s, err := New(APIs{NFTs: c.NFTs, Marketplace: c.Marketplace, Collections: c.Collections}, cluster.StaticBlockhash(tensortest.Blockhash))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return s
+}
+
+func prices(items []Item) []money.Lamports {
+	out := make([]money.Lamports, len(items))
+	for i, item := range items {
+		out[i] = item.Price
+	}
+	return out
+}
+
+func TestSweeper_Plan(t *testing.T) {
+	srv, c := newMarket(t, "1", "2", "3", "4", "5", "6")
+	// The buyer's own listing is never part of the sweep
+	srv.AddNFT(tensortest.NFT{Mint: tensortest.Address("own"), CollId: tensortest.CollID, Owner: buyer, Listing: &tensortest.Listing{Price: "500000000"}})
+	s := newSweeper(t, c)
+
+	plan, err := s.Plan(context.Background(), Request{
+		Buyer:       buyer,
+		CollId:      tensortest.CollID,
+		Budget:      money.MustParseSOL("7"),
+		MaxItems:    5,
+		TakerFeeBps: 200,
+		RoyaltyBps:  bps(500),
+	})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if got := prices(plan.Items); fmt.Sprint(got) != "[1 SOL 2 SOL 3 SOL]" {
+		t.Errorf("items = %v, want 1, 2 and 3 SOL", got)
+	}
+	if plan.Total != money.MustParseSOL("6.42") || plan.StopReason != "budget" {
+		t.Errorf("total, stop = %v, %q; want 6.42 SOL, budget", plan.Total, plan.StopReason)
+	}
+	first := plan.Items[0]
+	if first.TakerFee != money.MustParseSOL("0.02") || first.Royalty != money.MustParseSOL("0.05") || first.Cost != money.MustParseSOL("1.07") {
+		t.Errorf("first item = %+v, want 0.02 fee, 0.05 royalty, 1.07 cost", first)
+	}
+	if got := prices(plan.Backups); fmt.Sprint(got) != "[4 SOL 5 SOL 6 SOL]" {
+		t.Errorf("backups = %v, want 4, 5 and 6 SOL", got)
+	}
+	if q := srv.Requests()[0].Query; q.Get("sortBy") != "PriceAsc" || q.Get("onlyListings") != "true" {
+		t.Errorf("listing query = %v, want price ascending listings", q)
+	}
+}
+
+func TestSweeper_PlanLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        Request
+		wantItems  string
+		wantReason string
+	}{
+		{
+			name:       "max items",
+			req:        Request{Budget: money.MustParseSOL("100"), MaxItems: 2},
+			wantItems:  "[1 SOL 2 SOL]",
+			wantReason: "max items",
+		},
+		{
+			name:       "max price",
+			req:        Request{Budget: money.MustParseSOL("100"), MaxItems: 10, MaxPrice: money.MustParseSOL("2.5")},
+			wantItems:  "[1 SOL 2 SOL]",
+			wantReason: "no more listings",
+		},
+		{
+			name:       "listings run out",
+			req:        Request{Budget: money.MustParseSOL("100"), MaxItems: 10},
+			wantItems:  "[1 SOL 2 SOL 3 SOL 4 SOL]",
+			wantReason: "no more listings",
+		},
+		{
+			name:       "rarity rank",
+			req:        Request{Budget: money.MustParseSOL("100"), MaxItems: 10, Filter: Filter{MaxRarityRank: 2}},
+			wantItems:  "[3 SOL 4 SOL]",
+			wantReason: "no more listings",
+		},
+		{
+			name: "custom match",
+			req: Request{Budget: money.MustParseSOL("100"), MaxItems: 10, Filter: Filter{Match: func(n market.NFT) bool {
+				return n.Mint != mint("1")
+			}}},
+			wantItems:  "[2 SOL 3 SOL 4 SOL]",
+			wantReason: "no more listings",
+		},
+		{
+			name:       "nothing fits",
+			req:        Request{Budget: money.MustParseSOL("0.5"), MaxItems: 10},
+			wantItems:  "[]",
+			wantReason: "budget",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, c := newMarket(t, "1", "2", "3", "4")
+			req := tt.req
+			req.Buyer, req.CollId, req.RoyaltyBps = buyer, tensortest.CollID, bps(0)
+			plan, err := newSweeper(t, c).Plan(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if got := fmt.Sprint(prices(plan.Items)); got != tt.wantItems || plan.StopReason != tt.wantReason {
+				t.Errorf("items, stop = %s, %q; want %s, %q", got, plan.StopReason, tt.wantItems, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestSweeper_PlanReadsRoyaltyAndPages(t *testing.T) {
+	srv := tensortest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddCollection(tensortest.Collection{CollId: tensortest.CollID, SellRoyaltyFeeBPS: 500})
+	for i := 0; i < 150; i++ {
+		srv.AddNFT(tensortest.NFT{Mint: tensortest.Address(fmt.Sprint("paged-", i)), CollId: tensortest.CollID, Owner: seller, Listing: &tensortest.Listing{Price: "1000000000"}})
+	}
+	c := client.New(&client.Config{BaseURL: srv.URL})
+	traits := []string{"Hat:Cap"}
+
+	plan, err := newSweeper(t, c).Plan(context.Background(), Request{
+		Buyer: buyer, CollId: tensortest.CollID, Budget: money.MustParseSOL("1000"), MaxItems: 120, Backups: 1,
+		Filter: Filter{Traits: traits},
+	})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if plan.RoyaltyBps != 500 || plan.Items[0].Royalty != money.MustParseSOL("0.05") {
+		t.Errorf("royalty = %d bps, %v; want the collection's 500 bps", plan.RoyaltyBps, plan.Items[0].Royalty)
+	}
+	if len(plan.Items) != 120 || len(plan.Backups) != 1 || plan.StopReason != "max items" {
+		t.Errorf("plan = %d items, %d backups, %q", len(plan.Items), len(plan.Backups), plan.StopReason)
+	}
+	if n := srv.RequestCount("/api/v1/mint/collection"); n != 2 {
+		t.Errorf("listing pages = %d, want 2", n)
+	}
+	last := srv.Requests()[len(srv.Requests())-1]
+	if last.Query.Get("traits") == "" || last.Query.Get("cursor") == "" {
+		t.Errorf("second page query = %v, want traits and a cursor", last.Query)
+	}
+
+	if _, err := newSweeperWithout(t, c).Plan(context.Background(), Request{Buyer: buyer, CollId: tensortest.CollID, Budget: 1, MaxItems: 1}); err == nil {
+		t.Error("Plan() without royalty or collections API error = nil")
+	}
+}
+
+func newSweeperWithout(t *testing.T, c *client.Client) *Sweeper {
+	t.Helper()
+	s, err := New(APIs{NFTs: c.NFTs, Marketplace: c.Marketplace}, cluster.StaticBlockhash(tensortest.Blockhash))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return s
+}
+
+func TestSweeper_ExecuteRedistributesSoldBudget(t *testing.T) {
+	srv, c := newMarket(t, "1", "2", "3", "4", "10")
+	s := newSweeper(t, c)
+	plan, err := s.Plan(context.Background(), Request{Buyer: buyer, CollId: tensortest.CollID, Budget: money.MustParseSOL("7"), MaxItems: 3, RoyaltyBps: bps(0)})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.Items) != 3 || len(plan.Backups) != 1 {
+		t.Fatalf("plan = %v with backups %v, want 3 items and the 4 SOL backup", prices(plan.Items), prices(plan.Backups))
+	}
+
+	// A rival buys the 3 SOL listing before the sweep runs
+	if _, _, err := c.Marketplace.BuyNFT(context.Background(), &marketplace.BuyNFTRequest{
+		Buyer: rival, Mint: mint("3"), Owner: seller, MaxPrice: money.MustParseSOL("3"), Blockhash: tensortest.Blockhash,
+	}); err != nil {
+		t.Fatalf("rival BuyNFT() error = %v", err)
+	}
+
+	fill, err := s.Execute(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var got []string
+	for _, r := range fill.Results {
+		got = append(got, fmt.Sprintf("%s:%s", r.Item.Price.SOL(), r.Status))
+	}
+	if want := "[1:built 2:built 3:sold 4:built]"; fmt.Sprint(got) != want {
+		t.Errorf("results = %v, want %s", got, want)
+	}
+	if fill.Built != 3 || fill.Partial() || fill.Spent != money.MustParseSOL("7") {
+		t.Errorf("fill = %d built, partial %v, spent %v; want 3, false, 7 SOL", fill.Built, fill.Partial(), fill.Spent)
+	}
+	if n, _ := srv.NFT(mint("4")); n.Owner != buyer {
+		t.Errorf("backup owner = %s, want the buyer", n.Owner)
+	}
+}
+
+func TestSweeper_ExecuteClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        *apierrors.APIError
+		wantStatus Status
+	}{
+		{name: "not found", err: &apierrors.APIError{Code: 404, Message: "not found"}, wantStatus: StatusSold},
+		{name: "conflict", err: &apierrors.APIError{Code: 409, Message: "conflict"}, wantStatus: StatusSold},
+		{name: "delisted", err: &apierrors.APIError{Code: 400, Message: "mint " + mint("3") + " is not listed"}, wantStatus: StatusSold},
+		{name: "repriced", err: &apierrors.APIError{Code: 400, Message: "listing price 5 exceeds maxPrice 3"}, wantStatus: StatusSold},
+		{name: "bad request", err: &apierrors.APIError{Code: 400, Message: "invalid blockhash"}, wantStatus: StatusFailed},
+		{name: "unprocessable", err: &apierrors.APIError{Code: 422, Message: "validation error"}, wantStatus: StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, c := newMarket(t, "1", "2", "3", "4")
+			m := &fakes.Marketplace{}
+			m.BuyNFTStub.Handle(func(_ context.Context, req *marketplace.BuyNFTRequest) (*marketplace.BuyNFTResponse, int, error) {
+				if req.Mint == mint("3") {
+					return nil, tt.err.Code, tt.err
+				}
+				return &marketplace.BuyNFTResponse{Txs: []marketplace.Transaction{{TxV0: "tx"}}}, 200, nil
+			})
+			s, err := New(APIs{NFTs: c.NFTs, Marketplace: m}, cluster.StaticBlockhash(tensortest.Blockhash))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			plan, err := s.Plan(context.Background(), Request{Buyer: buyer, CollId: tensortest.CollID, Budget: money.MustParseSOL("7"), MaxItems: 3, RoyaltyBps: bps(0)})
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			fill, err := s.Execute(context.Background(), plan)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for _, r := range fill.Results {
+				if r.Item.NFT.Mint == mint("3") && r.Status != tt.wantStatus {
+					t.Errorf("status = %s, want %s", r.Status, tt.wantStatus)
+				}
+			}
+			// Only a listing that is gone hands its budget to the 4 SOL backup.
+			backupBought := m.BuyNFTStub.CallCount() == 4
+			if backupBought != (tt.wantStatus == StatusSold) {
+				t.Errorf("backup bought = %v after a %s", backupBought, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSweeper_ExecutePartialFill(t *testing.T) {
+	srv, c := newMarket(t, "1", "2", "3", "10")
+	s := newSweeper(t, c)
+	plan, err := s.Plan(context.Background(), Request{Buyer: buyer, CollId: tensortest.CollID, Budget: money.MustParseSOL("6"), MaxItems: 3, RoyaltyBps: bps(0)})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	srv.AddNFT(tensortest.NFT{Mint: mint("2"), CollId: tensortest.CollID, Owner: rival}) // Delisted and moved
+
+	fill, err := s.Execute(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if fill.Built != 2 || !fill.Partial() || fill.Spent != money.MustParseSOL("4") {
+		t.Errorf("fill = %d built, partial %v, spent %v; want 2, true, 4 SOL", fill.Built, fill.Partial(), fill.Spent)
+	}
+}
+
+func TestSweeper_ExecuteBoundsConcurrency(t *testing.T) {
+	listings := make([]string, 0, 12)
+	for i := 1; i <= 12; i++ {
+		listings = append(listings, fmt.Sprintf(`{"mint":"%s","owner":"%s","listing":{"price":"%d"}}`, tensortest.Address(fmt.Sprint(i)), seller, i*1_000_000_000))
+	}
+	n := &fakes.NFTs{}
+	n.GetNFTsByCollectionStub.Returns([]byte(`{"mints":[`+strings.Join(listings, ",")+`],"page":{}}`), 200)
+
+	var active, peak atomic.Int32
+	var mu sync.Mutex
+	built := map[string]bool{}
+	m := &fakes.Marketplace{}
+	m.BuyNFTStub.Handle(func(ctx context.Context, req *marketplace.BuyNFTRequest) (*marketplace.BuyNFTResponse, int, error) {
+		cur := active.Add(1)
+		defer active.Add(-1)
+		for {
+			old := peak.Load()
+			if cur <= old || peak.CompareAndSwap(old, cur) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		switch req.Mint {
+		case tensortest.Address("5"):
+			return nil, 500, &apierrors.APIError{Code: 500, Message: "internal"}
+		case tensortest.Address("6"):
+			return nil, 429, &apierrors.APIError{Code: 429, Message: "slow down"}
+		}
+		mu.Lock()
+		built[req.Mint] = true
+		mu.Unlock()
+		return &marketplace.BuyNFTResponse{Txs: []marketplace.Transaction{{TxV0: "tx"}}}, 200, nil
+	})
+
+	s, err := New(APIs{NFTs: n, Marketplace: m}, cluster.StaticBlockhash(tensortest.Blockhash))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	plan, err := s.Plan(context.Background(), Request{Buyer: buyer, CollId: tensortest.CollID, Budget: money.MustParseSOL("1000"), MaxItems: 10, RoyaltyBps: bps(0), Concurrency: 3})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	fill, err := s.Execute(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("peak concurrency = %d, want at most 3", p)
+	}
+	if fill.Built != 8 || len(built) != 8 {
+		t.Errorf("built = %d, want 8", fill.Built)
+	}
+	for _, r := range fill.Results {
+		if r.Item.Price == money.MustParseSOL("5") || r.Item.Price == money.MustParseSOL("6") {
+			if r.Status != StatusFailed {
+				t.Errorf("%v status = %s, want failed: server and rate limit errors are not sales", r.Item.Price, r.Status)
+			}
+		}
+		if len(r.Txs) > 0 && r.Txs[0].TxV0 != "tx" {
+			t.Errorf("unexpected txs %v", r.Txs)
+		}
+	}
+	m.BuyNFTStub.AssertCalledWith(t, func(req *marketplace.BuyNFTRequest) bool {
+		return req.Blockhash == tensortest.Blockhash && req.MaxPrice == money.MustParseSOL("1") && req.Owner == seller
+	})
+	if got := n.GetNFTsByCollectionStub.LastCall(); got.ExcludeOwners[0] != buyer || got.Limit != 100 {
+		t.Errorf("listing request = %+v", got)
+	}
+}
+
+func TestSweeper_ExecuteCancelled(t *testing.T) {
+	_, c := newMarket(t, "1", "2")
+	s := newSweeper(t, c)
+	plan, err := s.Plan(context.Background(), Request{Buyer: buyer, CollId: tensortest.CollID, Budget: money.MustParseSOL("10"), MaxItems: 2, RoyaltyBps: bps(0)})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fill, err := s.Execute(ctx, plan)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if fill.Built != 0 || len(fill.Results) != 2 || fill.Results[0].Status != StatusFailed {
+		t.Errorf("cancelled fill = %+v, want every item failed", fill)
+	}
+}
+
+func TestRequest_Validate(t *testing.T) {
+	valid := Request{Buyer: buyer, CollId: tensortest.CollID, Budget: 1, MaxItems: 1}
+	tests := []struct {
+		name    string
+		mutate  func(*Request)
+		wantErr string
+	}{
+		{"valid", func(*Request) {}, ""},
+		{"missing buyer", func(r *Request) { r.Buyer = "" }, "buyer and collId are required"},
+		{"zero budget", func(r *Request) { r.Budget = 0 }, "budget must be > 0"},
+		{"zero items", func(r *Request) { r.MaxItems = 0 }, "maxItems must be > 0"},
+		{"negative max price", func(r *Request) { r.MaxPrice = -1 }, "maxPrice must be >= 0"},
+		{"taker fee", func(r *Request) { r.TakerFeeBps = 10_001 }, "takerFeeBps"},
+		{"royalty", func(r *Request) { r.RoyaltyBps = bps(-1) }, "royaltyBps"},
+		{"concurrency", func(r *Request) { r.Concurrency = -1 }, "concurrency must be >= 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid
+			tt.mutate(&r)
+			err := r.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
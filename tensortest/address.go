@@ -0,0 +1,14 @@
+package tensortest
+
+import (
+	"crypto/sha256"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Address derives a deterministic, valid looking Solana address from seed.
+// Tests use it to create wallets and mints that pass SDK validation.
+func Address(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return solana.PublicKeyFromBytes(sum[:]).String()
+}
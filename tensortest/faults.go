@@ -0,0 +1,53 @@
+package tensortest
+
+import (
+	"strings"
+	"time"
+)
+
+// Fault describes an injected failure for requests whose path starts with PathPrefix
+type Fault struct {
+	PathPrefix string        // Paths the fault applies to, empty matches every path
+	Latency    time.Duration // Delay before the request is handled
+	StatusCode int           // Error status to respond with, 0 to serve the request normally after Latency
+	RetryAfter time.Duration // Retry-After header sent with 429 responses
+	Times      int           // Number of requests affected, 0 until the fault is cleared
+}
+
+type faultState struct {
+	Fault
+	remaining int
+}
+
+// InjectFault registers a fault. Faults are matched in registration order
+// and the first matching one applies.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &faultState{Fault: f, remaining: f.Times})
+}
+
+// ClearFaults removes every injected fault
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns the fault for path, consuming one use of it. Callers must hold s.mu.
+func (s *Server) matchFault(path string) *Fault {
+	for i, f := range s.faults {
+		if !strings.HasPrefix(path, f.PathPrefix) {
+			continue
+		}
+		if f.Times > 0 {
+			f.remaining--
+			if f.remaining <= 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		fault := f.Fault
+		return &fault
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package tensortest
+
+import (
+	"strconv"
+
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+// CollID is a collection ID for tests that seed a single collection
+const CollID = "test-collection"
+
+// Blockhash is a well formed recent blockhash for building transactions against the fake
+const Blockhash = "11111111111111111111111111111111"
+
+// Lamports formats a decimal SOL amount such as "1.5" as the lamports string
+// that fixture prices such as Listing.Price and Bid.Price take
+func Lamports(sol string) string {
+	return strconv.FormatInt(int64(money.MustParseSOL(sol)), 10)
+}
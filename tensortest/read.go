@@ -0,0 +1,349 @@
+package tensortest
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+)
+
+func (s *Server) handlePortfolio(q url.Values) (any, error) {
+	wallet := q.Get("wallet")
+	counts := make(map[string]int)
+	for _, mint := range s.nftOrder {
+		if n := s.nfts[mint]; n.Owner == wallet || (n.Listing != nil && n.Listing.Seller == wallet) {
+			counts[n.CollId]++
+		}
+	}
+
+	type portfolioCollection struct {
+		ID         string  `json:"id"`
+		Name       string  `json:"name"`
+		Symbol     string  `json:"symbol"`
+		FloorPrice float64 `json:"floorPrice"`
+		Count      int     `json:"count"`
+		Verified   bool    `json:"verified"`
+		Compressed bool    `json:"compressed"`
+	}
+	out := []portfolioCollection{}
+	for _, id := range s.collectionIDs() {
+		if counts[id] == 0 {
+			continue
+		}
+		pc := portfolioCollection{ID: id, Count: counts[id]}
+		if c, ok := s.colls[id]; ok {
+			pc.Name, pc.Symbol = c.Name, c.Symbol
+			pc.Verified, pc.Compressed = c.TensorVerified, c.Compressed
+		}
+		if floor, ok := s.floor(id); ok {
+			pc.FloorPrice = float64(floor)
+		}
+		out = append(out, pc)
+	}
+	return map[string]any{"message": "Ok", "collections": out}, nil
+}
+
+func (s *Server) handleActiveListings(q url.Values) (any, error) {
+	wallets := list(q, "wallets")
+	collID := q.Get("collId")
+	items := []NFT{}
+	for _, mint := range s.nftOrder {
+		n := s.nfts[mint]
+		if n.Listing == nil || !contains(wallets, n.Listing.Seller) {
+			continue
+		}
+		if collID != "" && n.CollId != collID {
+			continue
+		}
+		items = append(items, copyNFT(n))
+	}
+	switch q.Get("sortBy") {
+	case "PriceAsc", "NormalizedPriceAsc":
+		sortByPrice(items, false)
+	case "PriceDesc", "NormalizedPriceDesc":
+		sortByPrice(items, true)
+	}
+	page, p := paginate(items, q, 100)
+	return map[string]any{"listings": page, "page": p}, nil
+}
+
+func (s *Server) bidsHandler(target string) handlerFunc {
+	return func(q url.Values) (any, error) {
+		owner := q.Get("owner")
+		collID := q.Get("collId")
+		addresses := list(q, "bidAddresses")
+		items := []Bid{}
+		for _, b := range s.activeBids() {
+			if b.Target != target || b.Owner != owner {
+				continue
+			}
+			if collID != "" && b.CollId != collID {
+				continue
+			}
+			if len(addresses) > 0 && !contains(addresses, b.Address) {
+				continue
+			}
+			items = append(items, *b)
+		}
+		page, p := paginate(items, q, 100)
+		return map[string]any{"bids": page, "page": p}, nil
+	}
+}
+
+func (s *Server) handlePools(q url.Values) (any, error) {
+	owner := q.Get("owner")
+	addresses := list(q, "poolAddresses")
+	items := []Pool{}
+	for _, addr := range s.poolOrder {
+		p := s.pools[addr]
+		if p.Owner != owner {
+			continue
+		}
+		if len(addresses) > 0 && !contains(addresses, p.Address) {
+			continue
+		}
+		out := *p
+		out.NftsHeld = append([]string{}, p.NftsHeld...)
+		items = append(items, out)
+	}
+	page, p := paginate(items, q, 100)
+	return map[string]any{"pools": page, "page": p}, nil
+}
+
+// TAmm pools are not simulated; the endpoint always returns an empty page
+func (s *Server) handleTAmmPools(q url.Values) (any, error) {
+	return map[string]any{"pools": []any{}, "page": Page{}}, nil
+}
+
+func (s *Server) handleTransactions(q url.Values) (any, error) {
+	wallets := list(q, "wallets")
+	txTypes := list(q, "txTypes")
+	collID := q.Get("collId")
+	items := []Transaction{}
+	// Newest first, like the real API
+	for i := len(s.txs) - 1; i >= 0; i-- {
+		tx := s.txs[i]
+		if !contains(wallets, tx.Buyer) && !contains(wallets, tx.Seller) {
+			continue
+		}
+		if len(txTypes) > 0 && !contains(txTypes, tx.TxType) {
+			continue
+		}
+		if collID != "" && tx.CollId != collID {
+			continue
+		}
+		items = append(items, tx)
+	}
+	page, p := paginate(items, q, 100)
+	return map[string]any{"txs": page, "page": p}, nil
+}
+
+func (s *Server) handleEscrowAccounts(q url.Values) (any, error) {
+	out := []EscrowAccount{}
+	if e, ok := s.escrows[q.Get("owner")]; ok {
+		out = append(out, *e)
+	}
+	return map[string]any{"escrowAccounts": out}, nil
+}
+
+func (s *Server) handleInventory(q url.Values) (any, error) {
+	wallets := list(q, "wallets")
+	collID := q.Get("collId")
+	items := []NFT{}
+	for _, mint := range s.nftOrder {
+		n := s.nfts[mint]
+		if !contains(wallets, n.Owner) {
+			continue
+		}
+		if collID != "" && n.CollId != collID {
+			continue
+		}
+		items = append(items, copyNFT(n))
+	}
+	page, p := paginate(items, q, 100)
+	return map[string]any{"nfts": page, "page": p}, nil
+}
+
+func (s *Server) handleMints(q url.Values) (any, error) {
+	out := []NFT{}
+	for _, mint := range list(q, "mints") {
+		if n, ok := s.nfts[mint]; ok {
+			out = append(out, copyNFT(n))
+		}
+	}
+	return out, nil
+}
+
+func (s *Server) handleMintsByCollection(q url.Values) (any, error) {
+	collID := q.Get("collId")
+	if collID == "" {
+		return nil, badRequest("collId is required")
+	}
+	onlyListings := q.Get("onlyListings") == "true"
+	mints := list(q, "mints")
+	include := list(q, "includeOwners")
+	exclude := list(q, "excludeOwners")
+	minPrice, hasMin := optionalLamports(q, "minPrice")
+	maxPrice, hasMax := optionalLamports(q, "maxPrice")
+
+	items := []NFT{}
+	for _, mint := range s.nftOrder {
+		n := s.nfts[mint]
+		if n.CollId != collID {
+			continue
+		}
+		if len(mints) > 0 && !contains(mints, n.Mint) {
+			continue
+		}
+		if len(include) > 0 && !contains(include, n.Owner) {
+			continue
+		}
+		if contains(exclude, n.Owner) {
+			continue
+		}
+		if n.Listing == nil {
+			if onlyListings || hasMin || hasMax {
+				continue
+			}
+		} else {
+			price := parseLamports(n.Listing.Price)
+			if (hasMin && price < minPrice) || (hasMax && price > maxPrice) {
+				continue
+			}
+		}
+		items = append(items, copyNFT(n))
+	}
+
+	switch q.Get("sortBy") {
+	case "PriceAsc", "NormalizedPriceAsc":
+		sortByPrice(items, false)
+	case "PriceDesc", "NormalizedPriceDesc":
+		sortByPrice(items, true)
+	case "RarityAsc", "RankTnAsc":
+		sort.SliceStable(items, func(i, j int) bool { return items[i].RarityRank < items[j].RarityRank })
+	case "RarityDesc", "RankTnDesc":
+		sort.SliceStable(items, func(i, j int) bool { return items[i].RarityRank > items[j].RarityRank })
+	}
+	page, p := paginate(items, q, 100)
+	return map[string]any{"mints": page, "page": p}, nil
+}
+
+func (s *Server) handleCollections(q url.Values) (any, error) {
+	ids := list(q, "collIds")
+	slugs := list(q, "slugDisplays")
+	items := []collections.CollectionDetailed{}
+	for _, id := range s.collOrder {
+		c := *s.colls[id]
+		if len(ids) > 0 && !contains(ids, c.CollId) {
+			continue
+		}
+		if len(slugs) > 0 && !contains(slugs, c.SlugDisplay) {
+			continue
+		}
+		c.Stats = s.stats(c.CollId, c.Stats)
+		items = append(items, c)
+	}
+	page := intParam(q, "page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := intParam(q, "limit", 50)
+	start := (page - 1) * limit
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return collections.GetVerifiedCollectionsResponse{
+		Page:        int32(page),
+		Total:       int32(len(items)),
+		Collections: items[start:end],
+	}, nil
+}
+
+func (s *Server) handlePriorityFees(q url.Values) (any, error) {
+	return s.priorityFees, nil
+}
+
+// stats overlays the live listing and bid figures of a collection on the seeded stats
+func (s *Server) stats(collID string, base collections.CollectionStats) collections.CollectionStats {
+	var numMints, numListed int32
+	for _, mint := range s.nftOrder {
+		n := s.nfts[mint]
+		if n.CollId != collID {
+			continue
+		}
+		numMints++
+		if n.Listing != nil {
+			numListed++
+		}
+	}
+	if numMints > 0 {
+		base.NumMints = numMints
+		base.PctListed = float64(numListed) / float64(numMints) * 100
+	}
+	base.NumListed = numListed
+	base.BuyNowPrice, base.BuyNowPriceNetFees = "", ""
+	if floor, ok := s.floor(collID); ok {
+		base.BuyNowPrice = formatLamports(floor)
+		base.BuyNowPriceNetFees = base.BuyNowPrice
+	}
+
+	var numBids int32
+	var best int64
+	for _, b := range s.activeBids() {
+		if b.CollId != collID {
+			continue
+		}
+		numBids++
+		if b.Target == BidTargetCollection {
+			if price := parseLamports(b.Price); price > best {
+				best = price
+			}
+		}
+	}
+	base.NumBids = numBids
+	base.SellNowPrice, base.SellNowPriceNetFees = "", ""
+	if best > 0 {
+		base.SellNowPrice = formatLamports(best)
+		base.SellNowPriceNetFees = base.SellNowPrice
+	}
+	return base
+}
+
+// floor returns the lowest listing price of a collection. Callers must hold s.mu.
+func (s *Server) floor(collID string) (int64, bool) {
+	var floor int64
+	found := false
+	for _, mint := range s.nftOrder {
+		n := s.nfts[mint]
+		if n.CollId != collID || n.Listing == nil {
+			continue
+		}
+		if price := parseLamports(n.Listing.Price); !found || price < floor {
+			floor, found = price, true
+		}
+	}
+	return floor, found
+}
+
+// collectionIDs returns seeded collections followed by collections only known through NFTs
+func (s *Server) collectionIDs() []string {
+	ids := append([]string(nil), s.collOrder...)
+	for _, mint := range s.nftOrder {
+		if id := s.nfts[mint].CollId; id != "" && !contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func optionalLamports(q url.Values, key string) (int64, bool) {
+	if q.Get(key) == "" {
+		return 0, false
+	}
+	v, err := lamports(q, key)
+	return v, err == nil
+}
@@ -0,0 +1,333 @@
+// Package tensortest provides an in-process fake of the Tensor API for integration tests.
+//
+// The fake keeps marketplace state in memory: listing an NFT makes it show up in the
+// active listings endpoint, placing a bid makes it show up in the bids endpoints, buying
+// transfers ownership and so on. Transaction endpoints apply their effects immediately,
+// as if the returned transaction had been signed and confirmed.
+//
+//	srv := tensortest.NewServer()
+//	defer srv.Close()
+//	c := client.New(&client.Config{BaseURL: srv.URL})
+package tensortest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/rpc"
+)
+
+// Request is a request received by the fake server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+}
+
+// Server is a stateful fake Tensor API served over a local HTTP listener
+type Server struct {
+	URL string // Base URL to pass to client.Config.BaseURL
+
+	srv    *httptest.Server
+	apiKey string
+
+	mu           sync.Mutex
+	nfts         map[string]*NFT
+	nftOrder     []string
+	colls        map[string]*Collection
+	collOrder    []string
+	bids         map[string]*Bid
+	bidOrder     []string
+	pools        map[string]*Pool
+	poolOrder    []string
+	escrows      map[string]*EscrowAccount
+	txs          []Transaction
+	priorityFees rpc.PriorityFeesResponse
+	blockHeight  int64
+	seq          int
+	faults       []*faultState
+	requests     []Request
+	now          func() time.Time
+}
+
+// NewServer starts a fake Tensor API server with empty state.
+// The caller must call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		nfts:        make(map[string]*NFT),
+		colls:       make(map[string]*Collection),
+		bids:        make(map[string]*Bid),
+		pools:       make(map[string]*Pool),
+		escrows:     make(map[string]*EscrowAccount),
+		blockHeight: 250_000_000,
+		priorityFees: rpc.PriorityFeesResponse{
+			Min: 0, Low: 1_000, Medium: 10_000, High: 100_000, VeryHigh: 1_000_000,
+		},
+		now: time.Now,
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server and blocks until all outstanding requests have completed
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// RequireAPIKey makes the server reject requests that do not carry the given
+// x-tensor-api-key header with 401. An empty key disables the check.
+func (s *Server) RequireAPIKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = key
+}
+
+// Requests returns a copy of every request received so far, in arrival order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Request, len(s.requests))
+	copy(out, s.requests)
+	return out
+}
+
+// RequestCount returns how many requests hit paths starting with prefix
+func (s *Server) RequestCount(prefix string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, r := range s.requests {
+		if strings.HasPrefix(r.Path, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+type handlerFunc func(q url.Values) (any, error)
+
+func (s *Server) routes() map[string]handlerFunc {
+	return map[string]handlerFunc{
+		"/api/v1/user/portfolio":                s.handlePortfolio,
+		"/api/v1/user/active_listings":          s.handleActiveListings,
+		"/api/v1/user/nft_bids":                 s.bidsHandler(BidTargetNFT),
+		"/api/v1/user/coll_bids":                s.bidsHandler(BidTargetCollection),
+		"/api/v1/user/trait_bids":               s.bidsHandler(BidTargetTrait),
+		"/api/v1/user/amm_pools":                s.handlePools,
+		"/api/v1/user/tamm_pools":               s.handleTAmmPools,
+		"/api/v1/user/transactions":             s.handleTransactions,
+		"/api/v1/user/escrow_accounts":          s.handleEscrowAccounts,
+		"/api/v1/user/inventory_by_collection":  s.handleInventory,
+		"/api/v1/mint":                          s.handleMints,
+		"/api/v1/mint/collection":               s.handleMintsByCollection,
+		"/api/v1/collections":                   s.handleCollections,
+		"/api/v1/rpc/priority_fees":             s.handlePriorityFees,
+		"/api/v1/tx/buy":                        s.handleBuy,
+		"/api/v1/tx/sell":                       s.handleSell,
+		"/api/v1/tx/list":                       s.handleList,
+		"/api/v1/tx/delist":                     s.handleDelist,
+		"/api/v1/tx/edit":                       s.handleEditListing,
+		"/api/v1/tx/bid":                        s.handleNFTBid,
+		"/api/v1/tx/trait_bid":                  s.handleTraitBid,
+		"/api/v1/tx/collection_bid":             s.handleCollectionBid,
+		"/api/v1/tx/edit_bid":                   s.handleEditBid,
+		"/api/v1/tx/cancel_bid":                 s.handleCancelBid,
+		"/api/v1/tx/deposit_withdraw_escrow":    s.handleEscrow,
+		"/api/v1/tx/tswap/close_order":          s.handleClosePool,
+		"/api/v1/tx/tswap/edit_order":           s.handleEditPool,
+		"/api/v1/tx/tswap/deposit_withdraw":     s.handlePoolNFT,
+		"/api/v1/tx/tswap/deposit_withdraw_sol": s.handlePoolSOL,
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+	})
+	requestID := len(s.requests)
+	apiKey := s.apiKey
+	fault := s.matchFault(r.URL.Path)
+	s.mu.Unlock()
+
+	if fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.StatusCode != 0 {
+			if fault.StatusCode == http.StatusTooManyRequests && fault.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int((fault.RetryAfter+time.Second-1)/time.Second)))
+			}
+			writeError(w, fault.StatusCode, http.StatusText(fault.StatusCode))
+			return
+		}
+	}
+
+	if apiKey != "" && r.Header.Get("x-tensor-api-key") != apiKey {
+		writeError(w, http.StatusUnauthorized, "invalid api key")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	handler, ok := s.routes()[r.URL.Path]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	s.mu.Lock()
+	out, err := handler(r.URL.Query())
+	s.mu.Unlock()
+	if err != nil {
+		status := http.StatusBadRequest
+		if se, ok := err.(*statusError); ok {
+			status = se.status
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprintf("tensortest-%d", requestID))
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// statusError is a handler error that maps to a specific HTTP status
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string { return e.message }
+
+func notFound(format string, args ...any) error {
+	return &statusError{status: http.StatusNotFound, message: fmt.Sprintf(format, args...)}
+}
+
+func badRequest(format string, args ...any) error {
+	return &statusError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"statusCode": status,
+		"message":    message,
+	})
+}
+
+// list reads a list parameter, accepting comma separated, repeated and JSON array forms
+func list(q url.Values, key string) []string {
+	var out []string
+	for _, v := range q[key] {
+		if strings.HasPrefix(v, "[") {
+			var items []string
+			if json.Unmarshal([]byte(v), &items) == nil {
+				out = append(out, items...)
+				continue
+			}
+		}
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
+
+// lamports reads a numeric parameter as an integer lamport amount
+func lamports(q url.Values, key string) (int64, error) {
+	v := q.Get(key)
+	if v == "" {
+		return 0, badRequest("%s is required", key)
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return 0, badRequest("invalid %s: %s", key, v)
+	}
+	return int64(f + 0.5), nil
+}
+
+func intParam(q url.Values, key string, def int) int {
+	if n, err := strconv.Atoi(q.Get(key)); err == nil {
+		return n
+	}
+	return def
+}
+
+func contains(items []string, v string) bool {
+	for _, item := range items {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func formatLamports(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func parseLamports(v string) int64 {
+	n, _ := strconv.ParseInt(v, 10, 64)
+	return n
+}
+
+// paginate applies cursor based pagination, the cursor being the offset of the next item
+func paginate[T any](items []T, q url.Values, defaultLimit int) ([]T, Page) {
+	offset := 0
+	if c := q.Get("cursor"); c != "" {
+		offset, _ = strconv.Atoi(c)
+	}
+	limit := intParam(q, "limit", defaultLimit)
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	page := Page{HasMore: end < len(items)}
+	if page.HasMore {
+		page.EndCursor = strconv.Itoa(end)
+	}
+	return items[offset:end], page
+}
+
+// sortByPrice orders listed NFTs by listing price, unlisted NFTs last
+func sortByPrice(items []NFT, desc bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].Listing, items[j].Listing
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if desc {
+			return parseLamports(a.Price) > parseLamports(b.Price)
+		}
+		return parseLamports(a.Price) < parseLamports(b.Price)
+	})
+}
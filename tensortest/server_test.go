@@ -0,0 +1,279 @@
+package tensortest
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/collections"
+	"github.com/srpvpn/tensor-go-sdk/api/escrow"
+	"github.com/srpvpn/tensor-go-sdk/api/marketplace"
+	"github.com/srpvpn/tensor-go-sdk/api/tswap"
+	"github.com/srpvpn/tensor-go-sdk/api/user"
+	"github.com/srpvpn/tensor-go-sdk/client"
+	"github.com/srpvpn/tensor-go-sdk/internal/errors"
+	"github.com/srpvpn/tensor-go-sdk/money"
+)
+
+const blockhash = "11111111111111111111111111111111"
+
+var (
+	collID = "test-collection"
+	alice  = Address("alice")
+	bob    = Address("bob")
+	mintA  = Address("mint-a")
+	mintB  = Address("mint-b")
+)
+
+func newTestServer(t *testing.T) (*Server, *client.Client) {
+	t.Helper()
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddCollection(Collection{CollId: collID, Name: "Test", SlugDisplay: "test"})
+	srv.AddNFT(NFT{Mint: mintA, CollId: collID, Owner: alice})
+	srv.AddNFT(NFT{Mint: mintB, CollId: collID, Owner: alice, Listing: &Listing{Price: "2000000000"}})
+	return srv, client.New(&client.Config{BaseURL: srv.URL})
+}
+
+func decodeListings(t *testing.T, body []byte) []NFT {
+	t.Helper()
+	var resp struct {
+		Listings []NFT `json:"listings"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("failed to decode listings: %v", err)
+	}
+	return resp.Listings
+}
+
+func TestAddress(t *testing.T) {
+	if Address("x") != Address("x") {
+		t.Error("Address() is not deterministic")
+	}
+	if Address("x") == Address("y") {
+		t.Error("Address() returned the same address for different seeds")
+	}
+	if n := len(Address("x")); n < 32 || n > 44 {
+		t.Errorf("Address() length = %d, want 32-44", n)
+	}
+}
+
+func TestServer_ListingAppearsInActiveListings(t *testing.T) {
+	srv, c := newTestServer(t)
+	ctx := context.Background()
+
+	_, _, err := c.Marketplace.ListNFT(ctx, &marketplace.ListNFTRequest{Mint: mintA, Owner: alice, Price: 1_500_000_000, Blockhash: blockhash})
+	if err != nil {
+		t.Fatalf("ListNFT() error = %v", err)
+	}
+
+	body, status, err := c.User.GetListings(ctx, &user.ListingsRequest{Wallets: []string{alice}, SortBy: "PriceAsc", Limit: 10})
+	if err != nil {
+		t.Fatalf("GetListings() error = %v", err)
+	}
+	if status != 200 {
+		t.Errorf("GetListings() status = %d, want 200", status)
+	}
+	listings := decodeListings(t, body)
+	if len(listings) != 2 {
+		t.Fatalf("GetListings() returned %d listings, want 2", len(listings))
+	}
+	if listings[0].Mint != mintA || listings[0].Listing.Price != "1500000000" {
+		t.Errorf("GetListings()[0] = %+v, want %s at 1500000000", listings[0], mintA)
+	}
+
+	if _, _, err := c.Marketplace.DelistNFT(ctx, &marketplace.DelistNFTRequest{Mint: mintA, Owner: alice, Blockhash: blockhash}); err != nil {
+		t.Fatalf("DelistNFT() error = %v", err)
+	}
+	if n, _ := srv.NFT(mintA); n.Listing != nil {
+		t.Errorf("NFT still listed after DelistNFT(): %+v", n.Listing)
+	}
+}
+
+func TestServer_BuyTransfersOwnership(t *testing.T) {
+	srv, c := newTestServer(t)
+	ctx := context.Background()
+
+	_, _, err := c.Marketplace.BuyNFT(ctx, &marketplace.BuyNFTRequest{Buyer: bob, Mint: mintB, Owner: alice, MaxPrice: 1_000_000_000, Blockhash: blockhash})
+	if err == nil {
+		t.Fatal("BuyNFT() below listing price expected error")
+	}
+	var apiErr *errors.APIError
+	if !stderrors.As(err, &apiErr) || apiErr.Code != 400 {
+		t.Errorf("BuyNFT() error = %v, want 400 APIError", err)
+	}
+
+	resp, _, err := c.Marketplace.BuyNFT(ctx, &marketplace.BuyNFTRequest{Buyer: bob, Mint: mintB, Owner: alice, MaxPrice: 2_000_000_000, Blockhash: blockhash})
+	if err != nil {
+		t.Fatalf("BuyNFT() error = %v", err)
+	}
+	if len(resp.Txs) != 1 || resp.Txs[0].TxV0 == "" || resp.Txs[0].LastValidBlockHeight == nil {
+		t.Errorf("BuyNFT() txs = %+v, want one transaction", resp.Txs)
+	}
+
+	n, _ := srv.NFT(mintB)
+	if n.Owner != bob || n.Listing != nil {
+		t.Errorf("NFT after buy = owner %s listing %v, want owner %s unlisted", n.Owner, n.Listing, bob)
+	}
+	txs := srv.Transactions()
+	if len(txs) != 1 || txs[0].TxType != "SALE_BUY_NOW" || txs[0].Seller != alice {
+		t.Errorf("Transactions() = %+v, want one SALE_BUY_NOW", txs)
+	}
+}
+
+func TestServer_BidLifecycle(t *testing.T) {
+	srv, c := newTestServer(t)
+	ctx := context.Background()
+
+	if _, _, err := c.Marketplace.PlaceNFTBid(ctx, &marketplace.PlaceNFTBidRequest{Owner: bob, Mint: mintA, Price: 900_000_000, Blockhash: blockhash}); err != nil {
+		t.Fatalf("PlaceNFTBid() error = %v", err)
+	}
+
+	body, _, err := c.User.GetNFTBids(ctx, &user.NFTBidsRequest{Owner: bob, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetNFTBids() error = %v", err)
+	}
+	var bids struct {
+		Bids []Bid `json:"bids"`
+	}
+	if err := json.Unmarshal(body, &bids); err != nil {
+		t.Fatalf("failed to decode bids: %v", err)
+	}
+	if len(bids.Bids) != 1 || bids.Bids[0].TargetId != mintA || bids.Bids[0].Price != "900000000" {
+		t.Fatalf("GetNFTBids() = %+v, want one bid on %s", bids.Bids, mintA)
+	}
+	addr := bids.Bids[0].Address
+
+	price := money.Lamports(950_000_000)
+	edit, _, err := c.Marketplace.EditBid(ctx, &marketplace.EditBidRequest{BidStateAddress: addr, Price: &price, Blockhash: blockhash})
+	if err != nil {
+		t.Fatalf("EditBid() error = %v", err)
+	}
+	if edit.BidState != addr {
+		t.Errorf("EditBid() bidState = %q, want %q", edit.BidState, addr)
+	}
+	if b, _ := srv.Bid(addr); b.Price != "950000000" {
+		t.Errorf("bid price after edit = %s, want 950000000", b.Price)
+	}
+
+	if _, _, err := c.Marketplace.SellNFT(ctx, &marketplace.SellNFTRequest{Seller: alice, Mint: mintA, BidAddress: addr, MinPrice: 950_000_000, Blockhash: blockhash}); err != nil {
+		t.Fatalf("SellNFT() error = %v", err)
+	}
+	if n, _ := srv.NFT(mintA); n.Owner != bob {
+		t.Errorf("NFT owner after sell = %s, want %s", n.Owner, bob)
+	}
+	if _, ok := srv.Bid(addr); ok {
+		t.Error("filled bid is still open")
+	}
+	if _, _, err := c.Marketplace.CancelBid(ctx, &marketplace.CancelBidRequest{BidStateAddress: addr, Blockhash: blockhash}); err == nil {
+		t.Error("CancelBid() on filled bid expected error")
+	}
+}
+
+func TestServer_CollectionStats(t *testing.T) {
+	srv, c := newTestServer(t)
+	srv.AddBid(Bid{Owner: bob, Target: BidTargetCollection, TargetId: collID, CollId: collID, Price: "1200000000"})
+
+	body, _, err := c.Collections.GetVerifiedCollections(context.Background(), &collections.GetVerifiedCollectionsRequest{SortBy: "statsV2.volume24h:desc", Limit: 10})
+	if err != nil {
+		t.Fatalf("GetVerifiedCollections() error = %v", err)
+	}
+	var resp collections.GetVerifiedCollectionsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("failed to decode collections: %v", err)
+	}
+	if len(resp.Collections) != 1 {
+		t.Fatalf("GetVerifiedCollections() returned %d collections, want 1", len(resp.Collections))
+	}
+	stats := resp.Collections[0].Stats
+	if stats.BuyNowPrice != "2000000000" || stats.SellNowPrice != "1200000000" || stats.NumListed != 1 || stats.NumMints != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestServer_EscrowAndPools(t *testing.T) {
+	srv, c := newTestServer(t)
+	ctx := context.Background()
+
+	if _, _, err := c.Escrow.DepositWithdrawEscrow(ctx, &escrow.DepositWithdrawEscrowRequest{Action: "deposit", Owner: bob, Lamports: 3_000_000_000, Blockhash: blockhash}); err != nil {
+		t.Fatalf("deposit error = %v", err)
+	}
+	if _, _, err := c.Escrow.DepositWithdrawEscrow(ctx, &escrow.DepositWithdrawEscrowRequest{Action: "withdraw", Owner: bob, Lamports: 4_000_000_000, Blockhash: blockhash}); err == nil {
+		t.Error("overdrawing escrow expected error")
+	}
+	if got := srv.EscrowBalance(bob); got != 3_000_000_000 {
+		t.Errorf("EscrowBalance() = %d, want 3000000000", got)
+	}
+
+	pool := srv.AddPool(Pool{Owner: alice, CollId: collID, PoolType: "TOKEN", CurveType: "linear", StartingPrice: "1000000000", Delta: "0"})
+	if _, _, err := c.TSwap.DepositWithdrawSOL(ctx, &tswap.DepositWithdrawSOLRequest{Action: "DEPOSIT", PoolAddress: pool, Lamports: 500_000_000, Blockhash: blockhash}); err != nil {
+		t.Fatalf("DepositWithdrawSOL() error = %v", err)
+	}
+	if _, _, err := c.TSwap.DepositWithdrawNFT(ctx, &tswap.DepositWithdrawNFTRequest{Action: "DEPOSIT", PoolAddress: pool, Mint: mintA, Blockhash: blockhash}); err != nil {
+		t.Fatalf("DepositWithdrawNFT() error = %v", err)
+	}
+	p, _ := srv.Pool(pool)
+	if p.SolBalance != "500000000" || len(p.NftsHeld) != 1 {
+		t.Errorf("pool = %+v, want 500000000 lamports and one NFT", p)
+	}
+	if _, _, err := c.TSwap.CloseTSwapPool(ctx, &tswap.CloseTSwapPoolRequest{PoolAddress: pool, Blockhash: blockhash}); err == nil {
+		t.Error("closing a pool holding NFTs expected error")
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	srv, c := newTestServer(t)
+	ctx := context.Background()
+	req := &user.ListingsRequest{Wallets: []string{alice}, SortBy: "PriceAsc", Limit: 10}
+
+	srv.InjectFault(Fault{PathPrefix: "/api/v1/user/", StatusCode: 429, RetryAfter: 2 * time.Second, Times: 1})
+	_, status, err := c.User.GetListings(ctx, req)
+	if err == nil || status != 429 {
+		t.Errorf("GetListings() status = %d err = %v, want 429", status, err)
+	}
+	if reqs := srv.Requests(); len(reqs) != 1 {
+		t.Errorf("Requests() = %d, want 1", len(reqs))
+	}
+
+	// The fault was limited to one request
+	if _, status, err := c.User.GetListings(ctx, req); err != nil || status != 200 {
+		t.Errorf("GetListings() after fault status = %d err = %v, want 200", status, err)
+	}
+
+	srv.InjectFault(Fault{StatusCode: 503})
+	for i := 0; i < 2; i++ {
+		if _, status, _ := c.User.GetListings(ctx, req); status != 503 {
+			t.Errorf("GetListings() status = %d, want 503", status)
+		}
+	}
+	srv.ClearFaults()
+
+	srv.InjectFault(Fault{Latency: 50 * time.Millisecond})
+	start := time.Now()
+	if _, _, err := c.User.GetListings(ctx, req); err != nil {
+		t.Fatalf("GetListings() with latency error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("GetListings() took %v, want at least 50ms", elapsed)
+	}
+	if n := srv.RequestCount("/api/v1/user/active_listings"); n != 5 {
+		t.Errorf("RequestCount() = %d, want 5", n)
+	}
+}
+
+func TestServer_RequireAPIKey(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.RequireAPIKey("secret")
+	req := &user.ListingsRequest{Wallets: []string{alice}, SortBy: "PriceAsc", Limit: 10}
+
+	_, status, _ := client.New(&client.Config{BaseURL: srv.URL}).User.GetListings(context.Background(), req)
+	if status != 401 {
+		t.Errorf("GetListings() without key status = %d, want 401", status)
+	}
+	_, status, err := client.New(&client.Config{BaseURL: srv.URL, APIKey: "secret"}).User.GetListings(context.Background(), req)
+	if err != nil || status != 200 {
+		t.Errorf("GetListings() with key status = %d err = %v, want 200", status, err)
+	}
+}
@@ -0,0 +1,221 @@
+package tensortest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/srpvpn/tensor-go-sdk/api/rpc"
+)
+
+// AddCollection seeds a collection. CollId is required; stats are computed from server state.
+func (s *Server) AddCollection(c Collection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c.CollId == "" {
+		panic("tensortest: collection CollId is required")
+	}
+	if _, ok := s.colls[c.CollId]; !ok {
+		s.collOrder = append(s.collOrder, c.CollId)
+	}
+	s.colls[c.CollId] = &c
+}
+
+// AddNFT seeds an NFT. Mint and Owner are required. A non-nil Listing makes the NFT
+// listed; an empty Listing.Seller defaults to the owner.
+func (s *Server) AddNFT(n NFT) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n.Mint == "" || n.Owner == "" {
+		panic("tensortest: NFT Mint and Owner are required")
+	}
+	if n.Listing != nil {
+		l := *n.Listing
+		if l.Seller == "" {
+			l.Seller = n.Owner
+		}
+		if l.Source == "" {
+			l.Source = "TENSORSWAP"
+		}
+		n.Listing = &l
+	}
+	if _, ok := s.nfts[n.Mint]; !ok {
+		s.nftOrder = append(s.nftOrder, n.Mint)
+	}
+	s.nfts[n.Mint] = &n
+}
+
+// AddBid seeds a bid. An empty Address is generated; the assigned address is returned.
+func (s *Server) AddBid(b Bid) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b.Address == "" {
+		b.Address = s.nextAddress("bid")
+	}
+	if b.Quantity == 0 {
+		b.Quantity = 1
+	}
+	s.putBid(&b)
+	return b.Address
+}
+
+// AddPool seeds a TSwap pool. An empty Address is generated; the assigned address is returned.
+func (s *Server) AddPool(p Pool) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.Address == "" {
+		p.Address = s.nextAddress("pool")
+	}
+	if p.SolBalance == "" {
+		p.SolBalance = "0"
+	}
+	if _, ok := s.pools[p.Address]; !ok {
+		s.poolOrder = append(s.poolOrder, p.Address)
+	}
+	s.pools[p.Address] = &p
+	return p.Address
+}
+
+// SetEscrowBalance sets the shared escrow balance of owner in lamports
+func (s *Server) SetEscrowBalance(owner string, lamports int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.escrow(owner).Balance = formatLamports(lamports)
+}
+
+// SetPriorityFees sets the response of the priority fees endpoint
+func (s *Server) SetPriorityFees(fees rpc.PriorityFeesResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.priorityFees = fees
+}
+
+// SetClock overrides the clock used for bid expiry and transaction timestamps
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// NFT returns a copy of the NFT with the given mint
+func (s *Server) NFT(mint string) (NFT, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.nfts[mint]
+	if !ok {
+		return NFT{}, false
+	}
+	return copyNFT(n), true
+}
+
+// Bid returns a copy of the open bid at address
+func (s *Server) Bid(address string) (Bid, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.bids[address]
+	if !ok {
+		return Bid{}, false
+	}
+	return *b, true
+}
+
+// Bids returns copies of every open bid owned by owner, in creation order
+func (s *Server) Bids(owner string) []Bid {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Bid
+	for _, addr := range s.bidOrder {
+		if b := s.bids[addr]; b != nil && b.Owner == owner {
+			out = append(out, *b)
+		}
+	}
+	return out
+}
+
+// Pool returns a copy of the pool at address
+func (s *Server) Pool(address string) (Pool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pools[address]
+	if !ok {
+		return Pool{}, false
+	}
+	out := *p
+	out.NftsHeld = append([]string(nil), p.NftsHeld...)
+	return out, true
+}
+
+// EscrowBalance returns the shared escrow balance of owner in lamports
+func (s *Server) EscrowBalance(owner string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.escrows[owner]; ok {
+		return parseLamports(e.Balance)
+	}
+	return 0
+}
+
+// Transactions returns every transaction applied so far, oldest first
+func (s *Server) Transactions() []Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Transaction(nil), s.txs...)
+}
+
+func copyNFT(n *NFT) NFT {
+	out := *n
+	if n.Listing != nil {
+		l := *n.Listing
+		out.Listing = &l
+	}
+	out.Attributes = append([]Attribute(nil), n.Attributes...)
+	return out
+}
+
+// nextAddress returns a fresh deterministic address. Callers must hold s.mu.
+func (s *Server) nextAddress(kind string) string {
+	s.seq++
+	return Address(fmt.Sprintf("tensortest/%s/%d", kind, s.seq))
+}
+
+// escrow returns the escrow account of owner, creating it if needed. Callers must hold s.mu.
+func (s *Server) escrow(owner string) *EscrowAccount {
+	e, ok := s.escrows[owner]
+	if !ok {
+		e = &EscrowAccount{Address: Address("tensortest/escrow/" + owner), Owner: owner, Balance: "0"}
+		s.escrows[owner] = e
+	}
+	return e
+}
+
+// putBid stores b. Callers must hold s.mu.
+func (s *Server) putBid(b *Bid) {
+	if _, ok := s.bids[b.Address]; !ok {
+		s.bidOrder = append(s.bidOrder, b.Address)
+	}
+	s.bids[b.Address] = b
+}
+
+// deleteBid removes the bid at address. Callers must hold s.mu.
+func (s *Server) deleteBid(address string) {
+	delete(s.bids, address)
+	for i, addr := range s.bidOrder {
+		if addr == address {
+			s.bidOrder = append(s.bidOrder[:i], s.bidOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// activeBids returns the open, unexpired bids in creation order. Callers must hold s.mu.
+func (s *Server) activeBids() []*Bid {
+	now := s.now().Unix()
+	var out []*Bid
+	for _, addr := range s.bidOrder {
+		b := s.bids[addr]
+		if b.ExpiresAt != 0 && b.ExpiresAt <= now {
+			continue
+		}
+		out = append(out, b)
+	}
+	return out
+}
@@ -0,0 +1,483 @@
+package tensortest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// txEnvelope is an unsigned transaction as returned by the tx endpoints
+type txEnvelope struct {
+	Tx                   *string        `json:"tx"`
+	TxV0                 string         `json:"txV0"`
+	LastValidBlockHeight int64          `json:"lastValidBlockHeight"`
+	Metadata             map[string]any `json:"metadata"`
+	TotalCost            *int64         `json:"totalCost,omitempty"`
+}
+
+// newTx builds a placeholder transaction and records the marketplace event it represents.
+// Callers must hold s.mu.
+func (s *Server) newTx(event Transaction) txEnvelope {
+	s.seq++
+	s.blockHeight++
+	event.TxId = Address(fmt.Sprintf("tensortest/tx/%d", s.seq))
+	event.BlockTime = s.now().Unix()
+	s.txs = append(s.txs, event)
+	return txEnvelope{
+		TxV0:                 base64.StdEncoding.EncodeToString([]byte("tensortest:" + event.TxType + ":" + event.TxId)),
+		LastValidBlockHeight: s.blockHeight + 150,
+		Metadata:             map[string]any{"txType": event.TxType},
+	}
+}
+
+func txsResponse(txs ...txEnvelope) map[string]any {
+	return map[string]any{"txs": txs}
+}
+
+func requireParams(q url.Values, keys ...string) error {
+	for _, key := range keys {
+		if q.Get(key) == "" {
+			return badRequest("%s is required", key)
+		}
+	}
+	return nil
+}
+
+// ownedNFT returns the NFT when owner holds it. Callers must hold s.mu.
+func (s *Server) ownedNFT(mint, owner string) (*NFT, error) {
+	n, ok := s.nfts[mint]
+	if !ok {
+		return nil, notFound("mint %s not found", mint)
+	}
+	if n.Owner != owner {
+		return nil, badRequest("mint %s is not owned by %s", mint, owner)
+	}
+	return n, nil
+}
+
+func (s *Server) handleBuy(q url.Values) (any, error) {
+	if err := requireParams(q, "buyer", "mint", "owner", "blockhash"); err != nil {
+		return nil, err
+	}
+	maxPrice, err := lamports(q, "maxPrice")
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.ownedNFT(q.Get("mint"), q.Get("owner"))
+	if err != nil {
+		return nil, err
+	}
+	if n.Listing == nil {
+		return nil, badRequest("mint %s is not listed", n.Mint)
+	}
+	price := parseLamports(n.Listing.Price)
+	if price > maxPrice {
+		return nil, badRequest("listing price %d exceeds maxPrice %d", price, maxPrice)
+	}
+
+	seller := n.Owner
+	n.Owner = q.Get("buyer")
+	n.Listing = nil
+	tx := s.newTx(Transaction{TxType: "SALE_BUY_NOW", Mint: n.Mint, CollId: n.CollId, Price: formatLamports(price), Buyer: n.Owner, Seller: seller})
+	if q.Get("includeTotalCost") == "true" {
+		tx.TotalCost = &price
+	}
+	return txsResponse(tx), nil
+}
+
+func (s *Server) handleSell(q url.Values) (any, error) {
+	if err := requireParams(q, "seller", "mint", "bidAddress", "blockhash"); err != nil {
+		return nil, err
+	}
+	minPrice, err := lamports(q, "minPrice")
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.ownedNFT(q.Get("mint"), q.Get("seller"))
+	if err != nil {
+		return nil, err
+	}
+	b, ok := s.bids[q.Get("bidAddress")]
+	if !ok || (b.ExpiresAt != 0 && b.ExpiresAt <= s.now().Unix()) {
+		return nil, notFound("bid %s not found", q.Get("bidAddress"))
+	}
+	if !bidMatches(b, n) {
+		return nil, badRequest("bid %s does not target mint %s", b.Address, n.Mint)
+	}
+	price := parseLamports(b.Price)
+	if price < minPrice {
+		return nil, badRequest("bid price %d is below minPrice %d", price, minPrice)
+	}
+	if b.SharedEscrow {
+		e := s.escrow(b.Owner)
+		balance := parseLamports(e.Balance)
+		if balance < price {
+			return nil, badRequest("bidder escrow balance %d is below bid price %d", balance, price)
+		}
+		e.Balance = formatLamports(balance - price)
+	}
+
+	n.Owner = b.Owner
+	n.Listing = nil
+	b.FilledQuantity++
+	if b.FilledQuantity >= b.Quantity {
+		s.deleteBid(b.Address)
+	}
+	tx := s.newTx(Transaction{TxType: "SALE_ACCEPT_BID", Mint: n.Mint, CollId: n.CollId, Price: formatLamports(price), Buyer: b.Owner, Seller: q.Get("seller")})
+	return txsResponse(tx), nil
+}
+
+// bidMatches reports whether b can be filled with n
+func bidMatches(b *Bid, n *NFT) bool {
+	switch b.Target {
+	case BidTargetNFT:
+		return b.TargetId == n.Mint
+	case BidTargetTrait:
+		if b.CollId != n.CollId {
+			return false
+		}
+		for _, trait := range b.Traits {
+			found := false
+			for _, attr := range n.Attributes {
+				if trait == attr.TraitType+":"+attr.Value {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	default:
+		return b.CollId == n.CollId
+	}
+}
+
+func (s *Server) handleList(q url.Values) (any, error) {
+	if err := requireParams(q, "mint", "owner", "blockhash"); err != nil {
+		return nil, err
+	}
+	price, err := lamports(q, "price")
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.ownedNFT(q.Get("mint"), q.Get("owner"))
+	if err != nil {
+		return nil, err
+	}
+	if n.Listing != nil {
+		return nil, badRequest("mint %s is already listed", n.Mint)
+	}
+	n.Listing = &Listing{Price: formatLamports(price), Seller: n.Owner, Source: "TENSORSWAP"}
+	tx := s.newTx(Transaction{TxType: "LIST", Mint: n.Mint, CollId: n.CollId, Price: n.Listing.Price, Seller: n.Owner})
+	return txsResponse(tx), nil
+}
+
+func (s *Server) handleDelist(q url.Values) (any, error) {
+	if err := requireParams(q, "mint", "owner", "blockhash"); err != nil {
+		return nil, err
+	}
+	n, err := s.ownedNFT(q.Get("mint"), q.Get("owner"))
+	if err != nil {
+		return nil, err
+	}
+	if n.Listing == nil {
+		return nil, badRequest("mint %s is not listed", n.Mint)
+	}
+	price := n.Listing.Price
+	n.Listing = nil
+	tx := s.newTx(Transaction{TxType: "DELIST", Mint: n.Mint, CollId: n.CollId, Price: price, Seller: n.Owner})
+	return txsResponse(tx), nil
+}
+
+func (s *Server) handleEditListing(q url.Values) (any, error) {
+	if err := requireParams(q, "mint", "owner", "blockhash"); err != nil {
+		return nil, err
+	}
+	price, err := lamports(q, "price")
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.ownedNFT(q.Get("mint"), q.Get("owner"))
+	if err != nil {
+		return nil, err
+	}
+	if n.Listing == nil {
+		return nil, badRequest("mint %s is not listed", n.Mint)
+	}
+	n.Listing.Price = formatLamports(price)
+	tx := s.newTx(Transaction{TxType: "ADJUST_PRICE", Mint: n.Mint, CollId: n.CollId, Price: n.Listing.Price, Seller: n.Owner})
+	return txsResponse(tx), nil
+}
+
+// placeBid stores a new bid and returns the bid transaction response. Callers must hold s.mu.
+func (s *Server) placeBid(q url.Values, b *Bid) (any, error) {
+	price, err := lamports(q, "price")
+	if err != nil {
+		return nil, err
+	}
+	b.Address = s.nextAddress("bid")
+	b.Owner = q.Get("owner")
+	b.Price = formatLamports(price)
+	b.SharedEscrow = q.Get("useSharedEscrow") == "true"
+	if b.Quantity <= 0 {
+		b.Quantity = 1
+	}
+	if expireIn := intParam(q, "expireIn", 0); expireIn > 0 {
+		b.ExpiresAt = s.now().Unix() + int64(expireIn)
+	}
+	s.putBid(b)
+	tx := s.newTx(Transaction{TxType: "PLACE_BID", Mint: mintOf(b), CollId: b.CollId, Price: b.Price, Buyer: b.Owner})
+	return map[string]any{"message": tx.TxV0, "bidState": b.Address, "txs": []txEnvelope{tx}}, nil
+}
+
+func mintOf(b *Bid) string {
+	if b.Target == BidTargetNFT {
+		return b.TargetId
+	}
+	return ""
+}
+
+func (s *Server) handleNFTBid(q url.Values) (any, error) {
+	if err := requireParams(q, "owner", "mint", "blockhash"); err != nil {
+		return nil, err
+	}
+	n, ok := s.nfts[q.Get("mint")]
+	if !ok {
+		return nil, notFound("mint %s not found", q.Get("mint"))
+	}
+	return s.placeBid(q, &Bid{Target: BidTargetNFT, TargetId: n.Mint, CollId: n.CollId})
+}
+
+func (s *Server) handleCollectionBid(q url.Values) (any, error) {
+	if err := requireParams(q, "owner", "collId", "blockhash"); err != nil {
+		return nil, err
+	}
+	collID := q.Get("collId")
+	return s.placeBid(q, &Bid{Target: BidTargetCollection, TargetId: collID, CollId: collID, Quantity: int32(intParam(q, "quantity", 1))})
+}
+
+func (s *Server) handleTraitBid(q url.Values) (any, error) {
+	if err := requireParams(q, "owner", "collId", "blockhash"); err != nil {
+		return nil, err
+	}
+	collID := q.Get("collId")
+	return s.placeBid(q, &Bid{Target: BidTargetTrait, TargetId: collID, CollId: collID, Quantity: int32(intParam(q, "quantity", 1)), Traits: list(q, "traits")})
+}
+
+func (s *Server) handleEditBid(q url.Values) (any, error) {
+	if err := requireParams(q, "bidStateAddress", "blockhash"); err != nil {
+		return nil, err
+	}
+	b, ok := s.bids[q.Get("bidStateAddress")]
+	if !ok {
+		return nil, notFound("bid %s not found", q.Get("bidStateAddress"))
+	}
+	if q.Get("price") != "" {
+		price, err := lamports(q, "price")
+		if err != nil {
+			return nil, err
+		}
+		b.Price = formatLamports(price)
+	}
+	if q.Get("quantity") != "" {
+		quantity := intParam(q, "quantity", 0)
+		if quantity < int(b.FilledQuantity) || quantity <= 0 {
+			return nil, badRequest("invalid quantity: %s", q.Get("quantity"))
+		}
+		b.Quantity = int32(quantity)
+	}
+	if expireIn := intParam(q, "expireIn", 0); expireIn > 0 {
+		b.ExpiresAt = s.now().Unix() + int64(expireIn)
+	}
+	if q.Get("useSharedEscrow") != "" {
+		b.SharedEscrow = q.Get("useSharedEscrow") == "true"
+	}
+	tx := s.newTx(Transaction{TxType: "PLACE_BID", Mint: mintOf(b), CollId: b.CollId, Price: b.Price, Buyer: b.Owner})
+	return map[string]any{"txs": []txEnvelope{tx}, "bidState": b.Address}, nil
+}
+
+func (s *Server) handleCancelBid(q url.Values) (any, error) {
+	if err := requireParams(q, "bidStateAddress", "blockhash"); err != nil {
+		return nil, err
+	}
+	b, ok := s.bids[q.Get("bidStateAddress")]
+	if !ok {
+		return nil, notFound("bid %s not found", q.Get("bidStateAddress"))
+	}
+	s.deleteBid(b.Address)
+	tx := s.newTx(Transaction{TxType: "CANCEL_BID", Mint: mintOf(b), CollId: b.CollId, Price: b.Price, Buyer: b.Owner})
+	return map[string]any{"txs": []txEnvelope{tx}, "bidState": b.Address}, nil
+}
+
+func (s *Server) handleEscrow(q url.Values) (any, error) {
+	if err := requireParams(q, "action", "owner", "blockhash"); err != nil {
+		return nil, err
+	}
+	amount, err := lamports(q, "lamports")
+	if err != nil {
+		return nil, err
+	}
+	e := s.escrow(q.Get("owner"))
+	balance := parseLamports(e.Balance)
+	var tx txEnvelope
+	switch strings.ToLower(q.Get("action")) {
+	case "deposit":
+		balance += amount
+		tx = s.newTx(Transaction{TxType: "MARGIN_DEPOSIT", Price: formatLamports(amount), Buyer: e.Owner})
+	case "withdraw":
+		if amount > balance {
+			return nil, badRequest("insufficient escrow balance: have %d, want %d", balance, amount)
+		}
+		balance -= amount
+		tx = s.newTx(Transaction{TxType: "MARGIN_WITHDRAW", Price: formatLamports(amount), Buyer: e.Owner})
+	default:
+		return nil, badRequest("invalid action: %s", q.Get("action"))
+	}
+	e.Balance = formatLamports(balance)
+	resp := txsResponse(tx)
+	resp["status"] = "Ok"
+	return resp, nil
+}
+
+// pool returns the pool at the poolAddress parameter. Callers must hold s.mu.
+func (s *Server) pool(q url.Values) (*Pool, error) {
+	if err := requireParams(q, "poolAddress", "blockhash"); err != nil {
+		return nil, err
+	}
+	p, ok := s.pools[q.Get("poolAddress")]
+	if !ok {
+		return nil, notFound("pool %s not found", q.Get("poolAddress"))
+	}
+	return p, nil
+}
+
+func (s *Server) handleClosePool(q url.Values) (any, error) {
+	p, err := s.pool(q)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.NftsHeld) > 0 {
+		return nil, badRequest("pool %s still holds %d NFTs", p.Address, len(p.NftsHeld))
+	}
+	delete(s.pools, p.Address)
+	for i, addr := range s.poolOrder {
+		if addr == p.Address {
+			s.poolOrder = append(s.poolOrder[:i], s.poolOrder[i+1:]...)
+			break
+		}
+	}
+	tx := s.newTx(Transaction{TxType: "SWAP_CLOSE_POOL", CollId: p.CollId, Price: p.SolBalance, Seller: p.Owner})
+	return txsResponse(tx), nil
+}
+
+func (s *Server) handleEditPool(q url.Values) (any, error) {
+	p, err := s.pool(q)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireParams(q, "poolType", "curveType"); err != nil {
+		return nil, err
+	}
+	startingPrice, err := lamports(q, "startingPrice")
+	if err != nil {
+		return nil, err
+	}
+	delta, err := lamports(q, "delta")
+	if err != nil {
+		return nil, err
+	}
+	p.PoolType = q.Get("poolType")
+	p.CurveType = q.Get("curveType")
+	p.StartingPrice = formatLamports(startingPrice)
+	p.Delta = formatLamports(delta)
+	if v := q.Get("mmFeeBps"); v != "" {
+		bps, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, badRequest("invalid mmFeeBps: %s", v)
+		}
+		p.MmFeeBps = int32(bps)
+	}
+	tx := s.newTx(Transaction{TxType: "SWAP_EDIT_POOL", CollId: p.CollId, Price: p.StartingPrice, Seller: p.Owner})
+	return txsResponse(tx), nil
+}
+
+func (s *Server) handlePoolNFT(q url.Values) (any, error) {
+	p, err := s.pool(q)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireParams(q, "action", "mint"); err != nil {
+		return nil, err
+	}
+	mint := q.Get("mint")
+	switch strings.ToLower(q.Get("action")) {
+	case "deposit":
+		n, err := s.ownedNFT(mint, p.Owner)
+		if err != nil {
+			return nil, err
+		}
+		if n.Listing != nil {
+			return nil, badRequest("mint %s is listed", mint)
+		}
+		n.Owner = p.Address
+		p.NftsHeld = append(p.NftsHeld, mint)
+		tx := s.newTx(Transaction{TxType: "SWAP_DEPOSIT_NFT", Mint: mint, CollId: n.CollId, Seller: p.Owner})
+		return txsResponse(tx), nil
+	case "withdraw":
+		idx := -1
+		for i, held := range p.NftsHeld {
+			if held == mint {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, badRequest("mint %s is not held by pool %s", mint, p.Address)
+		}
+		p.NftsHeld = append(p.NftsHeld[:idx], p.NftsHeld[idx+1:]...)
+		var collID string
+		if n, ok := s.nfts[mint]; ok {
+			n.Owner = p.Owner
+			collID = n.CollId
+		}
+		tx := s.newTx(Transaction{TxType: "SWAP_WITHDRAW_NFT", Mint: mint, CollId: collID, Buyer: p.Owner})
+		return txsResponse(tx), nil
+	default:
+		return nil, badRequest("invalid action: %s", q.Get("action"))
+	}
+}
+
+func (s *Server) handlePoolSOL(q url.Values) (any, error) {
+	p, err := s.pool(q)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireParams(q, "action"); err != nil {
+		return nil, err
+	}
+	amount, err := lamports(q, "lamports")
+	if err != nil {
+		return nil, err
+	}
+	balance := parseLamports(p.SolBalance)
+	txType := "SWAP_DEPOSIT_SOL"
+	switch strings.ToLower(q.Get("action")) {
+	case "deposit":
+		balance += amount
+	case "withdraw":
+		if amount > balance {
+			return nil, badRequest("insufficient pool balance: have %d, want %d", balance, amount)
+		}
+		balance -= amount
+		txType = "SWAP_WITHDRAW_SOL"
+	default:
+		return nil, badRequest("invalid action: %s", q.Get("action"))
+	}
+	p.SolBalance = formatLamports(balance)
+	tx := s.newTx(Transaction{TxType: txType, CollId: p.CollId, Price: formatLamports(amount), Seller: p.Owner})
+	return txsResponse(tx), nil
+}
@@ -0,0 +1,92 @@
+package tensortest
+
+import "github.com/srpvpn/tensor-go-sdk/api/collections"
+
+// NFT is an NFT known to the fake server, in the shape returned by the mint endpoints
+type NFT struct {
+	Mint       string      `json:"mint"`
+	CollId     string      `json:"collId"`
+	Name       string      `json:"name"`
+	Owner      string      `json:"owner"`
+	RarityRank int32       `json:"rarityRank,omitempty"`
+	Attributes []Attribute `json:"attributes,omitempty"`
+	Listing    *Listing    `json:"listing"` // nil when the NFT is not listed
+}
+
+// Attribute is a single NFT trait
+type Attribute struct {
+	TraitType string `json:"trait_type"`
+	Value     string `json:"value"`
+}
+
+// Listing is an active listing of an NFT
+type Listing struct {
+	Price  string `json:"price"` // Price in lamports
+	Seller string `json:"seller"`
+	Source string `json:"source"`
+}
+
+// Bid targets
+const (
+	BidTargetNFT        = "NFT"
+	BidTargetCollection = "COLLECTION"
+	BidTargetTrait      = "TRAIT"
+)
+
+// Bid is an open single NFT, collection or trait bid
+type Bid struct {
+	Address        string   `json:"address"`
+	Owner          string   `json:"owner"`
+	Target         string   `json:"target"`   // One of the BidTarget constants
+	TargetId       string   `json:"targetId"` // Mint for NFT bids, collection ID otherwise
+	CollId         string   `json:"collId"`
+	Price          string   `json:"price"` // Price per item in lamports
+	Quantity       int32    `json:"quantity"`
+	FilledQuantity int32    `json:"filledQuantity"`
+	Traits         []string `json:"traits,omitempty"`
+	SharedEscrow   bool     `json:"sharedEscrow"`
+	ExpiresAt      int64    `json:"expiresAt,omitempty"` // Unix seconds, 0 if the bid does not expire
+}
+
+// EscrowAccount is a shared escrow account
+type EscrowAccount struct {
+	Address string `json:"address"`
+	Owner   string `json:"owner"`
+	Balance string `json:"balance"` // Balance in lamports
+}
+
+// Pool is a TSwap pool
+type Pool struct {
+	Address       string   `json:"address"`
+	Owner         string   `json:"owner"`
+	CollId        string   `json:"collId"`
+	PoolType      string   `json:"poolType"`
+	CurveType     string   `json:"curveType"`
+	StartingPrice string   `json:"startingPrice"` // Lamports
+	Delta         string   `json:"delta"`
+	MmFeeBps      int32    `json:"mmFeeBps"`
+	SolBalance    string   `json:"solBalance"` // Lamports
+	NftsHeld      []string `json:"nftsHeld"`
+}
+
+// Transaction is a completed marketplace event, as returned by the user transactions endpoint
+type Transaction struct {
+	TxId      string `json:"txId"`
+	TxType    string `json:"txType"`
+	Mint      string `json:"mint"`
+	CollId    string `json:"collId"`
+	Price     string `json:"price"` // Lamports
+	Buyer     string `json:"buyer,omitempty"`
+	Seller    string `json:"seller,omitempty"`
+	BlockTime int64  `json:"blockTime"` // Unix seconds
+}
+
+// Page is the pagination block of list responses
+type Page struct {
+	EndCursor string `json:"endCursor,omitempty"`
+	HasMore   bool   `json:"hasMore"`
+}
+
+// Collection is the collection metadata served by /api/v1/collections.
+// Stats are derived from the server state at request time.
+type Collection = collections.CollectionDetailed